@@ -0,0 +1,150 @@
+package eveapi
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/modules/auth"
+	"github.com/guarzo/eveapi/modules/esi"
+	"github.com/guarzo/eveapi/modules/mock"
+	"github.com/guarzo/eveapi/modules/monitor"
+	"github.com/guarzo/eveapi/modules/notify"
+	"github.com/guarzo/eveapi/modules/zkill"
+)
+
+// Defaults applied by New to empty Config fields.
+const (
+	DefaultUserAgent    = "eveapi (+https://github.com/guarzo/eveapi)"
+	DefaultESIBaseURL   = "https://esi.evetech.net/latest/"
+	DefaultZKillBaseURL = "https://zkillboard.com"
+)
+
+// Config describes the stack New builds. Every field is optional.
+type Config struct {
+	// UserAgent identifies the application to ESI and zKillboard; CCP asks
+	// for contact details in it.
+	UserAgent    string
+	ESIBaseURL   string
+	ZKillBaseURL string
+
+	// HTTPClient defaults to common.NewEveHttpClient with UserAgent.
+	HTTPClient common.HttpClient
+	// Mock replaces the network with generated data seeded by MockSeed (see
+	// package mock), for demos and tests; HTTPClient is then ignored.
+	Mock     bool
+	MockSeed int64
+	// Cache defaults to a common.MemoryCache.
+	Cache common.CacheRepository
+	// Auth refreshes expired tokens; without it, expired tokens fail.
+	Auth common.AuthClient
+	// Validator checks access tokens for ESI.GetUserInfo. It defaults to an
+	// auth.Validator discovering CCP's keys with HTTPClient; with Mock, the
+	// mock's /oauth/verify answers instead.
+	Validator esi.TokenValidator
+	// Notifier receives monitor events; it defaults to dropping them.
+	Notifier notify.Notifier
+
+	ESIClientOptions    []esi.ClientOption
+	ESIServiceOptions   []esi.ServiceOption
+	ZKillClientOptions  []zkill.ClientOption
+	ZKillServiceOptions []zkill.ServiceOption
+	KillmailOptions     []zkill.KillmailServiceOption
+	PollerOptions       []monitor.PollerOption
+}
+
+// Client is a wired ESI and zKillboard stack. Its fields are the components
+// New built, for use directly or to construct monitors with.
+type Client struct {
+	HTTP  common.HttpClient
+	Cache common.CacheRepository
+
+	ESIClient esi.EsiClient
+	ESI       esi.EsiService
+	// Pool hands out corporation tokens to monitors; add tokens with Pool.Add.
+	Pool *esi.ClientPool
+
+	ZKillClient zkill.ZKillClient
+	// ZKill returns killmails hydrated with their ESI details.
+	ZKill zkill.ZKillService
+
+	Notifier notify.Notifier
+	Poller   *monitor.Poller
+}
+
+// New builds a Client from cfg:
+//
+//	client := eveapi.New(eveapi.Config{UserAgent: "myapp (me@example.com)"})
+//	prices, err := client.ESI.GetMarketPrices(ctx)
+func New(cfg Config) *Client {
+	if cfg.UserAgent == "" {
+		cfg.UserAgent = DefaultUserAgent
+	}
+	if cfg.ESIBaseURL == "" {
+		cfg.ESIBaseURL = DefaultESIBaseURL
+	}
+	if cfg.ZKillBaseURL == "" {
+		cfg.ZKillBaseURL = DefaultZKillBaseURL
+	}
+	switch {
+	case cfg.Mock:
+		cfg.HTTPClient = common.NewEveHttpClient(cfg.UserAgent, &http.Client{Transport: mock.NewTransport(cfg.MockSeed)})
+	case cfg.HTTPClient == nil:
+		cfg.HTTPClient = common.NewEveHttpClient(cfg.UserAgent, &http.Client{})
+	}
+	if cfg.Cache == nil {
+		cfg.Cache = common.NewMemoryCache()
+	}
+	if cfg.Notifier == nil {
+		cfg.Notifier = notify.Multi{}
+	}
+	if cfg.Validator == nil && !cfg.Mock {
+		cfg.Validator = auth.NewValidator(cfg.HTTPClient, auth.WithMetadataURL(auth.DefaultMetadataURL))
+	}
+	serviceOpts := []esi.ServiceOption{esi.WithCache(cfg.Cache)}
+	if cfg.Validator != nil {
+		serviceOpts = append(serviceOpts, esi.WithTokenValidator(cfg.Validator))
+	}
+
+	var authClient esi.AuthClient
+	if cfg.Auth != nil {
+		authClient = cfg.Auth
+	}
+	esiClient := esi.NewEsiClient(cfg.ESIBaseURL, cfg.HTTPClient, cfg.Cache, authClient, cfg.ESIClientOptions...)
+	esiService := esi.NewEsiService(esiClient, append(serviceOpts, cfg.ESIServiceOptions...)...)
+
+	zkillClient := zkill.NewZkillClient(cfg.ZKillBaseURL, cfg.HTTPClient, cfg.Cache, cfg.ZKillClientOptions...)
+	zkillService := zkill.NewKillmailService(esiService,
+		zkill.NewZKillService(zkillClient, cfg.ZKillServiceOptions...),
+		append([]zkill.KillmailServiceOption{zkill.WithHydratedPriceBackfill(esiService)}, cfg.KillmailOptions...)...)
+
+	return &Client{
+		HTTP:        cfg.HTTPClient,
+		Cache:       cfg.Cache,
+		ESIClient:   esiClient,
+		ESI:         esiService,
+		Pool:        esi.NewClientPool(esiService),
+		ZKillClient: zkillClient,
+		ZKill:       zkillService,
+		Notifier:    cfg.Notifier,
+		Poller:      monitor.NewPoller(cfg.PollerOptions...),
+	}
+}
+
+// Run runs the poller, and so every monitor registered with it, until ctx
+// is done.
+func (c *Client) Run(ctx context.Context) {
+	c.Poller.Run(ctx)
+}
+
+// Close stops Run once the poller's task in flight has finished and waits
+// for it to return.
+func (c *Client) Close() error {
+	return c.Poller.Close()
+}
+
+// Shutdown is Close, except that the task in flight is cancelled when ctx is
+// done before it finishes.
+func (c *Client) Shutdown(ctx context.Context) error {
+	return c.Poller.Shutdown(ctx)
+}
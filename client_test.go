@@ -0,0 +1,57 @@
+package eveapi_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/guarzo/eveapi"
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/mock"
+	"github.com/guarzo/eveapi/testsupport"
+)
+
+func TestNew_WiresStack(t *testing.T) {
+	var paths []string
+	httpClient := &testsupport.HttpClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			paths = append(paths, req.URL.Host+req.URL.Path)
+			body := `[]`
+			switch {
+			case strings.HasPrefix(req.URL.Path, "/api/kills/"):
+				body = `[{"killmail_id":7,"zkb":{"hash":"abc","totalValue":1000}}]`
+			case req.URL.Path == "/latest/killmails/7/abc/":
+				body = `{"killmail_id":7,"killmail_time":"2024-05-01T12:00:00Z","solar_system_id":30000142,
+					"victim":{"character_id":1,"ship_type_id":587},"attackers":[{"character_id":2,"final_blow":true}]}`
+			}
+			return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(body))}, nil
+		},
+	}
+	client := eveapi.New(eveapi.Config{UserAgent: "test", HTTPClient: httpClient})
+
+	kills, err := client.ZKill.GetKillMailDataForMonth(context.Background(),
+		&model.Params{Characters: []model.CharacterID{1}}, 2024, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(kills) != 1 || kills[0].SolarSystemID != 30000142 || kills[0].TotalValue != 1000 {
+		t.Errorf("expected one hydrated kill, got %+v (requests %v)", kills, paths)
+	}
+	if client.Poller == nil || client.Pool == nil || client.Cache == nil || client.Notifier == nil {
+		t.Error("expected every component to be set")
+	}
+}
+
+func TestNew_Mock(t *testing.T) {
+	client := eveapi.New(eveapi.Config{Mock: true, MockSeed: 7})
+
+	char, err := client.ESI.GetCharacterInfo(context.Background(), mock.FirstCharacterID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if char.Name == "" || char.CorporationID != mock.CorporationOf(mock.FirstCharacterID) {
+		t.Errorf("expected a generated character, got %+v", char)
+	}
+}
@@ -0,0 +1,32 @@
+package common
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// DefaultConcurrency is the number of workers used for internal fan-out when
+// no other limit is configured.
+const DefaultConcurrency = 8
+
+// Batch calls worker for every item, running at most concurrency workers at
+// a time (DefaultConcurrency if concurrency <= 0). The first error cancels
+// the context passed to the remaining workers and is returned once all
+// started workers have finished.
+func Batch[T any](ctx context.Context, items []T, worker func(ctx context.Context, item T) error, concurrency int) error {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(concurrency)
+	for _, item := range items {
+		if gctx.Err() != nil {
+			break
+		}
+		g.Go(func() error {
+			return worker(gctx, item)
+		})
+	}
+	return g.Wait()
+}
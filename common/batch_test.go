@@ -0,0 +1,54 @@
+package common_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/guarzo/eveapi/common"
+)
+
+func TestBatch_LimitsConcurrency(t *testing.T) {
+	items := make([]int, 20)
+	for i := range items {
+		items[i] = i
+	}
+	var running, peak, sum int64
+	err := common.Batch(context.Background(), items, func(ctx context.Context, item int) error {
+		n := atomic.AddInt64(&running, 1)
+		for {
+			p := atomic.LoadInt64(&peak)
+			if n <= p || atomic.CompareAndSwapInt64(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		atomic.AddInt64(&sum, int64(item))
+		atomic.AddInt64(&running, -1)
+		return nil
+	}, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if peak > 3 {
+		t.Errorf("expected at most 3 concurrent workers, saw %d", peak)
+	}
+	if sum != 190 {
+		t.Errorf("expected every item to be processed, sum=%d", sum)
+	}
+}
+
+func TestBatch_ReturnsFirstError(t *testing.T) {
+	boom := errors.New("boom")
+	err := common.Batch(context.Background(), []int{1, 2, 3}, func(ctx context.Context, item int) error {
+		if item == 2 {
+			return boom
+		}
+		return nil
+	}, 1)
+	if !errors.Is(err, boom) {
+		t.Errorf("expected boom, got %v", err)
+	}
+}
@@ -1,6 +1,9 @@
 package common
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // CacheRepository defines a minimal interface for a key/value cache.
 // The values are stored as raw []byte, which you can marshal/unmarshal
@@ -16,3 +19,127 @@ type CacheRepository interface {
 	Set(key string, value []byte, expiration time.Duration)
 	Delete(key string)
 }
+
+// ContextCacheRepository is a CacheRepository for network-backed caches:
+// every call takes a context so deadlines are honored, and failures are
+// reported instead of being indistinguishable from a miss.
+//
+// Clients accept a plain CacheRepository; if the value also implements
+// ContextCacheRepository the context-aware methods are used.
+type ContextCacheRepository interface {
+	GetCtx(ctx context.Context, key string) (value []byte, found bool, err error)
+	SetCtx(ctx context.Context, key string, value []byte, expiration time.Duration) error
+	DeleteCtx(ctx context.Context, key string) error
+}
+
+// AsContextCache returns c as a ContextCacheRepository, wrapping it when it
+// only implements the legacy interface. The wrapper ignores the context and
+// never returns an error.
+func AsContextCache(c CacheRepository) ContextCacheRepository {
+	if cc, ok := c.(ContextCacheRepository); ok {
+		return cc
+	}
+	return legacyCacheAdapter{c}
+}
+
+type legacyCacheAdapter struct {
+	CacheRepository
+}
+
+func (a legacyCacheAdapter) GetCtx(_ context.Context, key string) ([]byte, bool, error) {
+	v, found := a.Get(key)
+	return v, found, nil
+}
+
+func (a legacyCacheAdapter) SetCtx(_ context.Context, key string, value []byte, expiration time.Duration) error {
+	a.Set(key, value, expiration)
+	return nil
+}
+
+func (a legacyCacheAdapter) DeleteCtx(_ context.Context, key string) error {
+	a.Delete(key)
+	return nil
+}
+
+// NewCacheAdapter exposes a ContextCacheRepository through the legacy
+// CacheRepository interface, using context.Background and treating errors as
+// misses. onError, if non-nil, is called with every failure. The result also
+// implements ContextCacheRepository, so context-aware callers still reach the
+// underlying cache directly.
+func NewCacheAdapter(c ContextCacheRepository, onError func(error)) CacheRepository {
+	return &contextCacheAdapter{ContextCacheRepository: c, onError: onError}
+}
+
+type contextCacheAdapter struct {
+	ContextCacheRepository
+	onError func(error)
+}
+
+func (a *contextCacheAdapter) report(err error) {
+	if err != nil && a.onError != nil {
+		a.onError(err)
+	}
+}
+
+func (a *contextCacheAdapter) Get(key string) ([]byte, bool) {
+	v, found, err := a.GetCtx(context.Background(), key)
+	if err != nil {
+		a.report(err)
+		return nil, false
+	}
+	return v, found
+}
+
+func (a *contextCacheAdapter) Set(key string, value []byte, expiration time.Duration) {
+	a.report(a.SetCtx(context.Background(), key, value, expiration))
+}
+
+func (a *contextCacheAdapter) Delete(key string) {
+	a.report(a.DeleteCtx(context.Background(), key))
+}
+
+// BatchCacheRepository is implemented by caches that can read or write many
+// keys in one round trip (e.g. Redis MGET / pipelined SET).
+type BatchCacheRepository interface {
+	MGet(ctx context.Context, keys []string) (map[string][]byte, error)
+	MSet(ctx context.Context, entries map[string][]byte, expiration time.Duration) error
+}
+
+// MGet returns the values found for keys, using a single batch call when c
+// implements BatchCacheRepository and one lookup per key otherwise. Missing
+// keys are absent from the result.
+func MGet(ctx context.Context, c CacheRepository, keys []string) (map[string][]byte, error) {
+	if b, ok := c.(BatchCacheRepository); ok {
+		return b.MGet(ctx, keys)
+	}
+	cc := AsContextCache(c)
+	out := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		v, found, err := cc.GetCtx(ctx, key)
+		if err != nil {
+			return out, err
+		}
+		if found {
+			out[key] = v
+		}
+	}
+	return out, nil
+}
+
+// MSet stores every entry with the same expiration, batching when c
+// implements BatchCacheRepository.
+func MSet(ctx context.Context, c CacheRepository, entries map[string][]byte, expiration time.Duration) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	if b, ok := c.(BatchCacheRepository); ok {
+		return b.MSet(ctx, entries, expiration)
+	}
+	cc := AsContextCache(c)
+	for key, v := range entries {
+		if err := cc.SetCtx(ctx, key, v, expiration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,8 +1,12 @@
 package common_test
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
+
+	"github.com/guarzo/eveapi/common"
 )
 
 type inMemCache struct {
@@ -40,3 +44,59 @@ func TestCacheRepository(t *testing.T) {
 		t.Error("expected 'foo' to be deleted, but still found")
 	}
 }
+
+type failingCache struct{}
+
+func (failingCache) GetCtx(ctx context.Context, key string) ([]byte, bool, error) {
+	return nil, false, errors.New("connection refused")
+}
+func (failingCache) SetCtx(ctx context.Context, key string, value []byte, _ time.Duration) error {
+	return errors.New("connection refused")
+}
+func (failingCache) DeleteCtx(ctx context.Context, key string) error { return nil }
+
+func TestAsContextCache_WrapsLegacy(t *testing.T) {
+	cc := common.AsContextCache(&inMemCache{store: make(map[string][]byte)})
+	if err := cc.SetCtx(context.Background(), "foo", []byte("bar"), time.Hour); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	val, found, err := cc.GetCtx(context.Background(), "foo")
+	if err != nil || !found || string(val) != "bar" {
+		t.Errorf("expected bar, got %q, %v, %v", val, found, err)
+	}
+}
+
+func TestNewCacheAdapter_ReportsErrors(t *testing.T) {
+	var errs int
+	c := common.NewCacheAdapter(failingCache{}, func(error) { errs++ })
+	c.Set("foo", []byte("bar"), time.Hour)
+	if _, found := c.Get("foo"); found {
+		t.Error("expected a failed lookup to be a miss")
+	}
+	if errs != 2 {
+		t.Errorf("expected 2 reported errors, got %d", errs)
+	}
+	if _, ok := common.AsContextCache(c).(failingCache); ok {
+		t.Error("expected adapter to be returned as-is, not unwrapped")
+	}
+	if _, _, err := common.AsContextCache(c).GetCtx(context.Background(), "foo"); err == nil {
+		t.Error("expected context-aware callers to see the underlying error")
+	}
+}
+
+func TestCodecs_RoundTrip(t *testing.T) {
+	type page struct {
+		IDs  []int64
+		Name string
+	}
+	for name, codec := range map[string]common.Codec{"json": common.JSONCodec{}, "gob": common.GobCodec{}} {
+		data, err := codec.Marshal(page{IDs: []int64{1, 2}, Name: "x"})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		var out page
+		if err := codec.Unmarshal(data, &out); err != nil || len(out.IDs) != 2 || out.Name != "x" {
+			t.Errorf("%s: round trip failed: %+v, %v", name, out, err)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// UnknownFieldFunc is told about each field of a response that the model it
+// was decoded into does not declare. source identifies the response (an ESI
+// endpoint or zKillboard URL); field is a dotted path such as
+// "[].victim.new_field".
+type UnknownFieldFunc func(source, field string)
+
+// JSONDecoder decodes API responses into models. The zero value is lenient
+// and silent, like json.Unmarshal. Strict rejects responses carrying fields
+// the model does not declare; OnUnknownField reports them in either mode, so
+// model drift against upstream schema changes can fail CI or be logged in
+// production.
+type JSONDecoder struct {
+	Strict         bool
+	OnUnknownField UnknownFieldFunc
+}
+
+// Decode unmarshals data into v.
+func (d JSONDecoder) Decode(source string, data []byte, v interface{}) error {
+	if d.OnUnknownField != nil {
+		for _, field := range UnknownFields(data, v) {
+			d.OnUnknownField(source, field)
+		}
+	}
+	if !d.Strict {
+		return json.Unmarshal(data, v)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// UnknownFields returns the sorted paths of the object keys in data that
+// decoding into v would ignore. Values of types with their own UnmarshalJSON
+// are not inspected. It returns nil if data is not valid JSON.
+func UnknownFields(data []byte, v interface{}) []string {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	collectUnknown(raw, reflect.TypeOf(v), "", seen)
+	if len(seen) == 0 {
+		return nil
+	}
+	fields := make([]string, 0, len(seen))
+	for f := range seen {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+func collectUnknown(raw interface{}, t reflect.Type, path string, seen map[string]bool) {
+	if t == nil {
+		return
+	}
+	for t.Kind() == reflect.Ptr {
+		if t.Implements(jsonUnmarshalerType) {
+			return
+		}
+		t = t.Elem()
+	}
+	if reflect.PtrTo(t).Implements(jsonUnmarshalerType) {
+		return
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		items, ok := raw.([]interface{})
+		if !ok {
+			return
+		}
+		for _, item := range items {
+			collectUnknown(item, t.Elem(), path+"[]", seen)
+		}
+	case reflect.Map:
+		obj, ok := raw.(map[string]interface{})
+		if !ok {
+			return
+		}
+		for _, val := range obj {
+			collectUnknown(val, t.Elem(), path+".*", seen)
+		}
+	case reflect.Struct:
+		obj, ok := raw.(map[string]interface{})
+		if !ok {
+			return
+		}
+		fields := jsonFields(t)
+		for key, val := range obj {
+			ft, ok := lookupField(fields, key)
+			if !ok {
+				seen[joinPath(path, key)] = true
+				continue
+			}
+			collectUnknown(val, ft, joinPath(path, key), seen)
+		}
+	}
+}
+
+// jsonFields maps the JSON names of a struct's fields, embedded ones
+// included, to their types.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			et := f.Type
+			if et.Kind() == reflect.Ptr {
+				et = et.Elem()
+			}
+			if et.Kind() == reflect.Struct {
+				for k, v := range jsonFields(et) {
+					if _, dup := fields[k]; !dup {
+						fields[k] = v
+					}
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// lookupField matches key the way encoding/json does: exactly, then
+// case-insensitively.
+func lookupField(fields map[string]reflect.Type, key string) (reflect.Type, bool) {
+	if t, ok := fields[key]; ok {
+		return t, true
+	}
+	for name, t := range fields {
+		if strings.EqualFold(name, key) {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
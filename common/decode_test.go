@@ -0,0 +1,59 @@
+package common_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/guarzo/eveapi/common"
+)
+
+type decodeBase struct {
+	ID int64 `json:"id"`
+}
+
+type decodeItem struct {
+	decodeBase
+	Name    string            `json:"name"`
+	When    time.Time         `json:"when"`
+	Tags    map[string]int    `json:"tags"`
+	Nested  *decodeItem       `json:"nested,omitempty"`
+	Ignored string            `json:"-"`
+	Extra   map[string]string `json:"extra"`
+}
+
+func TestUnknownFields(t *testing.T) {
+	data := []byte(`[{"id":1,"NAME":"a","when":{"weird":1},"ignored":"x",
+		"tags":{"a":1},"nested":{"id":2,"color":"red"},"size":3}]`)
+	got := common.UnknownFields(data, &[]decodeItem{})
+	want := []string{"[].ignored", "[].nested.color", "[].size"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got := common.UnknownFields([]byte(`{"id":1}`), &decodeItem{}); got != nil {
+		t.Errorf("expected no unknown fields, got %v", got)
+	}
+}
+
+func TestJSONDecoder(t *testing.T) {
+	data := []byte(`{"id":7,"size":3}`)
+	var reported []string
+	lenient := common.JSONDecoder{OnUnknownField: func(source, field string) {
+		reported = append(reported, source+":"+field)
+	}}
+	var item decodeItem
+	if err := lenient.Decode("items/7", data, &item); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if item.ID != 7 || !reflect.DeepEqual(reported, []string{"items/7:size"}) {
+		t.Errorf("unexpected result %+v, reported %v", item, reported)
+	}
+
+	strict := common.JSONDecoder{Strict: true}
+	if err := strict.Decode("items/7", data, &item); err == nil {
+		t.Error("expected strict decoding to fail on an unknown field")
+	}
+	if err := strict.Decode("items/7", []byte(`{"id":8}`), &item); err != nil || item.ID != 8 {
+		t.Errorf("expected strict decoding of known fields to succeed, got %+v, %v", item, err)
+	}
+}
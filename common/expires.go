@@ -0,0 +1,44 @@
+package common
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// expiresKey is the context key for an expiresRecorder.
+type expiresKey struct{}
+
+// expiresRecorder collects the Expires times reported while a context is in use.
+type expiresRecorder struct {
+	mu      sync.Mutex
+	expires time.Time
+}
+
+// WithExpiresRecorder returns a context on which RecordExpires can report
+// when fetched data goes stale, and a function returning the latest time
+// reported so far (zero if none). Pollers use it to avoid refetching data
+// before the upstream cache would serve anything new.
+func WithExpiresRecorder(ctx context.Context) (context.Context, func() time.Time) {
+	rec := &expiresRecorder{}
+	return context.WithValue(ctx, expiresKey{}, rec), func() time.Time {
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		return rec.expires
+	}
+}
+
+// RecordExpires reports that data fetched under ctx expires at t. It is a
+// no-op if ctx carries no recorder or t is zero. When several responses are
+// reported, the latest expiry wins.
+func RecordExpires(ctx context.Context, t time.Time) {
+	rec, ok := ctx.Value(expiresKey{}).(*expiresRecorder)
+	if !ok || t.IsZero() {
+		return
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if t.After(rec.expires) {
+		rec.expires = t
+	}
+}
@@ -0,0 +1,95 @@
+package common
+
+import (
+	"context"
+	"sync"
+)
+
+// Closer is a background subsystem that can be shut down while it runs.
+// Close stops it from starting new work, waits for work in flight to finish
+// and returns once its Run has returned. Shutdown does the same but gives up
+// waiting when ctx is done, cancelling the work in flight instead.
+type Closer interface {
+	Close() error
+	Shutdown(ctx context.Context) error
+}
+
+// Lifecycle implements Closer for a type whose Run loops call Begin. The
+// zero value is ready to use. After Close or Shutdown, Begin's stop channel
+// is closed at once, so a Lifecycle cannot be restarted.
+type Lifecycle struct {
+	mu       sync.Mutex
+	stop     chan struct{}
+	abort    context.Context
+	abortFn  context.CancelFunc
+	running  sync.WaitGroup
+	stopping bool
+}
+
+func (l *Lifecycle) init() {
+	if l.stop == nil {
+		l.stop = make(chan struct{})
+		l.abort, l.abortFn = context.WithCancel(context.Background())
+	}
+}
+
+// Begin marks the start of a Run. It returns the context for the run's work,
+// which is cancelled when ctx is done or a Shutdown gives up waiting, and a
+// channel closed when Close or Shutdown is called: Run should then finish
+// the work at hand, start no more and return. Run must call done on return.
+func (l *Lifecycle) Begin(ctx context.Context) (work context.Context, stop <-chan struct{}, done func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.init()
+	work, cancel := context.WithCancel(ctx)
+	unlink := context.AfterFunc(l.abort, cancel)
+	if l.stopping {
+		return work, l.stop, func() { unlink(); cancel() }
+	}
+	l.running.Add(1)
+	return work, l.stop, func() {
+		unlink()
+		cancel()
+		l.running.Done()
+	}
+}
+
+// Stopping returns the channel Begin returns as stop.
+func (l *Lifecycle) Stopping() <-chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.init()
+	return l.stop
+}
+
+// Close stops every Run and waits for them to drain.
+func (l *Lifecycle) Close() error {
+	return l.Shutdown(context.Background())
+}
+
+// Shutdown stops every Run and waits for them to drain or for ctx to be
+// done. In the latter case the work in flight is cancelled, Shutdown still
+// waits for the Runs to return and then reports ctx's error.
+func (l *Lifecycle) Shutdown(ctx context.Context) error {
+	l.mu.Lock()
+	l.init()
+	if !l.stopping {
+		l.stopping = true
+		close(l.stop)
+	}
+	l.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		l.running.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		l.abortFn()
+		<-drained
+		return ctx.Err()
+	}
+}
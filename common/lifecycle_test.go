@@ -0,0 +1,84 @@
+package common_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/guarzo/eveapi/common"
+)
+
+// worker runs one unit of work per stop check, like the library's Run loops.
+type worker struct {
+	life     common.Lifecycle
+	started  chan struct{}
+	finished chan error
+	unit     time.Duration
+}
+
+func (w *worker) Run(ctx context.Context) {
+	ctx, stop, done := w.life.Begin(ctx)
+	defer done()
+	close(w.started)
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		select {
+		case <-time.After(w.unit):
+			w.finished <- nil
+		case <-ctx.Done():
+			w.finished <- ctx.Err()
+			return
+		}
+	}
+}
+
+func TestLifecycle_CloseDrains(t *testing.T) {
+	w := &worker{started: make(chan struct{}), finished: make(chan error, 10), unit: 30 * time.Millisecond}
+	go w.Run(context.Background())
+	<-w.started
+
+	if err := w.life.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := <-w.finished; err != nil {
+		t.Errorf("expected the unit in flight to finish, got %v", err)
+	}
+	if len(w.finished) != 0 {
+		t.Error("expected no work to start after Close")
+	}
+	if err := w.life.Close(); err != nil {
+		t.Errorf("expected a second Close to succeed, got %v", err)
+	}
+}
+
+func TestLifecycle_ShutdownDeadlineCancels(t *testing.T) {
+	w := &worker{started: make(chan struct{}), finished: make(chan error, 10), unit: time.Hour}
+	go w.Run(context.Background())
+	<-w.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := w.life.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline error, got %v", err)
+	}
+	if err := <-w.finished; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the unit in flight to be cancelled, got %v", err)
+	}
+}
+
+func TestLifecycle_RunAfterClose(t *testing.T) {
+	var l common.Lifecycle
+	_ = l.Close()
+	_, stop, done := l.Begin(context.Background())
+	defer done()
+	select {
+	case <-stop:
+	default:
+		t.Error("expected a closed lifecycle to stop new runs at once")
+	}
+}
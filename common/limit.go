@@ -0,0 +1,31 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DefaultMaxResponseSize bounds the response bodies clients read. ESI's
+// largest responses (market order pages, killmail pages) are a few MB.
+const DefaultMaxResponseSize int64 = 32 << 20
+
+// ErrResponseTooLarge is returned when a response body exceeds a client's
+// maximum response size.
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// ReadLimited reads r to the end, failing with ErrResponseTooLarge once more
+// than limit bytes have been read. A limit <= 0 reads without bound.
+func ReadLimited(r io.Reader, limit int64) ([]byte, error) {
+	if limit <= 0 {
+		return io.ReadAll(r)
+	}
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%w: more than %d bytes", ErrResponseTooLarge, limit)
+	}
+	return data, nil
+}
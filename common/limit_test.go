@@ -0,0 +1,22 @@
+package common_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/guarzo/eveapi/common"
+)
+
+func TestReadLimited(t *testing.T) {
+	data, err := common.ReadLimited(strings.NewReader("12345"), 5)
+	if err != nil || string(data) != "12345" {
+		t.Errorf("expected a body at the limit to be read, got %q, %v", data, err)
+	}
+	if _, err := common.ReadLimited(strings.NewReader("123456"), 5); !errors.Is(err, common.ErrResponseTooLarge) {
+		t.Errorf("expected ErrResponseTooLarge, got %v", err)
+	}
+	if data, err := common.ReadLimited(strings.NewReader("123456"), 0); err != nil || len(data) != 6 {
+		t.Errorf("expected no bound with limit 0, got %q, %v", data, err)
+	}
+}
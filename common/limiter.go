@@ -0,0 +1,117 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Limiter paces outgoing requests. Clients call Wait before every request.
+type Limiter interface {
+	Wait(ctx context.Context) error
+}
+
+// NewLocalLimiter returns an in-process Limiter allowing limit requests per
+// window, spaced evenly.
+func NewLocalLimiter(limit int, window time.Duration) Limiter {
+	if limit <= 0 {
+		limit = 1
+	}
+	return &localLimiter{interval: window / time.Duration(limit)}
+}
+
+type localLimiter struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+func (l *localLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.next
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next = slot.Add(l.interval)
+	l.mu.Unlock()
+	return sleepCtx(ctx, time.Until(slot))
+}
+
+// RedisEvalFunc runs a Lua script on Redis and returns its result. It keeps
+// this package free of a Redis driver; with go-redis, for example:
+//
+//	func(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+//		return rdb.Eval(ctx, script, keys, args...).Result()
+//	}
+type RedisEvalFunc func(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error)
+
+// redisWindowScript counts a request in the current fixed window and returns
+// 0 if it is allowed, or the milliseconds until the window resets.
+const redisWindowScript = `
+local n = redis.call('INCR', KEYS[1])
+if n == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+if n > tonumber(ARGV[1]) then
+	local ttl = redis.call('PTTL', KEYS[1])
+	if ttl < 0 then
+		redis.call('PEXPIRE', KEYS[1], ARGV[2])
+		ttl = tonumber(ARGV[2])
+	end
+	return ttl
+end
+return 0
+`
+
+// NewRedisLimiter returns a Limiter shared by every process using the same
+// Redis key, allowing limit requests per fixed window. Use one key per
+// upstream budget (e.g. "ratelimit:esi", "ratelimit:zkill") so instances
+// behind one IP do not exceed it together. If Redis fails, Wait returns the
+// error rather than letting the request through unpaced.
+func NewRedisLimiter(eval RedisEvalFunc, key string, limit int, window time.Duration) Limiter {
+	return &redisLimiter{eval: eval, key: key, limit: limit, window: window}
+}
+
+type redisLimiter struct {
+	eval   RedisEvalFunc
+	key    string
+	limit  int
+	window time.Duration
+}
+
+func (l *redisLimiter) Wait(ctx context.Context) error {
+	for {
+		res, err := l.eval(ctx, redisWindowScript, []string{l.key}, l.limit, l.window.Milliseconds())
+		if err != nil {
+			return fmt.Errorf("redis limiter: %w", err)
+		}
+		wait, ok := res.(int64)
+		if !ok {
+			return fmt.Errorf("redis limiter: unexpected script result %T", res)
+		}
+		if wait <= 0 {
+			return nil
+		}
+		if err := sleepCtx(ctx, time.Duration(wait)*time.Millisecond); err != nil {
+			return err
+		}
+	}
+}
+
+// sleepCtx sleeps for d or until ctx is done.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
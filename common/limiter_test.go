@@ -0,0 +1,65 @@
+package common_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/guarzo/eveapi/common"
+)
+
+// fakeRedis emulates the fixed-window limiter script with an in-memory counter.
+type fakeRedis struct {
+	mu      sync.Mutex
+	count   map[string]int
+	expires map[string]time.Time
+}
+
+func (f *fakeRedis) eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key, limit, window := keys[0], args[0].(int), time.Duration(args[1].(int64))*time.Millisecond
+	if time.Now().After(f.expires[key]) {
+		f.count[key] = 0
+	}
+	f.count[key]++
+	if f.count[key] == 1 {
+		f.expires[key] = time.Now().Add(window)
+	}
+	if f.count[key] > limit {
+		return time.Until(f.expires[key]).Milliseconds() + 1, nil
+	}
+	return int64(0), nil
+}
+
+func TestRedisLimiter_SharesBudget(t *testing.T) {
+	redis := &fakeRedis{count: map[string]int{}, expires: map[string]time.Time{}}
+	// two "instances" sharing one key
+	a := common.NewRedisLimiter(redis.eval, "ratelimit:esi", 2, 40*time.Millisecond)
+	b := common.NewRedisLimiter(redis.eval, "ratelimit:esi", 2, 40*time.Millisecond)
+	ctx := context.Background()
+
+	start := time.Now()
+	for _, l := range []common.Limiter{a, b, a} {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("expected the third request to wait for the next window, took %s", elapsed)
+	}
+}
+
+func TestLocalLimiter_SpacesRequests(t *testing.T) {
+	l := common.NewLocalLimiter(100, time.Second)
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 25*time.Millisecond {
+		t.Errorf("expected requests 10ms apart, took %s", elapsed)
+	}
+}
@@ -0,0 +1,84 @@
+package common
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryCache is a thread-safe in-memory CacheRepository and
+// ContextCacheRepository that honors expirations. Expiration <= 0 keeps an
+// entry forever.
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryCache returns an empty MemoryCache. Entries are only dropped when
+// read after expiring or deleted, so it suits small deployments and tests.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]cacheEntry)}
+}
+
+// Get returns the value stored under key, if present and not expired.
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !e.expires.IsZero() && time.Now().After(e.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return e.value, true
+}
+
+// Set stores value under key.
+func (c *MemoryCache) Set(key string, value []byte, expiration time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e := cacheEntry{value: value}
+	if expiration > 0 {
+		e.expires = time.Now().Add(expiration)
+	}
+	c.entries[key] = e
+}
+
+// Delete removes key.
+func (c *MemoryCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+// GetCtx is Get; it never fails.
+func (c *MemoryCache) GetCtx(ctx context.Context, key string) ([]byte, bool, error) {
+	v, ok := c.Get(key)
+	return v, ok, nil
+}
+
+// SetCtx is Set; it never fails.
+func (c *MemoryCache) SetCtx(ctx context.Context, key string, value []byte, expiration time.Duration) error {
+	c.Set(key, value, expiration)
+	return nil
+}
+
+// DeleteCtx is Delete; it never fails.
+func (c *MemoryCache) DeleteCtx(ctx context.Context, key string) error {
+	c.Delete(key)
+	return nil
+}
+
+// Len returns the number of stored entries, expired ones included.
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
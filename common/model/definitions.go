@@ -19,78 +19,78 @@ func JSONUnmarshal(data []byte, out interface{}) error {
 
 // EsiCharacter is an EVE Online character as returned by ESI.
 type EsiCharacter struct {
-	Birthday       time.Time `json:"birthday"`
-	BloodlineID    int       `json:"bloodline_id"`
-	CorporationID  int       `json:"corporation_id"`
-	Description    string    `json:"description"`
-	Gender         string    `json:"gender"`
-	Name           string    `json:"name"`
-	RaceID         int       `json:"race_id"`
-	SecurityStatus float64   `json:"security_status"`
+	Birthday       time.Time     `json:"birthday"`
+	BloodlineID    int64         `json:"bloodline_id"`
+	CorporationID  CorporationID `json:"corporation_id"`
+	Description    string        `json:"description"`
+	Gender         string        `json:"gender"`
+	Name           string        `json:"name"`
+	RaceID         int64         `json:"race_id"`
+	SecurityStatus float64       `json:"security_status"`
 }
 
 // EsiAlliance represents an EVE Online alliance from ESI.
 type EsiAlliance struct {
-	CreatorCorporationID  int       `json:"creator_corporation_id"`
-	CreatorID             int       `json:"creator_id"`
-	DateFounded           time.Time `json:"date_founded"`
-	ExecutorCorporationID int       `json:"executor_corporation_id"`
-	Name                  string    `json:"name"`
-	Ticker                string    `json:"ticker"`
+	CreatorCorporationID  CorporationID `json:"creator_corporation_id"`
+	CreatorID             CharacterID   `json:"creator_id"`
+	DateFounded           time.Time     `json:"date_founded"`
+	ExecutorCorporationID CorporationID `json:"executor_corporation_id"`
+	Name                  string        `json:"name"`
+	Ticker                string        `json:"ticker"`
 }
 
 // EsiCorporation is detailed corporation info from ESI.
 type EsiCorporation struct {
-	AllianceID    int       `json:"alliance_id"`
-	CeoID         int       `json:"ceo_id"`
-	CreatorID     int       `json:"creator_id"`
-	DateFounded   time.Time `json:"date_founded"`
-	Description   string    `json:"description"`
-	HomeStationID int       `json:"home_station_id"`
-	MemberCount   int       `json:"member_count"`
-	Name          string    `json:"name"`
-	Shares        int       `json:"shares"`
-	TaxRate       float64   `json:"tax_rate"`
-	Ticker        string    `json:"ticker"`
-	URL           string    `json:"url"`
+	AllianceID    AllianceID  `json:"alliance_id"`
+	CeoID         CharacterID `json:"ceo_id"`
+	CreatorID     CharacterID `json:"creator_id"`
+	DateFounded   time.Time   `json:"date_founded"`
+	Description   string      `json:"description"`
+	HomeStationID int64       `json:"home_station_id"`
+	MemberCount   int         `json:"member_count"`
+	Name          string      `json:"name"`
+	Shares        int         `json:"shares"`
+	TaxRate       float64     `json:"tax_rate"`
+	Ticker        string      `json:"ticker"`
+	URL           string      `json:"url"`
 }
 
 // FailedCharacters tracks CharacterIDs that failed (404, etc.).
 type FailedCharacters struct {
-	CharacterIDs map[int]bool `json:"character_ids"`
+	CharacterIDs map[CharacterID]bool `json:"character_ids"`
 }
 
 // CharacterResponse is an ESI response shape for a single character.
 type CharacterResponse struct {
-	AllianceID     int32     `json:"alliance_id,omitempty"`
-	Birthday       time.Time `json:"birthday"`
-	BloodlineID    int32     `json:"bloodline_id"`
-	CorporationID  int32     `json:"corporation_id"`
-	Description    string    `json:"description,omitempty"`
-	FactionID      int32     `json:"faction_id,omitempty"`
-	Gender         string    `json:"gender"`
-	Name           string    `json:"name"`
-	RaceID         int32     `json:"race_id"`
-	SecurityStatus float64   `json:"security_status,omitempty"`
-	Title          string    `json:"title,omitempty"`
+	AllianceID     AllianceID    `json:"alliance_id,omitempty"`
+	Birthday       time.Time     `json:"birthday"`
+	BloodlineID    int64         `json:"bloodline_id"`
+	CorporationID  CorporationID `json:"corporation_id"`
+	Description    string        `json:"description,omitempty"`
+	FactionID      int64         `json:"faction_id,omitempty"`
+	Gender         string        `json:"gender"`
+	Name           string        `json:"name"`
+	RaceID         int64         `json:"race_id"`
+	SecurityStatus float64       `json:"security_status,omitempty"`
+	Title          string        `json:"title,omitempty"`
 }
 
 // EsiCorporationInfo is another shape you had for corporations.
 type EsiCorporationInfo struct {
-	AllianceID    *int32  `json:"alliance_id,omitempty"`
-	CEOId         int32   `json:"ceo_id"`
-	CreatorID     int32   `json:"creator_id"`
-	DateFounded   *string `json:"date_founded,omitempty"`
-	Description   *string `json:"description,omitempty"`
-	FactionID     *int32  `json:"faction_id,omitempty"`
-	HomeStationID *int32  `json:"home_station_id,omitempty"`
-	MemberCount   int32   `json:"member_count"`
-	Name          string  `json:"name"`
-	Shares        *int64  `json:"shares,omitempty"`
-	TaxRate       float64 `json:"tax_rate"`
-	Ticker        string  `json:"ticker"`
-	URL           *string `json:"url,omitempty"`
-	WarEligible   *bool   `json:"war_eligible,omitempty"`
+	AllianceID    *AllianceID `json:"alliance_id,omitempty"`
+	CEOId         CharacterID `json:"ceo_id"`
+	CreatorID     CharacterID `json:"creator_id"`
+	DateFounded   *string     `json:"date_founded,omitempty"`
+	Description   *string     `json:"description,omitempty"`
+	FactionID     *int64      `json:"faction_id,omitempty"`
+	HomeStationID *int64      `json:"home_station_id,omitempty"`
+	MemberCount   int32       `json:"member_count"`
+	Name          string      `json:"name"`
+	Shares        *int64      `json:"shares,omitempty"`
+	TaxRate       float64     `json:"tax_rate"`
+	Ticker        string      `json:"ticker"`
+	URL           *string     `json:"url,omitempty"`
+	WarEligible   *bool       `json:"war_eligible,omitempty"`
 }
 
 // EsiCharacterPortrait holds various portrait sizes for a character.
@@ -107,45 +107,50 @@ type EsiCharacterPortrait struct {
 
 // EsiKillMail is an ESI structure for killmail details.
 type EsiKillMail struct {
-	KillMailID    int        `json:"killmail_id"`
+	KillMailID    int64      `json:"killmail_id"`
 	KillMailTime  time.Time  `json:"killmail_time"`
-	SolarSystemID int        `json:"solar_system_id"`
+	SolarSystemID SystemID   `json:"solar_system_id"`
 	Victim        Victim     `json:"victim"`
 	Attackers     []Attacker `json:"attackers"`
 }
 
 // Attacker is an ESI shape for a killmail attacker.
 type Attacker struct {
-	AllianceID     int     `json:"alliance_id"`
-	CharacterID    int     `json:"character_id"`
-	CorporationID  int     `json:"corporation_id"`
-	DamageDone     int     `json:"damage_done"`
-	FinalBlow      bool    `json:"final_blow"`
-	SecurityStatus float64 `json:"security_status"`
-	ShipTypeID     int     `json:"ship_type_id"`
-	WeaponTypeID   int     `json:"weapon_type_id"`
+	AllianceID     AllianceID    `json:"alliance_id"`
+	CharacterID    CharacterID   `json:"character_id"`
+	CorporationID  CorporationID `json:"corporation_id"`
+	DamageDone     int           `json:"damage_done"`
+	FactionID      int64         `json:"faction_id,omitempty"`
+	FinalBlow      bool          `json:"final_blow"`
+	SecurityStatus float64       `json:"security_status"`
+	ShipTypeID     TypeID        `json:"ship_type_id"`
+	WeaponTypeID   TypeID        `json:"weapon_type_id"`
+
+	// Enriched names, filled by killmail.EnrichKillMails:
+	ShipName   string `json:"ship_name,omitempty"`
+	WeaponName string `json:"weapon_name,omitempty"`
 }
 
 // Victim is an ESI shape for a killmail victim.
 // Items is now typed as []VictimItem (instead of []interface{}).
 type Victim struct {
-	CharacterID   int          `json:"character_id"`
-	CorporationID int          `json:"corporation_id"`
-	AllianceID    int          `json:"alliance_id,omitempty"`
-	DamageTaken   int          `json:"damage_taken"`
-	Items         []VictimItem `json:"items"` // typed sub-items
+	CharacterID   CharacterID   `json:"character_id"`
+	CorporationID CorporationID `json:"corporation_id"`
+	AllianceID    AllianceID    `json:"alliance_id,omitempty"`
+	DamageTaken   int           `json:"damage_taken"`
+	Items         []VictimItem  `json:"items"` // typed sub-items
 	Position      struct {
 		X float64 `json:"x"`
 		Y float64 `json:"y"`
 		Z float64 `json:"z"`
 	} `json:"position"`
-	ShipTypeID int `json:"ship_type_id"`
+	ShipTypeID TypeID `json:"ship_type_id"`
 }
 
 // VictimItem is typed so we can do recursion.
 type VictimItem struct {
 	Flag              int          `json:"flag"`
-	ItemTypeID        int          `json:"item_type_id"`
+	ItemTypeID        TypeID       `json:"item_type_id"`
 	QuantityDestroyed int64        `json:"quantity_destroyed,omitempty"`
 	QuantityDropped   int64        `json:"quantity_dropped,omitempty"`
 	Singleton         int          `json:"singleton,omitempty"`
@@ -158,7 +163,7 @@ type VictimItem struct {
 type FlattenedKillMail struct {
 	KillMailID    int64     `json:"killmail_id"`
 	KillMailTime  time.Time `json:"killmail_time"`
-	SolarSystemID int       `json:"solar_system_id"`
+	SolarSystemID SystemID  `json:"solar_system_id"`
 	Victim        Victim    `json:"victim"`
 	Attackers     []Attacker
 
@@ -173,12 +178,16 @@ type FlattenedKillMail struct {
 	NPC            bool    `json:"npc"`
 	Solo           bool    `json:"solo"`
 	Awox           bool    `json:"awox"`
+
+	// Enriched names, filled by killmail.EnrichKillMails:
+	VictimShipName  string `json:"victimShipName,omitempty"`
+	SolarSystemName string `json:"solarSystemName,omitempty"`
 }
 
 // ConvertToFlattened merges an EsiKillMail with a ZkillMail into a FlattenedKillMail.
 func ConvertToFlattened(esi EsiKillMail, zkill ZkillMail) FlattenedKillMail {
 	return FlattenedKillMail{
-		KillMailID:     int64(esi.KillMailID),
+		KillMailID:     esi.KillMailID,
 		KillMailTime:   esi.KillMailTime,
 		SolarSystemID:  esi.SolarSystemID,
 		Victim:         esi.Victim,
@@ -199,12 +208,48 @@ func ConvertToFlattened(esi EsiKillMail, zkill ZkillMail) FlattenedKillMail {
 // ZkillMailFeedResponse is for zKill’s streaming feed
 type ZkillMailFeedResponse struct {
 	KillmailID    int64      `json:"killmail_id"`
-	SolarSystemID int        `json:"solar_system_id"`
+	KillMailTime  time.Time  `json:"killmail_time"`
+	SolarSystemID SystemID   `json:"solar_system_id"`
 	Victim        Victim     `json:"victim"`
 	Attackers     []Attacker `json:"attackers"`
 	ZKB           ZKB        `json:"zkb"`
 }
 
+// EsiKillMail returns the ESI part of a feed kill.
+func (f ZkillMailFeedResponse) EsiKillMail() EsiKillMail {
+	return EsiKillMail{
+		KillMailID:    f.KillmailID,
+		KillMailTime:  f.KillMailTime,
+		SolarSystemID: f.SolarSystemID,
+		Victim:        f.Victim,
+		Attackers:     f.Attackers,
+	}
+}
+
+// ZkillMail returns the zKill part of a feed kill.
+func (f ZkillMailFeedResponse) ZkillMail() ZkillMail {
+	return ZkillMail{KillMailID: f.KillmailID, ZKB: f.ZKB}
+}
+
+// NewFeedResponse combines an ESI killmail and its zKill entry into the feed
+// shape.
+func NewFeedResponse(esi EsiKillMail, zkill ZkillMail) ZkillMailFeedResponse {
+	return ZkillMailFeedResponse{
+		KillmailID:    esi.KillMailID,
+		KillMailTime:  esi.KillMailTime,
+		SolarSystemID: esi.SolarSystemID,
+		Victim:        esi.Victim,
+		Attackers:     esi.Attackers,
+		ZKB:           zkill.ZKB,
+	}
+}
+
+// ConvertFeedToFlattened flattens a feed kill. The result is identical to
+// ConvertToFlattened on the ESI killmail and zKill entry of the same kill.
+func ConvertFeedToFlattened(f ZkillMailFeedResponse) FlattenedKillMail {
+	return ConvertToFlattened(f.EsiKillMail(), f.ZkillMail())
+}
+
 // ----------------------------------------------------------------------
 // ZKill-Specific data
 // ----------------------------------------------------------------------
@@ -230,29 +275,101 @@ type ZKB struct {
 // Corporation, Alliance, Character, etc.
 // ----------------------------------------------------------------------
 type Corporation struct {
-	AllianceID    *int32  `json:"alliance_id,omitempty"`
-	CEOId         int32   `json:"ceo_id"`
-	CreatorID     int32   `json:"creator_id"`
-	DateFounded   *string `json:"date_founded,omitempty"`
-	Description   *string `json:"description,omitempty"`
-	FactionID     *int32  `json:"faction_id,omitempty"`
-	HomeStationID *int32  `json:"home_station_id,omitempty"`
-	MemberCount   int32   `json:"member_count"`
-	Name          string  `json:"name"`
-	Shares        *int64  `json:"shares,omitempty"`
-	TaxRate       float64 `json:"tax_rate"`
-	Ticker        string  `json:"ticker"`
-	URL           *string `json:"url,omitempty"`
-	WarEligible   *bool   `json:"war_eligible,omitempty"`
+	AllianceID    *AllianceID `json:"alliance_id,omitempty"`
+	CEOId         CharacterID `json:"ceo_id"`
+	CreatorID     CharacterID `json:"creator_id"`
+	DateFounded   *string     `json:"date_founded,omitempty"`
+	Description   *string     `json:"description,omitempty"`
+	FactionID     *int64      `json:"faction_id,omitempty"`
+	HomeStationID *int64      `json:"home_station_id,omitempty"`
+	MemberCount   int32       `json:"member_count"`
+	Name          string      `json:"name"`
+	Shares        *int64      `json:"shares,omitempty"`
+	TaxRate       float64     `json:"tax_rate"`
+	Ticker        string      `json:"ticker"`
+	URL           *string     `json:"url,omitempty"`
+	WarEligible   *bool       `json:"war_eligible,omitempty"`
 }
 
 type Alliance struct {
-	CreatorCorporationID  int       `json:"creator_corporation_id"`
-	CreatorID             int       `json:"creator_id"`
-	DateFounded           time.Time `json:"date_founded"`
-	ExecutorCorporationID int       `json:"executor_corporation_id"`
-	Name                  string    `json:"name"`
-	Ticker                string    `json:"ticker"`
+	CreatorCorporationID  CorporationID `json:"creator_corporation_id"`
+	CreatorID             CharacterID   `json:"creator_id"`
+	DateFounded           time.Time     `json:"date_founded"`
+	ExecutorCorporationID CorporationID `json:"executor_corporation_id"`
+	Name                  string        `json:"name"`
+	Ticker                string        `json:"ticker"`
+}
+
+// UniverseName is an entry from ESI /universe/names/.
+type UniverseName struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	Category string `json:"category"`
+}
+
+// UniverseIDs groups resolved names by category, as ESI /universe/ids/
+// answers.
+type UniverseIDs struct {
+	Agents         []UniverseName `json:"agents,omitempty"`
+	Alliances      []UniverseName `json:"alliances,omitempty"`
+	Characters     []UniverseName `json:"characters,omitempty"`
+	Constellations []UniverseName `json:"constellations,omitempty"`
+	Corporations   []UniverseName `json:"corporations,omitempty"`
+	Factions       []UniverseName `json:"factions,omitempty"`
+	InventoryTypes []UniverseName `json:"inventory_types,omitempty"`
+	Regions        []UniverseName `json:"regions,omitempty"`
+	Stations       []UniverseName `json:"stations,omitempty"`
+	Systems        []UniverseName `json:"systems,omitempty"`
+}
+
+// universeGroup pairs a UniverseIDs group with the /universe/names/
+// category of its entries.
+type universeGroup struct {
+	category string
+	names    *[]UniverseName
+}
+
+func (u *UniverseIDs) groups() []universeGroup {
+	return []universeGroup{
+		{"agent", &u.Agents},
+		{"alliance", &u.Alliances},
+		{"character", &u.Characters},
+		{"constellation", &u.Constellations},
+		{"corporation", &u.Corporations},
+		{"faction", &u.Factions},
+		{"inventory_type", &u.InventoryTypes},
+		{"region", &u.Regions},
+		{"station", &u.Stations},
+		{"solar_system", &u.Systems},
+	}
+}
+
+// GroupUniverseNames sorts names, e.g. from ResolveNames, into UniverseIDs
+// by their Category. Names of other categories are dropped.
+func GroupUniverseNames(names []UniverseName) *UniverseIDs {
+	u := &UniverseIDs{}
+	groups := make(map[string]*[]UniverseName)
+	for _, g := range u.groups() {
+		groups[g.category] = g.names
+	}
+	for _, n := range names {
+		if g, ok := groups[n.Category]; ok {
+			*g = append(*g, n)
+		}
+	}
+	return u
+}
+
+// All returns every entry, with Category set from its group.
+func (u *UniverseIDs) All() []UniverseName {
+	var out []UniverseName
+	for _, g := range u.groups() {
+		for _, n := range *g.names {
+			n.Category = g.category
+			out = append(out, n)
+		}
+	}
+	return out
 }
 
 // ----------------------------------------------------------------------
@@ -261,32 +378,48 @@ type Alliance struct {
 
 // ESIData might store loaded alliance/corp/character info in memory.
 type ESIData struct {
-	AllianceInfos    map[int]EsiAlliance
-	CharacterInfos   map[int]EsiCharacter
-	CorporationInfos map[int]EsiCorporation
+	AllianceInfos    map[AllianceID]EsiAlliance
+	CharacterInfos   map[CharacterID]EsiCharacter
+	CorporationInfos map[CorporationID]EsiCorporation
 }
 
 type Params struct {
-	Corporations []int
-	Alliances    []int
-	Characters   []int
+	Corporations []CorporationID
+	Alliances    []AllianceID
+	Characters   []CharacterID
 	Year         int
 	EsiData      *ESIData
 	ChangedIDs   bool
 	NewIDs       *Ids
+
+	// zKill flag filters, applied before any ESI hydration:
+	ExcludeNPC  bool // drop kills zKill marks as NPC
+	SoloOnly    bool // keep only solo kills
+	ExcludeAwox bool // drop awox (friendly-fire) kills
 }
 
 type Ids struct {
-	AllianceIDs    []int `json:"alliance_ids"`
-	CharacterIDs   []int `json:"character_ids"`
-	CorporationIDs []int `json:"corporation_ids"`
+	AllianceIDs    []AllianceID    `json:"alliance_ids"`
+	CharacterIDs   []CharacterID   `json:"character_ids"`
+	CorporationIDs []CorporationID `json:"corporation_ids"`
 }
 
 type ChartData struct {
 	KillMails []FlattenedKillMail
 	ESIData
-	TrackedCharacters []int
+	TrackedCharacters []CharacterID
 	LookupFunc        func(int) string
+	// Location is the time zone charts bucket by (hour of day, day, month).
+	// Nil means UTC, i.e. EVE time.
+	Location *time.Location
+}
+
+// LocalTime converts t into the chart's bucketing time zone.
+func (cd *ChartData) LocalTime(t time.Time) time.Time {
+	if cd.Location == nil {
+		return t.UTC()
+	}
+	return t.In(cd.Location)
 }
 
 // Chart is a single chart definition with data prep logic, for front-end rendering.
@@ -352,10 +485,10 @@ func DecodeState(stateStr string) (AuthState, error) {
 
 type ConfigCharacter struct {
 	User
-	Location       int64   `json:"Location"`
-	HomeLocation   int64   `json:"HomeLocation"`
-	CorporationID  int64   `json:"CorporationID"`
-	CloneLocations []int64 `json:"CloneLocations"`
+	Location       int64         `json:"Location"`
+	HomeLocation   int64         `json:"HomeLocation"`
+	CorporationID  CorporationID `json:"CorporationID"`
+	CloneLocations []int64       `json:"CloneLocations"`
 	CharacterRoles
 	StashList []Stash `json:"StashList"`
 }
@@ -373,42 +506,111 @@ type CharacterRoles struct {
 }
 
 type CharacterLocation struct {
-	SolarSystemID int64 `json:"solar_system_id"`
-	StructureID   int64 `json:"structure_id"`
+	SolarSystemID SystemID `json:"solar_system_id"`
+	StructureID   int64    `json:"structure_id"`
+}
+
+// CharacterShip is the ship a character is currently boarded.
+type CharacterShip struct {
+	ShipItemID int64  `json:"ship_item_id"`
+	ShipName   string `json:"ship_name"`
+	ShipTypeID TypeID `json:"ship_type_id"`
+}
+
+// CharacterOnline is the answer of ESI /characters/{character_id}/online/.
+type CharacterOnline struct {
+	Online     bool       `json:"online"`
+	LastLogin  *time.Time `json:"last_login,omitempty"`
+	LastLogout *time.Time `json:"last_logout,omitempty"`
+	Logins     int        `json:"logins,omitempty"`
+}
+
+// JumpFatigue is the answer of ESI /characters/{character_id}/fatigue/.
+// Characters that never jumped have no dates.
+type JumpFatigue struct {
+	JumpFatigueExpireDate *time.Time `json:"jump_fatigue_expire_date,omitempty"`
+	LastJumpDate          *time.Time `json:"last_jump_date,omitempty"`
+	LastUpdateDate        *time.Time `json:"last_update_date,omitempty"`
+}
+
+// Remaining returns how long the fatigue lasts after now; 0 once it expired.
+func (f *JumpFatigue) Remaining(now time.Time) time.Duration {
+	if f.JumpFatigueExpireDate == nil || !now.Before(*f.JumpFatigueExpireDate) {
+		return 0
+	}
+	return f.JumpFatigueExpireDate.Sub(now)
 }
 
+// CloneLocation is the answer of ESI /characters/{character_id}/clones/:
+// the home station and every jump clone with its implants.
 type CloneLocation struct {
 	HomeLocation struct {
 		LocationID   int64  `json:"location_id"`
 		LocationType string `json:"location_type"`
 	} `json:"home_location"`
-	JumpClones []struct {
-		Implants     []int  `json:"implants"`
-		JumpCloneID  int64  `json:"jump_clone_id"`
-		LocationID   int64  `json:"location_id"`
-		LocationType string `json:"location_type"`
-	} `json:"jump_clones"`
+	JumpClones            []JumpClone `json:"jump_clones"`
+	LastCloneJumpDate     time.Time   `json:"last_clone_jump_date,omitempty"`
+	LastStationChangeDate time.Time   `json:"last_station_change_date,omitempty"`
+}
+
+// JumpClone is one entry of CloneLocation.JumpClones.
+type JumpClone struct {
+	Implants     []TypeID `json:"implants"`
+	JumpCloneID  int64    `json:"jump_clone_id"`
+	LocationID   int64    `json:"location_id"`
+	LocationType string   `json:"location_type"` // "station" or "structure"
+	Name         string   `json:"name,omitempty"`
+}
+
+// Position is a point in space, in meters.
+type Position struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	Z float64 `json:"z"`
+}
+
+// SolarSystem is ESI's /universe/systems/{id}/ shape.
+type SolarSystem struct {
+	SystemID        SystemID `json:"system_id"`
+	Name            string   `json:"name"`
+	ConstellationID int64    `json:"constellation_id"`
+	SecurityStatus  float64  `json:"security_status"`
+	SecurityClass   string   `json:"security_class,omitempty"`
+	StarID          int64    `json:"star_id,omitempty"`
+	Position        Position `json:"position"`
+	Stargates       []int64  `json:"stargates,omitempty"`
+	Stations        []int64  `json:"stations,omitempty"`
 }
 
 type Station struct {
-	SystemID int64  `json:"system_id"`
-	ID       int64  `json:"station_id"`
-	Name     string `json:"station_name"`
+	SystemID SystemID `json:"system_id"`
+	ID       int64    `json:"station_id"`
+	Name     string   `json:"station_name"`
 }
 
 type Structure struct {
-	Name     string `json:"name"`
-	OwnerID  int64  `json:"owner_id"`
-	SystemID int64  `json:"solar_system_id"`
-	TypeID   int64  `json:"type_id"`
+	Name     string   `json:"name"`
+	OwnerID  int64    `json:"owner_id"`
+	SystemID SystemID `json:"solar_system_id"`
+	TypeID   TypeID   `json:"type_id"`
 }
 
 type Asset struct {
-	TypeID       int64  `json:"type_id"`
-	Quantity     int    `json:"quantity"`
-	LocationFlag string `json:"location_flag"`
-	LocationType string `json:"location_type"`
-	LocationID   int64  `json:"location_id"`
+	ItemID          int64  `json:"item_id"`
+	TypeID          TypeID `json:"type_id"`
+	Quantity        int    `json:"quantity"`
+	LocationFlag    string `json:"location_flag"`
+	LocationType    string `json:"location_type"`
+	LocationID      int64  `json:"location_id"`
+	IsSingleton     bool   `json:"is_singleton"`
+	IsBlueprintCopy bool   `json:"is_blueprint_copy,omitempty"`
+}
+
+// AssetLocation is the position of an asset item in space, from ESI's
+// /assets/locations/ endpoints.
+type AssetLocation struct {
+	ItemID   int64    `json:"item_id"`
+	Position Position `json:"position"`
 }
 
 type Item struct {
@@ -418,13 +620,96 @@ type Item struct {
 }
 
 type LocationInventory struct {
-	CharacterID int64          `json:"Id"`
+	CharacterID CharacterID    `json:"Id"`
 	LocFlag     string         `json:"LocFlag"`
 	LocType     string         `json:"LocType"`
-	LocID       int            `json:"LocID"`
+	LocID       int64          `json:"LocID"`
 	Items       map[string]int `json:"Items"`
 }
 
+// LocationAssets is the complete inventory of one location, with quantities
+// summed per type ID. Assets keeps the raw entries for callers needing flags.
+type LocationAssets struct {
+	OwnerID int64         `json:"owner_id"`
+	LocID   int64         `json:"location_id"`
+	LocType string        `json:"location_type"`
+	Items   map[int64]int `json:"items"`
+	Assets  []Asset       `json:"assets"`
+}
+
+// AssetValuation is the ISK value of a set of inventories.
+type AssetValuation struct {
+	Total      float64           `json:"total"`
+	ByLocation map[int64]float64 `json:"by_location"`
+	ByType     map[int64]float64 `json:"by_type"`
+	Unpriced   []int64           `json:"unpriced,omitempty"`
+}
+
+// MarketPrice is an entry from ESI /markets/prices/.
+type MarketPrice struct {
+	TypeID        TypeID  `json:"type_id"`
+	AveragePrice  float64 `json:"average_price,omitempty"`
+	AdjustedPrice float64 `json:"adjusted_price,omitempty"`
+}
+
+// MarketOrder is an entry from ESI /markets/{region_id}/orders/ or
+// /markets/structures/{structure_id}/.
+type MarketOrder struct {
+	OrderID      int64     `json:"order_id"`
+	TypeID       TypeID    `json:"type_id"`
+	LocationID   int64     `json:"location_id"`
+	SystemID     SystemID  `json:"system_id,omitempty"`
+	IsBuyOrder   bool      `json:"is_buy_order"`
+	Price        float64   `json:"price"`
+	VolumeRemain int64     `json:"volume_remain"`
+	VolumeTotal  int64     `json:"volume_total"`
+	MinVolume    int64     `json:"min_volume"`
+	Range        string    `json:"range"`
+	Issued       time.Time `json:"issued"`
+	Duration     int       `json:"duration"`
+}
+
+// OwnedMarketOrder is an entry from ESI's character and corporation
+// /orders/ and /orders/history/ endpoints.
+type OwnedMarketOrder struct {
+	MarketOrder
+	RegionID      int64   `json:"region_id"`
+	Escrow        float64 `json:"escrow,omitempty"`
+	IsCorporation bool    `json:"is_corporation,omitempty"`
+	// State is "cancelled" or "expired" for orders from the history; open
+	// orders have none.
+	State string `json:"state,omitempty"`
+	// IssuedBy and WalletDivision are only set on corporation orders.
+	IssuedBy       int64 `json:"issued_by,omitempty"`
+	WalletDivision int   `json:"wallet_division,omitempty"`
+}
+
+// MarketHistoryDay is one day from ESI /markets/{region_id}/history/.
+// Date is formatted YYYY-MM-DD.
+type MarketHistoryDay struct {
+	Date       string  `json:"date"`
+	Average    float64 `json:"average"`
+	Highest    float64 `json:"highest"`
+	Lowest     float64 `json:"lowest"`
+	OrderCount int64   `json:"order_count"`
+	Volume     int64   `json:"volume"`
+}
+
+// PriceTable maps type ID to a unit price, preferring the average price and
+// falling back to the adjusted price when no average is published.
+func PriceTable(prices []MarketPrice) map[int64]float64 {
+	m := make(map[int64]float64, len(prices))
+	for _, p := range prices {
+		switch {
+		case p.AveragePrice > 0:
+			m[p.TypeID] = p.AveragePrice
+		case p.AdjustedPrice > 0:
+			m[p.TypeID] = p.AdjustedPrice
+		}
+	}
+	return m
+}
+
 type Stash struct {
 	SystemId   int64  `json:"system_id"`
 	SystemName string `json:"system_name"`
@@ -437,17 +722,401 @@ type Namer interface {
 }
 
 type User struct {
-	CharacterID   int64  `json:"CharacterID"`
-	CharacterName string `json:"CharacterName"`
+	CharacterID   CharacterID `json:"CharacterID"`
+	CharacterName string      `json:"CharacterName"`
 }
 
 type Character struct {
-	Birthday       time.Time `json:"birthday"`
-	BloodlineID    int       `json:"bloodline_id"`
-	CorporationID  int       `json:"corporation_id"`
-	Description    string    `json:"description"`
-	Gender         string    `json:"gender"`
-	Name           string    `json:"name"`
-	RaceID         int       `json:"race_id"`
-	SecurityStatus float64   `json:"security_status"`
+	Birthday       time.Time     `json:"birthday"`
+	BloodlineID    int64         `json:"bloodline_id"`
+	CorporationID  CorporationID `json:"corporation_id"`
+	Description    string        `json:"description"`
+	Gender         string        `json:"gender"`
+	Name           string        `json:"name"`
+	RaceID         int64         `json:"race_id"`
+	SecurityStatus float64       `json:"security_status"`
+}
+
+// ----------------------------------------------------------------------
+// Wars
+// ----------------------------------------------------------------------
+
+// WarParty is the aggressor or defender of a war; exactly one of
+// AllianceID and CorporationID is set.
+type WarParty struct {
+	AllianceID    AllianceID    `json:"alliance_id,omitempty"`
+	CorporationID CorporationID `json:"corporation_id,omitempty"`
+	IskDestroyed  float64       `json:"isk_destroyed"`
+	ShipsKilled   int           `json:"ships_killed"`
+}
+
+// WarAlly is a third party fighting on the defender's side.
+type WarAlly struct {
+	AllianceID    AllianceID    `json:"alliance_id,omitempty"`
+	CorporationID CorporationID `json:"corporation_id,omitempty"`
+}
+
+// War is ESI's /wars/{war_id}/ shape.
+type War struct {
+	ID            int64      `json:"id"`
+	Aggressor     WarParty   `json:"aggressor"`
+	Defender      WarParty   `json:"defender"`
+	Allies        []WarAlly  `json:"allies,omitempty"`
+	Declared      time.Time  `json:"declared"`
+	Started       *time.Time `json:"started,omitempty"`
+	Finished      *time.Time `json:"finished,omitempty"`
+	Retracted     *time.Time `json:"retracted,omitempty"`
+	Mutual        bool       `json:"mutual"`
+	OpenForAllies bool       `json:"open_for_allies"`
+}
+
+// Involves reports whether the corporation or alliance id is a party to the war.
+func (w *War) Involves(id int64) bool {
+	if id == 0 {
+		return false
+	}
+	if w.Aggressor.AllianceID == id || w.Aggressor.CorporationID == id ||
+		w.Defender.AllianceID == id || w.Defender.CorporationID == id {
+		return true
+	}
+	for _, a := range w.Allies {
+		if a.AllianceID == id || a.CorporationID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Active reports whether the war has not finished as of now.
+func (w *War) Active(now time.Time) bool {
+	return w.Finished == nil || w.Finished.After(now)
+}
+
+// ----------------------------------------------------------------------
+// Corporation structures
+// ----------------------------------------------------------------------
+
+// Structure states reported by /corporations/{id}/structures/ that mean the
+// structure has been reinforced.
+const (
+	StructureStateArmorReinforce = "armor_reinforce"
+	StructureStateHullReinforce  = "hull_reinforce"
+)
+
+// CorporationStructure is one entry of ESI's /corporations/{id}/structures/.
+type CorporationStructure struct {
+	StructureID     int64      `json:"structure_id"`
+	TypeID          TypeID     `json:"type_id"`
+	SystemID        SystemID   `json:"system_id"`
+	Name            string     `json:"name,omitempty"`
+	FuelExpires     *time.Time `json:"fuel_expires,omitempty"`
+	State           string     `json:"state"`
+	StateTimerStart *time.Time `json:"state_timer_start,omitempty"`
+	StateTimerEnd   *time.Time `json:"state_timer_end,omitempty"`
+	ReinforceHour   *int       `json:"reinforce_hour,omitempty"`
+}
+
+// Reinforced reports whether the structure is in armor or hull reinforcement.
+func (s *CorporationStructure) Reinforced() bool {
+	return s.State == StructureStateArmorReinforce || s.State == StructureStateHullReinforce
+}
+
+// ----------------------------------------------------------------------
+// Character notifications
+// ----------------------------------------------------------------------
+
+// Notification is one entry of ESI's /characters/{id}/notifications/. Text
+// is a YAML document whose shape depends on Type.
+type Notification struct {
+	NotificationID int64     `json:"notification_id"`
+	SenderID       int64     `json:"sender_id"`
+	SenderType     string    `json:"sender_type"`
+	Text           string    `json:"text,omitempty"`
+	Timestamp      time.Time `json:"timestamp"`
+	Type           string    `json:"type"`
+	IsRead         bool      `json:"is_read,omitempty"`
+}
+
+// ----------------------------------------------------------------------
+// Sovereignty
+// ----------------------------------------------------------------------
+
+// SovereigntyCampaign is one entry of ESI's /sovereignty/campaigns/.
+type SovereigntyCampaign struct {
+	CampaignID      int64     `json:"campaign_id"`
+	ConstellationID int64     `json:"constellation_id"`
+	SolarSystemID   SystemID  `json:"solar_system_id"`
+	StructureID     int64     `json:"structure_id"`
+	EventType       string    `json:"event_type"`
+	DefenderID      int64     `json:"defender_id,omitempty"`
+	DefenderScore   float64   `json:"defender_score,omitempty"`
+	AttackersScore  float64   `json:"attackers_score,omitempty"`
+	StartTime       time.Time `json:"start_time"`
+}
+
+// Reinforcement timer kinds.
+const (
+	TimerStructureShield = "structure_shield" // shields lost, armor timer running
+	TimerStructureArmor  = "structure_armor"  // armor lost, hull timer running
+	TimerSovereignty     = "sovereignty"      // sovereignty campaign starting
+)
+
+// ReinforcementTimer is the moment a reinforced structure comes out of
+// reinforcement or a sovereignty campaign opens.
+type ReinforcementTimer struct {
+	ID              string    `json:"id"` // "structure:<id>" or "sov:<campaign id>"
+	Kind            string    `json:"kind"`
+	StructureID     int64     `json:"structure_id"`
+	StructureTypeID int64     `json:"structure_type_id,omitempty"`
+	SolarSystemID   SystemID  `json:"solar_system_id"`
+	CampaignID      int64     `json:"campaign_id,omitempty"`
+	EventType       string    `json:"event_type,omitempty"` // sovereignty campaign type
+	ExitsAt         time.Time `json:"exits_at"`
+
+	// RemindedLeads lists the lead times already reminded of.
+	RemindedLeads []time.Duration `json:"reminded_leads,omitempty"`
+}
+
+// ----------------------------------------------------------------------
+// Incursions
+// ----------------------------------------------------------------------
+
+// Incursion is one entry of ESI's /incursions/.
+type Incursion struct {
+	ConstellationID      int64    `json:"constellation_id"`
+	FactionID            int64    `json:"faction_id"`
+	HasBoss              bool     `json:"has_boss"`
+	InfestedSolarSystems []int64  `json:"infested_solar_systems"`
+	Influence            float64  `json:"influence"`
+	StagingSolarSystemID SystemID `json:"staging_solar_system_id"`
+	State                string   `json:"state"`
+	Type                 string   `json:"type"`
+}
+
+// Constellation is ESI's /universe/constellations/{id}/ shape.
+type Constellation struct {
+	ConstellationID int64    `json:"constellation_id"`
+	Name            string   `json:"name"`
+	RegionID        int64    `json:"region_id"`
+	Systems         []int64  `json:"systems"`
+	Position        Position `json:"position"`
+}
+
+// ----------------------------------------------------------------------
+// Faction warfare
+// ----------------------------------------------------------------------
+
+// FWSystem is one entry of ESI's /fw/systems/.
+type FWSystem struct {
+	SolarSystemID          SystemID `json:"solar_system_id"`
+	OwnerFactionID         int64    `json:"owner_faction_id"`
+	OccupierFactionID      int64    `json:"occupier_faction_id"`
+	Contested              string   `json:"contested"`
+	VictoryPoints          int      `json:"victory_points"`
+	VictoryPointsThreshold int      `json:"victory_points_threshold"`
+}
+
+// ContestedPercent is victory points as a percentage of the threshold.
+func (s *FWSystem) ContestedPercent() float64 {
+	if s.VictoryPointsThreshold == 0 {
+		return 0
+	}
+	return float64(s.VictoryPoints) / float64(s.VictoryPointsThreshold) * 100
+}
+
+// ----------------------------------------------------------------------
+// Contracts
+// ----------------------------------------------------------------------
+
+// Contract types as reported by ESI.
+const (
+	ContractItemExchange = "item_exchange"
+	ContractAuction      = "auction"
+	ContractCourier      = "courier"
+)
+
+// Contract is an entry from ESI /contracts/public/{region_id}/ or
+// /characters/{character_id}/contracts/. Status, AssigneeID and AcceptorID
+// are only set for character contracts.
+type Contract struct {
+	ContractID          int64         `json:"contract_id"`
+	Type                string        `json:"type"`
+	Title               string        `json:"title,omitempty"`
+	IssuerID            int64         `json:"issuer_id"`
+	IssuerCorporationID CorporationID `json:"issuer_corporation_id"`
+	AssigneeID          int64         `json:"assignee_id,omitempty"`
+	AcceptorID          int64         `json:"acceptor_id,omitempty"`
+	Status              string        `json:"status,omitempty"`
+	Price               float64       `json:"price"`
+	Reward              float64       `json:"reward"`
+	Collateral          float64       `json:"collateral"`
+	Buyout              float64       `json:"buyout,omitempty"`
+	Volume              float64       `json:"volume"`
+	StartLocationID     int64         `json:"start_location_id"`
+	EndLocationID       int64         `json:"end_location_id"`
+	DaysToComplete      int           `json:"days_to_complete"`
+	DateIssued          time.Time     `json:"date_issued"`
+	DateExpired         time.Time     `json:"date_expired"`
+	ForCorporation      bool          `json:"for_corporation"`
+}
+
+// ContractItem is one line of a contract's item list. IsIncluded is true
+// for items the issuer gives and false for items requested from the acceptor.
+type ContractItem struct {
+	RecordID        int64  `json:"record_id"`
+	TypeID          TypeID `json:"type_id"`
+	Quantity        int64  `json:"quantity"`
+	IsIncluded      bool   `json:"is_included"`
+	IsBlueprintCopy bool   `json:"is_blueprint_copy,omitempty"`
+	Runs            int    `json:"runs,omitempty"`
+}
+
+// ----------------------------------------------------------------------
+// Mining
+// ----------------------------------------------------------------------
+
+// MiningObserver is an entry from ESI /corporation/{corporation_id}/mining/observers/.
+// Observers are moon-mining structures (refineries).
+type MiningObserver struct {
+	ObserverID   int64  `json:"observer_id"`
+	ObserverType string `json:"observer_type"`
+	LastUpdated  string `json:"last_updated"` // YYYY-MM-DD
+}
+
+// MiningLedgerEntry is an entry from ESI
+// /corporation/{corporation_id}/mining/observers/{observer_id}/: the ore a
+// character mined at an observer on one day.
+type MiningLedgerEntry struct {
+	CharacterID           CharacterID   `json:"character_id"`
+	RecordedCorporationID CorporationID `json:"recorded_corporation_id"`
+	TypeID                TypeID        `json:"type_id"`
+	Quantity              int64         `json:"quantity"`
+	LastUpdated           string        `json:"last_updated"` // YYYY-MM-DD
+}
+
+// ----------------------------------------------------------------------
+// Types, skills and fittings
+// ----------------------------------------------------------------------
+
+// DogmaAttribute is one attribute value of a type.
+type DogmaAttribute struct {
+	AttributeID int64   `json:"attribute_id"`
+	Value       float64 `json:"value"`
+}
+
+// ItemType is ESI's /universe/types/{type_id}/ shape.
+type ItemType struct {
+	TypeID          TypeID           `json:"type_id"`
+	Name            string           `json:"name"`
+	GroupID         int64            `json:"group_id"`
+	Volume          float64          `json:"volume"`
+	PackagedVolume  float64          `json:"packaged_volume"`
+	Published       bool             `json:"published"`
+	DogmaAttributes []DogmaAttribute `json:"dogma_attributes"`
+}
+
+// Attribute returns the value of a dogma attribute and whether it is set.
+func (t *ItemType) Attribute(attributeID int64) (float64, bool) {
+	for _, a := range t.DogmaAttributes {
+		if a.AttributeID == attributeID {
+			return a.Value, true
+		}
+	}
+	return 0, false
+}
+
+// CharacterSkill is one trained skill from ESI /characters/{character_id}/skills/.
+type CharacterSkill struct {
+	SkillID            int64 `json:"skill_id"`
+	ActiveSkillLevel   int   `json:"active_skill_level"`
+	TrainedSkillLevel  int   `json:"trained_skill_level"`
+	SkillpointsInSkill int64 `json:"skillpoints_in_skill"`
+}
+
+// CharacterSkills is the answer of ESI /characters/{character_id}/skills/.
+type CharacterSkills struct {
+	Skills        []CharacterSkill `json:"skills"`
+	TotalSP       int64            `json:"total_sp"`
+	UnallocatedSP int64            `json:"unallocated_sp,omitempty"`
+}
+
+// Levels maps skill ID to trained level.
+func (s *CharacterSkills) Levels() map[int64]int {
+	out := make(map[int64]int, len(s.Skills))
+	for _, sk := range s.Skills {
+		out[sk.SkillID] = sk.TrainedSkillLevel
+	}
+	return out
+}
+
+// SkillQueueEntry is one entry from ESI /characters/{character_id}/skillqueue/.
+// StartDate and FinishDate are nil while the queue is paused.
+type SkillQueueEntry struct {
+	SkillID         int64      `json:"skill_id"`
+	FinishedLevel   int        `json:"finished_level"`
+	QueuePosition   int        `json:"queue_position"`
+	StartDate       *time.Time `json:"start_date,omitempty"`
+	FinishDate      *time.Time `json:"finish_date,omitempty"`
+	TrainingStartSP int64      `json:"training_start_sp,omitempty"`
+	LevelStartSP    int64      `json:"level_start_sp,omitempty"`
+	LevelEndSP      int64      `json:"level_end_sp,omitempty"`
+}
+
+// CharacterAttributes is the answer of ESI
+// /characters/{character_id}/attributes/.
+type CharacterAttributes struct {
+	Charisma                 int        `json:"charisma"`
+	Intelligence             int        `json:"intelligence"`
+	Memory                   int        `json:"memory"`
+	Perception               int        `json:"perception"`
+	Willpower                int        `json:"willpower"`
+	BonusRemaps              int        `json:"bonus_remaps,omitempty"`
+	LastRemapDate            *time.Time `json:"last_remap_date,omitempty"`
+	AccruedRemapCooldownDate *time.Time `json:"accrued_remap_cooldown_date,omitempty"`
+}
+
+// FittingItem is a module, charge, drone or cargo item of a Fitting. Flag is
+// the ESI slot flag (e.g. "HiSlot0", "DroneBay", "Cargo").
+type FittingItem struct {
+	TypeID   TypeID `json:"type_id"`
+	Flag     string `json:"flag"`
+	Quantity int64  `json:"quantity"`
+}
+
+// Fitting is ESI's /characters/{character_id}/fittings/ shape.
+type Fitting struct {
+	FittingID   int64         `json:"fitting_id,omitempty"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	ShipTypeID  TypeID        `json:"ship_type_id"`
+	Items       []FittingItem `json:"items"`
+}
+
+// ----------------------------------------------------------------------
+// Wallets
+// ----------------------------------------------------------------------
+
+// CorporationWallet is one division of ESI's /corporations/{corporation_id}/wallets/.
+type CorporationWallet struct {
+	Division int     `json:"division"`
+	Balance  float64 `json:"balance"`
+}
+
+// WalletJournalEntry is one entry of ESI's /characters/{character_id}/wallet/journal/
+// or /corporations/{corporation_id}/wallets/{division}/journal/. Amount is
+// positive for credits and negative for debits.
+type WalletJournalEntry struct {
+	ID            int64     `json:"id"`
+	Date          time.Time `json:"date"`
+	RefType       RefType   `json:"ref_type"`
+	Description   string    `json:"description"`
+	Amount        float64   `json:"amount,omitempty"`
+	Balance       float64   `json:"balance,omitempty"`
+	Reason        string    `json:"reason,omitempty"`
+	FirstPartyID  int64     `json:"first_party_id,omitempty"`
+	SecondPartyID int64     `json:"second_party_id,omitempty"`
+	ContextID     int64     `json:"context_id,omitempty"`
+	ContextIDType string    `json:"context_id_type,omitempty"`
+	Tax           float64   `json:"tax,omitempty"`
+	TaxReceiverID int64     `json:"tax_receiver_id,omitempty"`
 }
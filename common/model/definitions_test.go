@@ -0,0 +1,30 @@
+package model_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+func TestConvertFeedToFlattened_MatchesEsiPath(t *testing.T) {
+	esi := model.EsiKillMail{
+		KillMailID:    123,
+		KillMailTime:  time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		SolarSystemID: 30000142,
+		Victim:        model.Victim{CharacterID: 1, CorporationID: 2, ShipTypeID: 587},
+		Attackers:     []model.Attacker{{CharacterID: 3, FinalBlow: true}},
+	}
+	zkill := model.ZkillMail{KillMailID: 123, ZKB: model.ZKB{Hash: "abc", TotalValue: 1e7, Points: 5, Solo: true}}
+
+	fromEsi := model.ConvertToFlattened(esi, zkill)
+	feed := model.NewFeedResponse(esi, zkill)
+	fromFeed := model.ConvertFeedToFlattened(feed)
+	if !reflect.DeepEqual(fromEsi, fromFeed) {
+		t.Errorf("expected identical output\nesi:  %+v\nfeed: %+v", fromEsi, fromFeed)
+	}
+	if !reflect.DeepEqual(feed.EsiKillMail(), esi) || !reflect.DeepEqual(feed.ZkillMail(), zkill) {
+		t.Errorf("expected the feed to split back into its parts")
+	}
+}
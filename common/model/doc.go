@@ -1,2 +1,8 @@
 // Package model provides shared EVE-related data structures used by zkill, esi, and any other subpackages.
+//
+// The ID types (CharacterID, CorporationID, AllianceID, TypeID, SystemID) are
+// deliberately aliases of int64 rather than defined types: callers pass raw
+// ESI and zKillboard IDs straight through without conversions. They document
+// which ID a field or argument holds, but the compiler will not catch a
+// CharacterID passed where a CorporationID is expected.
 package model
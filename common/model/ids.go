@@ -0,0 +1,12 @@
+package model
+
+// Identifier types used by the models. They are aliases of int64, the width
+// ESI's IDs need, so model fields, service arguments and map keys share one
+// type without conversions; the names document which ID a field holds.
+type (
+	CharacterID   = int64
+	CorporationID = int64
+	AllianceID    = int64
+	TypeID        = int64
+	SystemID      = int64
+)
@@ -0,0 +1,146 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalid matches every ValidationError with errors.Is.
+var ErrInvalid = errors.New("invalid")
+
+// ValidationError lists what is wrong with a model.
+type ValidationError struct {
+	Model    string
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Model, strings.Join(e.Problems, "; "))
+}
+
+// Is reports whether target is ErrInvalid.
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalid
+}
+
+// Validator is implemented by models that can check themselves before they
+// are sent to ESI or persisted.
+type Validator interface {
+	Validate() error
+}
+
+// ESI limits checked by Validate.
+const (
+	MaxTickerLength             = 5
+	MaxFittingNameLength        = 50
+	MaxFittingDescriptionLength = 500
+	MaxFittingItems             = 512
+)
+
+// problems collects validation failures for one model.
+type problems struct {
+	model string
+	list  []string
+}
+
+func (p *problems) check(ok bool, format string, args ...interface{}) {
+	if !ok {
+		p.list = append(p.list, fmt.Sprintf(format, args...))
+	}
+}
+
+func (p *problems) err() error {
+	if len(p.list) == 0 {
+		return nil
+	}
+	return &ValidationError{Model: p.model, Problems: p.list}
+}
+
+// checkCorporation applies the checks shared by the corporation shapes.
+func (p *problems) checkCorporation(name, ticker string, ceoID, creatorID CharacterID, taxRate float64) {
+	p.check(strings.TrimSpace(name) != "", "name is required")
+	p.checkTicker(ticker)
+	p.check(ceoID > 0, "ceo_id is required")
+	p.check(creatorID > 0, "creator_id is required")
+	p.check(taxRate >= 0 && taxRate <= 1, "tax_rate %v is outside [0, 1]", taxRate)
+}
+
+// checkAlliance applies the checks shared by the alliance shapes.
+func (p *problems) checkAlliance(name, ticker string, creatorID CharacterID, creatorCorporationID CorporationID) {
+	p.check(strings.TrimSpace(name) != "", "name is required")
+	p.checkTicker(ticker)
+	p.check(creatorID > 0, "creator_id is required")
+	p.check(creatorCorporationID > 0, "creator_corporation_id is required")
+}
+
+func (p *problems) checkTicker(ticker string) {
+	t := strings.TrimSpace(ticker)
+	p.check(t != "", "ticker is required")
+	p.check(len([]rune(t)) <= MaxTickerLength, "ticker %q is longer than %d characters", ticker, MaxTickerLength)
+}
+
+// Validate checks the corporation's name, ticker, CEO and creator IDs and
+// tax rate.
+func (c *EsiCorporation) Validate() error {
+	p := problems{model: "corporation"}
+	p.checkCorporation(c.Name, c.Ticker, c.CeoID, c.CreatorID, c.TaxRate)
+	p.check(c.MemberCount >= 0, "member_count %d is negative", c.MemberCount)
+	return p.err()
+}
+
+// Validate checks the corporation's name, ticker, CEO and creator IDs and
+// tax rate.
+func (c *EsiCorporationInfo) Validate() error {
+	p := problems{model: "corporation"}
+	p.checkCorporation(c.Name, c.Ticker, c.CEOId, c.CreatorID, c.TaxRate)
+	p.check(c.MemberCount >= 0, "member_count %d is negative", c.MemberCount)
+	return p.err()
+}
+
+// Validate checks the corporation's name, ticker, CEO and creator IDs and
+// tax rate.
+func (c *Corporation) Validate() error {
+	p := problems{model: "corporation"}
+	p.checkCorporation(c.Name, c.Ticker, c.CEOId, c.CreatorID, c.TaxRate)
+	p.check(c.MemberCount >= 0, "member_count %d is negative", c.MemberCount)
+	return p.err()
+}
+
+// Validate checks the alliance's name, ticker and creator IDs.
+func (a *EsiAlliance) Validate() error {
+	p := problems{model: "alliance"}
+	p.checkAlliance(a.Name, a.Ticker, a.CreatorID, a.CreatorCorporationID)
+	return p.err()
+}
+
+// Validate checks the alliance's name, ticker and creator IDs.
+func (a *Alliance) Validate() error {
+	p := problems{model: "alliance"}
+	p.checkAlliance(a.Name, a.Ticker, a.CreatorID, a.CreatorCorporationID)
+	return p.err()
+}
+
+// Validate checks a fitting against ESI's limits before it is POSTed.
+func (f *Fitting) Validate() error {
+	p := problems{model: "fitting"}
+	p.check(strings.TrimSpace(f.Name) != "", "name is required")
+	p.check(len([]rune(f.Name)) <= MaxFittingNameLength, "name is longer than %d characters", MaxFittingNameLength)
+	p.check(len([]rune(f.Description)) <= MaxFittingDescriptionLength, "description is longer than %d characters", MaxFittingDescriptionLength)
+	p.check(f.ShipTypeID > 0, "ship_type_id is required")
+	p.check(len(f.Items) <= MaxFittingItems, "more than %d items", MaxFittingItems)
+	for i, it := range f.Items {
+		p.check(it.TypeID > 0, "item %d: type_id is required", i)
+		p.check(it.Flag != "", "item %d: flag is required", i)
+		p.check(it.Quantity > 0, "item %d: quantity must be positive", i)
+	}
+	return p.err()
+}
+
+// Validate checks the fields killmail storage indexes on.
+func (km *FlattenedKillMail) Validate() error {
+	p := problems{model: "killmail"}
+	p.check(km.KillMailID > 0, "killmail_id is required")
+	p.check(!km.KillMailTime.IsZero(), "killmail %d: killmail_time is required", km.KillMailID)
+	return p.err()
+}
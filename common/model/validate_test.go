@@ -0,0 +1,45 @@
+package model_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name     string
+		v        model.Validator
+		problems int
+	}{
+		{"valid corporation", &model.EsiCorporation{Name: "Corp", Ticker: "CORP", CeoID: 1, CreatorID: 1, TaxRate: 0.1}, 0},
+		{"corporation", &model.EsiCorporation{Name: " ", Ticker: "TOOLONG", TaxRate: 1.5, MemberCount: -1}, 6},
+		{"corporation info", &model.EsiCorporationInfo{Name: "Corp", CEOId: 1, CreatorID: 1}, 1},
+		{"alliance", &model.Alliance{Name: "Alliance", Ticker: "ALLY"}, 2},
+		{"valid fitting", &model.Fitting{Name: "Fit", ShipTypeID: 587,
+			Items: []model.FittingItem{{TypeID: 3829, Flag: "HiSlot0", Quantity: 1}}}, 0},
+		{"fitting", &model.Fitting{Name: strings.Repeat("x", 51),
+			Items: []model.FittingItem{{TypeID: 3829, Flag: "HiSlot0"}}}, 3},
+		{"killmail", &model.FlattenedKillMail{KillMailID: 1}, 1},
+		{"valid killmail", &model.FlattenedKillMail{KillMailID: 1, KillMailTime: time.Now()}, 0},
+	}
+	for _, c := range cases {
+		err := c.v.Validate()
+		if c.problems == 0 {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", c.name, err)
+			}
+			continue
+		}
+		var verr *model.ValidationError
+		if !errors.As(err, &verr) || !errors.Is(err, model.ErrInvalid) {
+			t.Fatalf("%s: expected a ValidationError, got %v", c.name, err)
+		}
+		if len(verr.Problems) != c.problems {
+			t.Errorf("%s: expected %d problems, got %v", c.name, c.problems, verr.Problems)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package model_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+func TestRefType_Category(t *testing.T) {
+	var e model.WalletJournalEntry
+	if err := json.Unmarshal([]byte(`{"id":1,"ref_type":"bounty_prizes","amount":100}`), &e); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e.RefType != model.RefBountyPrizes || e.RefType.Category() != model.RefCategoryBounties {
+		t.Errorf("expected a bounty, got %q in %q", e.RefType, e.RefType.Category())
+	}
+	if c := model.RefType("some_new_ref_type").Category(); c != model.RefCategoryOther {
+		t.Errorf("expected unknown ref types in other, got %q", c)
+	}
+}
+
+func TestSumJournalByCategory(t *testing.T) {
+	entries := []model.WalletJournalEntry{
+		{RefType: model.RefBountyPrizes, Amount: 1000},
+		{RefType: model.RefESSEscrowTransfer, Amount: 500},
+		{RefType: model.RefMarketTransaction, Amount: 2000},
+		{RefType: model.RefMarketTransaction, Amount: -1500},
+		{RefType: model.RefBrokersFee, Amount: -30},
+		{RefType: model.RefTransactionTax, Amount: -70},
+	}
+	sums := model.SumJournalByCategory(entries)
+	if s := sums[model.RefCategoryBounties]; s.Income != 1500 || s.Entries != 2 {
+		t.Errorf("unexpected bounties: %+v", s)
+	}
+	if s := sums[model.RefCategoryMarket]; s.Income != 2000 || s.Expenses != 1500 || s.Net() != 500 {
+		t.Errorf("unexpected market: %+v", s)
+	}
+	if s := sums[model.RefCategoryTaxes]; s.Expenses != 100 || s.Net() != -100 {
+		t.Errorf("unexpected taxes: %+v", s)
+	}
+	if s := model.SumJournalByRefType(entries)[model.RefMarketTransaction]; s.Entries != 2 {
+		t.Errorf("unexpected market transactions: %+v", s)
+	}
+}
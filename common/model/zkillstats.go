@@ -0,0 +1,207 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// ZKillStats is zKillboard's stats payload for a character, corporation or
+// alliance (/api/stats/<type>ID/<id>/).
+type ZKillStats struct {
+	ID   int64  `json:"id"`
+	Type string `json:"type"` // "characterID", "corporationID" or "allianceID"
+
+	ShipsDestroyed  int     `json:"shipsDestroyed"`
+	ShipsLost       int     `json:"shipsLost"`
+	PointsDestroyed int     `json:"pointsDestroyed"`
+	PointsLost      int     `json:"pointsLost"`
+	ISKDestroyed    float64 `json:"iskDestroyed"`
+	ISKLost         float64 `json:"iskLost"`
+	SoloKills       int     `json:"soloKills"`
+	SoloLosses      int     `json:"soloLosses"`
+
+	DangerRatio int     `json:"dangerRatio"` // share of kills in ships, 0-100
+	GangRatio   int     `json:"gangRatio"`   // share of kills not solo, 0-100
+	SoloRatio   float64 `json:"soloRatio"`
+	AvgGangSize float64 `json:"avgGangSize"`
+	AllTimeSum  int     `json:"allTimeSum"`
+
+	Months        ZKillMonths    `json:"months"`
+	Groups        ZKillGroups    `json:"groups"`
+	ActivePvP     ZKillActivePvP `json:"activepvp"`
+	TopLists      []ZKillTopList `json:"topLists"`
+	TopISKKillIDs []int64        `json:"topIskKillIDs"`
+}
+
+// ZKillMonthStats is one month of a ZKillStats breakdown.
+type ZKillMonthStats struct {
+	Year            int     `json:"year"`
+	Month           int     `json:"month"`
+	ShipsDestroyed  int     `json:"shipsDestroyed"`
+	ShipsLost       int     `json:"shipsLost"`
+	PointsDestroyed int     `json:"pointsDestroyed"`
+	PointsLost      int     `json:"pointsLost"`
+	ISKDestroyed    float64 `json:"iskDestroyed"`
+	ISKLost         float64 `json:"iskLost"`
+}
+
+// ZKillGroupStats is the activity against one ship group.
+type ZKillGroupStats struct {
+	GroupID         int     `json:"groupID"`
+	ShipsDestroyed  int     `json:"shipsDestroyed"`
+	ShipsLost       int     `json:"shipsLost"`
+	PointsDestroyed int     `json:"pointsDestroyed"`
+	PointsLost      int     `json:"pointsLost"`
+	ISKDestroyed    float64 `json:"iskDestroyed"`
+	ISKLost         float64 `json:"iskLost"`
+}
+
+// ZKillMonths is the monthly breakdown keyed by "YYYYMM".
+type ZKillMonths map[string]ZKillMonthStats
+
+// ZKillGroups is the ship group breakdown keyed by group ID.
+type ZKillGroups map[string]ZKillGroupStats
+
+// UnmarshalJSON accepts the empty array zKill sends instead of an empty object.
+func (m *ZKillMonths) UnmarshalJSON(data []byte) error {
+	return unmarshalPHPMap(data, (*map[string]ZKillMonthStats)(m))
+}
+
+// UnmarshalJSON accepts the empty array zKill sends instead of an empty object.
+func (g *ZKillGroups) UnmarshalJSON(data []byte) error {
+	return unmarshalPHPMap(data, (*map[string]ZKillGroupStats)(g))
+}
+
+// Get returns the stats for one month.
+func (m ZKillMonths) Get(year, month int) (ZKillMonthStats, bool) {
+	for _, ms := range m {
+		if ms.Year == year && ms.Month == month {
+			return ms, true
+		}
+	}
+	return ZKillMonthStats{}, false
+}
+
+// Sorted returns the months in chronological order.
+func (m ZKillMonths) Sorted() []ZKillMonthStats {
+	out := make([]ZKillMonthStats, 0, len(m))
+	for _, ms := range m {
+		out = append(out, ms)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Year != out[j].Year {
+			return out[i].Year < out[j].Year
+		}
+		return out[i].Month < out[j].Month
+	})
+	return out
+}
+
+// ZKillCount is one counter of the recent activity summary.
+type ZKillCount struct {
+	Type  string `json:"type"`
+	Count int    `json:"count"`
+}
+
+// ZKillActivePvP summarizes the entity's activity over the last week.
+type ZKillActivePvP struct {
+	Ships        ZKillCount `json:"ships"`
+	Systems      ZKillCount `json:"systems"`
+	Regions      ZKillCount `json:"regions"`
+	Kills        ZKillCount `json:"kills"`
+	Characters   ZKillCount `json:"characters"`
+	Corporations ZKillCount `json:"corporations"`
+	Alliances    ZKillCount `json:"alliances"`
+}
+
+// UnmarshalJSON accepts the empty array zKill sends without recent activity.
+func (a *ZKillActivePvP) UnmarshalJSON(data []byte) error {
+	type plain ZKillActivePvP
+	if isEmptyArray(data) {
+		*a = ZKillActivePvP{}
+		return nil
+	}
+	return json.Unmarshal(data, (*plain)(a))
+}
+
+// ZKillTopList is one of the weekly top lists (characters, ships, systems...).
+type ZKillTopList struct {
+	Type   string          `json:"type"` // e.g. "character", "shipType", "solarSystem"
+	Title  string          `json:"title"`
+	Values []ZKillTopValue `json:"values"`
+}
+
+// ZKillTopValue is a top list row. Which ID and name fields are set depends
+// on the list type; Entries normalizes them.
+type ZKillTopValue struct {
+	Kills           int    `json:"kills"`
+	CharacterID     int64  `json:"characterID,omitempty"`
+	CharacterName   string `json:"characterName,omitempty"`
+	CorporationID   int64  `json:"corporationID,omitempty"`
+	CorporationName string `json:"corporationName,omitempty"`
+	AllianceID      int64  `json:"allianceID,omitempty"`
+	AllianceName    string `json:"allianceName,omitempty"`
+	ShipTypeID      int64  `json:"shipTypeID,omitempty"`
+	ShipName        string `json:"shipName,omitempty"`
+	SolarSystemID   int64  `json:"solarSystemID,omitempty"`
+	SolarSystemName string `json:"solarSystemName,omitempty"`
+	LocationID      int64  `json:"locationID,omitempty"`
+	ItemName        string `json:"itemName,omitempty"`
+}
+
+// ZKillTopEntry is a top list row reduced to the listed entity.
+type ZKillTopEntry struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Kills int    `json:"kills"`
+}
+
+// Entries returns the list's rows with the ID and name of the list's type.
+// Rows of an unknown list type keep only their kill count.
+func (l ZKillTopList) Entries() []ZKillTopEntry {
+	out := make([]ZKillTopEntry, 0, len(l.Values))
+	for _, v := range l.Values {
+		e := ZKillTopEntry{Kills: v.Kills}
+		switch l.Type {
+		case "character":
+			e.ID, e.Name = v.CharacterID, v.CharacterName
+		case "corporation":
+			e.ID, e.Name = v.CorporationID, v.CorporationName
+		case "alliance":
+			e.ID, e.Name = v.AllianceID, v.AllianceName
+		case "shipType":
+			e.ID, e.Name = v.ShipTypeID, v.ShipName
+		case "solarSystem":
+			e.ID, e.Name = v.SolarSystemID, v.SolarSystemName
+		case "location":
+			e.ID, e.Name = v.LocationID, v.ItemName
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// TopList returns the top list of the given type.
+func (s *ZKillStats) TopList(listType string) (ZKillTopList, bool) {
+	for _, l := range s.TopLists {
+		if l.Type == listType {
+			return l, true
+		}
+	}
+	return ZKillTopList{}, false
+}
+
+// unmarshalPHPMap decodes a JSON object into m, treating an empty array as
+// an empty object.
+func unmarshalPHPMap[V any](data []byte, m *map[string]V) error {
+	if isEmptyArray(data) {
+		*m = nil
+		return nil
+	}
+	return json.Unmarshal(data, m)
+}
+
+func isEmptyArray(data []byte) bool {
+	return bytes.Equal(bytes.Join(bytes.Fields(data), nil), []byte("[]"))
+}
@@ -0,0 +1,58 @@
+package model_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+const zkillStatsJSON = `{
+	"id": 1959376155, "type": "characterID",
+	"shipsDestroyed": 12, "shipsLost": 3, "pointsDestroyed": 40, "pointsLost": 6,
+	"iskDestroyed": 1.5e9, "iskLost": 2e8, "soloKills": 4, "soloLosses": 1,
+	"dangerRatio": 87, "gangRatio": 66, "soloRatio": 33.3, "avgGangSize": 2.5, "allTimeSum": 15,
+	"months": {
+		"202402": {"year": 2024, "month": 2, "shipsDestroyed": 5, "iskDestroyed": 5e8},
+		"202401": {"year": 2024, "month": 1, "shipsDestroyed": 7, "iskDestroyed": 1e9}
+	},
+	"groups": {"25": {"groupID": 25, "shipsDestroyed": 12}},
+	"activepvp": {"kills": {"type": "Total Kills", "count": 8}, "ships": {"type": "Total Ships", "count": 2}},
+	"topLists": [
+		{"type": "character", "title": "Top Characters", "values": [{"kills": 5, "characterID": 1, "characterName": "Pilot"}]},
+		{"type": "shipType", "title": "Top Ships", "values": [{"kills": 3, "shipTypeID": 587, "shipName": "Rifter"}]}
+	],
+	"topIskKillIDs": [100, 101]
+}`
+
+func TestZKillStats_Decode(t *testing.T) {
+	var zs model.ZKillStats
+	if err := json.Unmarshal([]byte(zkillStatsJSON), &zs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if zs.DangerRatio != 87 || zs.ActivePvP.Kills.Count != 8 || zs.Groups["25"].ShipsDestroyed != 12 {
+		t.Errorf("unexpected stats: %+v", zs)
+	}
+	months := zs.Months.Sorted()
+	if len(months) != 2 || months[0].Month != 1 || months[1].Month != 2 {
+		t.Errorf("expected months in order, got %+v", months)
+	}
+	if feb, ok := zs.Months.Get(2024, 2); !ok || feb.ShipsDestroyed != 5 {
+		t.Errorf("unexpected February stats: %+v, %v", feb, ok)
+	}
+	ships, ok := zs.TopList("shipType")
+	if entries := ships.Entries(); !ok || len(entries) != 1 || entries[0] != (model.ZKillTopEntry{ID: 587, Name: "Rifter", Kills: 3}) {
+		t.Errorf("unexpected ship top list: %+v", entries)
+	}
+}
+
+func TestZKillStats_DecodeEmptyArrays(t *testing.T) {
+	var zs model.ZKillStats
+	data := `{"id": 1, "type": "characterID", "months": [], "groups": [ ], "activepvp": []}`
+	if err := json.Unmarshal([]byte(data), &zs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(zs.Months) != 0 || len(zs.Groups) != 0 || zs.ActivePvP.Kills.Count != 0 {
+		t.Errorf("expected empty breakdowns, got %+v", zs)
+	}
+}
@@ -0,0 +1,20 @@
+package common
+
+import "context"
+
+// noCacheKey is the context key set by WithoutCache.
+type noCacheKey struct{}
+
+// WithoutCache returns a context under which fetches bypass response caches,
+// neither reading nor writing them. It lets a caller that needs current data,
+// such as a monitor diffing state between polls, ask for it through a service
+// that otherwise caches the endpoint.
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCacheKey{}, true)
+}
+
+// CacheBypassed reports whether ctx was derived from WithoutCache.
+func CacheBypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(noCacheKey{}).(bool)
+	return bypass
+}
@@ -0,0 +1,104 @@
+package common
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultRateLimitPause is how long to pause after a 420/429 response that
+// does not say when to retry.
+const DefaultRateLimitPause = time.Minute
+
+// statusErrorLimited is ESI's "error limited" status.
+const statusErrorLimited = 420
+
+// PauseGate is a shared "pause until T" signal. Every goroutine issuing
+// requests through a client waits on the same gate, so a single rate-limit
+// response stops all of them instead of each one hitting the limit in turn.
+// Share one gate between clients to pause them together.
+type PauseGate struct {
+	mu    sync.Mutex
+	until time.Time
+}
+
+// NewPauseGate returns an open PauseGate.
+func NewPauseGate() *PauseGate {
+	return &PauseGate{}
+}
+
+// PauseUntil closes the gate until t. An earlier t never shortens an
+// existing pause.
+func (g *PauseGate) PauseUntil(t time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if t.After(g.until) {
+		g.until = t
+	}
+}
+
+// PausedUntil returns when the gate reopens; it is open if that is in the past.
+func (g *PauseGate) PausedUntil() time.Time {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.until
+}
+
+// Wait blocks until the gate is open or ctx is done.
+func (g *PauseGate) Wait(ctx context.Context) error {
+	for {
+		d := time.Until(g.PausedUntil())
+		if d <= 0 {
+			return nil
+		}
+		timer := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// RateLimitDelay reports whether resp is a 420 or 429 and, if so, how long
+// to pause: Retry-After (seconds or an HTTP date), then ESI's
+// X-Esi-Error-Limit-Reset, then fallback.
+func RateLimitDelay(resp *http.Response, fallback time.Duration) (time.Duration, bool) {
+	if resp == nil || (resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != statusErrorLimited) {
+		return 0, false
+	}
+	if ra := resp.Header.Get("Retry-After"); ra != "" {
+		if secs, err := strconv.Atoi(ra); err == nil {
+			return time.Duration(secs) * time.Second, true
+		}
+		if t, err := http.ParseTime(ra); err == nil {
+			return time.Until(t), true
+		}
+	}
+	if secs, err := strconv.Atoi(resp.Header.Get("X-Esi-Error-Limit-Reset")); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+	return fallback, true
+}
+
+// ErrorBudgetDelay reports whether resp says ESI's error budget has fallen
+// below threshold (X-Esi-Error-Limit-Remain) and, if so, how long until it
+// resets (X-Esi-Error-Limit-Reset). Exhausting the budget gets the whole IP
+// error limited, so callers should pause every request for that long.
+func ErrorBudgetDelay(resp *http.Response, threshold int) (time.Duration, bool) {
+	if resp == nil || threshold <= 0 {
+		return 0, false
+	}
+	remain, err := strconv.Atoi(resp.Header.Get("X-Esi-Error-Limit-Remain"))
+	if err != nil || remain >= threshold {
+		return 0, false
+	}
+	secs, err := strconv.Atoi(resp.Header.Get("X-Esi-Error-Limit-Reset"))
+	if err != nil {
+		return DefaultRateLimitPause, true
+	}
+	return time.Duration(secs) * time.Second, true
+}
@@ -0,0 +1,74 @@
+package common_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/guarzo/eveapi/common"
+)
+
+func TestPauseGate_BlocksAllWaiters(t *testing.T) {
+	g := common.NewPauseGate()
+	if err := g.Wait(context.Background()); err != nil {
+		t.Fatalf("expected an open gate, got %v", err)
+	}
+
+	g.PauseUntil(time.Now().Add(30 * time.Millisecond))
+	g.PauseUntil(time.Now()) // must not shorten the pause
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = g.Wait(context.Background())
+		}()
+	}
+	wg.Wait()
+	if elapsed := time.Since(start); elapsed < 25*time.Millisecond {
+		t.Errorf("expected waiters to block for the pause, returned after %s", elapsed)
+	}
+
+	g.PauseUntil(time.Now().Add(time.Hour))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := g.Wait(ctx); err == nil {
+		t.Errorf("expected the context error while paused")
+	}
+}
+
+func TestRateLimitDelay(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	resp.Header.Set("Retry-After", "7")
+	if d, ok := common.RateLimitDelay(resp, time.Minute); !ok || d != 7*time.Second {
+		t.Errorf("expected 7s from Retry-After, got %s, %v", d, ok)
+	}
+
+	resp = &http.Response{StatusCode: 420, Header: http.Header{}}
+	resp.Header.Set("X-Esi-Error-Limit-Reset", "42")
+	if d, ok := common.RateLimitDelay(resp, time.Minute); !ok || d != 42*time.Second {
+		t.Errorf("expected 42s from the error limit reset, got %s, %v", d, ok)
+	}
+
+	if _, ok := common.RateLimitDelay(&http.Response{StatusCode: http.StatusOK}, time.Minute); ok {
+		t.Errorf("expected a 200 not to be rate limited")
+	}
+}
+
+func TestErrorBudgetDelay(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusNotFound, Header: http.Header{}}
+	resp.Header.Set("X-Esi-Error-Limit-Remain", "5")
+	resp.Header.Set("X-Esi-Error-Limit-Reset", "31")
+	if d, ok := common.ErrorBudgetDelay(resp, 10); !ok || d != 31*time.Second {
+		t.Errorf("expected 31s until the budget resets, got %s, %v", d, ok)
+	}
+	if _, ok := common.ErrorBudgetDelay(resp, 5); ok {
+		t.Errorf("expected a budget at the threshold not to pause")
+	}
+	if _, ok := common.ErrorBudgetDelay(&http.Response{StatusCode: http.StatusOK, Header: http.Header{}}, 10); ok {
+		t.Errorf("expected a response without error limit headers not to pause")
+	}
+}
@@ -0,0 +1,121 @@
+package common
+
+import (
+	"sync"
+	"time"
+)
+
+// ClientStats is a point-in-time snapshot of a client's request counters,
+// suitable for exposing on a health endpoint.
+type ClientStats struct {
+	TotalCalls  int64 `json:"total_calls"`
+	Successes   int64 `json:"successes"`
+	NotFound    int64 `json:"not_found"`
+	Failures    int64 `json:"failures"`
+	CacheHits   int64 `json:"cache_hits"`
+	CacheMisses int64 `json:"cache_misses"`
+	// ErrorLimitRemain is the last error budget reported by the upstream API,
+	// or -1 if none has been seen.
+	ErrorLimitRemain int           `json:"error_limit_remain"`
+	AverageLatency   time.Duration `json:"average_latency"`
+}
+
+// MetricsRecorder receives per-request metrics from a client. Implement it to
+// forward numbers to Prometheus, StatsD and the like.
+type MetricsRecorder interface {
+	RecordResponse(status int, latency time.Duration)
+	RecordCache(hit bool)
+	SetErrorLimitRemain(remain int)
+}
+
+// MultiRecorder fans metrics out to every recorder, skipping nils.
+func MultiRecorder(recorders ...MetricsRecorder) MetricsRecorder {
+	var out multiRecorder
+	for _, r := range recorders {
+		if r != nil {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+type multiRecorder []MetricsRecorder
+
+func (m multiRecorder) RecordResponse(status int, latency time.Duration) {
+	for _, r := range m {
+		r.RecordResponse(status, latency)
+	}
+}
+
+func (m multiRecorder) RecordCache(hit bool) {
+	for _, r := range m {
+		r.RecordCache(hit)
+	}
+}
+
+func (m multiRecorder) SetErrorLimitRemain(remain int) {
+	for _, r := range m {
+		r.SetErrorLimitRemain(remain)
+	}
+}
+
+// StatsCounter is a MetricsRecorder that accumulates ClientStats for a
+// single client instance. The zero value is ready to use.
+type StatsCounter struct {
+	mu             sync.Mutex
+	stats          ClientStats
+	errorLimitSeen bool
+	totalLatency   time.Duration
+	timedCalls     int64
+}
+
+// RecordResponse counts a completed HTTP call by status code and latency.
+// A status of 0 means the request failed before a response arrived.
+func (s *StatsCounter) RecordResponse(status int, latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats.TotalCalls++
+	switch {
+	case status == 404:
+		s.stats.NotFound++
+	case status >= 200 && status < 300:
+		s.stats.Successes++
+	default:
+		s.stats.Failures++
+	}
+	s.totalLatency += latency
+	s.timedCalls++
+}
+
+// RecordCache counts a cache lookup.
+func (s *StatsCounter) RecordCache(hit bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if hit {
+		s.stats.CacheHits++
+	} else {
+		s.stats.CacheMisses++
+	}
+}
+
+// SetErrorLimitRemain records the latest error budget reported upstream.
+func (s *StatsCounter) SetErrorLimitRemain(remain int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats.ErrorLimitRemain = remain
+	s.errorLimitSeen = true
+}
+
+// Snapshot returns a copy of the current counters.
+func (s *StatsCounter) Snapshot() ClientStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := s.stats
+	if !s.errorLimitSeen {
+		out.ErrorLimitRemain = -1
+	}
+	if s.timedCalls > 0 {
+		out.AverageLatency = s.totalLatency / time.Duration(s.timedCalls)
+	}
+	return out
+}
@@ -0,0 +1,4 @@
+// Package eveapi wires the library's clients and services into one Client.
+// The packages under common and modules remain usable on their own; New only
+// saves assembling them by hand.
+package eveapi
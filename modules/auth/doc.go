@@ -0,0 +1,8 @@
+// Package auth validates EVE SSO v2 access tokens locally: it verifies the
+// JWT signature against CCP's published keys and checks issuer, audience and
+// expiry. Keys and validation results are cached, so services validating a
+// token on every request neither re-fetch keys nor re-verify signatures.
+//
+// SSOAuthenticator runs the authorization-code flow with PKCE that obtains
+// those tokens, and refreshes them; a TokenStore keeps them across restarts.
+package auth
@@ -0,0 +1,170 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/guarzo/eveapi/common"
+)
+
+// DefaultJWKSURL is where EVE SSO publishes its signing keys.
+const DefaultJWKSURL = "https://login.eveonline.com/oauth/jwks"
+
+// ErrUnknownKey is returned when a token is signed with a key the JWKS does
+// not list, even after refetching it.
+var ErrUnknownKey = errors.New("auth: unknown signing key")
+
+// jwk is one key of a JSON Web Key Set. Only the RSA and EC fields EVE SSO
+// uses are decoded.
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// publicKey decodes k into an *rsa.PublicKey or *ecdsa.PublicKey.
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus: %w", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exponent: %w", err)
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid x: %w", err)
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid y: %w", err)
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+// keySet caches the JWKS. Keys are refetched when they expire or when a
+// token names an unknown key ID, which is how a key rollover shows up; the
+// latter at most once per minRefresh, so forged key IDs cannot make every
+// request fetch the JWKS.
+type keySet struct {
+	url         string
+	metadataURL string // discovers url when it is empty
+	client      common.HttpClient
+	ttl         time.Duration
+	minRefresh  time.Duration
+	now         func() time.Time
+
+	mu      sync.Mutex
+	keys    map[string]crypto.PublicKey
+	fetched time.Time
+}
+
+// key returns the public key with ID kid, fetching the JWKS if needed.
+func (s *keySet) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if s.keys == nil || now.Sub(s.fetched) >= s.ttl {
+		if err := s.fetch(ctx, now); err != nil {
+			return nil, err
+		}
+	}
+	if k, ok := s.keys[kid]; ok {
+		return k, nil
+	}
+	if now.Sub(s.fetched) < s.minRefresh {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+	}
+	if err := s.fetch(ctx, now); err != nil {
+		return nil, err
+	}
+	if k, ok := s.keys[kid]; ok {
+		return k, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+}
+
+// invalidate drops the cached keys so the next lookup refetches them. A
+// discovered JWKS URL is discovered again.
+func (s *keySet) invalidate() {
+	s.mu.Lock()
+	s.keys = nil
+	if s.metadataURL != "" {
+		s.url = ""
+	}
+	s.mu.Unlock()
+}
+
+func (s *keySet) fetch(ctx context.Context, now time.Time) error {
+	if s.url == "" && s.metadataURL != "" {
+		md, err := FetchMetadata(ctx, s.client, s.metadataURL)
+		if err != nil {
+			return err
+		}
+		if md.JWKSURI == "" {
+			return fmt.Errorf("failed to discover JWKS: metadata at %s has no jwks_uri", s.metadataURL)
+		}
+		s.url = md.JWKSURI
+	}
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := getJSON(ctx, s.client, s.url, &set); err != nil {
+		return fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		// skip keys this package cannot use rather than failing the whole set
+		if pk, err := k.publicKey(); err == nil {
+			keys[k.Kid] = pk
+		}
+	}
+	s.keys, s.fetched = keys, now
+	return nil
+}
+
+// getJSON fetches url with client and decodes the JSON body into v.
+func getJSON(ctx context.Context, client common.HttpClient, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return &common.HTTPError{StatusCode: resp.StatusCode}
+	}
+	body, err := common.ReadLimited(resp.Body, common.DefaultMaxResponseSize)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	return common.JSONDecoder{}.Decode(url, body, v)
+}
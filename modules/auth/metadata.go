@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common"
+)
+
+// DefaultMetadataURL is EVE SSO's OAuth 2.0 authorization server metadata
+// document (RFC 8414).
+const DefaultMetadataURL = "https://login.eveonline.com/.well-known/oauth-authorization-server"
+
+// Metadata is the part of the authorization server metadata this package
+// uses.
+type Metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+	RevocationEndpoint    string `json:"revocation_endpoint"`
+}
+
+// FetchMetadata fetches the authorization server metadata at url.
+func FetchMetadata(ctx context.Context, client common.HttpClient, url string) (*Metadata, error) {
+	var md Metadata
+	if err := getJSON(ctx, client, url, &md); err != nil {
+		return nil, fmt.Errorf("failed to fetch SSO metadata: %w", err)
+	}
+	return &md, nil
+}
+
+// WithMetadataURL makes the validator discover the JWKS URL from the
+// metadata document at url on its first key fetch, instead of using a fixed
+// one.
+func WithMetadataURL(url string) ValidatorOption {
+	return func(v *Validator) {
+		v.keys.metadataURL = url
+		v.keys.url = ""
+	}
+}
+
+var (
+	defaultValidatorOnce sync.Once
+	defaultValidator     *Validator
+)
+
+// defaultUserAgent identifies DefaultValidator's requests.
+const defaultUserAgent = "eveapi (+https://github.com/guarzo/eveapi)"
+
+// DefaultValidator returns the Validator used by ValidateToken. It
+// discovers CCP's keys through DefaultMetadataURL.
+func DefaultValidator() *Validator {
+	defaultValidatorOnce.Do(func() {
+		client := common.NewEveHttpClient(defaultUserAgent, &http.Client{Timeout: 10 * time.Second})
+		defaultValidator = NewValidator(client, WithMetadataURL(DefaultMetadataURL))
+	})
+	return defaultValidator
+}
+
+// ValidateToken verifies the access token of token with DefaultValidator and
+// returns its claims.
+func ValidateToken(ctx context.Context, token *oauth2.Token) (*Claims, error) {
+	if token == nil || token.AccessToken == "" {
+		return nil, fmt.Errorf("%w: no access token", ErrInvalidToken)
+	}
+	return DefaultValidator().Validate(ctx, token.AccessToken)
+}
@@ -0,0 +1,96 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/modules/auth"
+)
+
+// tokenServer answers EVE SSO token requests, checking the PKCE verifier
+// against the challenge of the last authorize URL.
+func tokenServer(t *testing.T, challenge *string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		if r.Form.Get("client_id") != "app" {
+			http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+			return
+		}
+		switch r.Form.Get("grant_type") {
+		case "authorization_code":
+			sum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+			if r.Form.Get("code") != "abc" || base64.RawURLEncoding.EncodeToString(sum[:]) != *challenge {
+				http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+				return
+			}
+		case "refresh_token":
+			if r.Form.Get("refresh_token") != "refresh-1" {
+				http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+				return
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access-" + r.Form.Get("grant_type"), "token_type": "Bearer",
+			"expires_in": 1199, "refresh_token": "refresh-1",
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestSSOAuthenticator_Flow(t *testing.T) {
+	var challenge string
+	srv := tokenServer(t, &challenge)
+	var a common.AuthClient = auth.NewSSOAuthenticator("app", "http://localhost/callback",
+		[]string{"esi-location.read_location.v1"}, auth.WithSSOEndpoints(srv.URL+"/authorize", srv.URL+"/token"))
+	sso := a.(*auth.SSOAuthenticator)
+
+	authz, err := sso.Authorize("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	u, err := url.Parse(authz.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if authz.State == "" || q.Get("state") != authz.State || q.Get("code_challenge_method") != "S256" ||
+		q.Get("scope") != "esi-location.read_location.v1" || q.Get("redirect_uri") != "http://localhost/callback" {
+		t.Fatalf("unexpected authorize URL: %s", authz.URL)
+	}
+	challenge = q.Get("code_challenge")
+
+	ctx := context.Background()
+	if _, err := sso.Callback(ctx, url.Values{"state": {"forged"}, "code": {"abc"}}, authz); !errors.Is(err, auth.ErrStateMismatch) {
+		t.Errorf("expected ErrStateMismatch, got %v", err)
+	}
+	if _, err := sso.Callback(ctx, url.Values{"state": {authz.State}, "error": {"access_denied"}}, authz); !errors.Is(err, auth.ErrAuthorizationDenied) {
+		t.Errorf("expected ErrAuthorizationDenied, got %v", err)
+	}
+	token, err := sso.Callback(ctx, url.Values{"state": {authz.State}, "code": {"abc"}}, authz)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token.AccessToken != "access-authorization_code" || token.RefreshToken != "refresh-1" || token.Expiry.IsZero() {
+		t.Errorf("unexpected token: %+v", token)
+	}
+	if _, err := sso.Exchange(ctx, "abc", "wrong-verifier"); err == nil {
+		t.Error("expected a wrong verifier to be rejected")
+	}
+
+	fresh, err := a.RefreshToken(token.RefreshToken)
+	if err != nil || fresh.AccessToken != "access-refresh_token" {
+		t.Errorf("unexpected refresh result: %+v, %v", fresh, err)
+	}
+}
@@ -0,0 +1,241 @@
+package auth
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// ErrNoToken is returned by TokenStore.Load for characters without a token.
+var ErrNoToken = errors.New("auth: no token stored")
+
+// TokenStore persists the tokens of several characters across restarts.
+type TokenStore interface {
+	// Save stores a character's token, replacing any earlier one.
+	Save(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) error
+	// Load returns a character's token or ErrNoToken.
+	Load(ctx context.Context, characterID model.CharacterID) (*oauth2.Token, error)
+	// Delete removes a character's token; deleting a missing one is not an
+	// error.
+	Delete(ctx context.Context, characterID model.CharacterID) error
+	// List returns the characters with a stored token, in ascending order.
+	List(ctx context.Context) ([]model.CharacterID, error)
+}
+
+// memoryTokenStore is a map-backed TokenStore.
+type memoryTokenStore struct {
+	mu     sync.RWMutex
+	tokens map[model.CharacterID]oauth2.Token
+}
+
+// NewMemoryTokenStore returns a TokenStore held in memory, e.g. for tests.
+func NewMemoryTokenStore() TokenStore {
+	return &memoryTokenStore{tokens: make(map[model.CharacterID]oauth2.Token)}
+}
+
+func (s *memoryTokenStore) Save(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[characterID] = *token
+	return nil
+}
+
+func (s *memoryTokenStore) Load(ctx context.Context, characterID model.CharacterID) (*oauth2.Token, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t, ok := s.tokens[characterID]
+	if !ok {
+		return nil, ErrNoToken
+	}
+	return &t, nil
+}
+
+func (s *memoryTokenStore) Delete(ctx context.Context, characterID model.CharacterID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tokens, characterID)
+	return nil
+}
+
+func (s *memoryTokenStore) List(ctx context.Context) ([]model.CharacterID, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ids := make([]model.CharacterID, 0, len(s.tokens))
+	for id := range s.tokens {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+// fileTokenStore keeps every token in one file holding a model.Identities,
+// optionally sealed. Each call reads the file and each change rewrites it
+// through a temporary file, so a crash never leaves it half written.
+type fileTokenStore struct {
+	path string
+	seal func([]byte) ([]byte, error)
+	open func([]byte) ([]byte, error)
+
+	mu sync.Mutex
+}
+
+// NewFileTokenStore returns a TokenStore kept as plain JSON at path, created
+// with mode 0600 on the first Save. The tokens are readable by anyone who
+// can read the file.
+func NewFileTokenStore(path string) TokenStore {
+	plain := func(b []byte) ([]byte, error) { return b, nil }
+	return &fileTokenStore{path: path, seal: plain, open: plain}
+}
+
+// NewEncryptedFileTokenStore returns a TokenStore kept at path encrypted
+// with AES-GCM under key, which must be 16, 24 or 32 bytes long.
+func NewEncryptedFileTokenStore(path string, key []byte) (TokenStore, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token store key: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &fileTokenStore{
+		path: path,
+		seal: func(plain []byte) ([]byte, error) {
+			nonce := make([]byte, aead.NonceSize())
+			if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+				return nil, err
+			}
+			return aead.Seal(nonce, nonce, plain, nil), nil
+		},
+		open: func(sealed []byte) ([]byte, error) {
+			if len(sealed) < aead.NonceSize() {
+				return nil, errors.New("ciphertext too short")
+			}
+			nonce, data := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+			return aead.Open(nil, nonce, data, nil)
+		},
+	}, nil
+}
+
+func (s *fileTokenStore) Save(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids, err := s.read()
+	if err != nil {
+		return err
+	}
+	ids.Tokens[strconv.FormatInt(characterID, 10)] = *token
+	return s.write(ids)
+}
+
+func (s *fileTokenStore) Load(ctx context.Context, characterID model.CharacterID) (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	t, ok := ids.Tokens[strconv.FormatInt(characterID, 10)]
+	if !ok {
+		return nil, ErrNoToken
+	}
+	return &t, nil
+}
+
+func (s *fileTokenStore) Delete(ctx context.Context, characterID model.CharacterID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids, err := s.read()
+	if err != nil {
+		return err
+	}
+	key := strconv.FormatInt(characterID, 10)
+	if _, ok := ids.Tokens[key]; !ok {
+		return nil
+	}
+	delete(ids.Tokens, key)
+	return s.write(ids)
+}
+
+func (s *fileTokenStore) List(ctx context.Context) ([]model.CharacterID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]model.CharacterID, 0, len(ids.Tokens))
+	for k := range ids.Tokens {
+		id, err := strconv.ParseInt(k, 10, 64)
+		if err != nil {
+			continue // not written by this store
+		}
+		out = append(out, id)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out, nil
+}
+
+// read loads the file; a missing file is an empty store.
+func (s *fileTokenStore) read() (*model.Identities, error) {
+	ids := &model.Identities{Tokens: make(map[string]oauth2.Token)}
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ids, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token store: %w", err)
+	}
+	plain, err := s.open(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt token store: %w", err)
+	}
+	if err := json.Unmarshal(plain, ids); err != nil {
+		return nil, fmt.Errorf("failed to decode token store: %w", err)
+	}
+	if ids.Tokens == nil {
+		ids.Tokens = make(map[string]oauth2.Token)
+	}
+	return ids, nil
+}
+
+func (s *fileTokenStore) write(ids *model.Identities) error {
+	plain, err := json.Marshal(ids)
+	if err != nil {
+		return err
+	}
+	data, err := s.seal(plain)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt token store: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write token store: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write token store: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write token store: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write token store: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package auth_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/modules/auth"
+)
+
+func TestTokenStores(t *testing.T) {
+	dir := t.TempDir()
+	key := bytes.Repeat([]byte{7}, 32)
+	encrypted, err := auth.NewEncryptedFileTokenStore(filepath.Join(dir, "sealed.json"), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stores := map[string]auth.TokenStore{
+		"memory":    auth.NewMemoryTokenStore(),
+		"file":      auth.NewFileTokenStore(filepath.Join(dir, "plain.json")),
+		"encrypted": encrypted,
+	}
+	ctx := context.Background()
+	expiry := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	for name, s := range stores {
+		t.Run(name, func(t *testing.T) {
+			if _, err := s.Load(ctx, 90000001); !errors.Is(err, auth.ErrNoToken) {
+				t.Errorf("expected ErrNoToken, got %v", err)
+			}
+			for _, id := range []int64{90000002, 90000001} {
+				if err := s.Save(ctx, id, &oauth2.Token{AccessToken: "a", RefreshToken: "r", Expiry: expiry}); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			tok, err := s.Load(ctx, 90000001)
+			if err != nil || tok.RefreshToken != "r" || !tok.Expiry.Equal(expiry) {
+				t.Errorf("unexpected token %+v, %v", tok, err)
+			}
+			if err := s.Delete(ctx, 90000002); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ids, err := s.List(ctx); err != nil || len(ids) != 1 || ids[0] != 90000001 {
+				t.Errorf("unexpected list %v, %v", ids, err)
+			}
+		})
+	}
+
+	// a fresh store on the same file reloads its tokens
+	reopened, _ := auth.NewEncryptedFileTokenStore(filepath.Join(dir, "sealed.json"), key)
+	if tok, err := reopened.Load(ctx, 90000001); err != nil || tok.AccessToken != "a" {
+		t.Errorf("unexpected reloaded token %+v, %v", tok, err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "sealed.json"))
+	if bytes.Contains(data, []byte("refresh_token")) {
+		t.Error("expected the sealed file to be encrypted")
+	}
+	wrongKey, _ := auth.NewEncryptedFileTokenStore(filepath.Join(dir, "sealed.json"), bytes.Repeat([]byte{8}, 32))
+	if _, err := wrongKey.Load(ctx, 90000001); err == nil {
+		t.Error("expected the wrong key to fail")
+	}
+}
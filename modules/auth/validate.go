@@ -0,0 +1,345 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// EVE SSO token fields checked by Validate.
+var (
+	// Issuers are the iss values EVE SSO uses.
+	Issuers = []string{"login.eveonline.com", "https://login.eveonline.com"}
+	// Audience is the aud value every EVE SSO access token carries.
+	Audience = "EVE Online"
+)
+
+var (
+	// ErrInvalidToken is returned for malformed tokens and failed checks.
+	ErrInvalidToken = errors.New("auth: invalid token")
+	// ErrTokenExpired is returned for tokens past their expiry.
+	ErrTokenExpired = errors.New("auth: token expired")
+)
+
+// Claims is what a validated access token says about its character.
+type Claims struct {
+	CharacterID   model.CharacterID `json:"character_id"`
+	CharacterName string            `json:"character_name"`
+	Owner         string            `json:"owner"` // changes when the character is transferred
+	Scopes        []string          `json:"scopes"`
+	Issuer        string            `json:"issuer"`
+	ExpiresAt     time.Time         `json:"expires_at"`
+}
+
+// HasScope reports whether the token was granted scope.
+func (c *Claims) HasScope(scope string) bool {
+	return slices.Contains(c.Scopes, scope)
+}
+
+// ValidatorOption customizes a Validator.
+type ValidatorOption func(*Validator)
+
+// WithJWKSURL sets where signing keys are fetched (DefaultJWKSURL by default).
+func WithJWKSURL(url string) ValidatorOption {
+	return func(v *Validator) {
+		v.keys.url = url
+	}
+}
+
+// WithKeyTTL sets how long fetched keys are trusted before the JWKS is
+// fetched again (24h by default).
+func WithKeyTTL(d time.Duration) ValidatorOption {
+	return func(v *Validator) {
+		v.keys.ttl = d
+	}
+}
+
+// WithKeyRefreshInterval sets how soon after a fetch an unknown key ID may
+// trigger another one (1m by default).
+func WithKeyRefreshInterval(d time.Duration) ValidatorOption {
+	return func(v *Validator) {
+		v.keys.minRefresh = d
+	}
+}
+
+// WithResultCacheSize bounds how many validated tokens are remembered
+// (10000 by default; 0 disables the result cache).
+func WithResultCacheSize(n int) ValidatorOption {
+	return func(v *Validator) {
+		v.maxResults = n
+	}
+}
+
+// WithClock replaces time.Now, e.g. in tests.
+func WithClock(now func() time.Time) ValidatorOption {
+	return func(v *Validator) {
+		v.now = now
+		v.keys.now = now
+	}
+}
+
+// Validator verifies EVE SSO access tokens. Keys are cached for the key TTL
+// and refetched when a token names a key the cache does not hold; a
+// validated token's claims are cached until the token expires, so repeated
+// validations of the same token skip signature verification. A Validator is
+// safe for concurrent use.
+type Validator struct {
+	keys       *keySet
+	now        func() time.Time
+	maxResults int
+
+	mu      sync.Mutex
+	results map[[sha256.Size]byte]*Claims
+}
+
+// NewValidator constructs a Validator fetching keys with client.
+func NewValidator(client common.HttpClient, opts ...ValidatorOption) *Validator {
+	v := &Validator{
+		keys: &keySet{
+			url:        DefaultJWKSURL,
+			client:     client,
+			ttl:        24 * time.Hour,
+			minRefresh: time.Minute,
+			now:        time.Now,
+		},
+		now:        time.Now,
+		maxResults: 10000,
+		results:    make(map[[sha256.Size]byte]*Claims),
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// Validate verifies accessToken and returns its claims. Failures wrap
+// ErrInvalidToken, ErrTokenExpired or ErrUnknownKey.
+func (v *Validator) Validate(ctx context.Context, accessToken string) (*Claims, error) {
+	sum := sha256.Sum256([]byte(accessToken))
+	now := v.now()
+	v.mu.Lock()
+	cached, ok := v.results[sum]
+	v.mu.Unlock()
+	if ok {
+		if now.Before(cached.ExpiresAt) {
+			c := *cached
+			return &c, nil
+		}
+		v.mu.Lock()
+		delete(v.results, sum)
+		v.mu.Unlock()
+		return nil, ErrTokenExpired
+	}
+
+	claims, err := v.verify(ctx, accessToken, now)
+	if err != nil {
+		return nil, err
+	}
+	v.remember(sum, claims, now)
+	c := *claims
+	return &c, nil
+}
+
+// Invalidate forgets the cached keys and validation results, e.g. when CCP
+// announces a key rollover or a key is suspected compromised.
+func (v *Validator) Invalidate() {
+	v.keys.invalidate()
+	v.mu.Lock()
+	v.results = make(map[[sha256.Size]byte]*Claims)
+	v.mu.Unlock()
+}
+
+// InvalidateToken forgets the cached result for one token, e.g. after the
+// user logs out.
+func (v *Validator) InvalidateToken(accessToken string) {
+	sum := sha256.Sum256([]byte(accessToken))
+	v.mu.Lock()
+	delete(v.results, sum)
+	v.mu.Unlock()
+}
+
+// remember caches claims, making room by dropping expired results first and
+// then arbitrary ones.
+func (v *Validator) remember(sum [sha256.Size]byte, claims *Claims, now time.Time) {
+	if v.maxResults <= 0 {
+		return
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if len(v.results) >= v.maxResults {
+		for k, c := range v.results {
+			if !now.Before(c.ExpiresAt) {
+				delete(v.results, k)
+			}
+		}
+	}
+	for k := range v.results {
+		if len(v.results) < v.maxResults {
+			break
+		}
+		delete(v.results, k)
+	}
+	v.results[sum] = claims
+}
+
+// jwtHeader is the JOSE header of a token.
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// jwtClaims is the payload of an EVE SSO access token. scp and aud are a
+// string or a list depending on how many values they hold.
+type jwtClaims struct {
+	Sub   string          `json:"sub"`
+	Name  string          `json:"name"`
+	Owner string          `json:"owner"`
+	Iss   string          `json:"iss"`
+	Exp   int64           `json:"exp"`
+	Scp   json.RawMessage `json:"scp"`
+	Aud   json.RawMessage `json:"aud"`
+}
+
+// DecodeClaims reads the claims of accessToken WITHOUT verifying its
+// signature, issuer, audience or expiry. It suits pre-flight checks of a
+// token the caller obtained from the SSO itself, such as which scopes it
+// carries; use a Validator for tokens presented by someone else.
+func DecodeClaims(accessToken string) (*Claims, error) {
+	parts := strings.Split(accessToken, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: not a JWT", ErrInvalidToken)
+	}
+	var raw jwtClaims
+	if err := decodeSegment(parts[1], &raw); err != nil {
+		return nil, err
+	}
+	return raw.claims()
+}
+
+func (v *Validator) verify(ctx context.Context, token string, now time.Time) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: not a JWT", ErrInvalidToken)
+	}
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad signature encoding", ErrInvalidToken)
+	}
+	key, err := v.keys.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySignature(header.Alg, key, parts[0]+"."+parts[1], sig); err != nil {
+		return nil, err
+	}
+
+	var raw jwtClaims
+	if err := decodeSegment(parts[1], &raw); err != nil {
+		return nil, err
+	}
+	claims, err := raw.claims()
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(Issuers, raw.Iss) {
+		return nil, fmt.Errorf("%w: issuer %q", ErrInvalidToken, raw.Iss)
+	}
+	if !slices.Contains(stringOrList(raw.Aud), Audience) {
+		return nil, fmt.Errorf("%w: audience is not %q", ErrInvalidToken, Audience)
+	}
+	if !now.Before(claims.ExpiresAt) {
+		return nil, ErrTokenExpired
+	}
+	return claims, nil
+}
+
+// claims converts the raw payload, checking that its subject is a character.
+func (raw *jwtClaims) claims() (*Claims, error) {
+	id, ok := strings.CutPrefix(raw.Sub, "CHARACTER:EVE:")
+	if !ok {
+		return nil, fmt.Errorf("%w: subject %q is not a character", ErrInvalidToken, raw.Sub)
+	}
+	charID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: subject %q", ErrInvalidToken, raw.Sub)
+	}
+	return &Claims{
+		CharacterID:   charID,
+		CharacterName: raw.Name,
+		Owner:         raw.Owner,
+		Scopes:        stringOrList(raw.Scp),
+		Issuer:        raw.Iss,
+		ExpiresAt:     time.Unix(raw.Exp, 0),
+	}, nil
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return fmt.Errorf("%w: bad segment encoding", ErrInvalidToken)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	return nil
+}
+
+// verifySignature checks an RS256 or ES256 signature over signed.
+func verifySignature(alg string, key crypto.PublicKey, signed string, sig []byte) error {
+	digest := sha256.Sum256([]byte(signed))
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if alg != "RS256" {
+			break
+		}
+		if rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig) != nil {
+			return fmt.Errorf("%w: bad signature", ErrInvalidToken)
+		}
+		return nil
+	case *ecdsa.PublicKey:
+		if alg != "ES256" {
+			break
+		}
+		if len(sig) != 64 {
+			return fmt.Errorf("%w: bad signature", ErrInvalidToken)
+		}
+		r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+		if !ecdsa.Verify(k, digest[:], r, s) {
+			return fmt.Errorf("%w: bad signature", ErrInvalidToken)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: algorithm %q does not match the key", ErrInvalidToken, alg)
+}
+
+// stringOrList decodes a JSON string or list of strings.
+func stringOrList(raw json.RawMessage) []string {
+	var list []string
+	if json.Unmarshal(raw, &list) == nil {
+		return list
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil && s != "" {
+		return []string{s}
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package auth_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/modules/auth"
+)
+
+// jwksServer serves the public halves of keys and counts fetches.
+type jwksServer struct {
+	*httptest.Server
+	mu   sync.Mutex
+	keys map[string]*rsa.PrivateKey
+	hits int
+}
+
+func newJWKSServer(t *testing.T) *jwksServer {
+	s := &jwksServer{keys: make(map[string]*rsa.PrivateKey)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.hits++
+		var set struct {
+			Keys []map[string]string `json:"keys"`
+		}
+		for kid, k := range s.keys {
+			set.Keys = append(set.Keys, map[string]string{
+				"kid": kid, "kty": "RSA", "alg": "RS256",
+				"n": base64.RawURLEncoding.EncodeToString(k.N.Bytes()),
+				"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes()),
+			})
+		}
+		_ = json.NewEncoder(w).Encode(set)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *jwksServer) addKey(t *testing.T, kid string) *rsa.PrivateKey {
+	k, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.mu.Lock()
+	s.keys[kid] = k
+	s.mu.Unlock()
+	return k
+}
+
+func (s *jwksServer) fetches() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.hits
+}
+
+func sign(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	enc := func(v interface{}) string {
+		b, _ := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	signed := enc(map[string]string{"alg": "RS256", "kid": kid, "typ": "JWT"}) + "." + enc(claims)
+	digest := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestValidator(t *testing.T) {
+	srv := newJWKSServer(t)
+	key1 := srv.addKey(t, "key-1")
+	now := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	v := auth.NewValidator(common.NewEveHttpClient("UA", &http.Client{}),
+		auth.WithJWKSURL(srv.URL),
+		auth.WithClock(func() time.Time { return now }),
+	)
+	ctx := context.Background()
+	claims := func(exp time.Time) map[string]interface{} {
+		return map[string]interface{}{
+			"sub": "CHARACTER:EVE:90000001", "name": "Pilot", "owner": "abc",
+			"iss": "login.eveonline.com", "aud": []string{"client-id", "EVE Online"},
+			"exp": exp.Unix(), "scp": []string{"esi-assets.read_assets.v1", "esi-skills.read_skills.v1"},
+		}
+	}
+	token := sign(t, key1, "key-1", claims(now.Add(20*time.Minute)))
+
+	c, err := v.Validate(ctx, token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.CharacterID != 90000001 || c.CharacterName != "Pilot" || !c.HasScope("esi-skills.read_skills.v1") {
+		t.Errorf("unexpected claims: %+v", c)
+	}
+	if _, err := v.Validate(ctx, token); err != nil || srv.fetches() != 1 {
+		t.Errorf("expected a cached result and one JWKS fetch, got %d fetches, %v", srv.fetches(), err)
+	}
+
+	// a token signed with a key published after the last fetch
+	key2 := srv.addKey(t, "key-2")
+	rotated := sign(t, key2, "key-2", claims(now.Add(20*time.Minute)))
+	if _, err := v.Validate(ctx, rotated); !errors.Is(err, auth.ErrUnknownKey) {
+		t.Errorf("expected ErrUnknownKey within the refresh interval, got %v", err)
+	}
+	now = now.Add(2 * time.Minute)
+	if _, err := v.Validate(ctx, rotated); err != nil || srv.fetches() != 2 {
+		t.Errorf("expected the rotated key to be fetched, got %d fetches, %v", srv.fetches(), err)
+	}
+
+	tampered := token[:len(token)-4] + "AAAA"
+	if _, err := v.Validate(ctx, tampered); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("expected ErrInvalidToken, got %v", err)
+	}
+	expired := sign(t, key1, "key-1", claims(now.Add(-time.Minute)))
+	if _, err := v.Validate(ctx, expired); !errors.Is(err, auth.ErrTokenExpired) {
+		t.Errorf("expected ErrTokenExpired, got %v", err)
+	}
+
+	now = now.Add(30 * time.Minute)
+	if _, err := v.Validate(ctx, token); !errors.Is(err, auth.ErrTokenExpired) {
+		t.Errorf("expected the cached token to expire, got %v", err)
+	}
+
+	v.Invalidate()
+	fresh := sign(t, key1, "key-1", claims(now.Add(20*time.Minute)))
+	if _, err := v.Validate(ctx, fresh); err != nil || srv.fetches() != 3 {
+		t.Errorf("expected Invalidate to refetch the keys, got %d fetches, %v", srv.fetches(), err)
+	}
+}
+
+func TestValidator_MetadataDiscovery(t *testing.T) {
+	srv := newJWKSServer(t)
+	key := srv.addKey(t, "key-1")
+	md := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(auth.Metadata{Issuer: "https://login.eveonline.com", JWKSURI: srv.URL})
+	}))
+	t.Cleanup(md.Close)
+
+	v := auth.NewValidator(common.NewEveHttpClient("UA", &http.Client{}), auth.WithMetadataURL(md.URL))
+	token := sign(t, key, "key-1", map[string]interface{}{
+		"sub": "CHARACTER:EVE:90000001", "name": "Pilot", "iss": "https://login.eveonline.com",
+		"aud": "EVE Online", "exp": time.Now().Add(time.Minute).Unix(), "scp": "publicData",
+	})
+	c, err := v.Validate(context.Background(), token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.CharacterID != 90000001 || !c.HasScope("publicData") || srv.fetches() != 1 {
+		t.Errorf("unexpected claims %+v after %d fetches", c, srv.fetches())
+	}
+}
@@ -0,0 +1,208 @@
+package charts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// TimeFrame is a named window of time rendered as one tab of charts.
+type TimeFrame struct {
+	Name  string
+	Start time.Time // inclusive
+	End   time.Time // exclusive
+	// Open frames end at the moment they were resolved, like month to date
+	// or a rolling window. Dashboard.Add moves their End forward to take in
+	// later kills, up to Until when it is set.
+	Open  bool
+	Until time.Time
+}
+
+// accepts reports whether a kill at t belongs in f, counting the time an
+// open frame may still grow into.
+func (f TimeFrame) accepts(t time.Time) bool {
+	if !f.Open {
+		return inRange(t, f.Start, f.End)
+	}
+	return !t.Before(f.Start) && (f.Until.IsZero() || t.Before(f.Until))
+}
+
+// DefaultTimeFrames returns month-to-date and year-to-date windows ending at now (UTC).
+func DefaultTimeFrames(now time.Time) []TimeFrame {
+	return TimeFramesIn(now, time.UTC)
+}
+
+// TimeFramesIn returns month-to-date and year-to-date windows whose
+// boundaries fall on midnight in loc rather than EVE time. A nil loc is UTC.
+func TimeFramesIn(now time.Time, loc *time.Location) []TimeFrame {
+	if loc == nil {
+		loc = time.UTC
+	}
+	now = now.In(loc)
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc)
+	year := time.Date(now.Year(), 1, 1, 0, 0, 0, 0, loc)
+	return []TimeFrame{
+		{Name: "MTD", Start: month, End: now, Open: true, Until: month.AddDate(0, 1, 0)},
+		{Name: "YTD", Start: year, End: now, Open: true, Until: year.AddDate(1, 0, 0)},
+	}
+}
+
+// DailyFrames splits [start, end) into one frame per calendar day in loc,
+// named by date (2006-01-02). The first and last frames are clipped to
+// start and end. A nil loc is UTC.
+func DailyFrames(start, end time.Time, loc *time.Location) []TimeFrame {
+	if loc == nil {
+		loc = time.UTC
+	}
+	var out []TimeFrame
+	cur := start.In(loc)
+	for cur.Before(end) {
+		next := time.Date(cur.Year(), cur.Month(), cur.Day()+1, 0, 0, 0, 0, loc)
+		if next.After(end) {
+			next = end.In(loc)
+		}
+		out = append(out, TimeFrame{Name: cur.Format("2006-01-02"), Start: cur, End: next})
+		cur = next
+	}
+	return out
+}
+
+// Builder produces TemplateData from a KillmailSource and a set of charts.
+type Builder struct {
+	source            KillmailSource
+	charts            []model.Chart
+	trackedCharacters []model.CharacterID
+	lookup            func(int) string
+	location          *time.Location
+}
+
+// BuilderOption customizes a Builder.
+type BuilderOption func(*Builder)
+
+// WithTrackedCharacters sets ChartData.TrackedCharacters.
+func WithTrackedCharacters(ids []model.CharacterID) BuilderOption {
+	return func(b *Builder) {
+		b.trackedCharacters = ids
+	}
+}
+
+// WithLookup sets ChartData.LookupFunc, used by charts to turn IDs into names.
+func WithLookup(fn func(int) string) BuilderOption {
+	return func(b *Builder) {
+		b.lookup = fn
+	}
+}
+
+// WithLocation sets ChartData.Location so charts bucket by the given time
+// zone (e.g. a corp's local prime time) instead of EVE time.
+func WithLocation(loc *time.Location) BuilderOption {
+	return func(b *Builder) {
+		b.location = loc
+	}
+}
+
+// NewBuilder constructs a Builder. Use NewRepositorySource for offline generation.
+func NewBuilder(source KillmailSource, charts []model.Chart, opts ...BuilderOption) *Builder {
+	b := &Builder{source: source, charts: charts}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Build fetches the killmails covering every frame once, then prepares each
+// chart for each frame.
+func (b *Builder) Build(ctx context.Context, params *model.Params, frames []TimeFrame) (*model.TemplateData, error) {
+	if len(frames) == 0 {
+		return &model.TemplateData{}, nil
+	}
+	start, end := span(frames)
+
+	kills, err := b.source.KillMails(ctx, params, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load killmails: %w", err)
+	}
+
+	out := &model.TemplateData{}
+	for _, f := range frames {
+		tf, err := b.BuildFrame(f, params, kills)
+		if err != nil {
+			return nil, err
+		}
+		out.TimeFrames = append(out.TimeFrames, tf)
+	}
+	return out, nil
+}
+
+// BuildFrame prepares every chart for one frame from an already loaded kill set.
+func (b *Builder) BuildFrame(f TimeFrame, params *model.Params, kills []model.FlattenedKillMail) (model.TimeFrameData, error) {
+	return b.prepareFrame(f, b.chartData(params, framed(kills, f)))
+}
+
+// prepareFrame runs every chart against the frame's ChartData.
+func (b *Builder) prepareFrame(f TimeFrame, data *model.ChartData) (model.TimeFrameData, error) {
+	tf := model.TimeFrameData{Name: f.Name}
+	for _, c := range b.charts {
+		entry, err := prepareEntry(c, f.Name, data)
+		if err != nil {
+			return tf, err
+		}
+		tf.Charts = append(tf.Charts, entry)
+	}
+	return tf, nil
+}
+
+func (b *Builder) chartData(params *model.Params, kills []model.FlattenedKillMail) *model.ChartData {
+	cd := &model.ChartData{
+		KillMails:         kills,
+		TrackedCharacters: b.trackedCharacters,
+		LookupFunc:        b.lookup,
+		Location:          b.location,
+	}
+	if params != nil && params.EsiData != nil {
+		cd.ESIData = *params.EsiData
+	}
+	return cd
+}
+
+// prepareEntry runs a chart's PrepareFunc and marshals the result into a ChartEntry.
+func prepareEntry(c model.Chart, frameName string, data *model.ChartData) (model.ChartEntry, error) {
+	raw, err := json.Marshal(c.PrepareFunc(data))
+	if err != nil {
+		return model.ChartEntry{}, fmt.Errorf("failed to marshal chart %s: %w", c.FieldPrefix, err)
+	}
+	return model.ChartEntry{
+		Name: c.Description,
+		ID:   fmt.Sprintf("%s_%s", c.FieldPrefix, frameName),
+		Data: template.JS(raw),
+		Type: c.Type,
+	}, nil
+}
+
+// span returns the earliest start and latest end across frames.
+func span(frames []TimeFrame) (time.Time, time.Time) {
+	start, end := frames[0].Start, frames[0].End
+	for _, f := range frames[1:] {
+		if f.Start.Before(start) {
+			start = f.Start
+		}
+		if f.End.After(end) {
+			end = f.End
+		}
+	}
+	return start, end
+}
+
+func framed(kills []model.FlattenedKillMail, f TimeFrame) []model.FlattenedKillMail {
+	var out []model.FlattenedKillMail
+	for _, km := range kills {
+		if inRange(km.KillMailTime, f.Start, f.End) {
+			out = append(out, km)
+		}
+	}
+	return out
+}
@@ -0,0 +1,83 @@
+package charts_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/charts"
+	"github.com/guarzo/eveapi/modules/storage"
+)
+
+func TestBuilder_OfflineFromRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := storage.NewMemoryKillmailRepository()
+	now := time.Date(2024, 10, 15, 0, 0, 0, 0, time.UTC)
+	_ = repo.Save(ctx,
+		model.FlattenedKillMail{KillMailID: 1, KillMailTime: time.Date(2024, 10, 2, 0, 0, 0, 0, time.UTC),
+			Attackers: []model.Attacker{{CharacterID: 7}}},
+		model.FlattenedKillMail{KillMailID: 2, KillMailTime: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+			Victim: model.Victim{CharacterID: 7}},
+		model.FlattenedKillMail{KillMailID: 3, KillMailTime: time.Date(2024, 10, 3, 0, 0, 0, 0, time.UTC),
+			Victim: model.Victim{CharacterID: 99}},
+	)
+
+	count := model.Chart{
+		FieldPrefix: "killCount",
+		Description: "Kill count",
+		Type:        "bar",
+		PrepareFunc: func(cd *model.ChartData) interface{} { return len(cd.KillMails) },
+	}
+
+	b := charts.NewBuilder(charts.NewRepositorySource(repo), []model.Chart{count})
+	out, err := b.Build(ctx, &model.Params{Characters: []model.CharacterID{7}}, charts.DefaultTimeFrames(now))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out.TimeFrames) != 2 {
+		t.Fatalf("expected 2 time frames, got %d", len(out.TimeFrames))
+	}
+	mtd, ytd := out.TimeFrames[0], out.TimeFrames[1]
+	if mtd.Charts[0].ID != "killCount_MTD" || string(mtd.Charts[0].Data) != "1" {
+		t.Errorf("unexpected MTD chart: %#v", mtd.Charts[0])
+	}
+	if string(ytd.Charts[0].Data) != "2" {
+		t.Errorf("unexpected YTD chart: %#v", ytd.Charts[0])
+	}
+}
+
+func TestTimeFramesIn_UsesLocalBoundaries(t *testing.T) {
+	loc := time.FixedZone("UTC-5", -5*3600)
+	// 2024-11-01 03:00 UTC is still October 31st in UTC-5.
+	now := time.Date(2024, 11, 1, 3, 0, 0, 0, time.UTC)
+
+	frames := charts.TimeFramesIn(now, loc)
+	wantMTD := time.Date(2024, 10, 1, 0, 0, 0, 0, loc)
+	if !frames[0].Start.Equal(wantMTD) {
+		t.Errorf("expected MTD start %v, got %v", wantMTD, frames[0].Start)
+	}
+	if utc := charts.DefaultTimeFrames(now); utc[0].Start.Month() != time.November {
+		t.Errorf("expected UTC MTD to start in November, got %v", utc[0].Start)
+	}
+}
+
+func TestDailyFrames(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*3600)
+	start := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 10, 3, 12, 0, 0, 0, time.UTC)
+
+	frames := charts.DailyFrames(start, end, loc)
+	if len(frames) != 3 {
+		t.Fatalf("expected 3 frames, got %d", len(frames))
+	}
+	if frames[0].Name != "2024-10-01" || frames[2].Name != "2024-10-03" {
+		t.Errorf("unexpected names: %s, %s", frames[0].Name, frames[2].Name)
+	}
+	if !frames[1].Start.Equal(time.Date(2024, 10, 2, 0, 0, 0, 0, loc)) {
+		t.Errorf("unexpected day boundary: %v", frames[1].Start)
+	}
+	if !frames[2].End.Equal(end) {
+		t.Errorf("expected last frame clipped to end, got %v", frames[2].End)
+	}
+}
@@ -0,0 +1,3 @@
+// Package charts turns killmail datasets into the Chart/TimeFrame structures
+// rendered by front-ends, reading either from zKillboard or from storage.
+package charts
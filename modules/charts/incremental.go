@@ -0,0 +1,113 @@
+package charts
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// UpdateChartData merges newKills into existing, skipping killmails already
+// present and keeping KillMails ordered by time. It returns the number added.
+func UpdateChartData(existing *model.ChartData, newKills []model.FlattenedKillMail) int {
+	seen := make(map[int64]bool, len(existing.KillMails))
+	for _, km := range existing.KillMails {
+		seen[km.KillMailID] = true
+	}
+	added := 0
+	for _, km := range newKills {
+		if seen[km.KillMailID] {
+			continue
+		}
+		seen[km.KillMailID] = true
+		existing.KillMails = append(existing.KillMails, km)
+		added++
+	}
+	if added > 0 {
+		sort.SliceStable(existing.KillMails, func(i, j int) bool {
+			return existing.KillMails[i].KillMailTime.Before(existing.KillMails[j].KillMailTime)
+		})
+	}
+	return added
+}
+
+// Dashboard keeps the per-frame ChartData of a built TemplateData so that
+// streamed kills only re-prepare the frames they fall into.
+type Dashboard struct {
+	mu      sync.RWMutex
+	builder *Builder
+	frames  []TimeFrame
+	data    []*model.ChartData
+	out     model.TemplateData
+}
+
+// NewDashboard performs an initial full build and returns a Dashboard ready for updates.
+func (b *Builder) NewDashboard(ctx context.Context, params *model.Params, frames []TimeFrame) (*Dashboard, error) {
+	d := &Dashboard{builder: b, frames: append([]TimeFrame(nil), frames...)}
+	if len(frames) == 0 {
+		return d, nil
+	}
+
+	start, end := span(frames)
+	kills, err := b.source.KillMails(ctx, params, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load killmails: %w", err)
+	}
+
+	for _, f := range frames {
+		cd := b.chartData(params, framed(kills, f))
+		tf, err := b.prepareFrame(f, cd)
+		if err != nil {
+			return nil, err
+		}
+		d.data = append(d.data, cd)
+		d.out.TimeFrames = append(d.out.TimeFrames, tf)
+	}
+	return d, nil
+}
+
+// Add merges newKills into every frame whose window they fall in and re-prepares
+// only those frames. Open frames, such as month to date, grow to take in kills
+// that happened after the dashboard was built. It returns the names of the
+// frames that changed.
+func (d *Dashboard) Add(newKills []model.FlattenedKillMail) ([]string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var updated []string
+	for i := range d.frames {
+		f := &d.frames[i]
+		var inFrame []model.FlattenedKillMail
+		for _, km := range newKills {
+			if !f.accepts(km.KillMailTime) {
+				continue
+			}
+			inFrame = append(inFrame, km)
+			if !km.KillMailTime.Before(f.End) {
+				f.End = km.KillMailTime.Add(time.Nanosecond)
+			}
+		}
+		if len(inFrame) == 0 || UpdateChartData(d.data[i], inFrame) == 0 {
+			continue
+		}
+		tf, err := d.builder.prepareFrame(*f, d.data[i])
+		if err != nil {
+			return updated, err
+		}
+		d.out.TimeFrames[i] = tf
+		updated = append(updated, f.Name)
+	}
+	return updated, nil
+}
+
+// TemplateData returns a copy of the current rendered frames.
+func (d *Dashboard) TemplateData() model.TemplateData {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	frames := make([]model.TimeFrameData, len(d.out.TimeFrames))
+	copy(frames, d.out.TimeFrames)
+	return model.TemplateData{TimeFrames: frames}
+}
@@ -0,0 +1,89 @@
+package charts_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/charts"
+	"github.com/guarzo/eveapi/modules/storage"
+)
+
+func TestUpdateChartData(t *testing.T) {
+	t0 := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	cd := &model.ChartData{KillMails: []model.FlattenedKillMail{{KillMailID: 1, KillMailTime: t0.Add(time.Hour)}}}
+
+	added := charts.UpdateChartData(cd, []model.FlattenedKillMail{
+		{KillMailID: 1, KillMailTime: t0.Add(time.Hour)},
+		{KillMailID: 2, KillMailTime: t0},
+	})
+	if added != 1 || len(cd.KillMails) != 2 || cd.KillMails[0].KillMailID != 2 {
+		t.Errorf("unexpected merge result (%d): %#v", added, cd.KillMails)
+	}
+}
+
+func TestDashboard_AddOnlyTouchesAffectedFrames(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 10, 15, 0, 0, 0, 0, time.UTC)
+	repo := storage.NewMemoryKillmailRepository()
+
+	count := model.Chart{
+		FieldPrefix: "count",
+		PrepareFunc: func(cd *model.ChartData) interface{} { return len(cd.KillMails) },
+	}
+	frames := []charts.TimeFrame{
+		{Name: "Oct", Start: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC), End: now},
+		{Name: "Sep", Start: time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	b := charts.NewBuilder(charts.NewRepositorySource(repo), []model.Chart{count})
+	d, err := b.NewDashboard(ctx, &model.Params{Characters: []model.CharacterID{7}}, frames)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	updated, err := d.Add([]model.FlattenedKillMail{{KillMailID: 5, KillMailTime: now.Add(-time.Hour)}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(updated, []string{"Oct"}) {
+		t.Errorf("expected only Oct to be updated, got %v", updated)
+	}
+	td := d.TemplateData()
+	if string(td.TimeFrames[0].Charts[0].Data) != "1" || string(td.TimeFrames[1].Charts[0].Data) != "0" {
+		t.Errorf("unexpected chart data: %s / %s", td.TimeFrames[0].Charts[0].Data, td.TimeFrames[1].Charts[0].Data)
+	}
+}
+
+func TestDashboard_AddGrowsOpenFrames(t *testing.T) {
+	ctx := context.Background()
+	built := time.Date(2024, 10, 15, 12, 0, 0, 0, time.UTC)
+	repo := storage.NewMemoryKillmailRepository()
+
+	count := model.Chart{
+		FieldPrefix: "count",
+		PrepareFunc: func(cd *model.ChartData) interface{} { return len(cd.KillMails) },
+	}
+	b := charts.NewBuilder(charts.NewRepositorySource(repo), []model.Chart{count})
+	d, err := b.NewDashboard(ctx, &model.Params{Characters: []model.CharacterID{7}}, charts.DefaultTimeFrames(built))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	updated, err := d.Add([]model.FlattenedKillMail{
+		{KillMailID: 5, KillMailTime: built.Add(time.Hour)},
+		{KillMailID: 6, KillMailTime: time.Date(2024, 11, 2, 0, 0, 0, 0, time.UTC)}, // past MTD, within YTD
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(updated, []string{"MTD", "YTD"}) {
+		t.Errorf("expected both open frames to be updated, got %v", updated)
+	}
+	td := d.TemplateData()
+	if string(td.TimeFrames[0].Charts[0].Data) != "1" || string(td.TimeFrames[1].Charts[0].Data) != "2" {
+		t.Errorf("unexpected chart data: %s / %s", td.TimeFrames[0].Charts[0].Data, td.TimeFrames[1].Charts[0].Data)
+	}
+}
@@ -0,0 +1,123 @@
+package charts
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// ChartRegistry holds charts by name so applications can list them and let
+// users pick which ones to build at runtime.
+type ChartRegistry struct {
+	mu     sync.RWMutex
+	charts map[string]model.Chart
+	order  []string
+}
+
+// NewChartRegistry returns an empty registry.
+func NewChartRegistry() *ChartRegistry {
+	return &ChartRegistry{charts: make(map[string]model.Chart)}
+}
+
+// Register adds a chart under name. Names must be unique and the chart must
+// have a PrepareFunc.
+func (r *ChartRegistry) Register(name string, c model.Chart) error {
+	if name == "" {
+		return fmt.Errorf("chart name is required")
+	}
+	if c.PrepareFunc == nil {
+		return fmt.Errorf("chart %q has no PrepareFunc", name)
+	}
+	if c.FieldPrefix == "" {
+		c.FieldPrefix = name
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.charts[name]; exists {
+		return fmt.Errorf("chart %q already registered", name)
+	}
+	r.charts[name] = c
+	r.order = append(r.order, name)
+	return nil
+}
+
+// MustRegister is Register that panics on error, for package-level setup.
+func (r *ChartRegistry) MustRegister(name string, c model.Chart) {
+	if err := r.Register(name, c); err != nil {
+		panic(err)
+	}
+}
+
+// Unregister removes a chart; unknown names are ignored.
+func (r *ChartRegistry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.charts[name]; !ok {
+		return
+	}
+	delete(r.charts, name)
+	for i, n := range r.order {
+		if n == name {
+			r.order = append(r.order[:i], r.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// Get returns the chart registered under name.
+func (r *ChartRegistry) Get(name string) (model.Chart, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.charts[name]
+	return c, ok
+}
+
+// ChartInfo describes a registered chart for listing in a UI.
+type ChartInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Type        string `json:"type"`
+}
+
+// List returns every registered chart in registration order.
+func (r *ChartRegistry) List() []ChartInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]ChartInfo, 0, len(r.order))
+	for _, name := range r.order {
+		c := r.charts[name]
+		out = append(out, ChartInfo{Name: name, Description: c.Description, Type: c.Type})
+	}
+	return out
+}
+
+// Names returns the registered names sorted alphabetically.
+func (r *ChartRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := append([]string(nil), r.order...)
+	sort.Strings(out)
+	return out
+}
+
+// Select returns the charts for the given names in that order, failing on
+// unknown names. With no names it returns every chart in registration order.
+func (r *ChartRegistry) Select(names ...string) ([]model.Chart, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(names) == 0 {
+		names = r.order
+	}
+	out := make([]model.Chart, 0, len(names))
+	for _, name := range names {
+		c, ok := r.charts[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown chart %q", name)
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
@@ -0,0 +1,40 @@
+package charts_test
+
+import (
+	"testing"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/charts"
+)
+
+func TestChartRegistry(t *testing.T) {
+	noop := func(*model.ChartData) interface{} { return nil }
+	r := charts.NewChartRegistry()
+	r.MustRegister("kills", model.Chart{Description: "Kills", Type: "bar", PrepareFunc: noop})
+	r.MustRegister("losses", model.Chart{Description: "Losses", Type: "line", PrepareFunc: noop})
+
+	if err := r.Register("kills", model.Chart{PrepareFunc: noop}); err == nil {
+		t.Error("expected duplicate registration to fail")
+	}
+	if err := r.Register("empty", model.Chart{}); err == nil {
+		t.Error("expected registration without PrepareFunc to fail")
+	}
+
+	list := r.List()
+	if len(list) != 2 || list[0].Name != "kills" || list[1].Type != "line" {
+		t.Errorf("unexpected list: %#v", list)
+	}
+
+	sel, err := r.Select("losses")
+	if err != nil || len(sel) != 1 || sel[0].FieldPrefix != "losses" {
+		t.Errorf("unexpected selection: %#v, %v", sel, err)
+	}
+	if _, err := r.Select("nope"); err == nil {
+		t.Error("expected unknown chart to fail")
+	}
+
+	r.Unregister("kills")
+	if all, _ := r.Select(); len(all) != 1 {
+		t.Errorf("expected 1 chart after unregister, got %d", len(all))
+	}
+}
@@ -0,0 +1,168 @@
+package charts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+
+	gochart "github.com/wcharczuk/go-chart/v2"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// Format selects the output of the renderer.
+type Format string
+
+const (
+	PNG Format = "png"
+	SVG Format = "svg"
+)
+
+// Dataset is the labels/datasets JSON shape charts produce for front-ends and
+// the shape Render understands.
+type Dataset struct {
+	Labels   []string `json:"labels"`
+	Datasets []Series `json:"datasets"`
+}
+
+// Series is one named row of values in a Dataset.
+type Series struct {
+	Label string    `json:"label"`
+	Data  []float64 `json:"data"`
+}
+
+// RenderOptions controls image size; zero values use 800x400.
+type RenderOptions struct {
+	Width  int
+	Height int
+	Title  string
+}
+
+// Render prepares c against data and draws it, e.g. for Discord embeds or email.
+// The chart's PrepareFunc must return a value that marshals to the Dataset shape.
+func Render(c model.Chart, data *model.ChartData, format Format, opts RenderOptions) ([]byte, error) {
+	raw, err := json.Marshal(c.PrepareFunc(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal chart %s: %w", c.FieldPrefix, err)
+	}
+	if opts.Title == "" {
+		opts.Title = c.Description
+	}
+	return RenderEntry(model.ChartEntry{Name: c.Description, ID: c.FieldPrefix, Data: template.JS(raw), Type: c.Type}, format, opts)
+}
+
+// RenderEntry draws an already prepared ChartEntry. Supported types are "bar",
+// "line" and "pie"; pie and single-series bar charts use the first dataset.
+func RenderEntry(entry model.ChartEntry, format Format, opts RenderOptions) ([]byte, error) {
+	var ds Dataset
+	if err := json.Unmarshal([]byte(entry.Data), &ds); err != nil {
+		return nil, fmt.Errorf("chart %s is not in labels/datasets shape: %w", entry.ID, err)
+	}
+	if len(ds.Datasets) == 0 {
+		return nil, fmt.Errorf("chart %s has no datasets", entry.ID)
+	}
+	if opts.Width == 0 {
+		opts.Width = 800
+	}
+	if opts.Height == 0 {
+		opts.Height = 400
+	}
+	if opts.Title == "" {
+		opts.Title = entry.Name
+	}
+
+	var provider gochart.RendererProvider
+	switch format {
+	case PNG:
+		provider = gochart.PNG
+	case SVG:
+		provider = gochart.SVG
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+
+	var buf bytes.Buffer
+	var err error
+	switch entry.Type {
+	case "bar", "":
+		err = renderBar(&buf, provider, ds, opts)
+	case "line":
+		err = renderLine(&buf, provider, ds, opts)
+	case "pie":
+		err = renderPie(&buf, provider, ds, opts)
+	default:
+		return nil, fmt.Errorf("unsupported chart type %q", entry.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to render chart %s: %w", entry.ID, err)
+	}
+	return buf.Bytes(), nil
+}
+
+func renderBar(buf *bytes.Buffer, p gochart.RendererProvider, ds Dataset, opts RenderOptions) error {
+	if len(ds.Datasets) > 1 {
+		var bars []gochart.StackedBar
+		for i, label := range ds.Labels {
+			bar := gochart.StackedBar{Name: label}
+			for _, s := range ds.Datasets {
+				bar.Values = append(bar.Values, gochart.Value{Label: s.Label, Value: valueAt(s.Data, i)})
+			}
+			bars = append(bars, bar)
+		}
+		sbc := gochart.StackedBarChart{Title: opts.Title, Width: opts.Width, Height: opts.Height, Bars: bars}
+		return sbc.Render(p, buf)
+	}
+
+	var values []gochart.Value
+	for i, label := range ds.Labels {
+		values = append(values, gochart.Value{Label: label, Value: valueAt(ds.Datasets[0].Data, i)})
+	}
+	bc := gochart.BarChart{Title: opts.Title, Width: opts.Width, Height: opts.Height, Bars: values}
+	return bc.Render(p, buf)
+}
+
+func renderLine(buf *bytes.Buffer, p gochart.RendererProvider, ds Dataset, opts RenderOptions) error {
+	xs := make([]float64, len(ds.Labels))
+	ticks := make([]gochart.Tick, len(ds.Labels))
+	for i, label := range ds.Labels {
+		xs[i] = float64(i)
+		ticks[i] = gochart.Tick{Value: float64(i), Label: label}
+	}
+
+	c := gochart.Chart{
+		Title:  opts.Title,
+		Width:  opts.Width,
+		Height: opts.Height,
+		XAxis:  gochart.XAxis{Ticks: ticks},
+	}
+	for _, s := range ds.Datasets {
+		ys := make([]float64, len(xs))
+		for i := range xs {
+			ys[i] = valueAt(s.Data, i)
+		}
+		c.Series = append(c.Series, gochart.ContinuousSeries{Name: s.Label, XValues: xs, YValues: ys})
+	}
+	if len(ds.Datasets) > 1 {
+		c.Elements = []gochart.Renderable{gochart.Legend(&c)}
+	}
+	return c.Render(p, buf)
+}
+
+func renderPie(buf *bytes.Buffer, p gochart.RendererProvider, ds Dataset, opts RenderOptions) error {
+	var values []gochart.Value
+	for i, label := range ds.Labels {
+		if v := valueAt(ds.Datasets[0].Data, i); v > 0 {
+			values = append(values, gochart.Value{Label: label, Value: v})
+		}
+	}
+	pc := gochart.PieChart{Title: opts.Title, Width: opts.Width, Height: opts.Height, Values: values}
+	return pc.Render(p, buf)
+}
+
+func valueAt(data []float64, i int) float64 {
+	if i < len(data) {
+		return data[i]
+	}
+	return 0
+}
@@ -0,0 +1,45 @@
+package charts_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/charts"
+)
+
+func TestRender(t *testing.T) {
+	prepare := func(cd *model.ChartData) interface{} {
+		return charts.Dataset{
+			Labels:   []string{"Mon", "Tue", "Wed"},
+			Datasets: []charts.Series{{Label: "Kills", Data: []float64{3, 5, 2}}},
+		}
+	}
+
+	for _, typ := range []string{"bar", "line", "pie"} {
+		c := model.Chart{FieldPrefix: "daily", Description: "Kills per day", Type: typ, PrepareFunc: prepare}
+
+		png, err := charts.Render(c, &model.ChartData{}, charts.PNG, charts.RenderOptions{})
+		if err != nil {
+			t.Fatalf("%s: unexpected PNG error: %v", typ, err)
+		}
+		if !bytes.HasPrefix(png, []byte("\x89PNG")) {
+			t.Errorf("%s: output is not a PNG", typ)
+		}
+
+		svg, err := charts.Render(c, &model.ChartData{}, charts.SVG, charts.RenderOptions{Width: 400, Height: 200})
+		if err != nil {
+			t.Fatalf("%s: unexpected SVG error: %v", typ, err)
+		}
+		if !bytes.Contains(svg, []byte("<svg")) {
+			t.Errorf("%s: output is not an SVG", typ)
+		}
+	}
+}
+
+func TestRenderEntry_RejectsUnknownShape(t *testing.T) {
+	entry := model.ChartEntry{ID: "x", Type: "bar", Data: `{"foo":1}`}
+	if _, err := charts.RenderEntry(entry, charts.PNG, charts.RenderOptions{}); err == nil {
+		t.Error("expected error for chart without datasets")
+	}
+}
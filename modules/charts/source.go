@@ -0,0 +1,120 @@
+package charts
+
+import (
+	"context"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/storage"
+	"github.com/guarzo/eveapi/modules/zkill"
+)
+
+// KillmailSource supplies the killmails for the tracked entities in params
+// between start (inclusive) and end (exclusive).
+type KillmailSource interface {
+	KillMails(ctx context.Context, params *model.Params, start, end time.Time) ([]model.FlattenedKillMail, error)
+}
+
+// liveSource fetches month by month from zKillboard.
+type liveSource struct {
+	zkill zkill.ZKillService
+}
+
+// NewLiveSource returns a KillmailSource backed by zKillboard (and ESI, via the service).
+func NewLiveSource(svc zkill.ZKillService) KillmailSource {
+	return &liveSource{zkill: svc}
+}
+
+func (s *liveSource) KillMails(ctx context.Context, params *model.Params, start, end time.Time) ([]model.FlattenedKillMail, error) {
+	seen := make(map[int64]bool)
+	var out []model.FlattenedKillMail
+	for month := monthStart(start); month.Before(end); month = month.AddDate(0, 1, 0) {
+		kills, err := s.zkill.GetKillMailDataForMonth(ctx, params, month.Year(), int(month.Month()))
+		if err != nil {
+			return nil, err
+		}
+		for _, km := range kills {
+			if seen[km.KillMailID] || !inRange(km.KillMailTime, start, end) {
+				continue
+			}
+			seen[km.KillMailID] = true
+			out = append(out, km)
+		}
+	}
+	return out, nil
+}
+
+// repositorySource reads stored killmails, making no network calls at all.
+type repositorySource struct {
+	repo storage.KillmailRepository
+}
+
+// NewRepositorySource returns a KillmailSource that reads from repo, so charts
+// can be regenerated offline.
+func NewRepositorySource(repo storage.KillmailRepository) KillmailSource {
+	return &repositorySource{repo: repo}
+}
+
+func (s *repositorySource) KillMails(ctx context.Context, params *model.Params, start, end time.Time) ([]model.FlattenedKillMail, error) {
+	groups := []struct {
+		kind string
+		ids  []int64
+	}{
+		{storage.EntityCharacter, params.Characters},
+		{storage.EntityCorporation, params.Corporations},
+		{storage.EntityAlliance, params.Alliances},
+	}
+
+	seen := make(map[int64]bool)
+	var out []model.FlattenedKillMail
+	for _, g := range groups {
+		if len(g.ids) == 0 {
+			continue
+		}
+		kills, err := s.repo.Query(ctx, storage.KillmailQuery{Start: start, End: end, EntityType: g.kind, EntityIDs: g.ids})
+		if err != nil {
+			return nil, err
+		}
+		for _, km := range kills {
+			if !seen[km.KillMailID] {
+				seen[km.KillMailID] = true
+				out = append(out, km)
+			}
+		}
+	}
+	storage.SortKillmails(out)
+	return out, nil
+}
+
+// recordingSource passes through to another source and stores everything it returns.
+type recordingSource struct {
+	next KillmailSource
+	repo storage.KillmailRepository
+}
+
+// NewRecordingSource wraps next (typically a live source) and saves every
+// killmail it returns into repo, populating it for later offline runs.
+func NewRecordingSource(next KillmailSource, repo storage.KillmailRepository) KillmailSource {
+	return &recordingSource{next: next, repo: repo}
+}
+
+func (s *recordingSource) KillMails(ctx context.Context, params *model.Params, start, end time.Time) ([]model.FlattenedKillMail, error) {
+	kills, err := s.next.KillMails(ctx, params, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if len(kills) > 0 {
+		if err := s.repo.Save(ctx, kills...); err != nil {
+			return nil, err
+		}
+	}
+	return kills, nil
+}
+
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+func inRange(t, start, end time.Time) bool {
+	return !t.Before(start) && t.Before(end)
+}
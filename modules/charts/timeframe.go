@@ -0,0 +1,87 @@
+package charts
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// TimeFrameSpec defines a named time frame relative to when charts are built.
+// Either Start/End are set for a fixed window ("Deployment Week 3"), or
+// Rolling is set for a window ending now ("Last 30 days"). A zero End on a
+// fixed window means now.
+type TimeFrameSpec struct {
+	Name    string
+	Start   time.Time
+	End     time.Time
+	Rolling time.Duration
+}
+
+// FixedFrame defines a window with explicit boundaries.
+func FixedFrame(name string, start, end time.Time) TimeFrameSpec {
+	return TimeFrameSpec{Name: name, Start: start, End: end}
+}
+
+// RollingFrame defines a window covering the d before now.
+func RollingFrame(name string, d time.Duration) TimeFrameSpec {
+	return TimeFrameSpec{Name: name, Rolling: d}
+}
+
+// LastDays is a RollingFrame of n days named "Last n days".
+func LastDays(n int) TimeFrameSpec {
+	return RollingFrame(fmt.Sprintf("Last %d days", n), time.Duration(n)*24*time.Hour)
+}
+
+// Resolve turns the spec into a concrete TimeFrame as of now.
+func (s TimeFrameSpec) Resolve(now time.Time) (TimeFrame, error) {
+	if s.Name == "" {
+		return TimeFrame{}, fmt.Errorf("time frame name is required")
+	}
+	if s.Rolling > 0 {
+		if !s.Start.IsZero() || !s.End.IsZero() {
+			return TimeFrame{}, fmt.Errorf("time frame %q: rolling window cannot also set start/end", s.Name)
+		}
+		return TimeFrame{Name: s.Name, Start: now.Add(-s.Rolling), End: now, Open: true}, nil
+	}
+	if s.Start.IsZero() {
+		return TimeFrame{}, fmt.Errorf("time frame %q: start or rolling window is required", s.Name)
+	}
+	end, open := s.End, s.End.IsZero()
+	if open {
+		end = now
+	}
+	if !end.After(s.Start) {
+		return TimeFrame{}, fmt.Errorf("time frame %q: end must be after start", s.Name)
+	}
+	return TimeFrame{Name: s.Name, Start: s.Start, End: end, Open: open}, nil
+}
+
+// ResolveTimeFrames resolves every spec as of now, rejecting duplicate names
+// since they would collide in ChartEntry IDs.
+func ResolveTimeFrames(now time.Time, specs []TimeFrameSpec) ([]TimeFrame, error) {
+	seen := make(map[string]bool, len(specs))
+	out := make([]TimeFrame, 0, len(specs))
+	for _, s := range specs {
+		if seen[s.Name] {
+			return nil, fmt.Errorf("duplicate time frame %q", s.Name)
+		}
+		seen[s.Name] = true
+		f, err := s.Resolve(now)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, f)
+	}
+	return out, nil
+}
+
+// BuildSpecs resolves specs as of now and builds every chart for them.
+func (b *Builder) BuildSpecs(ctx context.Context, params *model.Params, now time.Time, specs []TimeFrameSpec) (*model.TemplateData, error) {
+	frames, err := ResolveTimeFrames(now, specs)
+	if err != nil {
+		return nil, err
+	}
+	return b.Build(ctx, params, frames)
+}
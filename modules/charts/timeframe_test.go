@@ -0,0 +1,38 @@
+package charts_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/guarzo/eveapi/modules/charts"
+)
+
+func TestResolveTimeFrames(t *testing.T) {
+	now := time.Date(2024, 10, 15, 12, 0, 0, 0, time.UTC)
+	deployStart := time.Date(2024, 10, 7, 0, 0, 0, 0, time.UTC)
+
+	frames, err := charts.ResolveTimeFrames(now, []charts.TimeFrameSpec{
+		charts.FixedFrame("Deployment Week 2", deployStart, deployStart.Add(7*24*time.Hour)),
+		charts.LastDays(30),
+		{Name: "Since deployment", Start: deployStart},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(frames) != 3 {
+		t.Fatalf("expected 3 frames, got %d", len(frames))
+	}
+	if frames[1].Name != "Last 30 days" || !frames[1].Start.Equal(now.AddDate(0, 0, -30)) {
+		t.Errorf("unexpected rolling frame: %+v", frames[1])
+	}
+	if !frames[2].End.Equal(now) {
+		t.Errorf("expected open-ended frame to end now, got %v", frames[2].End)
+	}
+
+	if _, err := charts.ResolveTimeFrames(now, []charts.TimeFrameSpec{charts.LastDays(7), charts.LastDays(7)}); err == nil {
+		t.Error("expected duplicate names to fail")
+	}
+	if _, err := charts.ResolveTimeFrames(now, []charts.TimeFrameSpec{charts.FixedFrame("bad", now, now)}); err == nil {
+		t.Error("expected empty window to fail")
+	}
+}
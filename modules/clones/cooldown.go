@@ -0,0 +1,109 @@
+package clones
+
+import (
+	"sync"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+const (
+	// BaseJumpCooldown is the clone jump cooldown with no training.
+	BaseJumpCooldown = 24 * time.Hour
+	// InfomorphSynchronizingSkillID reduces the cooldown by one hour per level.
+	InfomorphSynchronizingSkillID int64 = 33399
+)
+
+// JumpCooldown returns the clone jump cooldown for an Infomorph
+// Synchronizing level (0-5).
+func JumpCooldown(infomorphSynchronizing int) time.Duration {
+	if infomorphSynchronizing < 0 {
+		infomorphSynchronizing = 0
+	}
+	if infomorphSynchronizing > 5 {
+		infomorphSynchronizing = 5
+	}
+	return BaseJumpCooldown - time.Duration(infomorphSynchronizing)*time.Hour
+}
+
+// CooldownTracker remembers each character's last clone jump and reports
+// when the next one is available.
+type CooldownTracker struct {
+	now func() time.Time
+
+	mu       sync.RWMutex
+	lastJump map[int64]time.Time
+	skill    map[int64]int
+}
+
+// NewCooldownTracker constructs an empty CooldownTracker.
+func NewCooldownTracker() *CooldownTracker {
+	return &CooldownTracker{
+		now:      time.Now,
+		lastJump: make(map[int64]time.Time),
+		skill:    make(map[int64]int),
+	}
+}
+
+// Observe records the last clone jump reported by ESI's
+// /characters/{character_id}/clones/. Older values never replace newer ones.
+func (t *CooldownTracker) Observe(characterID model.CharacterID, clones *model.CloneLocation) {
+	if clones == nil {
+		return
+	}
+	t.RecordJump(characterID, clones.LastCloneJumpDate)
+}
+
+// RecordJump records a clone jump at the given time, e.g. from a
+// notification, unless a later one is already known.
+func (t *CooldownTracker) RecordJump(characterID model.CharacterID, at time.Time) {
+	if at.IsZero() {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if at.After(t.lastJump[characterID]) {
+		t.lastJump[characterID] = at
+	}
+}
+
+// SetSkills records a character's Infomorph Synchronizing level from their
+// trained skills, shortening the cooldown used by NextJumpAvailable.
+func (t *CooldownTracker) SetSkills(characterID model.CharacterID, skills []model.CharacterSkill) {
+	level := 0
+	for _, s := range skills {
+		if s.SkillID == InfomorphSynchronizingSkillID {
+			level = s.ActiveSkillLevel
+		}
+	}
+	t.mu.Lock()
+	t.skill[characterID] = level
+	t.mu.Unlock()
+}
+
+// LastJump returns the last known clone jump of a character.
+func (t *CooldownTracker) LastJump(characterID model.CharacterID) (time.Time, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	at, ok := t.lastJump[characterID]
+	return at, ok
+}
+
+// NextJumpAvailable returns when the character may next clone jump. A
+// character with no recorded jump, or whose cooldown has elapsed, can jump
+// now.
+func (t *CooldownTracker) NextJumpAvailable(characterID model.CharacterID) time.Time {
+	now := t.now()
+	t.mu.RLock()
+	last, ok := t.lastJump[characterID]
+	level := t.skill[characterID]
+	t.mu.RUnlock()
+	if !ok {
+		return now
+	}
+	next := last.Add(JumpCooldown(level))
+	if next.Before(now) {
+		return now
+	}
+	return next
+}
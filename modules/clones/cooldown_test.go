@@ -0,0 +1,29 @@
+package clones_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/clones"
+)
+
+func TestCooldownTracker(t *testing.T) {
+	tr := clones.NewCooldownTracker()
+	jumped := time.Now().Add(-2 * time.Hour).UTC().Truncate(time.Second)
+
+	if next := tr.NextJumpAvailable(1); time.Until(next) > time.Second {
+		t.Errorf("expected unknown character to be able to jump now, got %v", next)
+	}
+
+	tr.Observe(1, &model.CloneLocation{LastCloneJumpDate: jumped})
+	tr.RecordJump(1, jumped.Add(-time.Hour)) // older, ignored
+	if got := tr.NextJumpAvailable(1); !got.Equal(jumped.Add(24 * time.Hour)) {
+		t.Errorf("expected %v, got %v", jumped.Add(24*time.Hour), got)
+	}
+
+	tr.SetSkills(1, []model.CharacterSkill{{SkillID: clones.InfomorphSynchronizingSkillID, ActiveSkillLevel: 5}})
+	if got := tr.NextJumpAvailable(1); !got.Equal(jumped.Add(19 * time.Hour)) {
+		t.Errorf("expected skill-reduced cooldown, got %v", got)
+	}
+}
@@ -0,0 +1,3 @@
+// Package clones works with a character's active and jump clones: valuing
+// the implants each clone carries and tracking clone jump cooldowns.
+package clones
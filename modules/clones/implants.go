@@ -0,0 +1,107 @@
+package clones
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/market"
+)
+
+// NameResolver is the part of esi.EsiService used to name implants.
+type NameResolver interface {
+	ResolveNames(ctx context.Context, ids []int64) ([]model.UniverseName, error)
+}
+
+// Implant is a valued implant.
+type Implant struct {
+	TypeID int64   `json:"type_id"`
+	Name   string  `json:"name"`
+	Value  float64 `json:"value"`
+}
+
+// PodValue is the implant value of one clone. JumpCloneID is 0 for the
+// active clone.
+type PodValue struct {
+	JumpCloneID  int64     `json:"jump_clone_id"`
+	LocationID   int64     `json:"location_id,omitempty"`
+	LocationType string    `json:"location_type,omitempty"`
+	Implants     []Implant `json:"implants"`
+	Total        float64   `json:"total"`
+}
+
+// Valuer prices implant sets at the best sell price in a region, i.e. what
+// replacing them would cost.
+type Valuer struct {
+	names     NameResolver
+	appraiser market.Appraiser
+	regionID  int64
+}
+
+// NewValuer constructs a Valuer pricing in regionID.
+func NewValuer(names NameResolver, appraiser market.Appraiser, regionID int64) *Valuer {
+	return &Valuer{names: names, appraiser: appraiser, regionID: regionID}
+}
+
+// ValueClones values the active clone's implants followed by every jump
+// clone in clones (which may be nil). All implants are named and priced in
+// one batch.
+func (v *Valuer) ValueClones(ctx context.Context, active []model.TypeID, clones *model.CloneLocation) ([]PodValue, error) {
+	pods := []PodValue{{Implants: implants(active)}}
+	if clones != nil {
+		for _, jc := range clones.JumpClones {
+			pods = append(pods, PodValue{
+				JumpCloneID:  jc.JumpCloneID,
+				LocationID:   jc.LocationID,
+				LocationType: jc.LocationType,
+				Implants:     implants(jc.Implants),
+			})
+		}
+	}
+
+	quantities := make(map[int64]int64)
+	var ids []int64
+	for _, p := range pods {
+		for _, imp := range p.Implants {
+			if _, ok := quantities[imp.TypeID]; !ok {
+				ids = append(ids, imp.TypeID)
+			}
+			quantities[imp.TypeID]++
+		}
+	}
+	if len(ids) == 0 {
+		return pods, nil
+	}
+
+	resolved, err := v.names.ResolveNames(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve implant names: %w", err)
+	}
+	names := make(map[int64]string, len(resolved))
+	for _, n := range resolved {
+		names[n.ID] = n.Name
+	}
+	appraisal, err := v.appraiser.Appraise(ctx, quantities, v.regionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to price implants: %w", err)
+	}
+	prices := appraisal.SellPrices()
+
+	for i := range pods {
+		for j := range pods[i].Implants {
+			imp := &pods[i].Implants[j]
+			imp.Name = names[imp.TypeID]
+			imp.Value = prices[imp.TypeID]
+			pods[i].Total += imp.Value
+		}
+	}
+	return pods, nil
+}
+
+func implants(typeIDs []model.TypeID) []Implant {
+	out := make([]Implant, 0, len(typeIDs))
+	for _, id := range typeIDs {
+		out = append(out, Implant{TypeID: id})
+	}
+	return out
+}
@@ -0,0 +1,51 @@
+package clones_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/clones"
+	"github.com/guarzo/eveapi/modules/market"
+)
+
+type names struct{}
+
+func (names) ResolveNames(ctx context.Context, ids []int64) ([]model.UniverseName, error) {
+	var out []model.UniverseName
+	for _, id := range ids {
+		out = append(out, model.UniverseName{ID: id, Name: map[int64]string{20499: "High-grade Snake Alpha", 10228: "Zainou 'Gnome' KYA-1000"}[id]})
+	}
+	return out, nil
+}
+
+type appraiser map[int64]float64
+
+func (a appraiser) Appraise(ctx context.Context, items map[int64]int64, regionID int64) (*market.Appraisal, error) {
+	out := &market.Appraisal{RegionID: regionID}
+	for id, qty := range items {
+		out.Items = append(out.Items, market.AppraisalItem{TypeID: id, Quantity: qty, Sell: a[id]})
+	}
+	return out, nil
+}
+
+func TestValueClones(t *testing.T) {
+	cl := model.CloneLocation{JumpClones: []model.JumpClone{
+		{Implants: []model.TypeID{20499, 10228}, JumpCloneID: 7, LocationID: 60003760, LocationType: "station"},
+	}}
+
+	v := clones.NewValuer(names{}, appraiser{20499: 300e6, 10228: 2e6}, market.TheForgeRegionID)
+	pods, err := v.ValueClones(context.Background(), []model.TypeID{20499}, &cl)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pods) != 2 {
+		t.Fatalf("expected 2 pods, got %d", len(pods))
+	}
+	if pods[0].JumpCloneID != 0 || pods[0].Total != 300e6 || pods[0].Implants[0].Name != "High-grade Snake Alpha" {
+		t.Errorf("unexpected active clone: %+v", pods[0])
+	}
+	if pods[1].JumpCloneID != 7 || pods[1].Total != 302e6 {
+		t.Errorf("unexpected jump clone: %+v", pods[1])
+	}
+}
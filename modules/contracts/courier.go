@@ -0,0 +1,115 @@
+package contracts
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/navigation"
+)
+
+// CourierSource is the part of esi.EsiService the courier calculator needs.
+type CourierSource interface {
+	navigation.LocationResolver
+	navigation.RouteSource
+}
+
+// CourierThresholds are the minimum acceptable rewards. Zero disables a check.
+type CourierThresholds struct {
+	MinPerJump float64
+	MinPerM3   float64
+}
+
+// CourierRating is a courier contract's reward broken down by distance and
+// volume. Jumps counts stargate jumps; a same-system contract counts as one.
+type CourierRating struct {
+	ContractID    int64   `json:"contract_id"`
+	StartSystemID int64   `json:"start_system_id"`
+	EndSystemID   int64   `json:"end_system_id"`
+	Jumps         int     `json:"jumps"`
+	Volume        float64 `json:"volume"`
+	Reward        float64 `json:"reward"`
+	Collateral    float64 `json:"collateral"`
+	RewardPerJump float64 `json:"reward_per_jump"`
+	RewardPerM3   float64 `json:"reward_per_m3"`
+	BelowPerJump  bool    `json:"below_per_jump"`
+	BelowPerM3    bool    `json:"below_per_m3"`
+}
+
+// Flagged reports whether the contract fell below any threshold.
+func (r *CourierRating) Flagged() bool {
+	return r.BelowPerJump || r.BelowPerM3
+}
+
+// CourierCalculator rates courier contracts.
+type CourierCalculator struct {
+	source     CourierSource
+	thresholds CourierThresholds
+	routeFlag  string
+}
+
+// NewCourierCalculator constructs a CourierCalculator using the secure route,
+// which is what haulers in high-sec fly.
+func NewCourierCalculator(source CourierSource, thresholds CourierThresholds) *CourierCalculator {
+	return &CourierCalculator{source: source, thresholds: thresholds, routeFlag: "secure"}
+}
+
+// SetRouteFlag selects the ESI route flag ("shortest", "secure", "insecure").
+func (c *CourierCalculator) SetRouteFlag(flag string) {
+	c.routeFlag = flag
+}
+
+// Rate computes the reward per jump and per m³ of a courier contract. token
+// is only needed when either end is a player structure.
+func (c *CourierCalculator) Rate(ctx context.Context, contract model.Contract, token *oauth2.Token) (*CourierRating, error) {
+	if contract.Type != model.ContractCourier {
+		return nil, fmt.Errorf("contract %d is %s, not courier", contract.ContractID, contract.Type)
+	}
+	start, err := navigation.SystemOfLocation(ctx, c.source, contract.StartLocationID, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve start of contract %d: %w", contract.ContractID, err)
+	}
+	end, err := navigation.SystemOfLocation(ctx, c.source, contract.EndLocationID, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve end of contract %d: %w", contract.ContractID, err)
+	}
+	jumps, err := navigation.GateJumps(ctx, c.source, start, end, c.routeFlag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to route contract %d: %w", contract.ContractID, err)
+	}
+
+	r := &CourierRating{
+		ContractID:    contract.ContractID,
+		StartSystemID: start,
+		EndSystemID:   end,
+		Jumps:         max(jumps, 1),
+		Volume:        contract.Volume,
+		Reward:        contract.Reward,
+		Collateral:    contract.Collateral,
+	}
+	r.RewardPerJump = r.Reward / float64(r.Jumps)
+	if r.Volume > 0 {
+		r.RewardPerM3 = r.Reward / r.Volume
+	}
+	r.BelowPerJump = c.thresholds.MinPerJump > 0 && r.RewardPerJump < c.thresholds.MinPerJump
+	r.BelowPerM3 = c.thresholds.MinPerM3 > 0 && r.Volume > 0 && r.RewardPerM3 < c.thresholds.MinPerM3
+	return r, nil
+}
+
+// RateAll rates every courier contract in contracts, skipping other types.
+func (c *CourierCalculator) RateAll(ctx context.Context, contracts []model.Contract, token *oauth2.Token) ([]CourierRating, error) {
+	var out []CourierRating
+	for _, contract := range contracts {
+		if contract.Type != model.ContractCourier {
+			continue
+		}
+		r, err := c.Rate(ctx, contract, token)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *r)
+	}
+	return out, nil
+}
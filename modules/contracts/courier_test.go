@@ -0,0 +1,55 @@
+package contracts_test
+
+import (
+	"context"
+	"testing"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/contracts"
+)
+
+type courierSource struct{}
+
+func (courierSource) GetStation(ctx context.Context, stationID int64) (*model.Station, error) {
+	return &model.Station{ID: stationID, SystemID: 30000142}, nil
+}
+
+func (courierSource) GetStructure(ctx context.Context, structureID int64, token *oauth2.Token) (*model.Structure, error) {
+	return &model.Structure{SystemID: 30002187}, nil
+}
+
+func (courierSource) GetRoute(ctx context.Context, origin, destination int64, flag string) ([]int64, error) {
+	return []int64{origin, 30000144, 30000139, 30002053, destination}, nil
+}
+
+func TestCourierCalculator(t *testing.T) {
+	calc := contracts.NewCourierCalculator(courierSource{}, contracts.CourierThresholds{MinPerJump: 1_000_000, MinPerM3: 500})
+	c := model.Contract{
+		ContractID:      7,
+		Type:            model.ContractCourier,
+		StartLocationID: 60003760,
+		EndLocationID:   1022734985679,
+		Reward:          2_000_000,
+		Volume:          10_000,
+	}
+	r, err := calc.Rate(context.Background(), c, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.StartSystemID != 30000142 || r.EndSystemID != 30002187 || r.Jumps != 4 {
+		t.Errorf("unexpected route: %+v", r)
+	}
+	if r.RewardPerJump != 500_000 || r.RewardPerM3 != 200 {
+		t.Errorf("unexpected rates: %+v", r)
+	}
+	if !r.BelowPerJump || !r.BelowPerM3 || !r.Flagged() {
+		t.Errorf("expected contract to be flagged: %+v", r)
+	}
+
+	all, err := calc.RateAll(context.Background(), []model.Contract{c, {Type: model.ContractItemExchange}}, nil)
+	if err != nil || len(all) != 1 {
+		t.Fatalf("expected one courier rating, got %d (%v)", len(all), err)
+	}
+}
@@ -0,0 +1,4 @@
+// Package contracts evaluates public and character contracts: appraising
+// their items against the asking price and collateral, and rating courier
+// contracts by reward per jump and per m³.
+package contracts
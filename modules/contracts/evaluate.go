@@ -0,0 +1,161 @@
+package contracts
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/market"
+)
+
+// ItemSource is the part of esi.EsiService needed to list contract items.
+type ItemSource interface {
+	GetPublicContractItems(ctx context.Context, contractID int64) ([]model.ContractItem, error)
+}
+
+// Rating is the outcome of a contract evaluation.
+type Rating string
+
+const (
+	RatingFair       Rating = "fair"
+	RatingBargain    Rating = "bargain"
+	RatingOverpriced Rating = "overpriced"
+	RatingSuspicious Rating = "suspicious"
+)
+
+// Thresholds tune how a price-to-value ratio maps to a Rating.
+type Thresholds struct {
+	Overpriced float64 // price/value above this is overpriced
+	Bargain    float64 // price/value below this is a bargain
+	Suspicious float64 // price/value below this is too good to be true
+	Collateral float64 // courier collateral/value above this is suspicious
+}
+
+// DefaultThresholds returns the thresholds used when none are configured.
+func DefaultThresholds() Thresholds {
+	return Thresholds{Overpriced: 1.1, Bargain: 0.9, Suspicious: 0.5, Collateral: 1.5}
+}
+
+// Verdict is the result of EvaluateContract. Values use the appraisal's
+// sell (ask) prices.
+type Verdict struct {
+	ContractID     int64             `json:"contract_id"`
+	Type           string            `json:"type"`
+	Price          float64           `json:"price"`
+	Collateral     float64           `json:"collateral"`
+	OfferedValue   float64           `json:"offered_value"`   // items the issuer gives
+	RequestedValue float64           `json:"requested_value"` // items the acceptor must give
+	NetValue       float64           `json:"net_value"`       // offered minus requested
+	Ratio          float64           `json:"ratio"`           // price (or collateral) / net value
+	Rating         Rating            `json:"rating"`
+	Warnings       []string          `json:"warnings,omitempty"`
+	Appraisal      *market.Appraisal `json:"appraisal,omitempty"`
+}
+
+// Evaluator appraises contracts against a market region.
+type Evaluator struct {
+	items      ItemSource
+	appraiser  market.Appraiser
+	regionID   int64
+	thresholds Thresholds
+}
+
+// NewEvaluator constructs an Evaluator pricing items in regionID.
+func NewEvaluator(items ItemSource, appraiser market.Appraiser, regionID int64) *Evaluator {
+	return &Evaluator{items: items, appraiser: appraiser, regionID: regionID, thresholds: DefaultThresholds()}
+}
+
+// SetThresholds replaces the rating thresholds.
+func (e *Evaluator) SetThresholds(t Thresholds) {
+	e.thresholds = t
+}
+
+// EvaluateContract fetches c's items, appraises them and compares the result
+// with the asking price (item exchange and auction contracts) or collateral
+// (courier contracts).
+func (e *Evaluator) EvaluateContract(ctx context.Context, c model.Contract) (*Verdict, error) {
+	items, err := e.items.GetPublicContractItems(ctx, c.ContractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get items for contract %d: %w", c.ContractID, err)
+	}
+	v := &Verdict{ContractID: c.ContractID, Type: c.Type, Price: c.Price, Collateral: c.Collateral}
+
+	quantities := make(map[int64]int64)
+	for _, it := range items {
+		if it.IsBlueprintCopy {
+			// Market prices are for originals; a copy is worth far less.
+			v.Warnings = append(v.Warnings, fmt.Sprintf("type %d is a blueprint copy and is not valued", it.TypeID))
+			continue
+		}
+		quantities[it.TypeID] += it.Quantity
+	}
+	if len(quantities) > 0 {
+		v.Appraisal, err = e.appraiser.Appraise(ctx, quantities, e.regionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to appraise contract %d: %w", c.ContractID, err)
+		}
+		for _, typeID := range v.Appraisal.Unpriced {
+			v.Warnings = append(v.Warnings, fmt.Sprintf("type %d has no market price", typeID))
+		}
+	}
+
+	prices := map[int64]float64{}
+	if v.Appraisal != nil {
+		prices = v.Appraisal.SellPrices()
+	}
+	for _, it := range items {
+		if it.IsBlueprintCopy {
+			continue
+		}
+		value := prices[it.TypeID] * float64(it.Quantity)
+		if it.IsIncluded {
+			v.OfferedValue += value
+		} else {
+			v.RequestedValue += value
+		}
+	}
+	v.NetValue = v.OfferedValue - v.RequestedValue
+
+	if c.Type == model.ContractCourier {
+		e.rateCourier(v)
+	} else {
+		e.rateSale(v)
+	}
+	return v, nil
+}
+
+func (e *Evaluator) rateSale(v *Verdict) {
+	if v.NetValue <= 0 {
+		v.Rating = RatingSuspicious
+		v.Warnings = append(v.Warnings, "contract gives nothing of market value")
+		return
+	}
+	v.Ratio = v.Price / v.NetValue
+	switch {
+	case v.Ratio < e.thresholds.Suspicious:
+		v.Rating = RatingSuspicious
+		v.Warnings = append(v.Warnings, "price is far below market value")
+	case v.Ratio < e.thresholds.Bargain:
+		v.Rating = RatingBargain
+	case v.Ratio > e.thresholds.Overpriced:
+		v.Rating = RatingOverpriced
+	default:
+		v.Rating = RatingFair
+	}
+}
+
+func (e *Evaluator) rateCourier(v *Verdict) {
+	v.Rating = RatingFair
+	if v.OfferedValue <= 0 {
+		if v.Collateral > 0 {
+			v.Rating = RatingSuspicious
+			v.Warnings = append(v.Warnings, "collateral is required for cargo of no market value")
+		}
+		return
+	}
+	v.Ratio = v.Collateral / v.OfferedValue
+	if v.Ratio > e.thresholds.Collateral {
+		v.Rating = RatingSuspicious
+		v.Warnings = append(v.Warnings, "collateral is well above cargo value")
+	}
+}
@@ -0,0 +1,60 @@
+package contracts_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/contracts"
+	"github.com/guarzo/eveapi/modules/market"
+)
+
+type itemSource map[int64][]model.ContractItem
+
+func (s itemSource) GetPublicContractItems(ctx context.Context, contractID int64) ([]model.ContractItem, error) {
+	return s[contractID], nil
+}
+
+type fixedAppraiser map[int64]float64
+
+func (a fixedAppraiser) Appraise(ctx context.Context, items map[int64]int64, regionID int64) (*market.Appraisal, error) {
+	out := &market.Appraisal{RegionID: regionID}
+	for typeID, qty := range items {
+		out.Items = append(out.Items, market.AppraisalItem{TypeID: typeID, Quantity: qty, Sell: a[typeID]})
+	}
+	return out, nil
+}
+
+func TestEvaluateContract(t *testing.T) {
+	items := itemSource{
+		1: {{TypeID: 34, Quantity: 1000, IsIncluded: true}},
+		2: {{TypeID: 34, Quantity: 1000, IsIncluded: true}, {TypeID: 35, Quantity: 100}},
+		3: {{TypeID: 34, Quantity: 1000, IsIncluded: true}},
+		4: {{TypeID: 999, Quantity: 1, IsIncluded: true, IsBlueprintCopy: true}},
+	}
+	e := contracts.NewEvaluator(items, fixedAppraiser{34: 5, 35: 10}, market.TheForgeRegionID)
+
+	cases := []struct {
+		contract model.Contract
+		want     contracts.Rating
+	}{
+		{model.Contract{ContractID: 1, Type: model.ContractItemExchange, Price: 5000}, contracts.RatingFair},
+		{model.Contract{ContractID: 2, Type: model.ContractItemExchange, Price: 6000}, contracts.RatingOverpriced},
+		{model.Contract{ContractID: 3, Type: model.ContractCourier, Collateral: 50000}, contracts.RatingSuspicious},
+		{model.Contract{ContractID: 4, Type: model.ContractItemExchange, Price: 1e9}, contracts.RatingSuspicious},
+	}
+	for _, tc := range cases {
+		v, err := e.EvaluateContract(context.Background(), tc.contract)
+		if err != nil {
+			t.Fatalf("contract %d: unexpected error: %v", tc.contract.ContractID, err)
+		}
+		if v.Rating != tc.want {
+			t.Errorf("contract %d: expected %s, got %s (%+v)", tc.contract.ContractID, tc.want, v.Rating, v)
+		}
+	}
+
+	v, _ := e.EvaluateContract(context.Background(), model.Contract{ContractID: 2, Type: model.ContractItemExchange, Price: 4000})
+	if v.NetValue != 4000 || v.RequestedValue != 1000 {
+		t.Errorf("unexpected values: %+v", v)
+	}
+}
@@ -0,0 +1,143 @@
+package esi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// CachePolicy maps EsiService method names to how long their decoded results
+// are cached. Methods missing from the policy, or with a TTL <= 0, are passed
+// straight through.
+type CachePolicy map[string]time.Duration
+
+// DefaultCachePolicy caches immutable data (killmails) for a long time and
+// slowly changing data (entities, universe, prices) for hours.
+func DefaultCachePolicy() CachePolicy {
+	return CachePolicy{
+		"GetCharacterInfo":     6 * time.Hour,
+		"GetCorporationInfo":   6 * time.Hour,
+		"GetAllianceInfo":      24 * time.Hour,
+		"GetCharacterPortrait": 24 * time.Hour,
+		"GetEsiKillMail":       30 * 24 * time.Hour,
+		"GetSolarSystem":       7 * 24 * time.Hour,
+		"GetStation":           7 * 24 * time.Hour,
+		"GetStructure":         24 * time.Hour,
+		"GetMarketPrices":      time.Hour,
+	}
+}
+
+// CachedServiceOption customizes a cached EsiService.
+type CachedServiceOption func(*cachedEsiService)
+
+// WithCachedCodec sets the serialization for cached results (JSON by default).
+func WithCachedCodec(codec common.Codec) CachedServiceOption {
+	return func(c *cachedEsiService) {
+		c.codec = codec
+	}
+}
+
+// NewCachedEsiService wraps next with a read-through cache of decoded
+// results for the methods listed in policy. Cache failures fall back to next.
+func NewCachedEsiService(next EsiService, cache common.CacheRepository, policy CachePolicy, opts ...CachedServiceOption) EsiService {
+	c := &cachedEsiService{
+		EsiService: next,
+		cache:      common.AsContextCache(cache),
+		policy:     policy,
+		codec:      common.DefaultCodec,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+type cachedEsiService struct {
+	EsiService
+	cache  common.ContextCacheRepository
+	policy CachePolicy
+	codec  common.Codec
+}
+
+// cachedCall returns the cached result of method for key, or calls fetch and
+// caches its result according to the policy.
+func cachedCall[T any](ctx context.Context, c *cachedEsiService, method, key string, fetch func() (T, error)) (T, error) {
+	ttl := c.policy[method]
+	if ttl <= 0 || common.CacheBypassed(ctx) {
+		return fetch()
+	}
+	cacheKey := fmt.Sprintf("esi:svc:%s:%s", method, key)
+	if raw, found, err := c.cache.GetCtx(ctx, cacheKey); err == nil && found {
+		var v T
+		if c.codec.Unmarshal(raw, &v) == nil {
+			return v, nil
+		}
+	}
+	v, err := fetch()
+	if err != nil {
+		return v, err
+	}
+	if raw, err := c.codec.Marshal(v); err == nil {
+		_ = c.cache.SetCtx(ctx, cacheKey, raw, ttl)
+	}
+	return v, nil
+}
+
+func (c *cachedEsiService) GetCharacterInfo(ctx context.Context, characterID model.CharacterID) (*model.Character, error) {
+	return cachedCall(ctx, c, "GetCharacterInfo", fmt.Sprint(characterID), func() (*model.Character, error) {
+		return c.EsiService.GetCharacterInfo(ctx, characterID)
+	})
+}
+
+func (c *cachedEsiService) GetCorporationInfo(ctx context.Context, corporationID model.CorporationID) (*model.Corporation, error) {
+	return cachedCall(ctx, c, "GetCorporationInfo", fmt.Sprint(corporationID), func() (*model.Corporation, error) {
+		return c.EsiService.GetCorporationInfo(ctx, corporationID)
+	})
+}
+
+func (c *cachedEsiService) GetAllianceInfo(ctx context.Context, allianceID model.AllianceID) (*model.Alliance, error) {
+	return cachedCall(ctx, c, "GetAllianceInfo", fmt.Sprint(allianceID), func() (*model.Alliance, error) {
+		return c.EsiService.GetAllianceInfo(ctx, allianceID)
+	})
+}
+
+func (c *cachedEsiService) GetCharacterPortrait(characterID model.CharacterID) (string, error) {
+	return cachedCall(context.Background(), c, "GetCharacterPortrait", fmt.Sprint(characterID), func() (string, error) {
+		return c.EsiService.GetCharacterPortrait(characterID)
+	})
+}
+
+func (c *cachedEsiService) GetEsiKillMail(ctx context.Context, killID int64, hash string) (*model.EsiKillMail, error) {
+	return cachedCall(ctx, c, "GetEsiKillMail", fmt.Sprintf("%d:%s", killID, hash), func() (*model.EsiKillMail, error) {
+		return c.EsiService.GetEsiKillMail(ctx, killID, hash)
+	})
+}
+
+func (c *cachedEsiService) GetSolarSystem(ctx context.Context, systemID model.SystemID) (*model.SolarSystem, error) {
+	return cachedCall(ctx, c, "GetSolarSystem", fmt.Sprint(systemID), func() (*model.SolarSystem, error) {
+		return c.EsiService.GetSolarSystem(ctx, systemID)
+	})
+}
+
+func (c *cachedEsiService) GetStation(ctx context.Context, stationID int64) (*model.Station, error) {
+	return cachedCall(ctx, c, "GetStation", fmt.Sprint(stationID), func() (*model.Station, error) {
+		return c.EsiService.GetStation(ctx, stationID)
+	})
+}
+
+func (c *cachedEsiService) GetStructure(ctx context.Context, structureID int64, token *oauth2.Token) (*model.Structure, error) {
+	return cachedCall(ctx, c, "GetStructure", fmt.Sprint(structureID), func() (*model.Structure, error) {
+		return c.EsiService.GetStructure(ctx, structureID, token)
+	})
+}
+
+func (c *cachedEsiService) GetMarketPrices(ctx context.Context) ([]model.MarketPrice, error) {
+	return cachedCall(ctx, c, "GetMarketPrices", "all", func() ([]model.MarketPrice, error) {
+		return c.EsiService.GetMarketPrices(ctx)
+	})
+}
@@ -0,0 +1,52 @@
+package esi_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/modules/esi"
+)
+
+func TestCachedEsiService_ReadThrough(t *testing.T) {
+	calls := map[string]int{}
+	client := &mockEsiClient{
+		getJSONFunc: func(ctx context.Context, endpoint string, entity interface{}, token *oauth2.Token, params map[string]string) error {
+			calls[endpoint]++
+			return common.JSONCodec{}.Unmarshal([]byte(`{"name":"Alpha","ticker":"A"}`), entity)
+		},
+	}
+	cache := &mockCache{store: make(map[string][]byte)}
+	svc := esi.NewCachedEsiService(esi.NewEsiService(client), cache,
+		esi.CachePolicy{"GetCorporationInfo": time.Hour}, esi.WithCachedCodec(common.GobCodec{}))
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		corp, err := svc.GetCorporationInfo(ctx, 98000001)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if corp.Name != "Alpha" {
+			t.Errorf("expected Alpha, got %q", corp.Name)
+		}
+		if _, err := svc.GetAllianceInfo(ctx, 99000001); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if calls["corporations/98000001/"] != 1 {
+		t.Errorf("expected corporation to be fetched once, got %d", calls["corporations/98000001/"])
+	}
+	if calls["alliances/99000001/"] != 3 {
+		t.Errorf("expected alliance without policy to pass through, got %d", calls["alliances/99000001/"])
+	}
+
+	if _, err := svc.GetCorporationInfo(common.WithoutCache(ctx), 98000001); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls["corporations/98000001/"] != 2 {
+		t.Errorf("expected common.WithoutCache to bypass the cache, got %d fetches", calls["corporations/98000001/"])
+	}
+}
@@ -0,0 +1,112 @@
+package esi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// ErrNotModified is returned when a request made WithEtag is answered with
+// 304 Not Modified; the caller's previous result is still current.
+var ErrNotModified = errors.New("esi: not modified")
+
+// CallOption tunes a single EsiClient GET request.
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	page     int
+	noCache  bool
+	etag     *string
+	timeout  time.Duration
+	expected []int
+	header   *http.Header
+
+	// refreshed is the token a refresh during this call returned, so that
+	// retries of the call use it instead of the stale one
+	refreshed *oauth2.Token
+}
+
+// WithPage requests one page of a paginated endpoint.
+func WithPage(page int) CallOption {
+	return func(o *callOptions) {
+		o.page = page
+	}
+}
+
+// WithNoCache bypasses the client's response cache for GetJSON/GetBytes,
+// neither reading nor writing it. common.WithoutCache does the same for every
+// call made with a context, including through EsiService methods.
+func WithNoCache() CallOption {
+	return func(o *callOptions) {
+		o.noCache = true
+	}
+}
+
+// WithEtag makes the request conditional on *etag (if non-empty) and stores
+// the response's ETag back into it. A 304 response returns ErrNotModified.
+// The response cache is bypassed, since a cached body carries no ETag.
+func WithEtag(etag *string) CallOption {
+	return func(o *callOptions) {
+		o.etag = etag
+		o.noCache = true
+	}
+}
+
+// WithTimeout bounds the request, retries included, by d.
+func WithTimeout(d time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.timeout = d
+	}
+}
+
+// WithExpectedStatus replaces the status codes treated as success
+// (200 by default).
+func WithExpectedStatus(codes ...int) CallOption {
+	return func(o *callOptions) {
+		o.expected = codes
+	}
+}
+
+// WithResponseHeader stores the response headers (e.g. X-Pages, Expires) in
+// *h. Cached responses leave *h untouched.
+func WithResponseHeader(h *http.Header) CallOption {
+	return func(o *callOptions) {
+		o.header = h
+	}
+}
+
+func newCallOptions(opts []CallOption) *callOptions {
+	o := &callOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// query returns a copy of params with the datasource default and the
+// requested page applied.
+func (o *callOptions) query(params map[string]string) map[string]string {
+	q := make(map[string]string, len(params)+2)
+	for k, v := range params {
+		q[k] = v
+	}
+	if _, found := q["datasource"]; !found {
+		q["datasource"] = "tranquility"
+	}
+	if o.page > 0 {
+		q["page"] = strconv.Itoa(o.page)
+	}
+	return q
+}
+
+// context applies the timeout, if any.
+func (o *callOptions) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if o.timeout > 0 {
+		return context.WithTimeout(ctx, o.timeout)
+	}
+	return ctx, func() {}
+}
@@ -8,11 +8,14 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"slices"
 	"sort"
-	"sync/atomic"
+	"strconv"
+	"sync"
 	"time"
 
 	"golang.org/x/oauth2"
+	"golang.org/x/sync/singleflight"
 
 	"github.com/guarzo/eveapi/common"
 )
@@ -20,11 +23,14 @@ import (
 // EsiClient defines lower-level HTTP operations for ESI:
 // handling Get/POST/DELETE, token refresh checks, caching, etc.
 type EsiClient interface {
-	GetJSON(ctx context.Context, endpoint string, entity interface{}, token *oauth2.Token, params map[string]string) error
-	GetBytes(ctx context.Context, endpoint string, token *oauth2.Token, params map[string]string) ([]byte, error)
+	GetJSON(ctx context.Context, endpoint string, entity interface{}, token *oauth2.Token, params map[string]string, opts ...CallOption) error
+	GetBytes(ctx context.Context, endpoint string, token *oauth2.Token, params map[string]string, opts ...CallOption) ([]byte, error)
+	GetFreshJSON(ctx context.Context, endpoint string, entity interface{}, token *oauth2.Token, params map[string]string, opts ...CallOption) error
+	GetJSONAllPages(ctx context.Context, endpoint string, entity interface{}, token *oauth2.Token, params map[string]string, opts ...CallOption) error
 	PostJSON(ctx context.Context, endpoint string, token *oauth2.Token, body io.Reader, expectedStatusCodes ...int) ([]byte, error)
 	DeleteJSON(ctx context.Context, endpoint string, token *oauth2.Token, body io.Reader, expectedStatusCodes ...int) ([]byte, error)
 	DoRequest(ctx context.Context, method, urlStr string, token *oauth2.Token, body io.Reader, expectedStatus ...int) ([]byte, error)
+	Stats() common.ClientStats
 }
 
 // AuthClient is optional. If you want to do token refresh externally, define it here.
@@ -35,29 +41,165 @@ type AuthClient interface {
 type esiClient struct {
 	baseURL    string
 	httpClient common.HttpClient
-	cache      common.CacheRepository
+	cache      common.ContextCacheRepository
 	authClient AuthClient
+	stats      *common.StatsCounter
+	metrics    common.MetricsRecorder
+	pause      *common.PauseGate
+	errorFloor int
+	limiter    common.Limiter
+	decoder    common.JSONDecoder
+	maxBody    int64
+	ttls       TTLPolicy
+	defaultTTL time.Duration
+
+	pageWorkers int
+
+	retry             RetryPolicy
+	idempotencyWindow time.Duration
+	writes            singleflight.Group
+	onTokenUpdate     TokenUpdateFunc
+
+	refreshSkew time.Duration
+	refreshes   singleflight.Group
+	now         func() time.Time
+
+	mu        sync.Mutex
+	refreshed map[string]*oauth2.Token // by the refresh token it replaced
 }
 
-// Some metrics counters (optional)
-var (
-	totalCalls    int64
-	notFoundCount int64
-	successCount  int64
-	failCount     int64
-)
+// ClientOption customizes an EsiClient.
+type ClientOption func(*esiClient)
+
+// WithMetricsRecorder forwards request metrics to r in addition to the
+// client's own counters reported by Stats.
+func WithMetricsRecorder(r common.MetricsRecorder) ClientOption {
+	return func(c *esiClient) {
+		c.metrics = common.MultiRecorder(c.stats, r)
+	}
+}
+
+// WithPauseGate shares a rate-limit pause with other clients. By default each
+// client has its own gate, shared by every goroutine using it.
+func WithPauseGate(g *common.PauseGate) ClientOption {
+	return func(c *esiClient) {
+		c.pause = g
+	}
+}
+
+// DefaultErrorLimitThreshold is the error budget below which the client
+// pauses by default. ESI grants 100 errors per window.
+const DefaultErrorLimitThreshold = 10
+
+// WithErrorLimitThreshold pauses every request of the client until ESI's
+// error window resets once a response reports fewer than n errors left
+// (DefaultErrorLimitThreshold by default, <= 0 to disable). The pause goes
+// through the client's pause gate, so clients sharing one via WithPauseGate
+// back off together.
+func WithErrorLimitThreshold(n int) ClientOption {
+	return func(c *esiClient) {
+		c.errorFloor = n
+	}
+}
+
+// WithLimiter paces every request through l, e.g. a common.NewRedisLimiter
+// shared by several instances of an application.
+func WithLimiter(l common.Limiter) ClientOption {
+	return func(c *esiClient) {
+		c.limiter = l
+	}
+}
+
+// WithStrictDecoding makes GetJSON and GetFreshJSON fail when a response
+// carries fields its model does not declare.
+func WithStrictDecoding() ClientOption {
+	return func(c *esiClient) {
+		c.decoder.Strict = true
+	}
+}
+
+// WithUnknownFieldHandler reports response fields missing from the model
+// they are decoded into, with the endpoint as source. Cached responses are
+// reported again each time they are decoded.
+func WithUnknownFieldHandler(fn common.UnknownFieldFunc) ClientOption {
+	return func(c *esiClient) {
+		c.decoder.OnUnknownField = fn
+	}
+}
+
+// WithMaxResponseSize bounds the response bodies the client reads
+// (common.DefaultMaxResponseSize by default, <= 0 for no bound). Larger
+// responses fail with common.ErrResponseTooLarge.
+func WithMaxResponseSize(n int64) ClientOption {
+	return func(c *esiClient) {
+		c.maxBody = n
+	}
+}
+
+// TokenUpdateFunc receives a token the client refreshed in the middle of a
+// call together with the token it replaces, which the caller still holds.
+type TokenUpdateFunc func(ctx context.Context, old, refreshed *oauth2.Token)
+
+// WithTokenUpdateCallback calls fn whenever a request refreshes its token,
+// so rotated refresh tokens can be persisted; otherwise the refreshed token
+// is used for the call only and then lost.
+func WithTokenUpdateCallback(fn TokenUpdateFunc) ClientOption {
+	return func(c *esiClient) {
+		c.onTokenUpdate = fn
+	}
+}
+
+// WithTTLPolicy replaces the TTLs GetJSON and GetBytes cache responses for
+// (DefaultTTLPolicy by default).
+func WithTTLPolicy(p TTLPolicy) ClientOption {
+	return func(c *esiClient) {
+		c.ttls = p
+	}
+}
+
+// WithDefaultTTL sets the TTL of endpoints the TTL policy does not match
+// (DefaultCacheTTL by default, <= 0 to leave them uncached).
+func WithDefaultTTL(d time.Duration) ClientOption {
+	return func(c *esiClient) {
+		c.defaultTTL = d
+	}
+}
 
-// Default for how long to cache data. Adjust as needed.
-const defaultCacheExpiration = 770 * time.Hour
+// DefaultCacheTTL is how long responses of endpoints missing from the TTL
+// policy are cached by default.
+const DefaultCacheTTL = 770 * time.Hour
 
 // NewEsiClient creates a new EsiClient that will communicate with EVE ESI.
-func NewEsiClient(baseURL string, httpClient common.HttpClient, cache common.CacheRepository, authClient AuthClient) EsiClient {
-	return &esiClient{
+// Each client keeps its own metrics, so clients for different servers
+// (e.g. Tranquility and Singularity) report independently.
+func NewEsiClient(baseURL string, httpClient common.HttpClient, cache common.CacheRepository, authClient AuthClient, opts ...ClientOption) EsiClient {
+	stats := &common.StatsCounter{}
+	c := &esiClient{
 		baseURL:    baseURL,
 		httpClient: httpClient,
-		cache:      cache,
+		cache:      common.AsContextCache(cache),
 		authClient: authClient,
+		stats:      stats,
+		metrics:    stats,
+		pause:      common.NewPauseGate(),
+		errorFloor: DefaultErrorLimitThreshold,
+		maxBody:    common.DefaultMaxResponseSize,
+		ttls:       DefaultTTLPolicy(),
+		defaultTTL: DefaultCacheTTL,
+
+		pageWorkers: common.DefaultConcurrency,
+
+		retry:             DefaultRetryPolicy(),
+		idempotencyWindow: DefaultIdempotencyWindow,
+
+		refreshSkew: DefaultRefreshSkew,
+		now:         time.Now,
+		refreshed:   make(map[string]*oauth2.Token),
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
 // ---------------------------------------------------
@@ -65,52 +207,88 @@ func NewEsiClient(baseURL string, httpClient common.HttpClient, cache common.Cac
 // ---------------------------------------------------
 
 // GetJSON retrieves JSON from an ESI endpoint and unmarshals into entity.
-func (c *esiClient) GetJSON(ctx context.Context, endpoint string, entity interface{}, token *oauth2.Token, params map[string]string) error {
-	data, err := c.GetBytes(ctx, endpoint, token, params)
+func (c *esiClient) GetJSON(ctx context.Context, endpoint string, entity interface{}, token *oauth2.Token, params map[string]string, opts ...CallOption) error {
+	data, err := c.GetBytes(ctx, endpoint, token, params, opts...)
 	if err != nil {
 		return err
 	}
-	return unmarshalJSON(data, entity)
+	return c.decoder.Decode(endpoint, data, entity)
 }
 
 // GetBytes retrieves raw bytes from an ESI endpoint, with caching if desired.
-func (c *esiClient) GetBytes(ctx context.Context, endpoint string, token *oauth2.Token, params map[string]string) ([]byte, error) {
-	if params == nil {
-		params = map[string]string{}
-	}
-	// Example: set default datasource if not present
-	if _, found := params["datasource"]; !found {
-		params["datasource"] = "tranquility"
-	}
+func (c *esiClient) GetBytes(ctx context.Context, endpoint string, token *oauth2.Token, params map[string]string, opts ...CallOption) ([]byte, error) {
+	o := newCallOptions(opts)
+	ctx, cancel := o.context(ctx)
+	defer cancel()
+	query := o.query(params)
 
 	// build a cache key if you want to store the response
-	cacheKey := c.buildCacheKey(endpoint, params)
-	if cached, found := c.cache.Get(cacheKey); found {
-		return cached, nil
+	cacheKey := c.buildCacheKey(endpoint, query)
+	ttl := c.ttl(endpoint)
+	if ttl <= 0 || common.CacheBypassed(ctx) {
+		o.noCache = true
+	}
+	if !o.noCache {
+		// cache failures are treated as a miss; the cache is best effort
+		cached, found, cacheErr := c.cache.GetCtx(ctx, cacheKey)
+		found = found && cacheErr == nil
+		c.metrics.RecordCache(found)
+		if found {
+			return cached, nil
+		}
 	}
 
-	urlStr, err := c.buildURL(endpoint, params)
+	urlStr, err := c.buildURL(endpoint, query)
 	if err != nil {
 		return nil, err
 	}
 
 	operation := func() (interface{}, error) {
-		data, err := c.DoRequest(ctx, http.MethodGet, urlStr, token, nil)
+		data, err := c.doRequest(ctx, http.MethodGet, urlStr, token, nil, o)
 		if err != nil {
 			return nil, err
 		}
 		// store in cache
-		c.cache.Set(cacheKey, data, defaultCacheExpiration)
+		if !o.noCache {
+			_ = c.cache.SetCtx(ctx, cacheKey, data, ttl)
+		}
 		return data, nil
 	}
 
-	result, err := c.httpClient.RetryWithExponentialBackoff(operation)
+	result, err := c.retrying(http.MethodGet, operation)
 	if err != nil {
 		return nil, err
 	}
 	return result.([]byte), nil
 }
 
+// ttl returns how long responses of endpoint are cached.
+func (c *esiClient) ttl(endpoint string) time.Duration {
+	if d, ok := c.ttls.TTL(endpoint); ok {
+		return d
+	}
+	return c.defaultTTL
+}
+
+// GetFreshJSON is GetJSON without the response cache, for data that changes
+// between polls (member lists, wars, structure state).
+func (c *esiClient) GetFreshJSON(ctx context.Context, endpoint string, entity interface{}, token *oauth2.Token, params map[string]string, opts ...CallOption) error {
+	o := newCallOptions(opts)
+	ctx, cancel := o.context(ctx)
+	defer cancel()
+	urlStr, err := c.buildURL(endpoint, o.query(params))
+	if err != nil {
+		return err
+	}
+	result, err := c.retrying(http.MethodGet, func() (interface{}, error) {
+		return c.doRequest(ctx, http.MethodGet, urlStr, token, nil, o)
+	})
+	if err != nil {
+		return err
+	}
+	return c.decoder.Decode(endpoint, result.([]byte), entity)
+}
+
 // PostJSON sends a POST with optional expected status codes.
 func (c *esiClient) PostJSON(ctx context.Context, endpoint string, token *oauth2.Token, body io.Reader, expectedStatusCodes ...int) ([]byte, error) {
 	urlStr, err := c.buildURL(endpoint, nil)
@@ -129,54 +307,72 @@ func (c *esiClient) DeleteJSON(ctx context.Context, endpoint string, token *oaut
 	return c.DoRequest(ctx, http.MethodDelete, urlStr, token, body, expectedStatusCodes...)
 }
 
-// DoRequest is the core method that actually performs the HTTP request.
+// DoRequest is the core method that actually performs the HTTP request. It
+// is retried as the client's RetryPolicy allows for method, and deduplicated
+// for writes made with WithIdempotencyKey.
 func (c *esiClient) DoRequest(ctx context.Context, method, urlStr string, token *oauth2.Token, body io.Reader, expectedStatus ...int) ([]byte, error) {
+	o := &callOptions{expected: expectedStatus}
+	return c.deduplicated(ctx, method, urlStr, func() ([]byte, error) {
+		// read the body up front so retries can resend it
+		var bodyBytes []byte
+		if body != nil {
+			b, err := io.ReadAll(body)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read request body: %w", err)
+			}
+			bodyBytes = b
+		}
+		result, err := c.retrying(method, func() (interface{}, error) {
+			return c.doRequest(ctx, method, urlStr, token, bodyBytes, o)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return result.([]byte), nil
+	})
+}
+
+// doRequest performs one request with body, refreshing the token first if it
+// is about to expire and once on 401/403.
+func (c *esiClient) doRequest(ctx context.Context, method, urlStr string, token *oauth2.Token, body []byte, o *callOptions) ([]byte, error) {
+	expectedStatus := o.expected
 	if len(expectedStatus) == 0 {
 		expectedStatus = []int{http.StatusOK}
 	}
 
-	// read the entire body so we can retry
-	var bodyBytes []byte
-	if body != nil {
-		b, err := io.ReadAll(body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read request body: %w", err)
-		}
-		bodyBytes = b
+	// an earlier attempt of this call may have refreshed the token already
+	if o.refreshed != nil {
+		token = o.refreshed
+	} else if fresh, err := c.refreshEarly(ctx, token); err != nil {
+		return nil, fmt.Errorf("token refresh failed: %w", err)
+	} else if fresh != token {
+		token, o.refreshed = fresh, fresh
 	}
 
 	// Execute request
-	data, status, err := c.executeRequest(ctx, method, urlStr, token, bytes.NewReader(bodyBytes))
+	data, status, err := c.executeRequest(ctx, method, urlStr, token, bytes.NewReader(body), o)
 	if err != nil {
 		return nil, err
 	}
 
 	// if unauthorized/forbidden and we have refresh capability, try refresh
-	if (status == http.StatusUnauthorized || status == http.StatusForbidden) && canRefresh(token, c.authClient) {
-		newToken, refreshErr := c.authClient.RefreshToken(token.RefreshToken)
-		if refreshErr == nil && newToken != nil {
-			// retry with new token
-			token = newToken
-			data, status, err = c.executeRequest(ctx, method, urlStr, token, bytes.NewReader(bodyBytes))
-			if err != nil {
-				return nil, err
-			}
-		} else {
+	if (status == http.StatusUnauthorized || status == http.StatusForbidden) && canRefresh(token, c.authClient) &&
+		slices.Contains(c.retry.ReplayAfterRefresh, method) {
+		newToken, refreshErr := c.refresh(ctx, token)
+		if refreshErr != nil {
 			return nil, fmt.Errorf("token refresh failed: %w", refreshErr)
 		}
+		// retry with new token
+		token, o.refreshed = newToken, newToken
+		data, status, err = c.executeRequest(ctx, method, urlStr, token, bytes.NewReader(body), o)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	// metrics
-	atomic.AddInt64(&totalCalls, 1)
-	switch {
-	case status == http.StatusNotFound:
-		atomic.AddInt64(&notFoundCount, 1)
-	case status >= 200 && status < 300:
-		atomic.AddInt64(&successCount, 1)
-	default:
-		atomic.AddInt64(&failCount, 1)
+	if status == http.StatusNotModified && o.etag != nil && !statusMatches(status, expectedStatus) {
+		return nil, ErrNotModified
 	}
-
 	if !statusMatches(status, expectedStatus) {
 		return nil, &common.HTTPError{
 			StatusCode: status,
@@ -187,7 +383,7 @@ func (c *esiClient) DoRequest(ctx context.Context, method, urlStr string, token
 }
 
 // executeRequest actually does the low-level HTTP
-func (c *esiClient) executeRequest(ctx context.Context, method, urlStr string, token *oauth2.Token, body io.Reader) ([]byte, int, error) {
+func (c *esiClient) executeRequest(ctx context.Context, method, urlStr string, token *oauth2.Token, body io.Reader, o *callOptions) ([]byte, int, error) {
 	req, err := http.NewRequestWithContext(ctx, method, urlStr, body)
 	if err != nil {
 		return nil, 0, err
@@ -197,20 +393,60 @@ func (c *esiClient) executeRequest(ctx context.Context, method, urlStr string, t
 	if token != nil && token.AccessToken != "" {
 		req.Header.Set("Authorization", "Bearer "+token.AccessToken)
 	}
+	if o.etag != nil && *o.etag != "" {
+		req.Header.Set("If-None-Match", *o.etag)
+	}
 
+	// a 420/429 seen by any goroutine holds every request back
+	if err := c.pause.Wait(ctx); err != nil {
+		return nil, 0, err
+	}
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	start := time.Now()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		c.metrics.RecordResponse(0, time.Since(start))
 		return nil, 0, fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
+	c.metrics.RecordResponse(resp.StatusCode, time.Since(start))
+	if remain, convErr := strconv.Atoi(resp.Header.Get("X-Esi-Error-Limit-Remain")); convErr == nil {
+		c.metrics.SetErrorLimitRemain(remain)
+	}
+	if delay, limited := common.RateLimitDelay(resp, common.DefaultRateLimitPause); limited {
+		c.pause.PauseUntil(time.Now().Add(delay))
+	} else if delay, low := common.ErrorBudgetDelay(resp, c.errorFloor); low {
+		c.pause.PauseUntil(time.Now().Add(delay))
+	}
+	if o.header != nil {
+		*o.header = resp.Header.Clone()
+	}
+	if etag := resp.Header.Get("ETag"); etag != "" && o.etag != nil {
+		*o.etag = etag
+	}
+	if resp.StatusCode < http.StatusBadRequest {
+		if expires, convErr := http.ParseTime(resp.Header.Get("Expires")); convErr == nil {
+			common.RecordExpires(ctx, expires)
+		}
+	}
 
-	data, readErr := io.ReadAll(resp.Body)
+	data, readErr := common.ReadLimited(resp.Body, c.maxBody)
 	if readErr != nil {
-		return nil, resp.StatusCode, fmt.Errorf("failed to read response body: %v", readErr)
+		return nil, resp.StatusCode, fmt.Errorf("failed to read response body: %w", readErr)
 	}
 	return data, resp.StatusCode, nil
 }
 
+// Stats returns a snapshot of this client's request counters.
+func (c *esiClient) Stats() common.ClientStats {
+	return c.stats.Snapshot()
+}
+
 // buildURL merges baseURL + endpoint + params
 func (c *esiClient) buildURL(endpoint string, params map[string]string) (string, error) {
 	base, err := url.Parse(c.baseURL)
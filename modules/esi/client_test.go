@@ -12,6 +12,8 @@ import (
 
 	"golang.org/x/oauth2"
 
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/common/model"
 	"github.com/guarzo/eveapi/modules/esi"
 )
 
@@ -198,3 +200,310 @@ func TestEsiClient_GetBytes_Caching(t *testing.T) {
 		t.Errorf("expected called=1 after second call, got %d", called)
 	}
 }
+
+func TestEsiClient_Stats(t *testing.T) {
+	mockHTTP := &mockHttpClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			status := http.StatusOK
+			if req.URL.Path == "/latest/missing/" {
+				status = http.StatusNotFound
+			}
+			header := http.Header{}
+			header.Set("X-Esi-Error-Limit-Remain", "87")
+			return &http.Response{
+				StatusCode: status,
+				Header:     header,
+				Body:       io.NopCloser(bytes.NewBufferString(`{}`)),
+			}, nil
+		},
+	}
+	client := esi.NewEsiClient("https://esi.evetech.net/latest/", mockHTTP, &mockCache{store: make(map[string][]byte)}, &mockAuth{})
+
+	if s := client.Stats(); s.ErrorLimitRemain != -1 {
+		t.Errorf("expected unknown error limit before any call, got %d", s.ErrorLimitRemain)
+	}
+
+	ctx := context.Background()
+	_, _ = client.GetBytes(ctx, "found/", nil, nil)
+	_, _ = client.GetBytes(ctx, "found/", nil, nil)
+	_, _ = client.GetBytes(ctx, "missing/", nil, nil)
+
+	s := client.Stats()
+	if s.TotalCalls != 2 || s.Successes != 1 || s.NotFound != 1 || s.Failures != 0 {
+		t.Errorf("unexpected call counters: %+v", s)
+	}
+	if s.CacheHits != 1 || s.CacheMisses != 2 {
+		t.Errorf("unexpected cache counters: %+v", s)
+	}
+	if s.ErrorLimitRemain != 87 {
+		t.Errorf("expected error limit 87, got %d", s.ErrorLimitRemain)
+	}
+}
+
+type countingRecorder struct {
+	responses int
+}
+
+func (r *countingRecorder) RecordResponse(int, time.Duration) { r.responses++ }
+func (r *countingRecorder) RecordCache(bool)                  {}
+func (r *countingRecorder) SetErrorLimitRemain(int)           {}
+
+func TestEsiClient_MetricsArePerInstance(t *testing.T) {
+	mockHTTP := &mockHttpClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(`{}`))}, nil
+		},
+	}
+	rec := &countingRecorder{}
+	tq := esi.NewEsiClient("https://esi.evetech.net/latest/", mockHTTP, &mockCache{store: make(map[string][]byte)}, &mockAuth{},
+		esi.WithMetricsRecorder(rec))
+	sisi := esi.NewEsiClient("https://esi.evetech.net/latest/", mockHTTP, &mockCache{store: make(map[string][]byte)}, &mockAuth{})
+
+	ctx := context.Background()
+	_, _ = tq.DoRequest(ctx, http.MethodGet, "https://example.com/a", nil, nil)
+	_, _ = tq.DoRequest(ctx, http.MethodGet, "https://example.com/b", nil, nil)
+	_, _ = sisi.DoRequest(ctx, http.MethodGet, "https://example.com/a", nil, nil)
+
+	if got := tq.Stats().TotalCalls; got != 2 {
+		t.Errorf("expected 2 calls on first client, got %d", got)
+	}
+	if got := sisi.Stats().TotalCalls; got != 1 {
+		t.Errorf("expected 1 call on second client, got %d", got)
+	}
+	if rec.responses != 2 {
+		t.Errorf("expected recorder to see 2 responses, got %d", rec.responses)
+	}
+}
+
+func TestEsiClient_RecordsExpires(t *testing.T) {
+	expires := time.Now().Add(2 * time.Minute).UTC().Truncate(time.Second)
+	mockHTTP := &mockHttpClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			header := http.Header{}
+			header.Set("Expires", expires.Format(http.TimeFormat))
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     header,
+				Body:       io.NopCloser(bytes.NewBufferString(`12.5`)),
+			}, nil
+		},
+	}
+	client := esi.NewEsiClient("https://esi.evetech.net/latest/", mockHTTP, &mockCache{store: make(map[string][]byte)}, &mockAuth{})
+
+	ctx, recorded := common.WithExpiresRecorder(context.Background())
+	var balance float64
+	if err := client.GetFreshJSON(ctx, "characters/1/wallet/", &balance, nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !recorded().Equal(expires) {
+		t.Errorf("expected expiry %s, got %s", expires, recorded())
+	}
+}
+
+func TestEsiClient_LowErrorBudgetPausesGate(t *testing.T) {
+	mockHTTP := &mockHttpClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			header := http.Header{}
+			header.Set("X-Esi-Error-Limit-Remain", "8")
+			header.Set("X-Esi-Error-Limit-Reset", "40")
+			return &http.Response{StatusCode: http.StatusOK, Header: header, Body: io.NopCloser(bytes.NewBufferString(`{}`))}, nil
+		},
+	}
+	gate := common.NewPauseGate()
+	client := esi.NewEsiClient("https://esi.evetech.net/latest/", mockHTTP, &mockCache{store: make(map[string][]byte)}, &mockAuth{},
+		esi.WithPauseGate(gate), esi.WithErrorLimitThreshold(10))
+
+	if _, err := client.GetBytes(context.Background(), "ok/", nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if until := gate.PausedUntil(); time.Until(until) < 30*time.Second {
+		t.Errorf("expected the gate to pause until the error window resets, got %s", until)
+	}
+	if remain := client.Stats().ErrorLimitRemain; remain != 8 {
+		t.Errorf("expected the remaining budget in stats, got %d", remain)
+	}
+}
+
+func TestEsiClient_RateLimitPausesSharedGate(t *testing.T) {
+	var calls int
+	mockHTTP := &mockHttpClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			calls++
+			header := http.Header{}
+			header.Set("Retry-After", "60")
+			return &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Header:     header,
+				Body:       io.NopCloser(bytes.NewBufferString(`{}`)),
+			}, nil
+		},
+	}
+	gate := common.NewPauseGate()
+	client := esi.NewEsiClient("https://esi.evetech.net/latest/", mockHTTP, &mockCache{store: make(map[string][]byte)}, &mockAuth{}, esi.WithPauseGate(gate))
+
+	if _, err := client.GetBytes(context.Background(), "limited/", nil, nil); err == nil {
+		t.Fatalf("expected the 429 to be returned")
+	}
+	if until := gate.PausedUntil(); time.Until(until) < 50*time.Second {
+		t.Fatalf("expected the gate to pause for Retry-After, got %s", until)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetBytes(ctx, "other/", nil, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the paused request to wait for the context, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected no request while paused, got %d calls", calls)
+	}
+}
+
+func TestEsiClient_CallOptions(t *testing.T) {
+	var lastQuery url.Values
+	var lastIfNoneMatch string
+	mockHTTP := &mockHttpClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			lastQuery = req.URL.Query()
+			lastIfNoneMatch = req.Header.Get("If-None-Match")
+			header := http.Header{}
+			header.Set("ETag", `"v2"`)
+			status := http.StatusOK
+			if lastIfNoneMatch == `"v2"` {
+				status = http.StatusNotModified
+			}
+			return &http.Response{
+				StatusCode: status,
+				Header:     header,
+				Body:       io.NopCloser(bytes.NewBufferString(`[1,2]`)),
+			}, nil
+		},
+	}
+	cache := &mockCache{store: make(map[string][]byte)}
+	client := esi.NewEsiClient("https://esi.evetech.net/latest/", mockHTTP, cache, &mockAuth{})
+	ctx := context.Background()
+
+	var ids []int64
+	if err := client.GetJSON(ctx, "wars/", &ids, nil, nil, esi.WithPage(3), esi.WithNoCache()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lastQuery.Get("page") != "3" || lastQuery.Get("datasource") != "tranquility" {
+		t.Errorf("unexpected query: %v", lastQuery)
+	}
+	if len(cache.store) != 0 {
+		t.Errorf("expected WithNoCache to skip the response cache, got %d entries", len(cache.store))
+	}
+	if err := client.GetJSON(common.WithoutCache(ctx), "wars/", &ids, nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cache.store) != 0 {
+		t.Errorf("expected common.WithoutCache to skip the response cache, got %d entries", len(cache.store))
+	}
+
+	var etag string
+	if err := client.GetFreshJSON(ctx, "wars/", &ids, nil, nil, esi.WithEtag(&etag)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if etag != `"v2"` || lastIfNoneMatch != "" {
+		t.Errorf("expected the ETag to be captured, got %q (sent %q)", etag, lastIfNoneMatch)
+	}
+	if err := client.GetFreshJSON(ctx, "wars/", &ids, nil, nil, esi.WithEtag(&etag)); !errors.Is(err, esi.ErrNotModified) {
+		t.Errorf("expected ErrNotModified, got %v", err)
+	}
+}
+
+func TestEsiClient_StrictDecoding(t *testing.T) {
+	mockHTTP := &mockHttpClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{},
+				Body:       io.NopCloser(bytes.NewBufferString(`{"name":"Jita","region_tag":"forge"}`)),
+			}, nil
+		},
+	}
+	var reported []string
+	onUnknown := func(source, field string) { reported = append(reported, source+" "+field) }
+
+	lenient := esi.NewEsiClient("https://esi.evetech.net/latest/", mockHTTP, &mockCache{store: make(map[string][]byte)}, &mockAuth{},
+		esi.WithUnknownFieldHandler(onUnknown))
+	var sys model.SolarSystem
+	if err := lenient.GetFreshJSON(context.Background(), "universe/systems/30000142/", &sys, nil, nil); err != nil {
+		t.Fatalf("unexpected error in lenient mode: %v", err)
+	}
+	if sys.Name != "Jita" || len(reported) != 1 || reported[0] != "universe/systems/30000142/ region_tag" {
+		t.Errorf("expected the unknown field to be reported, got %+v, %v", sys, reported)
+	}
+
+	strict := esi.NewEsiClient("https://esi.evetech.net/latest/", mockHTTP, &mockCache{store: make(map[string][]byte)}, &mockAuth{},
+		esi.WithStrictDecoding())
+	if err := strict.GetFreshJSON(context.Background(), "universe/systems/30000142/", &sys, nil, nil); err == nil {
+		t.Error("expected strict decoding to reject the unknown field")
+	}
+}
+
+func TestEsiClient_MaxResponseSize(t *testing.T) {
+	mockHTTP := &mockHttpClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{},
+				Body:       io.NopCloser(bytes.NewBufferString(`[1,2,3,4,5,6,7,8,9,10]`)),
+			}, nil
+		},
+	}
+	client := esi.NewEsiClient("https://esi.evetech.net/latest/", mockHTTP, &mockCache{store: make(map[string][]byte)}, &mockAuth{},
+		esi.WithMaxResponseSize(8))
+
+	var ids []int
+	err := client.GetFreshJSON(context.Background(), "markets/prices/", &ids, nil, nil)
+	if !errors.Is(err, common.ErrResponseTooLarge) {
+		t.Errorf("expected ErrResponseTooLarge, got %v", err)
+	}
+}
+
+func TestEsiClient_TokenUpdateCallback(t *testing.T) {
+	var auths []string
+	mockHTTP := &mockHttpClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			auths = append(auths, req.Header.Get("Authorization"))
+			status := http.StatusOK
+			switch len(auths) {
+			case 1:
+				status = http.StatusUnauthorized
+			case 2:
+				status = http.StatusBadGateway
+			}
+			return &http.Response{StatusCode: status, Body: io.NopCloser(bytes.NewBufferString(`{}`))}, nil
+		},
+		retryFunc: func(op func() (interface{}, error)) (interface{}, error) {
+			if v, err := op(); err == nil {
+				return v, nil
+			}
+			return op()
+		},
+	}
+	refreshes := 0
+	auth := &mockAuth{refreshFunc: func(r string) (*oauth2.Token, error) {
+		refreshes++
+		return &oauth2.Token{AccessToken: "new"}, nil // refresh token not rotated
+	}}
+	var updates [][2]*oauth2.Token
+	client := esi.NewEsiClient("https://esi.evetech.net/latest/", mockHTTP, &mockCache{store: map[string][]byte{}}, auth,
+		esi.WithTokenUpdateCallback(func(ctx context.Context, old, refreshed *oauth2.Token) {
+			updates = append(updates, [2]*oauth2.Token{old, refreshed})
+		}))
+
+	token := &oauth2.Token{AccessToken: "old", RefreshToken: "refresh", TokenType: "Bearer"}
+	if _, err := client.DoRequest(context.Background(), http.MethodGet, "https://example.com/test", token, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if refreshes != 1 || len(auths) != 3 || auths[2] != "Bearer new" {
+		t.Errorf("expected the retry to reuse the refreshed token, got %d refreshes and %v", refreshes, auths)
+	}
+	if len(updates) != 1 || updates[0][0] != token {
+		t.Fatalf("expected one update for the caller's token, got %+v", updates)
+	}
+	if r := updates[0][1]; r.AccessToken != "new" || r.RefreshToken != "refresh" || r.TokenType != "Bearer" {
+		t.Errorf("expected the refreshed token hydrated from the old one, got %+v", r)
+	}
+}
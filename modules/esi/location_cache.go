@@ -0,0 +1,119 @@
+package esi
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// defaultLocationCacheTTL bounds how long a station/structure to system
+// mapping is trusted. Structures can be unanchored and re-anchored elsewhere.
+const defaultLocationCacheTTL = 24 * time.Hour
+
+// LocationCache maps station and structure IDs to their solar system.
+type LocationCache interface {
+	Get(locationID int64) (systemID model.SystemID, found bool)
+	Set(locationID int64, systemID model.SystemID)
+	Clear()
+}
+
+// NewLocationCache returns an in-memory LocationCache whose entries expire
+// after ttl. A ttl <= 0 disables expiry.
+func NewLocationCache(ttl time.Duration) LocationCache {
+	return &memoryLocationCache{ttl: ttl, entries: make(map[int64]locationEntry)}
+}
+
+type locationEntry struct {
+	systemID int64
+	expires  time.Time
+}
+
+type memoryLocationCache struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[int64]locationEntry
+}
+
+func (c *memoryLocationCache) Get(locationID int64) (int64, bool) {
+	c.mu.RLock()
+	e, ok := c.entries[locationID]
+	c.mu.RUnlock()
+	if !ok {
+		return 0, false
+	}
+	if !e.expires.IsZero() && time.Now().After(e.expires) {
+		c.mu.Lock()
+		delete(c.entries, locationID)
+		c.mu.Unlock()
+		return 0, false
+	}
+	return e.systemID, true
+}
+
+func (c *memoryLocationCache) Set(locationID int64, systemID model.SystemID) {
+	e := locationEntry{systemID: systemID}
+	if c.ttl > 0 {
+		e.expires = time.Now().Add(c.ttl)
+	}
+	c.mu.Lock()
+	c.entries[locationID] = e
+	c.mu.Unlock()
+}
+
+func (c *memoryLocationCache) Clear() {
+	c.mu.Lock()
+	c.entries = make(map[int64]locationEntry)
+	c.mu.Unlock()
+}
+
+// NewRepositoryLocationCache stores location mappings in repo so they can be
+// shared between processes (e.g. through Redis). Clear only removes the keys
+// written through this instance.
+func NewRepositoryLocationCache(repo common.CacheRepository, ttl time.Duration) LocationCache {
+	return &repoLocationCache{repo: repo, ttl: ttl, keys: make(map[string]struct{})}
+}
+
+type repoLocationCache struct {
+	repo common.CacheRepository
+	ttl  time.Duration
+
+	mu   sync.Mutex
+	keys map[string]struct{}
+}
+
+func locationCacheKey(locationID int64) string {
+	return "esi:location:" + strconv.FormatInt(locationID, 10)
+}
+
+func (c *repoLocationCache) Get(locationID int64) (int64, bool) {
+	raw, found := c.repo.Get(locationCacheKey(locationID))
+	if !found {
+		return 0, false
+	}
+	systemID, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return systemID, true
+}
+
+func (c *repoLocationCache) Set(locationID int64, systemID model.SystemID) {
+	key := locationCacheKey(locationID)
+	c.repo.Set(key, []byte(strconv.FormatInt(systemID, 10)), c.ttl)
+	c.mu.Lock()
+	c.keys[key] = struct{}{}
+	c.mu.Unlock()
+}
+
+func (c *repoLocationCache) Clear() {
+	c.mu.Lock()
+	keys := c.keys
+	c.keys = make(map[string]struct{})
+	c.mu.Unlock()
+	for key := range keys {
+		c.repo.Delete(key)
+	}
+}
@@ -0,0 +1,65 @@
+package esi_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/modules/esi"
+)
+
+func TestLocationCache_ExpiryAndClear(t *testing.T) {
+	c := esi.NewLocationCache(time.Millisecond)
+	c.Set(60003760, 30000142)
+	if sys, ok := c.Get(60003760); !ok || sys != 30000142 {
+		t.Fatalf("expected cached system, got %d, %v", sys, ok)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := c.Get(60003760); ok {
+		t.Error("expected entry to expire")
+	}
+
+	c = esi.NewLocationCache(0)
+	c.Set(1, 2)
+	c.Clear()
+	if _, ok := c.Get(1); ok {
+		t.Error("expected Clear to drop entries")
+	}
+}
+
+func TestRepositoryLocationCache(t *testing.T) {
+	repo := &mockCache{store: make(map[string][]byte)}
+	c := esi.NewRepositoryLocationCache(repo, time.Hour)
+	c.Set(1, 30000142)
+
+	shared := esi.NewRepositoryLocationCache(repo, time.Hour)
+	if sys, ok := shared.Get(1); !ok || sys != 30000142 {
+		t.Fatalf("expected shared lookup to hit, got %d, %v", sys, ok)
+	}
+	c.Clear()
+	if _, ok := shared.Get(1); ok {
+		t.Error("expected Clear to delete the repository key")
+	}
+}
+
+func TestEsiService_LocationCacheNotShared(t *testing.T) {
+	calls := 0
+	client := &mockEsiClient{
+		getBytesFunc: func(ctx context.Context, endpoint string, token *oauth2.Token, params map[string]string) ([]byte, error) {
+			calls++
+			return []byte(`{"station_id":60003760,"system_id":30000142}`), nil
+		},
+	}
+	ctx := context.Background()
+	a := esi.NewEsiService(client)
+	b := esi.NewEsiService(client, esi.WithLocationCache(esi.NewLocationCache(time.Hour)))
+
+	_, _ = a.GetStation(ctx, 60003760)
+	_, _ = a.GetStation(ctx, 60003760)
+	_, _ = b.GetStation(ctx, 60003760)
+	if calls != 2 {
+		t.Errorf("expected one fetch per service, got %d", calls)
+	}
+}
@@ -0,0 +1,140 @@
+package esi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common"
+)
+
+// PageIterator walks a paginated ESI endpoint one page at a time, fetching
+// each page only when Next is called, so callers can stop early:
+//
+//	it := esi.Pages[model.MarketOrder](ctx, client, "markets/10000002/orders/", nil, nil)
+//	for it.Next() && it.PageNumber() <= 3 {
+//		use(it.Page())
+//	}
+//	if err := it.Err(); err != nil { ... }
+type PageIterator[T any] struct {
+	ctx      context.Context
+	client   EsiClient
+	endpoint string
+	token    *oauth2.Token
+	params   map[string]string
+
+	page  int
+	total int
+	items []T
+	err   error
+}
+
+// Pages returns an iterator over the pages of endpoint. The page count is
+// taken from the X-Pages header of the first response; without one the
+// endpoint is treated as a single page.
+func Pages[T any](ctx context.Context, client EsiClient, endpoint string, token *oauth2.Token, params map[string]string) *PageIterator[T] {
+	return &PageIterator[T]{ctx: ctx, client: client, endpoint: endpoint, token: token, params: params, total: 1}
+}
+
+// Next fetches the next page. It returns false once every page has been read
+// or a fetch failed; check Err to tell the two apart.
+func (it *PageIterator[T]) Next() bool {
+	if it.err != nil || it.page >= it.total {
+		return false
+	}
+	next := it.page + 1
+	var chunk []T
+	var header http.Header
+	if err := it.client.GetFreshJSON(it.ctx, it.endpoint, &chunk, it.token, it.params, WithPage(next), WithResponseHeader(&header)); err != nil {
+		it.err = fmt.Errorf("failed to fetch %s page %d: %w", it.endpoint, next, err)
+		return false
+	}
+	if next == 1 {
+		if n, err := strconv.Atoi(header.Get("X-Pages")); err == nil && n > 0 {
+			it.total = n
+		}
+	}
+	it.page, it.items = next, chunk
+	return true
+}
+
+// Page returns the items of the current page.
+func (it *PageIterator[T]) Page() []T {
+	return it.items
+}
+
+// PageNumber returns the 1-based number of the current page.
+func (it *PageIterator[T]) PageNumber() int {
+	return it.page
+}
+
+// TotalPages returns the page count reported by ESI, known after the first Next.
+func (it *PageIterator[T]) TotalPages() int {
+	return it.total
+}
+
+// Err returns the error that stopped iteration, if any.
+func (it *PageIterator[T]) Err() error {
+	return it.err
+}
+
+// WithPageConcurrency bounds how many pages GetJSONAllPages fetches at once
+// (common.DefaultConcurrency by default).
+func WithPageConcurrency(n int) ClientOption {
+	return func(c *esiClient) {
+		c.pageWorkers = n
+	}
+}
+
+// GetJSONAllPages fetches every page of a paginated endpoint into entity,
+// which must point to a slice. The first page's X-Pages header gives the
+// page count; the remaining pages are fetched concurrently and appended in
+// page order. Pages are fetched fresh, as with GetFreshJSON, so they all
+// come from the same ESI snapshot as far as ESI's cache allows.
+func (c *esiClient) GetJSONAllPages(ctx context.Context, endpoint string, entity interface{}, token *oauth2.Token, params map[string]string, opts ...CallOption) error {
+	dst := reflect.ValueOf(entity)
+	if dst.Kind() != reflect.Pointer || dst.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("GetJSONAllPages needs a pointer to a slice, got %T", entity)
+	}
+	sliceType := dst.Elem().Type()
+	fetch := func(ctx context.Context, page int, extra ...CallOption) (reflect.Value, error) {
+		chunk := reflect.New(sliceType)
+		pageOpts := append(append(append([]CallOption(nil), opts...), extra...), WithPage(page))
+		if err := c.GetFreshJSON(ctx, endpoint, chunk.Interface(), token, params, pageOpts...); err != nil {
+			return reflect.Value{}, fmt.Errorf("failed to fetch %s page %d: %w", endpoint, page, err)
+		}
+		return chunk.Elem(), nil
+	}
+
+	var header http.Header
+	first, err := fetch(ctx, 1, WithResponseHeader(&header))
+	if err != nil {
+		return err
+	}
+	total, _ := strconv.Atoi(header.Get("X-Pages"))
+	chunks := make([]reflect.Value, max(total, 1))
+	chunks[0] = first
+	var rest []int
+	for page := 2; page <= total; page++ {
+		rest = append(rest, page)
+	}
+	err = common.Batch(ctx, rest, func(ctx context.Context, page int) error {
+		chunk, err := fetch(ctx, page)
+		chunks[page-1] = chunk
+		return err
+	}, c.pageWorkers)
+	if err != nil {
+		return err
+	}
+
+	out := reflect.MakeSlice(sliceType, 0, 0)
+	for _, chunk := range chunks {
+		out = reflect.AppendSlice(out, chunk)
+	}
+	dst.Elem().Set(out)
+	return nil
+}
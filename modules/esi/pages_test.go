@@ -0,0 +1,125 @@
+package esi_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/modules/esi"
+)
+
+func TestPages_StopsEarly(t *testing.T) {
+	var fetched []string
+	mockHTTP := &mockHttpClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			page := req.URL.Query().Get("page")
+			fetched = append(fetched, page)
+			header := http.Header{}
+			header.Set("X-Pages", "5")
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     header,
+				Body:       io.NopCloser(bytes.NewBufferString(`[` + page + `]`)),
+			}, nil
+		},
+	}
+	client := esi.NewEsiClient("https://esi.evetech.net/latest/", mockHTTP, &mockCache{store: make(map[string][]byte)}, &mockAuth{})
+
+	it := esi.Pages[int](context.Background(), client, "markets/10000002/orders/", nil, nil)
+	var got []int
+	for it.Next() {
+		got = append(got, it.Page()...)
+		if it.PageNumber() == 3 {
+			break
+		}
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if it.TotalPages() != 5 {
+		t.Errorf("expected 5 pages, got %d", it.TotalPages())
+	}
+	if len(got) != 3 || got[2] != 3 || len(fetched) != 3 {
+		t.Errorf("expected only pages 1-3 to be fetched, got %v (fetched %v)", got, fetched)
+	}
+}
+
+func TestEsiClient_GetJSONAllPages(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	mockHTTP := &mockHttpClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+			}
+			time.Sleep(5 * time.Millisecond)
+			page := req.URL.Query().Get("page")
+			header := http.Header{}
+			header.Set("X-Pages", "6")
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     header,
+				Body:       io.NopCloser(bytes.NewBufferString(`[` + page + `,` + page + `0]`)),
+			}, nil
+		},
+	}
+	client := esi.NewEsiClient("https://esi.evetech.net/latest/", mockHTTP, &mockCache{store: make(map[string][]byte)}, &mockAuth{},
+		esi.WithPageConcurrency(2))
+
+	var got []int
+	if err := client.GetJSONAllPages(context.Background(), "characters/1/assets/", &got, nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 12 || got[0] != 1 || got[1] != 10 || got[10] != 6 {
+		t.Errorf("expected the pages concatenated in order, got %v", got)
+	}
+	if p := peak.Load(); p > 2 {
+		t.Errorf("expected at most 2 pages in flight, saw %d", p)
+	}
+
+	var wrong map[string]int
+	if err := client.GetJSONAllPages(context.Background(), "characters/1/assets/", &wrong, nil, nil); err == nil {
+		t.Error("expected a non-slice entity to be rejected")
+	}
+}
+
+func TestEsiService_WalletJournal_FullSinglePage(t *testing.T) {
+	full := "[" + strings.TrimSuffix(strings.Repeat(`{"id":1},`, 2500), ",") + "]"
+	var fetched []string
+	mockHTTP := &mockHttpClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			page := req.URL.Query().Get("page")
+			fetched = append(fetched, page)
+			if page != "1" {
+				return &http.Response{
+					StatusCode: http.StatusNotFound,
+					Body:       io.NopCloser(bytes.NewBufferString(`{"error":"Requested page does not exist!"}`)),
+				}, nil
+			}
+			header := http.Header{}
+			header.Set("X-Pages", "1")
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     header,
+				Body:       io.NopCloser(bytes.NewBufferString(full)),
+			}, nil
+		},
+	}
+	client := esi.NewEsiClient("https://esi.evetech.net/latest/", mockHTTP, &mockCache{store: make(map[string][]byte)}, &mockAuth{})
+	svc := esi.NewEsiService(client)
+
+	entries, err := svc.GetCharacterWalletJournal(context.Background(), 1, &oauth2.Token{AccessToken: "t"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 2500 || len(fetched) != 1 {
+		t.Errorf("expected one full page and no further requests, got %d entries from pages %v", len(entries), fetched)
+	}
+}
@@ -0,0 +1,204 @@
+package esi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// ErrNoPoolToken is returned when no pooled token satisfies a requirement.
+var ErrNoPoolToken = errors.New("esi: no pooled token satisfies the requirement")
+
+// defaultForbiddenCooldown is how long a token that got a 403 is skipped.
+const defaultForbiddenCooldown = time.Hour
+
+// PoolToken is a character token registered with a ClientPool, along with
+// the scopes it was granted and the character's corporation roles.
+type PoolToken struct {
+	CharacterID   model.CharacterID
+	CorporationID model.CorporationID
+	Token         *oauth2.Token
+	Scopes        []string
+	Roles         []string
+}
+
+// Requirement describes the scopes and corporation roles a call needs. A
+// token qualifies if it has every scope and at least one of the roles (any
+// role if Roles is empty).
+type Requirement struct {
+	Scopes []string
+	Roles  []string
+}
+
+func (r Requirement) satisfiedBy(t *PoolToken) bool {
+	for _, want := range r.Scopes {
+		if !contains(t.Scopes, want) {
+			return false
+		}
+	}
+	if len(r.Roles) == 0 {
+		return true
+	}
+	for _, want := range r.Roles {
+		if contains(t.Roles, want) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientPool spreads authenticated calls for a corporation across every
+// pooled token that may make them (e.g. all of its directors), rotating
+// round-robin and moving on to the next token when ESI answers 403.
+type ClientPool struct {
+	service  EsiService
+	cooldown time.Duration
+	now      func() time.Time
+
+	mu        sync.Mutex
+	tokens    []*PoolToken
+	next      map[int64]int
+	forbidden map[int64]time.Time
+}
+
+// NewClientPool constructs an empty ClientPool around service.
+func NewClientPool(service EsiService) *ClientPool {
+	return &ClientPool{
+		service:   service,
+		cooldown:  defaultForbiddenCooldown,
+		now:       time.Now,
+		next:      make(map[int64]int),
+		forbidden: make(map[int64]time.Time),
+	}
+}
+
+// Service returns the EsiService calls are made with.
+func (p *ClientPool) Service() EsiService {
+	return p.service
+}
+
+// SetForbiddenCooldown changes how long a token is skipped after a 403.
+func (p *ClientPool) SetForbiddenCooldown(d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cooldown = d
+}
+
+// Add registers a token, replacing any earlier token of the same character.
+func (p *ClientPool) Add(t PoolToken) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, existing := range p.tokens {
+		if existing.CharacterID == t.CharacterID {
+			p.tokens[i] = &t
+			delete(p.forbidden, t.CharacterID)
+			return
+		}
+	}
+	p.tokens = append(p.tokens, &t)
+}
+
+// Remove unregisters a character's token.
+func (p *ClientPool) Remove(characterID model.CharacterID) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, t := range p.tokens {
+		if t.CharacterID == characterID {
+			p.tokens = append(p.tokens[:i], p.tokens[i+1:]...)
+			break
+		}
+	}
+	delete(p.forbidden, characterID)
+}
+
+// candidates returns the usable tokens of a corporation that satisfy req,
+// starting at the corporation's round-robin position, and advances it.
+func (p *ClientPool) candidates(corporationID model.CorporationID, req Requirement) []PoolToken {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	var matching []PoolToken
+	for _, t := range p.tokens {
+		if t.CorporationID != corporationID || !req.satisfiedBy(t) {
+			continue
+		}
+		if until, ok := p.forbidden[t.CharacterID]; ok && now.Before(until) {
+			continue
+		}
+		matching = append(matching, *t)
+	}
+	if len(matching) == 0 {
+		return nil
+	}
+	start := p.next[corporationID] % len(matching)
+	p.next[corporationID] = start + 1
+	return append(matching[start:], matching[:start]...)
+}
+
+// Token returns the next token of a corporation that satisfies req.
+func (p *ClientPool) Token(corporationID model.CorporationID, req Requirement) (*PoolToken, error) {
+	c := p.candidates(corporationID, req)
+	if len(c) == 0 {
+		return nil, fmt.Errorf("corporation %d: %w", corporationID, ErrNoPoolToken)
+	}
+	return &c[0], nil
+}
+
+// Do calls fn with the service and a qualifying token of the corporation.
+// If fn fails with a 403, the token is benched for the forbidden cooldown
+// and the next qualifying token is tried.
+func (p *ClientPool) Do(ctx context.Context, corporationID model.CorporationID, req Requirement, fn func(ctx context.Context, svc EsiService, token *oauth2.Token) error) error {
+	c := p.candidates(corporationID, req)
+	if len(c) == 0 {
+		return fmt.Errorf("corporation %d: %w", corporationID, ErrNoPoolToken)
+	}
+	var errs []error
+	for _, t := range c {
+		err := fn(ctx, p.service, t.Token)
+		if err == nil {
+			return nil
+		}
+		var httpErr *common.HTTPError
+		if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusForbidden {
+			return err
+		}
+		p.bench(t.CharacterID)
+		errs = append(errs, fmt.Errorf("character %d: %w", t.CharacterID, err))
+	}
+	return errors.Join(errs...)
+}
+
+func (p *ClientPool) bench(characterID model.CharacterID) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.forbidden[characterID] = p.now().Add(p.cooldown)
+}
+
+// TokenSource returns a function handing out pooled tokens that satisfy
+// req; it can be used as a monitor.TokenSource.
+func (p *ClientPool) TokenSource(req Requirement) func(ctx context.Context, corporationID model.CorporationID) (*oauth2.Token, error) {
+	return func(ctx context.Context, corporationID model.CorporationID) (*oauth2.Token, error) {
+		t, err := p.Token(corporationID, req)
+		if err != nil {
+			return nil, err
+		}
+		return t.Token, nil
+	}
+}
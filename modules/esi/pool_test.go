@@ -0,0 +1,54 @@
+package esi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/modules/esi"
+)
+
+func TestClientPool_RotatesOnForbidden(t *testing.T) {
+	pool := esi.NewClientPool(esi.NewEsiService(&mockEsiClient{}))
+	const corp = 98000001
+	structures := esi.Requirement{Scopes: []string{"esi-corporations.read_structures.v1"}, Roles: []string{"Director", "Station_Manager"}}
+	pool.Add(esi.PoolToken{CharacterID: 1, CorporationID: corp, Token: &oauth2.Token{AccessToken: "one"},
+		Scopes: structures.Scopes, Roles: []string{"Director"}})
+	pool.Add(esi.PoolToken{CharacterID: 2, CorporationID: corp, Token: &oauth2.Token{AccessToken: "two"},
+		Scopes: structures.Scopes, Roles: []string{"Station_Manager"}})
+	pool.Add(esi.PoolToken{CharacterID: 3, CorporationID: corp, Token: &oauth2.Token{AccessToken: "three"},
+		Scopes: structures.Scopes})
+	pool.Add(esi.PoolToken{CharacterID: 4, CorporationID: 98000002, Token: &oauth2.Token{AccessToken: "four"},
+		Scopes: structures.Scopes, Roles: []string{"Director"}})
+
+	var used []string
+	err := pool.Do(context.Background(), corp, structures, func(ctx context.Context, svc esi.EsiService, token *oauth2.Token) error {
+		used = append(used, token.AccessToken)
+		if token.AccessToken == "one" {
+			return &common.HTTPError{StatusCode: http.StatusForbidden}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(used) != 2 || used[0] != "one" || used[1] != "two" {
+		t.Fatalf("expected rotation from one to two, got %v", used)
+	}
+
+	// the forbidden token is benched, so only "two" remains
+	for i := 0; i < 3; i++ {
+		tok, err := pool.Token(corp, structures)
+		if err != nil || tok.CharacterID != 2 {
+			t.Fatalf("expected character 2, got %+v, %v", tok, err)
+		}
+	}
+
+	if _, err := pool.Token(corp, esi.Requirement{Scopes: []string{"esi-wallet.read_corporation_wallets.v1"}}); !errors.Is(err, esi.ErrNoPoolToken) {
+		t.Errorf("expected ErrNoPoolToken, got %v", err)
+	}
+}
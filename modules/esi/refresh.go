@@ -0,0 +1,112 @@
+package esi
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// DefaultRefreshSkew is how long before its expiry a token is refreshed by
+// default. EVE SSO access tokens live for 20 minutes.
+const DefaultRefreshSkew = time.Minute
+
+// WithRefreshSkew refreshes tokens d before their Expiry, before the request
+// that would otherwise fail with them is sent (DefaultRefreshSkew by
+// default). With d < 0 tokens are only refreshed after ESI rejects them.
+func WithRefreshSkew(d time.Duration) ClientOption {
+	return func(c *esiClient) {
+		c.refreshSkew = d
+	}
+}
+
+// refreshEarly returns token, or a refreshed one if token expires within
+// the refresh skew. A failed refresh is only an error if token has expired.
+func (c *esiClient) refreshEarly(ctx context.Context, token *oauth2.Token) (*oauth2.Token, error) {
+	if c.refreshSkew < 0 || !canRefresh(token, c.authClient) || token.Expiry.IsZero() {
+		return token, nil
+	}
+	now := c.now()
+	if now.Add(c.refreshSkew).Before(token.Expiry) {
+		return token, nil
+	}
+	fresh, err := c.refresh(ctx, token)
+	if err != nil {
+		if now.Before(token.Expiry) {
+			return token, nil
+		}
+		return nil, err
+	}
+	return fresh, nil
+}
+
+// refresh exchanges token's refresh token for a new token. Fields the SSO
+// leaves out of its answer, such as an unrotated refresh token, are carried
+// over from token, and the result is reported to the token update callback.
+//
+// Concurrent refreshes of one refresh token share a single SSO request, and
+// callers still holding the old token get the new one for as long as it is
+// fresh, so a burst of calls with an expiring token refreshes it once.
+func (c *esiClient) refresh(ctx context.Context, token *oauth2.Token) (*oauth2.Token, error) {
+	key := token.RefreshToken
+	if fresh := c.recentRefresh(key, token); fresh != nil {
+		return fresh, nil
+	}
+	v, err, _ := c.refreshes.Do(key, func() (interface{}, error) {
+		fresh, err := c.authClient.RefreshToken(token.RefreshToken)
+		if err != nil {
+			return nil, err
+		}
+		if fresh == nil {
+			return nil, errors.New("auth client returned no token")
+		}
+		hydrated := *fresh
+		if hydrated.RefreshToken == "" {
+			hydrated.RefreshToken = token.RefreshToken
+		}
+		if hydrated.TokenType == "" {
+			hydrated.TokenType = token.TokenType
+		}
+		c.rememberRefresh(key, &hydrated)
+		if c.onTokenUpdate != nil {
+			c.onTokenUpdate(ctx, token, &hydrated)
+		}
+		return &hydrated, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*oauth2.Token), nil
+}
+
+// recentRefresh returns the token an earlier refresh of key produced, if it
+// is still fresh and is not the token being replaced.
+func (c *esiClient) recentRefresh(key string, token *oauth2.Token) *oauth2.Token {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fresh, ok := c.refreshed[key]
+	if !ok || fresh.AccessToken == token.AccessToken || !c.fresh(fresh, c.now()) {
+		return nil
+	}
+	return fresh
+}
+
+// rememberRefresh records the token a refresh of key produced, dropping
+// records that went stale.
+func (c *esiClient) rememberRefresh(key string, token *oauth2.Token) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	for k, t := range c.refreshed {
+		if !c.fresh(t, now) {
+			delete(c.refreshed, k)
+		}
+	}
+	c.refreshed[key] = token
+}
+
+// fresh reports whether token is outside the refresh skew at now.
+func (c *esiClient) fresh(token *oauth2.Token, now time.Time) bool {
+	return token.Expiry.IsZero() || now.Add(max(c.refreshSkew, 0)).Before(token.Expiry)
+}
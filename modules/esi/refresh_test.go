@@ -0,0 +1,76 @@
+package esi_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/modules/esi"
+)
+
+func TestEsiClient_ProactiveRefresh(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		auths []string
+	)
+	mockHTTP := &mockHttpClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			auths = append(auths, req.Header.Get("Authorization"))
+			mu.Unlock()
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(`{}`))}, nil
+		},
+	}
+	var refreshes atomic.Int32
+	release := make(chan struct{})
+	auth := &mockAuth{refreshFunc: func(r string) (*oauth2.Token, error) {
+		refreshes.Add(1)
+		<-release
+		return &oauth2.Token{AccessToken: "new", Expiry: time.Now().Add(20 * time.Minute)}, nil
+	}}
+	client := esi.NewEsiClient("https://esi.evetech.net/latest/", mockHTTP, &mockCache{store: map[string][]byte{}}, auth,
+		esi.WithRefreshSkew(time.Minute))
+
+	expiring := &oauth2.Token{AccessToken: "old", RefreshToken: "refresh", TokenType: "Bearer", Expiry: time.Now().Add(30 * time.Second)}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.DoRequest(context.Background(), http.MethodGet, "https://example.com/test", expiring, nil); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	// a late caller still holding the old token gets the refreshed one too
+	if _, err := client.DoRequest(context.Background(), http.MethodGet, "https://example.com/test", expiring, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := refreshes.Load(); n != 1 {
+		t.Errorf("expected one refresh, got %d", n)
+	}
+	for _, a := range auths {
+		if a != "Bearer new" {
+			t.Fatalf("expected every request to use the refreshed token, got %v", auths)
+		}
+	}
+
+	valid := &oauth2.Token{AccessToken: "valid", RefreshToken: "other", TokenType: "Bearer", Expiry: time.Now().Add(10 * time.Minute)}
+	if _, err := client.DoRequest(context.Background(), http.MethodGet, "https://example.com/test", valid, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := refreshes.Load(); n != 1 || auths[len(auths)-1] != "Bearer valid" {
+		t.Errorf("expected a token outside the skew to be used as is, got %d refreshes", n)
+	}
+}
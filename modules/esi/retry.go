@@ -0,0 +1,100 @@
+package esi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// RetryPolicy decides which requests the client sends more than once.
+type RetryPolicy struct {
+	// Methods are retried with exponential backoff after a 5xx response.
+	Methods []string
+	// ReplayAfterRefresh are sent again with a refreshed token after a 401
+	// or 403. The rejected attempt had no effect, so replaying is safe for
+	// any method; drop a method to surface the auth error instead.
+	ReplayAfterRefresh []string
+}
+
+// DefaultRetryPolicy retries reads only: a 5xx on a write does not say
+// whether ESI applied it, so a retried fleet invite or contact add may be
+// applied twice. Every method is replayed after a token refresh.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		Methods: []string{http.MethodGet, http.MethodHead},
+		ReplayAfterRefresh: []string{
+			http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodDelete,
+		},
+	}
+}
+
+// WithRetryPolicy replaces which requests are retried (DefaultRetryPolicy by
+// default).
+func WithRetryPolicy(p RetryPolicy) ClientOption {
+	return func(c *esiClient) {
+		c.retry = p
+	}
+}
+
+// DefaultIdempotencyWindow is how long a keyed write's response is kept by
+// default.
+const DefaultIdempotencyWindow = 24 * time.Hour
+
+// WithIdempotencyWindow sets how long the response of a write made with an
+// idempotency key answers repeats of it (DefaultIdempotencyWindow by default).
+func WithIdempotencyWindow(d time.Duration) ClientOption {
+	return func(c *esiClient) {
+		c.idempotencyWindow = d
+	}
+}
+
+type idempotencyKeyContext struct{}
+
+// WithIdempotencyKey returns a context whose writes (DoRequest, PostJSON,
+// DeleteJSON) are deduplicated by key: a write repeating the method, URL and
+// key of a successful one within the idempotency window returns the first
+// response without reaching ESI, and concurrent repeats share one request.
+// Responses are kept in the client's cache, so a shared cache deduplicates
+// across instances. Use a key per logical operation, e.g. per invite.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContext{}, key)
+}
+
+func idempotencyKey(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyKeyContext{}).(string)
+	return key, ok && key != ""
+}
+
+// retrying runs op, retrying it with backoff if the policy allows for method.
+func (c *esiClient) retrying(method string, op func() (interface{}, error)) (interface{}, error) {
+	if slices.Contains(c.retry.Methods, method) {
+		return c.httpClient.RetryWithExponentialBackoff(op)
+	}
+	return op()
+}
+
+// deduplicated runs a write once per idempotency key carried by ctx, if any.
+func (c *esiClient) deduplicated(ctx context.Context, method, urlStr string, send func() ([]byte, error)) ([]byte, error) {
+	key, ok := idempotencyKey(ctx)
+	if !ok || method == http.MethodGet || method == http.MethodHead {
+		return send()
+	}
+	cacheKey := fmt.Sprintf("esi:idempotency:%s:%s:%s", method, urlStr, key)
+	if data, found, err := c.cache.GetCtx(ctx, cacheKey); err == nil && found {
+		return data, nil
+	}
+	v, err, _ := c.writes.Do(cacheKey, func() (interface{}, error) {
+		data, err := send()
+		if err != nil {
+			return nil, err
+		}
+		_ = c.cache.SetCtx(ctx, cacheKey, data, c.idempotencyWindow)
+		return data, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
+}
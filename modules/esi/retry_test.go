@@ -0,0 +1,116 @@
+package esi_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/modules/esi"
+)
+
+// retryTwice retries an operation once, like a backoff with two attempts.
+func retryTwice(op func() (interface{}, error)) (interface{}, error) {
+	if v, err := op(); err == nil {
+		return v, nil
+	}
+	return op()
+}
+
+func TestEsiClient_RetryPolicy(t *testing.T) {
+	var mu sync.Mutex
+	calls := map[string]int{}
+	httpClient := &mockHttpClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			calls[req.Method]++
+			mu.Unlock()
+			return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: io.NopCloser(strings.NewReader(`{}`))}, nil
+		},
+		retryFunc: retryTwice,
+	}
+	ctx := context.Background()
+	const url = "https://esi.evetech.net/latest/fleets/1/members/"
+
+	client := esi.NewEsiClient("https://esi.evetech.net/latest/", httpClient, common.NewMemoryCache(), nil)
+	_, _ = client.DoRequest(ctx, http.MethodPost, url, nil, strings.NewReader(`{"character_id":1}`), http.StatusNoContent)
+	_, _ = client.DoRequest(ctx, http.MethodGet, url, nil, nil)
+	if calls[http.MethodPost] != 1 || calls[http.MethodGet] != 2 {
+		t.Errorf("expected only the GET to be retried by default, got %v", calls)
+	}
+
+	calls = map[string]int{}
+	client = esi.NewEsiClient("https://esi.evetech.net/latest/", httpClient, common.NewMemoryCache(), nil,
+		esi.WithRetryPolicy(esi.RetryPolicy{Methods: []string{http.MethodPost}}))
+	_, _ = client.DoRequest(ctx, http.MethodPost, url, nil, strings.NewReader(`{"character_id":1}`), http.StatusNoContent)
+	if calls[http.MethodPost] != 2 {
+		t.Errorf("expected the POST to be retried when the policy allows it, got %v", calls)
+	}
+}
+
+func TestEsiClient_NoReplayAfterRefresh(t *testing.T) {
+	calls, refreshed := 0, 0
+	httpClient := &mockHttpClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			calls++
+			return &http.Response{StatusCode: http.StatusForbidden, Body: io.NopCloser(strings.NewReader(`{}`))}, nil
+		},
+	}
+	auth := &mockAuth{refreshFunc: func(string) (*oauth2.Token, error) {
+		refreshed++
+		return &oauth2.Token{AccessToken: "new"}, nil
+	}}
+	client := esi.NewEsiClient("https://esi.evetech.net/latest/", httpClient, common.NewMemoryCache(), auth,
+		esi.WithRetryPolicy(esi.RetryPolicy{ReplayAfterRefresh: []string{http.MethodGet}}))
+
+	token := &oauth2.Token{AccessToken: "old", RefreshToken: "refresh"}
+	_, err := client.PostJSON(context.Background(), "characters/1/contacts/", token, strings.NewReader(`[2]`), http.StatusCreated)
+	if err == nil || calls != 1 || refreshed != 0 {
+		t.Errorf("expected the POST to fail without a replay, got err=%v calls=%d refreshes=%d", err, calls, refreshed)
+	}
+}
+
+func TestEsiClient_IdempotencyKey(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		bodies []string
+	)
+	httpClient := &mockHttpClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			b, _ := io.ReadAll(req.Body)
+			mu.Lock()
+			bodies = append(bodies, string(b))
+			mu.Unlock()
+			return &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(bytes.NewBufferString(`[7]`))}, nil
+		},
+	}
+	client := esi.NewEsiClient("https://esi.evetech.net/latest/", httpClient, common.NewMemoryCache(), nil)
+	ctx := esi.WithIdempotencyKey(context.Background(), "add-contact-2")
+
+	for i := 0; i < 3; i++ {
+		data, err := client.PostJSON(ctx, "characters/1/contacts/", nil, strings.NewReader(`[2]`), http.StatusCreated)
+		if err != nil || string(data) != `[7]` {
+			t.Fatalf("attempt %d: unexpected result %q, %v", i, data, err)
+		}
+	}
+	if len(bodies) != 1 || bodies[0] != `[2]` {
+		t.Errorf("expected one request with the body, got %q", bodies)
+	}
+
+	other := esi.WithIdempotencyKey(context.Background(), "add-contact-3")
+	if _, err := client.PostJSON(other, "characters/1/contacts/", nil, strings.NewReader(`[3]`), http.StatusCreated); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.PostJSON(context.Background(), "characters/1/contacts/", nil, strings.NewReader(`[2]`), http.StatusCreated); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(bodies) != 3 {
+		t.Errorf("expected other keys and unkeyed writes to be sent, got %q", bodies)
+	}
+}
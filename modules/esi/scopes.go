@@ -0,0 +1,138 @@
+package esi
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/modules/auth"
+)
+
+// ESI scopes needed by EsiService methods.
+const (
+	ScopeAssets               = "esi-assets.read_assets.v1"
+	ScopeCorporationAssets    = "esi-assets.read_corporation_assets.v1"
+	ScopeLocation             = "esi-location.read_location.v1"
+	ScopeShipType             = "esi-location.read_ship_type.v1"
+	ScopeOnline               = "esi-location.read_online.v1"
+	ScopeFatigue              = "esi-characters.read_fatigue.v1"
+	ScopeClones               = "esi-clones.read_clones.v1"
+	ScopeImplants             = "esi-clones.read_implants.v1"
+	ScopeStructures           = "esi-universe.read_structures.v1"
+	ScopeStructureMarkets     = "esi-markets.structure_markets.v1"
+	ScopeCharacterOrders      = "esi-markets.read_character_orders.v1"
+	ScopeCorporationOrders    = "esi-markets.read_corporation_orders.v1"
+	ScopeSearch               = "esi-search.search_structures.v1"
+	ScopeReadFittings         = "esi-fittings.read_fittings.v1"
+	ScopeWriteFittings        = "esi-fittings.write_fittings.v1"
+	ScopeMembership           = "esi-corporations.read_corporation_membership.v1"
+	ScopeCorporationStructure = "esi-corporations.read_structures.v1"
+	ScopeCorporationMining    = "esi-industry.read_corporation_mining.v1"
+	ScopeNotifications        = "esi-characters.read_notifications.v1"
+	ScopeCharacterContracts   = "esi-contracts.read_character_contracts.v1"
+	ScopeCharacterWallet      = "esi-wallet.read_character_wallet.v1"
+	ScopeCorporationWallets   = "esi-wallet.read_corporation_wallets.v1"
+	ScopeSkills               = "esi-skills.read_skills.v1"
+	ScopeSkillQueue           = "esi-skills.read_skillqueue.v1"
+)
+
+// MethodScopes maps EsiService method names to the scopes their token needs.
+// Methods missing here need none. Add entries for methods of your own
+// wrappers to check them with CheckScopes too.
+var MethodScopes = map[string][]string{
+	"GetCharacterAssets":           {ScopeAssets},
+	"GetAllCharacterAssets":        {ScopeAssets},
+	"GetCorporationAssets":         {ScopeCorporationAssets},
+	"GetAllCorporationAssets":      {ScopeCorporationAssets},
+	"GetCharacterAssetsRaw":        {ScopeAssets},
+	"GetCorporationAssetsRaw":      {ScopeCorporationAssets},
+	"GetCharacterAssetLocations":   {ScopeAssets},
+	"GetCorporationAssetLocations": {ScopeCorporationAssets},
+	"GetStructureOrders":           {ScopeStructureMarkets},
+	"GetCharacterOrders":           {ScopeCharacterOrders},
+	"GetCharacterOrderHistory":     {ScopeCharacterOrders},
+	"GetCorporationOrders":         {ScopeCorporationOrders},
+	"GetCharacterLocation":         {ScopeLocation},
+	"GetCharacterShip":             {ScopeShipType},
+	"GetCharacterOnline":           {ScopeOnline},
+	"GetJumpFatigue":               {ScopeFatigue},
+	"GetCloneLocations":            {ScopeClones, ScopeStructures},
+	"GetClones":                    {ScopeClones},
+	"GetImplants":                  {ScopeImplants},
+	"GetStructure":                 {ScopeStructures},
+	"CharacterIDSearch":            {ScopeSearch},
+	"CorporationIDSearch":          {ScopeSearch},
+	"AllianceIDSearch":             {ScopeSearch},
+	"IDSearch":                     {ScopeSearch},
+	"GetFittings":                  {ScopeReadFittings},
+	"CreateFitting":                {ScopeWriteFittings},
+	"GetCorporationMembers":        {ScopeMembership},
+	"GetCorporationStructures":     {ScopeCorporationStructure},
+	"GetMiningObservers":           {ScopeCorporationMining},
+	"GetMiningObserverLedger":      {ScopeCorporationMining},
+	"GetCharacterNotifications":    {ScopeNotifications},
+	"GetCharacterContracts":        {ScopeCharacterContracts},
+	"GetCharacterContractItems":    {ScopeCharacterContracts},
+	"GetCharacterWallet":           {ScopeCharacterWallet},
+	"GetCharacterWalletJournal":    {ScopeCharacterWallet},
+	"GetCorporationWallets":        {ScopeCorporationWallets},
+	"GetCorporationWalletJournal":  {ScopeCorporationWallets},
+	"GetCharacterSkills":           {ScopeSkills},
+	"GetCharacterSkillQueue":       {ScopeSkillQueue},
+	"GetCharacterAttributes":       {ScopeSkills},
+}
+
+// ErrMissingScope is matched by the *MissingScopeError CheckScopes returns.
+var ErrMissingScope = errors.New("esi: token lacks a required scope")
+
+// MissingScopeError lists the scopes a token lacks for a method.
+type MissingScopeError struct {
+	Method  string
+	Missing []string
+}
+
+func (e *MissingScopeError) Error() string {
+	return fmt.Sprintf("esi: token lacks scopes for %s: %s", e.Method, strings.Join(e.Missing, ", "))
+}
+
+// Is makes errors.Is(err, ErrMissingScope) match.
+func (e *MissingScopeError) Is(target error) bool {
+	return target == ErrMissingScope
+}
+
+// RequiredScopes returns the scopes method needs, per MethodScopes.
+func RequiredScopes(method string) []string {
+	return MethodScopes[method]
+}
+
+// CheckScopes fails with a *MissingScopeError if token was not granted every
+// scope method needs, so a call can be refused before ESI answers it with a
+// bare 403. The scopes are read from the access token's JWT claims without
+// verifying them; tokens that are not SSO v2 JWTs fail with
+// auth.ErrInvalidToken.
+func CheckScopes(token *oauth2.Token, method string) error {
+	required := RequiredScopes(method)
+	if len(required) == 0 {
+		return nil
+	}
+	if token == nil {
+		return &MissingScopeError{Method: method, Missing: required}
+	}
+	claims, err := auth.DecodeClaims(token.AccessToken)
+	if err != nil {
+		return err
+	}
+	var missing []string
+	for _, s := range required {
+		if !slices.Contains(claims.Scopes, s) {
+			missing = append(missing, s)
+		}
+	}
+	if len(missing) > 0 {
+		return &MissingScopeError{Method: method, Missing: missing}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package esi_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/modules/auth"
+	"github.com/guarzo/eveapi/modules/esi"
+)
+
+func jwtWithScopes(scopes ...string) *oauth2.Token {
+	enc := func(v interface{}) string {
+		b, _ := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	payload := enc(map[string]interface{}{"sub": "CHARACTER:EVE:90000001", "scp": scopes, "exp": 1})
+	return &oauth2.Token{AccessToken: enc(map[string]string{"alg": "RS256"}) + "." + payload + ".sig"}
+}
+
+func TestCheckScopes(t *testing.T) {
+	token := jwtWithScopes(esi.ScopeAssets, esi.ScopeLocation)
+	if err := esi.CheckScopes(token, "GetCharacterAssets"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := esi.CheckScopes(nil, "GetMarketPrices"); err != nil {
+		t.Errorf("expected public methods to pass, got %v", err)
+	}
+
+	err := esi.CheckScopes(token, "GetCloneLocations")
+	var missing *esi.MissingScopeError
+	if !errors.Is(err, esi.ErrMissingScope) || !errors.As(err, &missing) {
+		t.Fatalf("expected a MissingScopeError, got %v", err)
+	}
+	if len(missing.Missing) != 2 || missing.Missing[0] != esi.ScopeClones {
+		t.Errorf("unexpected missing scopes: %v", missing.Missing)
+	}
+	if err := esi.CheckScopes(&oauth2.Token{AccessToken: "opaque"}, "GetCharacterAssets"); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("expected ErrInvalidToken for a non-JWT token, got %v", err)
+	}
+}
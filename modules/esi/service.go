@@ -9,31 +9,82 @@ import (
 
 	"github.com/guarzo/eveapi/common"
 	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/auth"
 	"golang.org/x/oauth2"
 )
 
 // EsiService is a higher-level interface for retrieving or manipulating EVE data.
 type EsiService interface {
 	GetUserInfo(ctx context.Context, token *oauth2.Token) (*model.User, error)
-	GetCharacterInfo(ctx context.Context, characterID int) (*model.Character, error)
-	GetCharacterAssets(ctx context.Context, characterID int64, token *oauth2.Token) ([]model.LocationInventory, error)
-	GetCorporationAssets(ctx context.Context, corporationID int64, token *oauth2.Token) ([]model.LocationInventory, error)
-	GetCharacterLocation(ctx context.Context, characterID int64, token *oauth2.Token) (int64, error)
-	GetCloneLocations(ctx context.Context, characterID int64, token *oauth2.Token) (int64, []int64, error)
+	GetCharacterInfo(ctx context.Context, characterID model.CharacterID) (*model.Character, error)
+	GetCharacterAssets(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.LocationInventory, error)
+	GetCorporationAssets(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]model.LocationInventory, error)
+	GetAllCharacterAssets(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.LocationAssets, error)
+	GetAllCorporationAssets(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]model.LocationAssets, error)
+	GetCharacterAssetsRaw(ctx context.Context, characterID model.CharacterID, token *oauth2.Token, opts AssetOptions) ([]model.Asset, error)
+	GetCorporationAssetsRaw(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token, opts AssetOptions) ([]model.Asset, error)
+	GetCharacterAssetLocations(ctx context.Context, characterID model.CharacterID, itemIDs []int64, token *oauth2.Token) ([]model.AssetLocation, error)
+	GetCorporationAssetLocations(ctx context.Context, corporationID model.CorporationID, itemIDs []int64, token *oauth2.Token) ([]model.AssetLocation, error)
+	ValueAssets(ctx context.Context, locations []model.LocationAssets) (*model.AssetValuation, error)
+	GetMarketPrices(ctx context.Context) ([]model.MarketPrice, error)
+	GetRegionOrders(ctx context.Context, regionID int64, typeID model.TypeID, orderType string) ([]model.MarketOrder, error)
+	GetStructureOrders(ctx context.Context, structureID int64, token *oauth2.Token) ([]model.MarketOrder, error)
+	GetMarketHistory(ctx context.Context, regionID int64, typeID model.TypeID) ([]model.MarketHistoryDay, error)
+	GetCharacterOrders(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.OwnedMarketOrder, error)
+	GetCharacterOrderHistory(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.OwnedMarketOrder, error)
+	GetCorporationOrders(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]model.OwnedMarketOrder, error)
+	GetCharacterLocation(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (model.SystemID, error)
+	GetCharacterShip(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (*model.CharacterShip, error)
+	GetCloneLocations(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (int64, []int64, error)
+	GetCharacterOnline(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (*model.CharacterOnline, error)
+	GetJumpFatigue(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (*model.JumpFatigue, error)
+	GetClones(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (*model.CloneLocation, error)
+	GetImplants(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.TypeID, error)
 	GetStructure(ctx context.Context, structureID int64, token *oauth2.Token) (*model.Structure, error)
 	GetStation(ctx context.Context, stationID int64) (*model.Station, error)
-	GetEsiKillMail(ctx context.Context, killID int, hash string) (*model.EsiKillMail, error)
-	CharacterIDSearch(characterID int64, name string, token *oauth2.Token) (int32, error)
-	CorporationIDSearch(characterID int64, name string, token *oauth2.Token) (int32, error)
-	AllianceIDSearch(characterID int64, name string, token *oauth2.Token) (int32, error)
-	IDSearch(characterID int64, name, category string, token *oauth2.Token) (int32, error)
-	GetPublicCharacterData(characterID int64, token *oauth2.Token) (*model.CharacterResponse, error)
-	GetCharacterData(characterID int64, token *oauth2.Token) (*model.CharacterResponse, error)
-	GetSystemName(systemID int) string
-	GetCharacterCorporation(characterID int64, token *oauth2.Token) (int32, error)
-	GetCharacterPortrait(characterID int64) (string, error)
-	GetCorporationInfo(ctx context.Context, corporationID int) (*model.Corporation, error)
-	GetAllianceInfo(ctx context.Context, allianceID int) (*model.Alliance, error)
+	GetEsiKillMail(ctx context.Context, killID int64, hash string) (*model.EsiKillMail, error)
+	CharacterIDSearch(characterID model.CharacterID, name string, token *oauth2.Token) (model.CharacterID, error)
+	CorporationIDSearch(characterID model.CharacterID, name string, token *oauth2.Token) (model.CorporationID, error)
+	AllianceIDSearch(characterID model.CharacterID, name string, token *oauth2.Token) (model.AllianceID, error)
+	IDSearch(characterID model.CharacterID, name, category string, token *oauth2.Token) (int64, error)
+	GetPublicCharacterData(characterID model.CharacterID, token *oauth2.Token) (*model.CharacterResponse, error)
+	GetCharacterData(characterID model.CharacterID, token *oauth2.Token) (*model.CharacterResponse, error)
+	GetSystemName(systemID model.SystemID) string
+	GetSolarSystem(ctx context.Context, systemID model.SystemID) (*model.SolarSystem, error)
+	GetConstellation(ctx context.Context, constellationID int64) (*model.Constellation, error)
+	GetRoute(ctx context.Context, origin, destination int64, flag string) ([]int64, error)
+	GetType(ctx context.Context, typeID model.TypeID) (*model.ItemType, error)
+	GetFittings(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.Fitting, error)
+	CreateFitting(ctx context.Context, characterID model.CharacterID, fit model.Fitting, token *oauth2.Token) (int64, error)
+	ResolveNames(ctx context.Context, ids []int64) ([]model.UniverseName, error)
+	ResolveIDs(ctx context.Context, names []string) (*model.UniverseIDs, error)
+	LoadESIData(ctx context.Context, ids model.Ids) (*model.ESIData, error)
+	WarmCache(ctx context.Context, ids model.Ids) error
+	GetCharacterCorporation(characterID model.CharacterID, token *oauth2.Token) (model.CorporationID, error)
+	GetCharacterPortrait(characterID model.CharacterID) (string, error)
+	GetCorporationInfo(ctx context.Context, corporationID model.CorporationID) (*model.Corporation, error)
+	GetAllianceInfo(ctx context.Context, allianceID model.AllianceID) (*model.Alliance, error)
+	GetCorporationMembers(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]int64, error)
+	GetCorporationStructures(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]model.CorporationStructure, error)
+	GetMiningObservers(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]model.MiningObserver, error)
+	GetMiningObserverLedger(ctx context.Context, corporationID model.CorporationID, observerID int64, token *oauth2.Token) ([]model.MiningLedgerEntry, error)
+	GetCharacterNotifications(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.Notification, error)
+	GetSovereigntyCampaigns(ctx context.Context) ([]model.SovereigntyCampaign, error)
+	GetIncursions(ctx context.Context) ([]model.Incursion, error)
+	GetFWSystems(ctx context.Context) ([]model.FWSystem, error)
+	GetWars(ctx context.Context, maxWarID int64) ([]int64, error)
+	GetWar(ctx context.Context, warID int64) (*model.War, error)
+	GetPublicContracts(ctx context.Context, regionID int64) ([]model.Contract, error)
+	GetPublicContractItems(ctx context.Context, contractID int64) ([]model.ContractItem, error)
+	GetCharacterContracts(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.Contract, error)
+	GetCharacterContractItems(ctx context.Context, characterID model.CharacterID, contractID int64, token *oauth2.Token) ([]model.ContractItem, error)
+	GetCharacterWallet(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (float64, error)
+	GetCharacterWalletJournal(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.WalletJournalEntry, error)
+	GetCorporationWallets(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]model.CorporationWallet, error)
+	GetCorporationWalletJournal(ctx context.Context, corporationID model.CorporationID, division int, token *oauth2.Token) ([]model.WalletJournalEntry, error)
+	GetCharacterSkills(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (*model.CharacterSkills, error)
+	GetCharacterSkillQueue(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.SkillQueueEntry, error)
+	GetCharacterAttributes(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (*model.CharacterAttributes, error)
 }
 
 // esiService is the concrete implementation that uses an EsiClient.
@@ -41,23 +92,111 @@ type esiService struct {
 	esiClient EsiClient
 	cache     common.CacheRepository
 	auth      AuthClient
+	cynoItems ItemRequirementSet
+	locations LocationCache
+	failures  FailureTracker
+	workers   int
+	validator TokenValidator
+}
+
+// TokenValidator verifies an SSO access token locally; *auth.Validator
+// satisfies it.
+type TokenValidator interface {
+	Validate(ctx context.Context, accessToken string) (*auth.Claims, error)
+}
+
+// FailureTracker remembers IDs that ESI permanently rejects;
+// failures.Tracker satisfies it.
+type FailureTracker interface {
+	Skip(id int64) bool
+	RecordFailure(ctx context.Context, id int64, err error) bool
+}
+
+// ServiceOption customizes an esiService at construction time.
+type ServiceOption func(*esiService)
+
+// WithCynoRequirements replaces the item requirements used by
+// GetCharacterAssets/GetCorporationAssets to decide which locations to return.
+func WithCynoRequirements(reqs ItemRequirementSet) ServiceOption {
+	return func(s *esiService) {
+		s.cynoItems = reqs
+	}
+}
+
+// WithCache gives the service a cache for decoded lookups such as resolved
+// names and ESIData entities. Caches implementing common.BatchCacheRepository
+// are read and written in a single round trip per call.
+func WithCache(c common.CacheRepository) ServiceOption {
+	return func(s *esiService) {
+		s.cache = c
+	}
+}
+
+// WithFailureTracker makes LoadESIData skip flagged IDs and flag IDs that
+// fail permanently, leaving them out of the result instead of failing the load.
+func WithFailureTracker(t FailureTracker) ServiceOption {
+	return func(s *esiService) {
+		s.failures = t
+	}
+}
+
+// WithLocationCache replaces the per-service in-memory cache used to map
+// stations and structures to their solar system.
+func WithLocationCache(c LocationCache) ServiceOption {
+	return func(s *esiService) {
+		s.locations = c
+	}
+}
+
+// WithConcurrency bounds how many ESI requests the service issues at once
+// when one call fans out (name chunks, entity lookups). Defaults to
+// common.DefaultConcurrency.
+func WithConcurrency(n int) ServiceOption {
+	return func(s *esiService) {
+		s.workers = n
+	}
+}
+
+// WithTokenValidator makes GetUserInfo read the character from the token's
+// verified JWT claims instead of calling the deprecated /oauth/verify
+// endpoint.
+func WithTokenValidator(v TokenValidator) ServiceOption {
+	return func(s *esiService) {
+		s.validator = v
+	}
 }
 
 // NewEsiService constructs an EsiService.
-func NewEsiService(client EsiClient) EsiService {
-	return &esiService{
+func NewEsiService(client EsiClient, opts ...ServiceOption) EsiService {
+	s := &esiService{
 		esiClient: client,
+		cynoItems: DefaultCynoRequirements(),
+		locations: NewLocationCache(defaultLocationCacheTTL),
+		workers:   common.DefaultConcurrency,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 // ---------------------------------------------------------------------------------------
 // 1) Existing Methods
 // ---------------------------------------------------------------------------------------
 
+// GetUserInfo returns the character a token belongs to, from its validated
+// claims if the service has a TokenValidator and from /oauth/verify otherwise.
 func (s *esiService) GetUserInfo(ctx context.Context, token *oauth2.Token) (*model.User, error) {
 	if token == nil || token.AccessToken == "" {
 		return nil, fmt.Errorf("no token provided")
 	}
+	if s.validator != nil {
+		claims, err := s.validator.Validate(ctx, token.AccessToken)
+		if err != nil {
+			return nil, err
+		}
+		return &model.User{CharacterID: claims.CharacterID, CharacterName: claims.CharacterName}, nil
+	}
 
 	url := "https://login.eveonline.com/oauth/verify"
 	data, err := s.esiClient.DoRequest(ctx, http.MethodGet, url, token, nil)
@@ -72,7 +211,7 @@ func (s *esiService) GetUserInfo(ctx context.Context, token *oauth2.Token) (*mod
 	return &user, nil
 }
 
-func (s *esiService) GetCharacterInfo(ctx context.Context, characterID int) (*model.Character, error) {
+func (s *esiService) GetCharacterInfo(ctx context.Context, characterID model.CharacterID) (*model.Character, error) {
 	endpoint := fmt.Sprintf("characters/%d/", characterID)
 	var char model.Character
 	err := s.esiClient.GetJSON(ctx, endpoint, &char, nil, nil)
@@ -87,7 +226,7 @@ func (s *esiService) GetCharacterInfo(ctx context.Context, characterID int) (*mo
 	return &char, nil
 }
 
-func (s *esiService) GetEsiKillMail(ctx context.Context, killMailID int, hash string) (*model.EsiKillMail, error) {
+func (s *esiService) GetEsiKillMail(ctx context.Context, killMailID int64, hash string) (*model.EsiKillMail, error) {
 	endpoint := fmt.Sprintf("killmails/%d/%s/", killMailID, hash)
 	var km model.EsiKillMail
 	if err := s.esiClient.GetJSON(ctx, endpoint, &km, nil, nil); err != nil {
@@ -101,19 +240,19 @@ func (s *esiService) GetEsiKillMail(ctx context.Context, killMailID int, hash st
 // ---------------------------------------------------------------------------------------
 
 // (A) ID search methods
-func (s *esiService) CharacterIDSearch(characterID int64, name string, token *oauth2.Token) (int32, error) {
+func (s *esiService) CharacterIDSearch(characterID model.CharacterID, name string, token *oauth2.Token) (model.CharacterID, error) {
 	return s.IDSearch(characterID, name, "character", token)
 }
 
-func (s *esiService) CorporationIDSearch(characterID int64, name string, token *oauth2.Token) (int32, error) {
+func (s *esiService) CorporationIDSearch(characterID model.CharacterID, name string, token *oauth2.Token) (model.CorporationID, error) {
 	return s.IDSearch(characterID, name, "corporation", token)
 }
 
-func (s *esiService) AllianceIDSearch(characterID int64, name string, token *oauth2.Token) (int32, error) {
+func (s *esiService) AllianceIDSearch(characterID model.CharacterID, name string, token *oauth2.Token) (model.AllianceID, error) {
 	return s.IDSearch(characterID, name, "alliance", token)
 }
 
-func (s *esiService) IDSearch(characterID int64, name, category string, token *oauth2.Token) (int32, error) {
+func (s *esiService) IDSearch(characterID model.CharacterID, name, category string, token *oauth2.Token) (int64, error) {
 	ctx := context.Background()
 	baseURL := fmt.Sprintf("characters/%d/search/", characterID)
 	params := map[string]string{
@@ -132,7 +271,7 @@ func (s *esiService) IDSearch(characterID int64, name, category string, token *o
 		return 0, err
 	}
 
-	var result map[string][]int32
+	var result map[string][]int64
 	if err = json.Unmarshal(data, &result); err != nil {
 		return 0, fmt.Errorf("failed to parse JSON response: %v", err)
 	}
@@ -146,7 +285,7 @@ func (s *esiService) IDSearch(characterID int64, name, category string, token *o
 	if len(ids) > 1 {
 		// verify exact match
 		for _, id := range ids {
-			data, err := s.GetPublicCharacterData(int64(id), token)
+			data, err := s.GetPublicCharacterData(id, token)
 			if err != nil {
 				continue
 			}
@@ -161,11 +300,11 @@ func (s *esiService) IDSearch(characterID int64, name, category string, token *o
 }
 
 // (B) Character data methods
-func (s *esiService) GetPublicCharacterData(characterID int64, token *oauth2.Token) (*model.CharacterResponse, error) {
+func (s *esiService) GetPublicCharacterData(characterID model.CharacterID, token *oauth2.Token) (*model.CharacterResponse, error) {
 	return s.GetCharacterData(characterID, token)
 }
 
-func (s *esiService) GetCharacterData(characterID int64, token *oauth2.Token) (*model.CharacterResponse, error) {
+func (s *esiService) GetCharacterData(characterID model.CharacterID, token *oauth2.Token) (*model.CharacterResponse, error) {
 	ctx := context.Background()
 	endpoint := fmt.Sprintf("characters/%d/", characterID)
 	var character model.CharacterResponse
@@ -177,7 +316,7 @@ func (s *esiService) GetCharacterData(characterID int64, token *oauth2.Token) (*
 }
 
 // (C) System name
-func (s *esiService) GetSystemName(systemID int) string {
+func (s *esiService) GetSystemName(systemID model.SystemID) string {
 	ctx := context.Background()
 	url := fmt.Sprintf("universe/systems/%d/", systemID)
 	var sys struct {
@@ -188,7 +327,7 @@ func (s *esiService) GetSystemName(systemID int) string {
 }
 
 // (D) Misc character corp methods
-func (s *esiService) GetCharacterCorporation(characterID int64, token *oauth2.Token) (int32, error) {
+func (s *esiService) GetCharacterCorporation(characterID model.CharacterID, token *oauth2.Token) (model.CorporationID, error) {
 	data, err := s.GetCharacterData(characterID, token)
 	if err != nil {
 		return 0, err
@@ -196,7 +335,7 @@ func (s *esiService) GetCharacterCorporation(characterID int64, token *oauth2.To
 	return data.CorporationID, nil
 }
 
-func (s *esiService) GetCharacterPortrait(characterID int64) (string, error) {
+func (s *esiService) GetCharacterPortrait(characterID model.CharacterID) (string, error) {
 	ctx := context.Background()
 	endpoint := fmt.Sprintf("characters/%d/portrait/", characterID)
 
@@ -210,7 +349,7 @@ func (s *esiService) GetCharacterPortrait(characterID int64) (string, error) {
 }
 
 // (E) Corporation / Alliance Info
-func (s *esiService) GetCorporationInfo(ctx context.Context, corporationID int) (*model.Corporation, error) {
+func (s *esiService) GetCorporationInfo(ctx context.Context, corporationID model.CorporationID) (*model.Corporation, error) {
 	var corporation model.Corporation
 	endpoint := fmt.Sprintf("corporations/%d/", corporationID)
 	if err := s.esiClient.GetJSON(ctx, endpoint, &corporation, nil, nil); err != nil {
@@ -219,7 +358,7 @@ func (s *esiService) GetCorporationInfo(ctx context.Context, corporationID int)
 	return &corporation, nil
 }
 
-func (s *esiService) GetAllianceInfo(ctx context.Context, allianceID int) (*model.Alliance, error) {
+func (s *esiService) GetAllianceInfo(ctx context.Context, allianceID model.AllianceID) (*model.Alliance, error) {
 	if allianceID == 0 {
 		return nil, fmt.Errorf("no alliance specified")
 	}
@@ -1,83 +1,302 @@
 package esi
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"sort"
+	"sync"
 
 	"golang.org/x/oauth2"
 
+	"github.com/guarzo/eveapi/common"
 	"github.com/guarzo/eveapi/common/model"
 )
 
 // This file focuses on asset endpoints and cyno logic.
 
-// CynoItems might be defined globally here or in your service.
-var CynoItems = []model.Item{
-	{ID: 16273, Name: "Liquid Ozone", Qty: 200},
-	{ID: 32880, Name: "Venture", Qty: 1},
-	{ID: 19744, Name: "Covetor", Qty: 1},
+// MatchMode controls how the members of a requirement group are combined.
+type MatchMode int
+
+const (
+	// MatchAny is satisfied when at least one member is satisfied.
+	MatchAny MatchMode = iota
+	// MatchAll is satisfied only when every member is satisfied.
+	MatchAll
+)
+
+// ItemGroup is a set of items with minimum quantities, combined using Mode.
+type ItemGroup struct {
+	Name  string
+	Mode  MatchMode
+	Items []model.Item
+}
+
+// ItemRequirementSet decides which locations count as a cyno stash.
+// Groups are combined using Mode, items within a group using the group's Mode.
+type ItemRequirementSet struct {
+	Mode   MatchMode
+	Groups []ItemGroup
+}
+
+// DefaultCynoRequirements returns the classic check: any location holding enough
+// Liquid Ozone, a Venture, or a Covetor.
+func DefaultCynoRequirements() ItemRequirementSet {
+	return ItemRequirementSet{
+		Mode: MatchAny,
+		Groups: []ItemGroup{{
+			Name: "cyno",
+			Mode: MatchAny,
+			Items: []model.Item{
+				{ID: 16273, Name: "Liquid Ozone", Qty: 200},
+				{ID: 32880, Name: "Venture", Qty: 1},
+				{ID: 19744, Name: "Covetor", Qty: 1},
+			},
+		}},
+	}
+}
+
+// Satisfied reports whether the item counts (typeID -> quantity) meet the set.
+func (r ItemRequirementSet) Satisfied(counts map[int64]int) bool {
+	if len(r.Groups) == 0 {
+		return false
+	}
+	for _, g := range r.Groups {
+		ok := g.satisfied(counts)
+		if ok && r.Mode == MatchAny {
+			return true
+		}
+		if !ok && r.Mode == MatchAll {
+			return false
+		}
+	}
+	return r.Mode == MatchAll
+}
+
+// ItemName returns the configured name for typeID, if the set tracks it.
+func (r ItemRequirementSet) ItemName(typeID model.TypeID) (string, bool) {
+	for _, g := range r.Groups {
+		for _, it := range g.Items {
+			if it.ID == typeID {
+				return it.Name, true
+			}
+		}
+	}
+	return "", false
+}
+
+func (g ItemGroup) satisfied(counts map[int64]int) bool {
+	if len(g.Items) == 0 {
+		return false
+	}
+	for _, it := range g.Items {
+		ok := counts[it.ID] >= it.Qty
+		if ok && g.Mode == MatchAny {
+			return true
+		}
+		if !ok && g.Mode == MatchAll {
+			return false
+		}
+	}
+	return g.Mode == MatchAll
 }
 
 // GetCharacterAssets calls ESI’s /characters/{id}/assets/
-func (s *esiService) GetCharacterAssets(ctx context.Context, characterID int64, token *oauth2.Token) ([]model.LocationInventory, error) {
+func (s *esiService) GetCharacterAssets(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.LocationInventory, error) {
 	rawAssets, err := s.fetchAssets(ctx, fmt.Sprintf("characters/%d", characterID), token)
 	if err != nil {
 		return nil, err
 	}
-	locItems := groupAssetsByLocation(rawAssets)
-	cynoMap := createCynoMap(CynoItems)
+	return s.filterCynoLocations(characterID, groupAssetsByLocation(rawAssets)), nil
+}
+
+// GetCorporationAssets calls ESI’s /corporations/{id}/assets/
+func (s *esiService) GetCorporationAssets(ctx context.Context, corpID model.CorporationID, token *oauth2.Token) ([]model.LocationInventory, error) {
+	rawAssets, err := s.fetchAssets(ctx, fmt.Sprintf("corporations/%d", corpID), token)
+	if err != nil {
+		return nil, err
+	}
+	return s.filterCynoLocations(corpID, groupAssetsByLocation(rawAssets)), nil
+}
 
+// filterCynoLocations keeps the locations that satisfy the service's cyno requirements.
+func (s *esiService) filterCynoLocations(ownerID int64, locItems map[int64][]model.Asset) []model.LocationInventory {
 	var results []model.LocationInventory
 	for locID, assets := range locItems {
 		itemsInLoc := summarizeItemsInLocation(assets)
-		if hasRequiredCynoItems(itemsInLoc, cynoMap) {
-			inv := buildLocationInventory(characterID, int64(locID), assets)
+		if s.cynoItems.Satisfied(itemsInLoc) {
+			inv := buildLocationInventory(s.cynoItems, ownerID, locID, assets)
 			results = append(results, inv)
 		}
 	}
-	return results, nil
+	return results
 }
 
-// GetCorporationAssets calls ESI’s /corporations/{id}/assets/
-func (s *esiService) GetCorporationAssets(ctx context.Context, corpID int64, token *oauth2.Token) ([]model.LocationInventory, error) {
+// GetAllCharacterAssets calls ESI’s /characters/{id}/assets/ and returns the full
+// inventory of every location, not only those holding cyno items. Assets
+// inside ships, containers and office hangars count toward the location
+// holding them.
+func (s *esiService) GetAllCharacterAssets(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.LocationAssets, error) {
+	rawAssets, err := s.fetchAssets(ctx, fmt.Sprintf("characters/%d", characterID), token)
+	if err != nil {
+		return nil, err
+	}
+	return buildAllLocationAssets(characterID, groupAssetsByLocation(rawAssets)), nil
+}
+
+// GetAllCorporationAssets calls ESI’s /corporations/{id}/assets/ and returns the full
+// inventory of every location, not only those holding cyno items. Assets
+// inside ships, containers and office hangars count toward the location
+// holding them.
+func (s *esiService) GetAllCorporationAssets(ctx context.Context, corpID model.CorporationID, token *oauth2.Token) ([]model.LocationAssets, error) {
 	rawAssets, err := s.fetchAssets(ctx, fmt.Sprintf("corporations/%d", corpID), token)
 	if err != nil {
 		return nil, err
 	}
-	locItems := groupAssetsByLocation(rawAssets)
-	cynoMap := createCynoMap(CynoItems)
+	return buildAllLocationAssets(corpID, groupAssetsByLocation(rawAssets)), nil
+}
 
-	var results []model.LocationInventory
-	for locID, assets := range locItems {
-		itemsInLoc := summarizeItemsInLocation(assets)
-		if hasRequiredCynoItems(itemsInLoc, cynoMap) {
-			inv := buildLocationInventory(corpID, int64(locID), assets)
-			results = append(results, inv)
+// AssetOptions selects the assets GetCharacterAssetsRaw and
+// GetCorporationAssetsRaw return. The zero value selects every asset.
+type AssetOptions struct {
+	// Filter keeps the assets it returns true for; nil keeps all.
+	Filter func(model.Asset) bool
+}
+
+// GetCharacterAssetsRaw calls ESI’s /characters/{id}/assets/ and returns the
+// assets as listed, every page, with no cyno or location filtering.
+func (s *esiService) GetCharacterAssetsRaw(ctx context.Context, characterID model.CharacterID, token *oauth2.Token, opts AssetOptions) ([]model.Asset, error) {
+	assets, err := s.fetchAssets(ctx, fmt.Sprintf("characters/%d", characterID), token)
+	if err != nil {
+		return nil, err
+	}
+	return opts.apply(assets), nil
+}
+
+// GetCorporationAssetsRaw calls ESI’s /corporations/{id}/assets/ and returns
+// the assets as listed, every page, with no cyno or location filtering.
+func (s *esiService) GetCorporationAssetsRaw(ctx context.Context, corpID model.CorporationID, token *oauth2.Token, opts AssetOptions) ([]model.Asset, error) {
+	assets, err := s.fetchAssets(ctx, fmt.Sprintf("corporations/%d", corpID), token)
+	if err != nil {
+		return nil, err
+	}
+	return opts.apply(assets), nil
+}
+
+func (o AssetOptions) apply(assets []model.Asset) []model.Asset {
+	if o.Filter == nil {
+		return assets
+	}
+	kept := assets[:0]
+	for _, a := range assets {
+		if o.Filter(a) {
+			kept = append(kept, a)
+		}
+	}
+	return kept
+}
+
+// assetLocationsChunkSize is the maximum number of item IDs the
+// /assets/locations/ endpoints accept per call.
+const assetLocationsChunkSize = 1000
+
+// GetCharacterAssetLocations calls ESI’s POST /characters/{id}/assets/locations/
+// for itemIDs, e.g. the ItemIDs of GetCharacterAssetsRaw, posting chunks of
+// at most 1000 IDs concurrently, and returns the positions ordered by item ID.
+// Only items ESI can place in space, such as assembled ships and containers
+// outside hangars, are listed.
+func (s *esiService) GetCharacterAssetLocations(ctx context.Context, characterID model.CharacterID, itemIDs []int64, token *oauth2.Token) ([]model.AssetLocation, error) {
+	return s.postAssetLocations(ctx, fmt.Sprintf("characters/%d/assets/locations/", characterID), itemIDs, token)
+}
+
+// GetCorporationAssetLocations calls ESI’s POST
+// /corporations/{id}/assets/locations/ like GetCharacterAssetLocations.
+func (s *esiService) GetCorporationAssetLocations(ctx context.Context, corpID model.CorporationID, itemIDs []int64, token *oauth2.Token) ([]model.AssetLocation, error) {
+	return s.postAssetLocations(ctx, fmt.Sprintf("corporations/%d/assets/locations/", corpID), itemIDs, token)
+}
+
+func (s *esiService) postAssetLocations(ctx context.Context, endpoint string, itemIDs []int64, token *oauth2.Token) ([]model.AssetLocation, error) {
+	var (
+		mu  sync.Mutex
+		out []model.AssetLocation
+	)
+	err := common.Batch(ctx, chunked(dedupeIDs(itemIDs), assetLocationsChunkSize), func(ctx context.Context, ids []int64) error {
+		body, err := json.Marshal(ids)
+		if err != nil {
+			return err
+		}
+		data, err := s.esiClient.PostJSON(ctx, endpoint, token, bytes.NewReader(body), http.StatusOK)
+		if err != nil {
+			return fmt.Errorf("failed to fetch asset locations: %w", err)
+		}
+		var chunk []model.AssetLocation
+		if err := unmarshalJSON(data, &chunk); err != nil {
+			return err
 		}
+		mu.Lock()
+		out = append(out, chunk...)
+		mu.Unlock()
+		return nil
+	}, s.workers)
+	if err != nil {
+		return nil, err
 	}
-	return results, nil
+	sort.Slice(out, func(i, j int) bool { return out[i].ItemID < out[j].ItemID })
+	return out, nil
 }
 
-// fetchAssets uses EsiClient.GetJSON to get an array of model.Asset
+// fetchAssets uses EsiClient.GetJSONAllPages to get every model.Asset
 func (s *esiService) fetchAssets(ctx context.Context, path string, token *oauth2.Token) ([]model.Asset, error) {
 	endpoint := fmt.Sprintf("%s/assets/?datasource=tranquility", path)
 	var out []model.Asset
-	err := s.esiClient.GetJSON(ctx, endpoint, &out, token, nil)
+	err := s.esiClient.GetJSONAllPages(ctx, endpoint, &out, token, nil)
 	return out, err
 }
 
-// group them by location
-func groupAssetsByLocation(raw []model.Asset) map[int][]model.Asset {
-	m := make(map[int][]model.Asset)
+// groupAssetsByLocation groups assets by the station, structure or solar
+// system they are in. Assets inside ships, containers and office hangars
+// (location_type "item") are filed under the location of the outermost item
+// holding them; those whose chain cannot be resolved are dropped.
+func groupAssetsByLocation(raw []model.Asset) map[int64][]model.Asset {
+	byItem := make(map[int64]model.Asset, len(raw))
+	for _, a := range raw {
+		byItem[a.ItemID] = a
+	}
+	m := make(map[int64][]model.Asset)
 	for _, asset := range raw {
-		if isRelevantLocation(asset.LocationType) {
-			lid := int(asset.LocationID)
-			m[lid] = append(m[lid], asset)
+		if root, ok := rootAsset(byItem, asset); ok && isRelevantLocation(root.LocationType) {
+			m[root.LocationID] = append(m[root.LocationID], asset)
 		}
 	}
 	return m
 }
 
+// rootAsset follows a's item_id -> location_id chain up to the outermost
+// item, the one not inside another item.
+func rootAsset(byItem map[int64]model.Asset, a model.Asset) (model.Asset, bool) {
+	for depth := 0; a.LocationType == "item"; depth++ {
+		parent, ok := byItem[a.LocationID]
+		if !ok || depth > len(byItem) { // missing parent or a cycle
+			return model.Asset{}, false
+		}
+		a = parent
+	}
+	return a, true
+}
+
+// locationType is the location_type of the assets directly in a grouped
+// location, as nested assets report "item".
+func locationType(assets []model.Asset) string {
+	for _, a := range assets {
+		if a.LocationType != "item" {
+			return a.LocationType
+		}
+	}
+	return ""
+}
+
 func isRelevantLocation(locType string) bool {
 	return locType == "station" || locType == "solar_system" || locType == "structure"
 }
@@ -91,51 +310,39 @@ func summarizeItemsInLocation(assets []model.Asset) map[int64]int {
 	return counts
 }
 
-// Check if we have at least 1 cyno item
-func hasRequiredCynoItems(items map[int64]int, cyno map[int64]int) bool {
-	for itemID, needed := range cyno {
-		if items[itemID] >= needed {
-			return true
-		}
-	}
-	return false
-}
-
-func buildLocationInventory(ownerID, locID int64, assets []model.Asset) model.LocationInventory {
+func buildLocationInventory(reqs ItemRequirementSet, ownerID, locID int64, assets []model.Asset) model.LocationInventory {
 	invMap := make(map[string]int)
-	var locFlag, locType string
+	var locFlag string
 
 	for _, a := range assets {
-		if cynoName, ok := getCynoItemName(a.TypeID); ok {
+		if cynoName, ok := reqs.ItemName(a.TypeID); ok {
 			invMap[cynoName] += a.Quantity
 			locFlag = a.LocationFlag
-			locType = a.LocationType
 		}
 	}
 
 	return model.LocationInventory{
 		CharacterID: ownerID, // if it’s corp, we can rename. But we’ll keep the field name for now.
 		LocFlag:     locFlag,
-		LocType:     locType,
-		LocID:       int(locID),
+		LocType:     locationType(assets),
+		LocID:       locID,
 		Items:       invMap,
 	}
 }
 
-func getCynoItemName(typeID int64) (string, bool) {
-	for _, it := range CynoItems {
-		if it.ID == typeID {
-			return it.Name, true
-		}
-	}
-	return "", false
-}
-
-// create map of cyno items from a []model.Item
-func createCynoMap(items []model.Item) map[int64]int {
-	m := make(map[int64]int)
-	for _, i := range items {
-		m[i.ID] = i.Qty
+// buildAllLocationAssets turns grouped assets into one LocationAssets per location,
+// ordered by location ID so results are stable between calls.
+func buildAllLocationAssets(ownerID int64, locItems map[int64][]model.Asset) []model.LocationAssets {
+	results := make([]model.LocationAssets, 0, len(locItems))
+	for locID, assets := range locItems {
+		results = append(results, model.LocationAssets{
+			OwnerID: ownerID,
+			LocID:   locID,
+			LocType: locationType(assets),
+			Items:   summarizeItemsInLocation(assets),
+			Assets:  assets,
+		})
 	}
-	return m
+	sort.Slice(results, func(i, j int) bool { return results[i].LocID < results[j].LocID })
+	return results
 }
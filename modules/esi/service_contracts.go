@@ -0,0 +1,55 @@
+package esi
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// This file focuses on contract endpoints.
+
+// GetPublicContracts calls ESI’s /contracts/public/{region_id}/, fetching
+// every page.
+func (s *esiService) GetPublicContracts(ctx context.Context, regionID int64) ([]model.Contract, error) {
+	endpoint := fmt.Sprintf("contracts/public/%d/", regionID)
+	var contracts []model.Contract
+	if err := s.esiClient.GetJSONAllPages(ctx, endpoint, &contracts, nil, nil); err != nil {
+		return nil, err
+	}
+	return contracts, nil
+}
+
+// GetPublicContractItems calls ESI’s /contracts/public/items/{contract_id}/.
+func (s *esiService) GetPublicContractItems(ctx context.Context, contractID int64) ([]model.ContractItem, error) {
+	endpoint := fmt.Sprintf("contracts/public/items/%d/", contractID)
+	var items []model.ContractItem
+	if err := s.esiClient.GetJSONAllPages(ctx, endpoint, &items, nil, nil); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// GetCharacterContracts calls ESI’s /characters/{character_id}/contracts/
+// (requires esi-contracts.read_character_contracts.v1).
+func (s *esiService) GetCharacterContracts(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.Contract, error) {
+	endpoint := fmt.Sprintf("characters/%d/contracts/", characterID)
+	var contracts []model.Contract
+	if err := s.esiClient.GetJSONAllPages(ctx, endpoint, &contracts, token, nil); err != nil {
+		return nil, err
+	}
+	return contracts, nil
+}
+
+// GetCharacterContractItems calls ESI’s
+// /characters/{character_id}/contracts/{contract_id}/items/.
+func (s *esiService) GetCharacterContractItems(ctx context.Context, characterID model.CharacterID, contractID int64, token *oauth2.Token) ([]model.ContractItem, error) {
+	endpoint := fmt.Sprintf("characters/%d/contracts/%d/items/", characterID, contractID)
+	var items []model.ContractItem
+	if err := s.esiClient.GetFreshJSON(ctx, endpoint, &items, token, nil); err != nil {
+		return nil, fmt.Errorf("failed to fetch items of contract %d: %w", contractID, err)
+	}
+	return items, nil
+}
@@ -0,0 +1,34 @@
+package esi
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// This file focuses on authenticated corporation endpoints.
+
+// GetCorporationMembers calls ESI’s /corporations/{corporation_id}/members/
+// (requires esi-corporations.read_corporation_membership.v1).
+func (s *esiService) GetCorporationMembers(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]int64, error) {
+	endpoint := fmt.Sprintf("corporations/%d/members/", corporationID)
+	var members []int64
+	if err := s.esiClient.GetFreshJSON(ctx, endpoint, &members, token, nil); err != nil {
+		return nil, fmt.Errorf("failed to fetch members of corporation %d: %w", corporationID, err)
+	}
+	return members, nil
+}
+
+// GetCorporationStructures calls ESI’s /corporations/{corporation_id}/structures/
+// (requires esi-corporations.read_structures.v1), fetching every page.
+func (s *esiService) GetCorporationStructures(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]model.CorporationStructure, error) {
+	endpoint := fmt.Sprintf("corporations/%d/structures/", corporationID)
+	var structures []model.CorporationStructure
+	if err := s.esiClient.GetJSONAllPages(ctx, endpoint, &structures, token, nil); err != nil {
+		return nil, fmt.Errorf("failed to fetch structures of corporation %d: %w", corporationID, err)
+	}
+	return structures, nil
+}
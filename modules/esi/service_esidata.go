@@ -0,0 +1,112 @@
+package esi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// This file hydrates model.ESIData for the characters, corporations and
+// alliances a dashboard tracks.
+
+// esiDataCacheExpiration bounds how long hydrated entity info is cached.
+const esiDataCacheExpiration = 24 * time.Hour
+
+// LoadESIData fetches character, corporation and alliance info for ids.
+// With a service cache configured, every entity already cached is read in one
+// batch call and only the misses are fetched from ESI and written back.
+// With a failure tracker configured, flagged IDs are skipped and IDs that
+// fail permanently are flagged and omitted.
+func (s *esiService) LoadESIData(ctx context.Context, ids model.Ids) (*model.ESIData, error) {
+	return s.loadESIData(ctx, ids, false)
+}
+
+// loadESIData skips the cache read when refresh is set, so every entity is
+// fetched and written back with a fresh expiration.
+func (s *esiService) loadESIData(ctx context.Context, ids model.Ids, refresh bool) (*model.ESIData, error) {
+	data := &model.ESIData{}
+	var err error
+	if data.CharacterInfos, err = loadEntities[model.EsiCharacter](ctx, s, "characters", ids.CharacterIDs, refresh); err != nil {
+		return nil, err
+	}
+	if data.CorporationInfos, err = loadEntities[model.EsiCorporation](ctx, s, "corporations", ids.CorporationIDs, refresh); err != nil {
+		return nil, err
+	}
+	if data.AllianceInfos, err = loadEntities[model.EsiAlliance](ctx, s, "alliances", ids.AllianceIDs, refresh); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func esiDataCacheKey(kind string, id int64) string {
+	return fmt.Sprintf("esi:data:%s:%d", kind, id)
+}
+
+// loadEntities resolves /{kind}/{id}/ for every ID, cache first, fetching
+// the misses concurrently.
+func loadEntities[T any](ctx context.Context, s *esiService, kind string, ids []int64, refresh bool) (map[int64]T, error) {
+	out := make(map[int64]T, len(ids))
+	if len(ids) == 0 {
+		return out, nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = esiDataCacheKey(kind, id)
+	}
+	var hits map[string][]byte
+	if s.cache != nil && !refresh {
+		// a failing cache only costs us the ESI round trips
+		hits, _ = common.MGet(ctx, s.cache, keys)
+	}
+
+	var misses []int64
+	for i, id := range ids {
+		if _, done := out[id]; done {
+			continue
+		}
+		if s.failures != nil && s.failures.Skip(id) {
+			continue
+		}
+		var v T
+		if raw, ok := hits[keys[i]]; ok && json.Unmarshal(raw, &v) == nil {
+			out[id] = v
+			continue
+		}
+		misses = append(misses, id)
+	}
+
+	var mu sync.Mutex
+	fetched := make(map[string][]byte)
+	err := common.Batch(ctx, dedupeIDs(misses), func(ctx context.Context, id int64) error {
+		raw, err := s.esiClient.GetBytes(ctx, fmt.Sprintf("%s/%d/", kind, id), nil, nil)
+		if err != nil {
+			if s.failures != nil && s.failures.RecordFailure(ctx, id, err) {
+				return nil
+			}
+			return fmt.Errorf("failed to load %s %d: %w", kind, id, err)
+		}
+		var v T
+		if err := unmarshalJSON(raw, &v); err != nil {
+			return fmt.Errorf("failed to decode %s %d: %w", kind, id, err)
+		}
+		mu.Lock()
+		out[id] = v
+		fetched[esiDataCacheKey(kind, id)] = raw
+		mu.Unlock()
+		return nil
+	}, s.workers)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.cache != nil {
+		_ = common.MSet(ctx, s.cache, fetched, esiDataCacheExpiration)
+	}
+	return out, nil
+}
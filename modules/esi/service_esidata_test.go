@@ -0,0 +1,199 @@
+package esi_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/esi"
+	"github.com/guarzo/eveapi/modules/failures"
+)
+
+// batchCache counts batch round trips on top of mockCache.
+type batchCache struct {
+	mockCache
+	mgets, msets int
+}
+
+func (c *batchCache) MGet(ctx context.Context, keys []string) (map[string][]byte, error) {
+	c.mgets++
+	out := make(map[string][]byte)
+	for _, k := range keys {
+		if v, ok := c.store[k]; ok {
+			out[k] = v
+		}
+	}
+	return out, nil
+}
+
+func (c *batchCache) MSet(ctx context.Context, entries map[string][]byte, _ time.Duration) error {
+	c.msets++
+	for k, v := range entries {
+		c.store[k] = v
+	}
+	return nil
+}
+
+func TestEsiService_LoadESIData_BatchesCache(t *testing.T) {
+	var fetches atomic.Int32
+	client := &mockEsiClient{
+		getBytesFunc: func(ctx context.Context, endpoint string, token *oauth2.Token, params map[string]string) ([]byte, error) {
+			fetches.Add(1)
+			return []byte(`{"name":"` + endpoint + `"}`), nil
+		},
+	}
+	cache := &batchCache{mockCache: mockCache{store: make(map[string][]byte)}}
+	svc := esi.NewEsiService(client, esi.WithCache(cache))
+	ids := model.Ids{CharacterIDs: []model.CharacterID{1, 2}, CorporationIDs: []model.CorporationID{10}, AllianceIDs: []model.AllianceID{100}}
+
+	data, err := svc.LoadESIData(context.Background(), ids)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data.CharacterInfos[2].Name != "characters/2/" || data.AllianceInfos[100].Name != "alliances/100/" {
+		t.Errorf("unexpected data: %+v", data)
+	}
+	if n := fetches.Load(); n != 4 {
+		t.Errorf("expected 4 ESI fetches on a cold cache, got %d", n)
+	}
+
+	if _, err := svc.LoadESIData(context.Background(), ids); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := fetches.Load(); n != 4 {
+		t.Errorf("expected warm cache to avoid ESI, got %d fetches", n)
+	}
+	if cache.mgets != 6 || cache.msets != 3 {
+		t.Errorf("expected one batch call per entity kind, got %d mgets and %d msets", cache.mgets, cache.msets)
+	}
+}
+
+func TestEsiService_ResolveNames_UsesCache(t *testing.T) {
+	var posted [][]int64
+	client := &mockEsiClient{
+		postJSONFunc: func(ctx context.Context, endpoint string, token *oauth2.Token, body io.Reader, expectedStatusCodes ...int) ([]byte, error) {
+			var ids []int64
+			_ = json.NewDecoder(body).Decode(&ids)
+			posted = append(posted, ids)
+			var out bytes.Buffer
+			names := make([]model.UniverseName, len(ids))
+			for i, id := range ids {
+				names[i] = model.UniverseName{ID: id, Name: "n", Category: "character"}
+			}
+			_ = json.NewEncoder(&out).Encode(names)
+			return out.Bytes(), nil
+		},
+	}
+	cache := &batchCache{mockCache: mockCache{store: make(map[string][]byte)}}
+	svc := esi.NewEsiService(client, esi.WithCache(cache))
+
+	ctx := context.Background()
+	if _, err := svc.ResolveNames(ctx, []int64{1, 2}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	names, err := svc.ResolveNames(ctx, []int64{1, 2, 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(names) != 3 {
+		t.Errorf("expected 3 names, got %d", len(names))
+	}
+	if len(posted) != 2 || len(posted[1]) != 1 || posted[1][0] != 3 {
+		t.Errorf("expected second call to post only the miss, got %v", posted)
+	}
+}
+
+func TestEsiService_ResolveIDs(t *testing.T) {
+	var chunks []int
+	client := &mockEsiClient{
+		postJSONFunc: func(ctx context.Context, endpoint string, token *oauth2.Token, body io.Reader, expectedStatusCodes ...int) ([]byte, error) {
+			if endpoint != "universe/ids/" {
+				t.Errorf("unexpected endpoint %s", endpoint)
+			}
+			var names []string
+			_ = json.NewDecoder(body).Decode(&names)
+			chunks = append(chunks, len(names))
+			var out model.UniverseIDs
+			for _, n := range names {
+				switch n {
+				case "Jita":
+					out.Systems = append(out.Systems, model.UniverseName{ID: 30000142, Name: n})
+				case "Tritanium":
+					out.InventoryTypes = append(out.InventoryTypes, model.UniverseName{ID: 34, Name: n})
+				}
+			}
+			return json.Marshal(out)
+		},
+	}
+	cache := &batchCache{mockCache: mockCache{store: make(map[string][]byte)}}
+	svc := esi.NewEsiService(client, esi.WithCache(cache), esi.WithConcurrency(1))
+
+	ctx := context.Background()
+	names := []string{"Jita", "jita", "", "Tritanium"}
+	for i := 0; i < 600; i++ {
+		names = append(names, fmt.Sprintf("nobody %d", i))
+	}
+	ids, err := svc.ResolveIDs(ctx, names)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(chunks) != 2 || chunks[0] != 500 || chunks[1] != 102 {
+		t.Errorf("expected chunks of at most 500 unique names, got %v", chunks)
+	}
+	if len(ids.Systems) != 1 || ids.Systems[0].ID != 30000142 || len(ids.InventoryTypes) != 1 {
+		t.Errorf("unexpected resolved IDs %+v", ids)
+	}
+
+	// the resolved names are now cached for ResolveNames
+	resolved, err := svc.ResolveNames(ctx, []int64{34})
+	if err != nil || len(resolved) != 1 || resolved[0].Category != "inventory_type" || len(chunks) != 2 {
+		t.Errorf("expected a cached name, got %+v, %v", resolved, err)
+	}
+}
+
+func TestEsiService_LoadESIData_SkipsFailedIDs(t *testing.T) {
+	ctx := context.Background()
+	tracker, _ := failures.NewTracker(ctx, failures.NewMemoryStore())
+	_ = tracker.Flag(ctx, failures.Record{ID: 3})
+
+	var (
+		mu      sync.Mutex
+		fetched []string
+	)
+	client := &mockEsiClient{
+		getBytesFunc: func(ctx context.Context, endpoint string, token *oauth2.Token, params map[string]string) ([]byte, error) {
+			mu.Lock()
+			fetched = append(fetched, endpoint)
+			mu.Unlock()
+			if endpoint == "characters/2/" {
+				return nil, &common.HTTPError{StatusCode: 404}
+			}
+			return []byte(`{"name":"ok"}`), nil
+		},
+	}
+	svc := esi.NewEsiService(client, esi.WithFailureTracker(tracker))
+
+	data, err := svc.LoadESIData(ctx, model.Ids{CharacterIDs: []model.CharacterID{1, 2, 3}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(data.CharacterInfos) != 1 {
+		t.Errorf("expected only character 1, got %+v", data.CharacterInfos)
+	}
+	if len(fetched) != 2 {
+		t.Errorf("expected flagged ID to be skipped, fetched %v", fetched)
+	}
+	if !tracker.Skip(2) {
+		t.Error("expected 404 to be flagged")
+	}
+}
@@ -0,0 +1,17 @@
+package esi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// GetFWSystems calls ESI’s /fw/systems/ for every faction warfare system.
+func (s *esiService) GetFWSystems(ctx context.Context) ([]model.FWSystem, error) {
+	var out []model.FWSystem
+	if err := s.esiClient.GetFreshJSON(ctx, "fw/systems/", &out, nil, nil); err != nil {
+		return nil, fmt.Errorf("failed to fetch faction warfare systems: %w", err)
+	}
+	return out, nil
+}
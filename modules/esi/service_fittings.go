@@ -0,0 +1,52 @@
+package esi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// This file focuses on saved ship fittings.
+
+// GetFittings calls ESI’s /characters/{character_id}/fittings/
+// (requires esi-fittings.read_fittings.v1).
+func (s *esiService) GetFittings(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.Fitting, error) {
+	endpoint := fmt.Sprintf("characters/%d/fittings/", characterID)
+	var fits []model.Fitting
+	if err := s.esiClient.GetFreshJSON(ctx, endpoint, &fits, token, nil); err != nil {
+		return nil, fmt.Errorf("failed to fetch fittings of character %d: %w", characterID, err)
+	}
+	return fits, nil
+}
+
+// CreateFitting POSTs to ESI’s /characters/{character_id}/fittings/
+// (requires esi-fittings.write_fittings.v1) and returns the new fitting ID.
+// Fittings failing model.Fitting.Validate are rejected without a request.
+func (s *esiService) CreateFitting(ctx context.Context, characterID model.CharacterID, fit model.Fitting, token *oauth2.Token) (int64, error) {
+	fit.FittingID = 0
+	if err := fit.Validate(); err != nil {
+		return 0, err
+	}
+	body, err := json.Marshal(fit)
+	if err != nil {
+		return 0, err
+	}
+	endpoint := fmt.Sprintf("characters/%d/fittings/", characterID)
+	data, err := s.esiClient.PostJSON(ctx, endpoint, token, bytes.NewReader(body), http.StatusCreated)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create fitting for character %d: %w", characterID, err)
+	}
+	var resp struct {
+		FittingID int64 `json:"fitting_id"`
+	}
+	if err := unmarshalJSON(data, &resp); err != nil {
+		return 0, err
+	}
+	return resp.FittingID, nil
+}
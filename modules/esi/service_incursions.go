@@ -0,0 +1,17 @@
+package esi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// GetIncursions calls ESI’s /incursions/
+func (s *esiService) GetIncursions(ctx context.Context) ([]model.Incursion, error) {
+	var out []model.Incursion
+	if err := s.esiClient.GetFreshJSON(ctx, "incursions/", &out, nil, nil); err != nil {
+		return nil, fmt.Errorf("failed to fetch incursions: %w", err)
+	}
+	return out, nil
+}
@@ -4,21 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"sync"
 
 	"golang.org/x/oauth2"
 
 	"github.com/guarzo/eveapi/common/model"
 )
 
-// We’ll keep a small local map-based cache for station/structure lookups
-var (
-	locCache  = make(map[int64]int64)
-	locCacheM sync.RWMutex
-)
-
 // GetCharacterLocation calls ESI /characters/{id}/location/
-func (s *esiService) GetCharacterLocation(ctx context.Context, characterID int64, token *oauth2.Token) (int64, error) {
+func (s *esiService) GetCharacterLocation(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (model.SystemID, error) {
 	endpoint := fmt.Sprintf("characters/%d/location/?datasource=tranquility", characterID)
 	var loc model.CharacterLocation
 	err := s.esiClient.GetJSON(ctx, endpoint, &loc, token, nil)
@@ -28,11 +21,63 @@ func (s *esiService) GetCharacterLocation(ctx context.Context, characterID int64
 	return loc.SolarSystemID, nil
 }
 
-// GetCloneLocations calls ESI /characters/{id}/clones/
-func (s *esiService) GetCloneLocations(ctx context.Context, characterID int64, token *oauth2.Token) (int64, []int64, error) {
+// GetCharacterShip calls ESI /characters/{id}/ship/
+func (s *esiService) GetCharacterShip(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (*model.CharacterShip, error) {
+	endpoint := fmt.Sprintf("characters/%d/ship/?datasource=tranquility", characterID)
+	var ship model.CharacterShip
+	if err := s.esiClient.GetJSON(ctx, endpoint, &ship, token, nil); err != nil {
+		return nil, err
+	}
+	return &ship, nil
+}
+
+// GetCharacterOnline calls ESI /characters/{id}/online/
+func (s *esiService) GetCharacterOnline(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (*model.CharacterOnline, error) {
+	endpoint := fmt.Sprintf("characters/%d/online/?datasource=tranquility", characterID)
+	var online model.CharacterOnline
+	if err := s.esiClient.GetFreshJSON(ctx, endpoint, &online, token, nil); err != nil {
+		return nil, err
+	}
+	return &online, nil
+}
+
+// GetJumpFatigue calls ESI /characters/{id}/fatigue/
+func (s *esiService) GetJumpFatigue(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (*model.JumpFatigue, error) {
+	endpoint := fmt.Sprintf("characters/%d/fatigue/?datasource=tranquility", characterID)
+	var fatigue model.JumpFatigue
+	if err := s.esiClient.GetFreshJSON(ctx, endpoint, &fatigue, token, nil); err != nil {
+		return nil, err
+	}
+	return &fatigue, nil
+}
+
+// GetClones calls ESI /characters/{id}/clones/ and returns the home location
+// and jump clones as ESI reports them.
+func (s *esiService) GetClones(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (*model.CloneLocation, error) {
 	endpoint := fmt.Sprintf("characters/%d/clones/?datasource=tranquility", characterID)
 	var cl model.CloneLocation
-	if err := s.esiClient.GetJSON(ctx, endpoint, &cl, token, nil); err != nil {
+	if err := s.esiClient.GetFreshJSON(ctx, endpoint, &cl, token, nil); err != nil {
+		return nil, err
+	}
+	return &cl, nil
+}
+
+// GetImplants calls ESI /characters/{id}/implants/ for the type IDs of the
+// implants in the active clone.
+func (s *esiService) GetImplants(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.TypeID, error) {
+	endpoint := fmt.Sprintf("characters/%d/implants/?datasource=tranquility", characterID)
+	var implants []model.TypeID
+	if err := s.esiClient.GetFreshJSON(ctx, endpoint, &implants, token, nil); err != nil {
+		return nil, err
+	}
+	return implants, nil
+}
+
+// GetCloneLocations calls GetClones and resolves the home and jump clone
+// locations to their solar systems.
+func (s *esiService) GetCloneLocations(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (int64, []int64, error) {
+	cl, err := s.GetClones(ctx, characterID, token)
+	if err != nil {
 		return 0, nil, err
 	}
 
@@ -117,14 +162,9 @@ func (s *esiService) GetStation(ctx context.Context, stationID int64) (*model.St
 
 // local cache get/set
 func (s *esiService) getCache(key int64) (int64, bool) {
-	locCacheM.RLock()
-	defer locCacheM.RUnlock()
-	val, ok := locCache[key]
-	return val, ok
+	return s.locations.Get(key)
 }
 
 func (s *esiService) setCache(key, val int64) {
-	locCacheM.Lock()
-	defer locCacheM.Unlock()
-	locCache[key] = val
+	s.locations.Set(key, val)
 }
@@ -0,0 +1,135 @@
+package esi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// This file focuses on market endpoints.
+
+// GetMarketPrices calls ESI’s /markets/prices/ for CCP's average and adjusted prices.
+func (s *esiService) GetMarketPrices(ctx context.Context) ([]model.MarketPrice, error) {
+	var prices []model.MarketPrice
+	if err := s.esiClient.GetJSON(ctx, "markets/prices/", &prices, nil, nil); err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
+
+// GetRegionOrders calls ESI’s /markets/{region_id}/orders/ for one type.
+// orderType is "buy", "sell" or "all" (the default when empty).
+func (s *esiService) GetRegionOrders(ctx context.Context, regionID int64, typeID model.TypeID, orderType string) ([]model.MarketOrder, error) {
+	if orderType == "" {
+		orderType = "all"
+	}
+	endpoint := fmt.Sprintf("markets/%d/orders/", regionID)
+	params := map[string]string{"order_type": orderType}
+	if typeID != 0 {
+		params["type_id"] = strconv.FormatInt(typeID, 10)
+	}
+	return s.getOrderPages(ctx, endpoint, nil, params)
+}
+
+// ErrNoStructureAccess is matched by the *StructureAccessError
+// GetStructureOrders returns when ESI refuses the structure's market.
+var ErrNoStructureAccess = errors.New("esi: no access to structure market")
+
+// StructureAccessError reports a 403 from a structure market: the token's
+// character is not allowed to dock at StructureID, or its market is off.
+type StructureAccessError struct {
+	StructureID int64
+	Err         error
+}
+
+func (e *StructureAccessError) Error() string {
+	return fmt.Sprintf("esi: no access to market of structure %d: %v", e.StructureID, e.Err)
+}
+
+// Is makes errors.Is(err, ErrNoStructureAccess) match.
+func (e *StructureAccessError) Is(target error) bool {
+	return target == ErrNoStructureAccess
+}
+
+func (e *StructureAccessError) Unwrap() error {
+	return e.Err
+}
+
+// GetStructureOrders calls ESI’s /markets/structures/{structure_id}/
+// (requires esi-markets.structure_markets.v1) and returns every order. When
+// the character lacks docking access it fails with a *StructureAccessError.
+func (s *esiService) GetStructureOrders(ctx context.Context, structureID int64, token *oauth2.Token) ([]model.MarketOrder, error) {
+	endpoint := fmt.Sprintf("markets/structures/%d/", structureID)
+	orders, err := s.getOrderPages(ctx, endpoint, token, map[string]string{})
+	var httpErr *common.HTTPError
+	if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusForbidden {
+		return nil, &StructureAccessError{StructureID: structureID, Err: err}
+	}
+	return orders, err
+}
+
+// getOrderPages fetches every page the X-Pages header announces, bypassing
+// the response cache so that the pages are consistent.
+func (s *esiService) getOrderPages(ctx context.Context, endpoint string, token *oauth2.Token, params map[string]string) ([]model.MarketOrder, error) {
+	var out []model.MarketOrder
+	if err := s.esiClient.GetJSONAllPages(ctx, endpoint, &out, token, params); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetCharacterOrders calls ESI’s /characters/{character_id}/orders/
+// (requires esi-markets.read_character_orders.v1) for the character's open
+// orders, including those placed on behalf of its corporation.
+func (s *esiService) GetCharacterOrders(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.OwnedMarketOrder, error) {
+	endpoint := fmt.Sprintf("characters/%d/orders/", characterID)
+	var orders []model.OwnedMarketOrder
+	if err := s.esiClient.GetFreshJSON(ctx, endpoint, &orders, token, nil); err != nil {
+		return nil, fmt.Errorf("failed to fetch orders of character %d: %w", characterID, err)
+	}
+	return orders, nil
+}
+
+// GetCharacterOrderHistory calls ESI’s
+// /characters/{character_id}/orders/history/ (requires
+// esi-markets.read_character_orders.v1) for the character's cancelled and
+// expired orders of the last 90 days.
+func (s *esiService) GetCharacterOrderHistory(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.OwnedMarketOrder, error) {
+	endpoint := fmt.Sprintf("characters/%d/orders/history/", characterID)
+	var orders []model.OwnedMarketOrder
+	if err := s.esiClient.GetJSONAllPages(ctx, endpoint, &orders, token, nil); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// GetCorporationOrders calls ESI’s /corporations/{corporation_id}/orders/
+// (requires esi-markets.read_corporation_orders.v1 and an Accountant or
+// Trader role) for every open order of the corporation.
+func (s *esiService) GetCorporationOrders(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]model.OwnedMarketOrder, error) {
+	endpoint := fmt.Sprintf("corporations/%d/orders/", corporationID)
+	var orders []model.OwnedMarketOrder
+	if err := s.esiClient.GetJSONAllPages(ctx, endpoint, &orders, token, nil); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// GetMarketHistory calls ESI’s /markets/{region_id}/history/ for one type.
+// History changes daily, so the long-lived response cache is bypassed.
+func (s *esiService) GetMarketHistory(ctx context.Context, regionID int64, typeID model.TypeID) ([]model.MarketHistoryDay, error) {
+	endpoint := fmt.Sprintf("markets/%d/history/", regionID)
+	params := map[string]string{"type_id": strconv.FormatInt(typeID, 10)}
+	var days []model.MarketHistoryDay
+	if err := s.esiClient.GetFreshJSON(ctx, endpoint, &days, nil, params); err != nil {
+		return nil, fmt.Errorf("failed to fetch market history for type %d: %w", typeID, err)
+	}
+	return days, nil
+}
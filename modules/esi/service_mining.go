@@ -0,0 +1,34 @@
+package esi
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// This file focuses on corporation mining endpoints.
+
+// GetMiningObservers calls ESI’s /corporation/{corporation_id}/mining/observers/
+// (requires esi-industry.read_corporation_mining.v1).
+func (s *esiService) GetMiningObservers(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]model.MiningObserver, error) {
+	endpoint := fmt.Sprintf("corporation/%d/mining/observers/", corporationID)
+	var observers []model.MiningObserver
+	if err := s.esiClient.GetJSONAllPages(ctx, endpoint, &observers, token, nil); err != nil {
+		return nil, err
+	}
+	return observers, nil
+}
+
+// GetMiningObserverLedger calls ESI’s
+// /corporation/{corporation_id}/mining/observers/{observer_id}/.
+func (s *esiService) GetMiningObserverLedger(ctx context.Context, corporationID model.CorporationID, observerID int64, token *oauth2.Token) ([]model.MiningLedgerEntry, error) {
+	endpoint := fmt.Sprintf("corporation/%d/mining/observers/%d/", corporationID, observerID)
+	var entries []model.MiningLedgerEntry
+	if err := s.esiClient.GetJSONAllPages(ctx, endpoint, &entries, token, nil); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,181 @@
+package esi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// This file focuses on bulk name/ID resolution.
+
+// namesChunkSize is the maximum number of IDs /universe/names/ accepts per call.
+const namesChunkSize = 1000
+
+// idsChunkSize is the maximum number of names /universe/ids/ accepts per call.
+const idsChunkSize = 500
+
+// nameCacheExpiration bounds how long resolved names are cached; characters
+// and corporations can be renamed.
+const nameCacheExpiration = 24 * time.Hour
+
+// ResolveNames calls ESI’s POST /universe/names/ for the given IDs, splitting
+// the request into chunks of at most 1000 IDs that are posted concurrently.
+// Duplicate and zero IDs are dropped. model.GroupUniverseNames sorts the
+// result by category.
+// With a service cache configured, cached names are read in one batch and
+// only the misses are sent to ESI.
+func (s *esiService) ResolveNames(ctx context.Context, ids []int64) ([]model.UniverseName, error) {
+	unique := dedupeIDs(ids)
+	out, unique := s.cachedNames(ctx, unique)
+	chunks := chunked(unique, namesChunkSize)
+
+	var mu sync.Mutex
+	var resolved []model.UniverseName
+	err := common.Batch(ctx, chunks, func(ctx context.Context, ids []int64) error {
+		body, err := json.Marshal(ids)
+		if err != nil {
+			return err
+		}
+		data, err := s.esiClient.PostJSON(ctx, "universe/names/", nil, bytes.NewReader(body), http.StatusOK)
+		if err != nil {
+			return err
+		}
+		var chunk []model.UniverseName
+		if err := unmarshalJSON(data, &chunk); err != nil {
+			return err
+		}
+		mu.Lock()
+		resolved = append(resolved, chunk...)
+		mu.Unlock()
+		return nil
+	}, s.workers)
+	if err != nil {
+		return nil, err
+	}
+	s.cacheNames(ctx, resolved)
+	return append(out, resolved...), nil
+}
+
+// ResolveIDs calls ESI’s POST /universe/ids/ for the given names, splitting
+// the request into chunks of at most 500 names that are posted concurrently,
+// and merges the answers by category. Names must match exactly, ignoring
+// case; names nothing matches are left out. Duplicate and empty names are
+// dropped. With a service cache configured, the resolved IDs' names are
+// cached for ResolveNames.
+func (s *esiService) ResolveIDs(ctx context.Context, names []string) (*model.UniverseIDs, error) {
+	var mu sync.Mutex
+	var resolved []model.UniverseName
+	err := common.Batch(ctx, chunked(dedupeNames(names), idsChunkSize), func(ctx context.Context, names []string) error {
+		body, err := json.Marshal(names)
+		if err != nil {
+			return err
+		}
+		data, err := s.esiClient.PostJSON(ctx, "universe/ids/", nil, bytes.NewReader(body), http.StatusOK)
+		if err != nil {
+			return err
+		}
+		var chunk model.UniverseIDs
+		if err := unmarshalJSON(data, &chunk); err != nil {
+			return err
+		}
+		mu.Lock()
+		resolved = append(resolved, chunk.All()...)
+		mu.Unlock()
+		return nil
+	}, s.workers)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(resolved, func(i, j int) bool { return resolved[i].ID < resolved[j].ID })
+	s.cacheNames(ctx, resolved)
+	return model.GroupUniverseNames(resolved), nil
+}
+
+func nameCacheKey(id int64) string {
+	return fmt.Sprintf("esi:name:%d", id)
+}
+
+// cachedNames splits ids into names already in the service cache and the IDs
+// still to resolve. Cache failures are treated as misses.
+func (s *esiService) cachedNames(ctx context.Context, ids []int64) ([]model.UniverseName, []int64) {
+	if s.cache == nil || len(ids) == 0 {
+		return nil, ids
+	}
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = nameCacheKey(id)
+	}
+	hits, err := common.MGet(ctx, s.cache, keys)
+	if err != nil {
+		return nil, ids
+	}
+	var found []model.UniverseName
+	var missing []int64
+	for i, id := range ids {
+		var n model.UniverseName
+		if raw, ok := hits[keys[i]]; ok && json.Unmarshal(raw, &n) == nil {
+			found = append(found, n)
+			continue
+		}
+		missing = append(missing, id)
+	}
+	return found, missing
+}
+
+func (s *esiService) cacheNames(ctx context.Context, names []model.UniverseName) {
+	if s.cache == nil || len(names) == 0 {
+		return
+	}
+	entries := make(map[string][]byte, len(names))
+	for _, n := range names {
+		if raw, err := json.Marshal(n); err == nil {
+			entries[nameCacheKey(n.ID)] = raw
+		}
+	}
+	_ = common.MSet(ctx, s.cache, entries, nameCacheExpiration)
+}
+
+// chunked splits items into consecutive chunks of at most size items.
+func chunked[T any](items []T, size int) [][]T {
+	var chunks [][]T
+	for start := 0; start < len(items); start += size {
+		chunks = append(chunks, items[start:min(start+size, len(items))])
+	}
+	return chunks
+}
+
+func dedupeNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	out := make([]string, 0, len(names))
+	for _, n := range names {
+		key := strings.ToLower(n)
+		if n == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, n)
+	}
+	return out
+}
+
+func dedupeIDs(ids []int64) []int64 {
+	seen := make(map[int64]bool, len(ids))
+	out := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if id == 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		out = append(out, id)
+	}
+	return out
+}
@@ -0,0 +1,21 @@
+package esi
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// GetCharacterNotifications calls ESI’s /characters/{character_id}/notifications/
+// (requires esi-characters.read_notifications.v1).
+func (s *esiService) GetCharacterNotifications(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.Notification, error) {
+	endpoint := fmt.Sprintf("characters/%d/notifications/", characterID)
+	var out []model.Notification
+	if err := s.esiClient.GetFreshJSON(ctx, endpoint, &out, token, nil); err != nil {
+		return nil, fmt.Errorf("failed to fetch notifications for character %d: %w", characterID, err)
+	}
+	return out, nil
+}
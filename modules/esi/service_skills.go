@@ -0,0 +1,47 @@
+package esi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// This file focuses on character skill endpoints.
+
+// GetCharacterSkills calls ESI’s /characters/{character_id}/skills/
+// (requires esi-skills.read_skills.v1).
+func (s *esiService) GetCharacterSkills(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (*model.CharacterSkills, error) {
+	endpoint := fmt.Sprintf("characters/%d/skills/", characterID)
+	var skills model.CharacterSkills
+	if err := s.esiClient.GetFreshJSON(ctx, endpoint, &skills, token, nil); err != nil {
+		return nil, fmt.Errorf("failed to fetch skills of character %d: %w", characterID, err)
+	}
+	return &skills, nil
+}
+
+// GetCharacterSkillQueue calls ESI’s /characters/{character_id}/skillqueue/
+// (requires esi-skills.read_skillqueue.v1). Entries are in queue order.
+func (s *esiService) GetCharacterSkillQueue(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.SkillQueueEntry, error) {
+	endpoint := fmt.Sprintf("characters/%d/skillqueue/", characterID)
+	var queue []model.SkillQueueEntry
+	if err := s.esiClient.GetFreshJSON(ctx, endpoint, &queue, token, nil); err != nil {
+		return nil, fmt.Errorf("failed to fetch skill queue of character %d: %w", characterID, err)
+	}
+	sort.SliceStable(queue, func(i, j int) bool { return queue[i].QueuePosition < queue[j].QueuePosition })
+	return queue, nil
+}
+
+// GetCharacterAttributes calls ESI’s /characters/{character_id}/attributes/
+// (requires esi-skills.read_skills.v1).
+func (s *esiService) GetCharacterAttributes(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (*model.CharacterAttributes, error) {
+	endpoint := fmt.Sprintf("characters/%d/attributes/", characterID)
+	var attrs model.CharacterAttributes
+	if err := s.esiClient.GetFreshJSON(ctx, endpoint, &attrs, token, nil); err != nil {
+		return nil, fmt.Errorf("failed to fetch attributes of character %d: %w", characterID, err)
+	}
+	return &attrs, nil
+}
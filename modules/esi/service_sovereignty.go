@@ -0,0 +1,17 @@
+package esi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// GetSovereigntyCampaigns calls ESI’s /sovereignty/campaigns/
+func (s *esiService) GetSovereigntyCampaigns(ctx context.Context) ([]model.SovereigntyCampaign, error) {
+	var out []model.SovereigntyCampaign
+	if err := s.esiClient.GetFreshJSON(ctx, "sovereignty/campaigns/", &out, nil, nil); err != nil {
+		return nil, fmt.Errorf("failed to fetch sovereignty campaigns: %w", err)
+	}
+	return out, nil
+}
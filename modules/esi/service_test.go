@@ -2,13 +2,19 @@ package esi_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"golang.org/x/oauth2"
 	"io"
 	"reflect"
+	"sort"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/guarzo/eveapi/common"
 	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/auth"
 	"github.com/guarzo/eveapi/modules/esi"
 )
 
@@ -20,10 +26,16 @@ type mockEsiClient struct {
 	deleteJSONFunc func(ctx context.Context, endpoint string, token *oauth2.Token, body io.Reader, expectedStatusCodes ...int) ([]byte, error)
 }
 
-func (m *mockEsiClient) GetJSON(ctx context.Context, endpoint string, entity interface{}, token *oauth2.Token, params map[string]string) error {
+func (m *mockEsiClient) GetJSON(ctx context.Context, endpoint string, entity interface{}, token *oauth2.Token, params map[string]string, opts ...esi.CallOption) error {
 	return m.getJSONFunc(ctx, endpoint, entity, token, params)
 }
-func (m *mockEsiClient) GetBytes(ctx context.Context, endpoint string, token *oauth2.Token, params map[string]string) ([]byte, error) {
+func (m *mockEsiClient) GetFreshJSON(ctx context.Context, endpoint string, entity interface{}, token *oauth2.Token, params map[string]string, opts ...esi.CallOption) error {
+	return m.getJSONFunc(ctx, endpoint, entity, token, params)
+}
+func (m *mockEsiClient) GetJSONAllPages(ctx context.Context, endpoint string, entity interface{}, token *oauth2.Token, params map[string]string, opts ...esi.CallOption) error {
+	return m.getJSONFunc(ctx, endpoint, entity, token, params)
+}
+func (m *mockEsiClient) GetBytes(ctx context.Context, endpoint string, token *oauth2.Token, params map[string]string, opts ...esi.CallOption) ([]byte, error) {
 	return m.getBytesFunc(ctx, endpoint, token, params)
 }
 func (m *mockEsiClient) DoRequest(ctx context.Context, method, urlStr string, token *oauth2.Token, body io.Reader, expectedStatus ...int) ([]byte, error) {
@@ -35,6 +47,9 @@ func (m *mockEsiClient) PostJSON(ctx context.Context, endpoint string, token *oa
 func (m *mockEsiClient) DeleteJSON(ctx context.Context, endpoint string, token *oauth2.Token, body io.Reader, expectedStatusCodes ...int) ([]byte, error) {
 	return m.deleteJSONFunc(ctx, endpoint, token, body, expectedStatusCodes...)
 }
+func (m *mockEsiClient) Stats() common.ClientStats {
+	return common.ClientStats{}
+}
 
 func TestEsiService_GetUserInfo(t *testing.T) {
 	mClient := &mockEsiClient{
@@ -58,3 +73,372 @@ func TestEsiService_GetUserInfo(t *testing.T) {
 		t.Errorf("got %#v, want %#v", user, expected)
 	}
 }
+
+type claimsValidator map[string]*auth.Claims
+
+func (v claimsValidator) Validate(ctx context.Context, accessToken string) (*auth.Claims, error) {
+	if c, ok := v[accessToken]; ok {
+		return c, nil
+	}
+	return nil, auth.ErrInvalidToken
+}
+
+func TestEsiService_GetUserInfo_Validator(t *testing.T) {
+	mClient := &mockEsiClient{
+		doRequestFunc: func(ctx context.Context, method, urlStr string, token *oauth2.Token, body io.Reader, expectedStatus ...int) ([]byte, error) {
+			return nil, errors.New("unexpected request to " + urlStr)
+		},
+	}
+	v := claimsValidator{"abc": {CharacterID: 123, CharacterName: "Test Char"}}
+	svc := esi.NewEsiService(mClient, esi.WithTokenValidator(v))
+
+	ctx := context.Background()
+	user, err := svc.GetUserInfo(ctx, &oauth2.Token{AccessToken: "abc"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := (&model.User{CharacterID: 123, CharacterName: "Test Char"}); !reflect.DeepEqual(user, expected) {
+		t.Errorf("got %#v, want %#v", user, expected)
+	}
+	if _, err := svc.GetUserInfo(ctx, &oauth2.Token{AccessToken: "forged"}); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("expected ErrInvalidToken, got %v", err)
+	}
+}
+
+func TestEsiService_GetAllCharacterAssets(t *testing.T) {
+	mClient := &mockEsiClient{
+		getJSONFunc: func(ctx context.Context, endpoint string, entity interface{}, token *oauth2.Token, params map[string]string) error {
+			assets := entity.(*[]model.Asset)
+			*assets = []model.Asset{
+				{TypeID: 34, Quantity: 100, LocationType: "station", LocationID: 60003760},
+				{TypeID: 34, Quantity: 50, LocationType: "station", LocationID: 60003760},
+				{TypeID: 587, Quantity: 1, LocationType: "station", LocationID: 60003760},
+				{TypeID: 35, Quantity: 10, LocationType: "structure", LocationID: 1022734985679},
+				{TypeID: 36, Quantity: 5, LocationType: "item", LocationID: 1000000000001},
+				// a container in the station holding a ship with cargo
+				{ItemID: 900, TypeID: 17366, Quantity: 1, LocationType: "station", LocationID: 60003760, IsSingleton: true},
+				{ItemID: 901, TypeID: 587, Quantity: 1, LocationType: "item", LocationID: 900, IsSingleton: true},
+				{ItemID: 902, TypeID: 34, Quantity: 25, LocationType: "item", LocationID: 901, LocationFlag: "Cargo"},
+			}
+			return nil
+		},
+	}
+
+	svc := esi.NewEsiService(mClient)
+	out, err := svc.GetAllCharacterAssets(context.Background(), 123, &oauth2.Token{AccessToken: "abc"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out) != 2 {
+		t.Fatalf("expected 2 locations, got %d", len(out))
+	}
+	if out[0].LocID != 60003760 || out[0].LocType != "station" || out[0].Items[34] != 175 || out[0].Items[587] != 2 || out[0].Items[17366] != 1 {
+		t.Errorf("expected nested assets under their station, got %#v", out[0])
+	}
+	if out[1].LocType != "structure" || out[1].Items[35] != 10 {
+		t.Errorf("unexpected structure inventory: %#v", out[1])
+	}
+}
+
+func TestEsiService_GetCharacterAssetsRaw(t *testing.T) {
+	mClient := &mockEsiClient{
+		getJSONFunc: func(ctx context.Context, endpoint string, entity interface{}, token *oauth2.Token, params map[string]string) error {
+			*entity.(*[]model.Asset) = []model.Asset{
+				{ItemID: 1, TypeID: 34, Quantity: 100, LocationType: "station", LocationID: 60003760},
+				{ItemID: 2, TypeID: 587, Quantity: 1, LocationType: "station", LocationID: 60003760, IsSingleton: true},
+				{ItemID: 3, TypeID: 36, Quantity: 5, LocationType: "item", LocationID: 2},
+			}
+			return nil
+		},
+	}
+	svc := esi.NewEsiService(mClient)
+	ctx := context.Background()
+
+	all, err := svc.GetCharacterAssetsRaw(ctx, 123, &oauth2.Token{AccessToken: "abc"}, esi.AssetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(all) != 3 || all[2].LocationType != "item" {
+		t.Errorf("expected every asset unfiltered, got %+v", all)
+	}
+	ships, err := svc.GetCharacterAssetsRaw(ctx, 123, &oauth2.Token{AccessToken: "abc"}, esi.AssetOptions{
+		Filter: func(a model.Asset) bool { return a.IsSingleton },
+	})
+	if err != nil || len(ships) != 1 || ships[0].ItemID != 2 {
+		t.Errorf("expected only the assembled ship, got %+v, %v", ships, err)
+	}
+}
+
+func TestEsiService_GetCharacterAssetLocations(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		chunks []int
+	)
+	mClient := &mockEsiClient{
+		postJSONFunc: func(ctx context.Context, endpoint string, token *oauth2.Token, body io.Reader, expectedStatusCodes ...int) ([]byte, error) {
+			if endpoint != "characters/123/assets/locations/" {
+				t.Errorf("unexpected endpoint %s", endpoint)
+			}
+			var ids []int64
+			if err := json.NewDecoder(body).Decode(&ids); err != nil {
+				return nil, err
+			}
+			mu.Lock()
+			chunks = append(chunks, len(ids))
+			mu.Unlock()
+			locs := make([]model.AssetLocation, len(ids))
+			for i, id := range ids {
+				locs[i] = model.AssetLocation{ItemID: id, Position: model.Position{X: float64(id)}}
+			}
+			return json.Marshal(locs)
+		},
+	}
+	svc := esi.NewEsiService(mClient)
+
+	itemIDs := make([]int64, 0, 2501)
+	for id := int64(2500); id > 0; id-- {
+		itemIDs = append(itemIDs, id)
+	}
+	itemIDs = append(itemIDs, 7) // duplicates are only asked for once
+	locs, err := svc.GetCharacterAssetLocations(context.Background(), 123, itemIDs, &oauth2.Token{AccessToken: "abc"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sort.Ints(chunks)
+	if !reflect.DeepEqual(chunks, []int{500, 1000, 1000}) {
+		t.Errorf("expected chunks of at most 1000 IDs, got %v", chunks)
+	}
+	if len(locs) != 2500 || locs[0].ItemID != 1 || locs[2499].Position.X != 2500 {
+		t.Errorf("expected 2500 locations ordered by item ID, got %d", len(locs))
+	}
+}
+
+func TestEsiService_GetCharacterAssets_CustomRequirements(t *testing.T) {
+	mClient := &mockEsiClient{
+		getJSONFunc: func(ctx context.Context, endpoint string, entity interface{}, token *oauth2.Token, params map[string]string) error {
+			assets := entity.(*[]model.Asset)
+			*assets = []model.Asset{
+				// has both items => matches
+				{TypeID: 16273, Quantity: 500, LocationType: "station", LocationID: 1},
+				{TypeID: 21096, Quantity: 1, LocationType: "station", LocationID: 1},
+				// only fuel => fails the AND group
+				{TypeID: 16273, Quantity: 500, LocationType: "station", LocationID: 2},
+			}
+			return nil
+		},
+	}
+
+	reqs := esi.ItemRequirementSet{
+		Mode: esi.MatchAny,
+		Groups: []esi.ItemGroup{{
+			Name: "cyno fit",
+			Mode: esi.MatchAll,
+			Items: []model.Item{
+				{ID: 16273, Name: "Liquid Ozone", Qty: 200},
+				{ID: 21096, Name: "Cynosural Field Generator I", Qty: 1},
+			},
+		}},
+	}
+
+	svc := esi.NewEsiService(mClient, esi.WithCynoRequirements(reqs))
+	out, err := svc.GetCharacterAssets(context.Background(), 123, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out) != 1 || out[0].LocID != 1 {
+		t.Fatalf("expected only location 1, got %#v", out)
+	}
+	if out[0].Items["Cynosural Field Generator I"] != 1 || out[0].Items["Liquid Ozone"] != 500 {
+		t.Errorf("unexpected items: %#v", out[0].Items)
+	}
+}
+
+func TestEsiService_GetRegionOrders(t *testing.T) {
+	mClient := &mockEsiClient{
+		getJSONFunc: func(ctx context.Context, endpoint string, entity interface{}, token *oauth2.Token, params map[string]string) error {
+			if endpoint != "markets/10000002/orders/" {
+				return errors.New("unexpected endpoint " + endpoint)
+			}
+			if params["order_type"] != "all" || params["type_id"] != "34" {
+				t.Errorf("unexpected params %v", params)
+			}
+			*entity.(*[]model.MarketOrder) = []model.MarketOrder{
+				{OrderID: 1, TypeID: 34, Price: 5.1},
+				{OrderID: 2, TypeID: 34, Price: 4.9, IsBuyOrder: true},
+			}
+			return nil
+		},
+	}
+
+	svc := esi.NewEsiService(mClient)
+	orders, err := svc.GetRegionOrders(context.Background(), 10000002, 34, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(orders) != 2 || !orders[1].IsBuyOrder {
+		t.Errorf("unexpected orders %+v", orders)
+	}
+}
+
+func TestEsiService_GetCorporationOrders(t *testing.T) {
+	mClient := &mockEsiClient{
+		getJSONFunc: func(ctx context.Context, endpoint string, entity interface{}, token *oauth2.Token, params map[string]string) error {
+			if endpoint != "corporations/98000001/orders/" {
+				return errors.New("unexpected endpoint " + endpoint)
+			}
+			return json.Unmarshal([]byte(`[{"order_id":7,"type_id":34,"region_id":10000002,"location_id":60003760,
+				"is_buy_order":true,"price":4.5,"volume_remain":10,"volume_total":20,"escrow":45,
+				"issued_by":90000001,"wallet_division":2,"range":"station","duration":90,"issued":"2026-10-01T12:00:00Z"}]`), entity)
+		},
+	}
+
+	svc := esi.NewEsiService(mClient)
+	orders, err := svc.GetCorporationOrders(context.Background(), 98000001, &oauth2.Token{AccessToken: "abc"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(orders) != 1 {
+		t.Fatalf("expected one order, got %d", len(orders))
+	}
+	o := orders[0]
+	if o.OrderID != 7 || o.TypeID != 34 || !o.IsBuyOrder || o.RegionID != 10000002 || o.Escrow != 45 || o.WalletDivision != 2 {
+		t.Errorf("unexpected order %+v", o)
+	}
+}
+
+func TestEsiService_GetStructureOrders_NoAccess(t *testing.T) {
+	mClient := &mockEsiClient{
+		getJSONFunc: func(ctx context.Context, endpoint string, entity interface{}, token *oauth2.Token, params map[string]string) error {
+			return &common.HTTPError{StatusCode: 403, Body: []byte(`{"error":"Forbidden"}`)}
+		},
+	}
+
+	svc := esi.NewEsiService(mClient)
+	_, err := svc.GetStructureOrders(context.Background(), 1035466617946, &oauth2.Token{AccessToken: "abc"})
+	if !errors.Is(err, esi.ErrNoStructureAccess) {
+		t.Fatalf("expected ErrNoStructureAccess, got %v", err)
+	}
+	var accessErr *esi.StructureAccessError
+	var httpErr *common.HTTPError
+	if !errors.As(err, &accessErr) || accessErr.StructureID != 1035466617946 || !errors.As(err, &httpErr) {
+		t.Errorf("expected a StructureAccessError wrapping the HTTP error, got %v", err)
+	}
+}
+
+func TestEsiService_GetCharacterSkillQueue(t *testing.T) {
+	mClient := &mockEsiClient{
+		getJSONFunc: func(ctx context.Context, endpoint string, entity interface{}, token *oauth2.Token, params map[string]string) error {
+			if endpoint != "characters/123/skillqueue/" {
+				return errors.New("unexpected endpoint " + endpoint)
+			}
+			return json.Unmarshal([]byte(`[
+				{"skill_id":3300,"finished_level":5,"queue_position":1,"level_start_sp":45255,"level_end_sp":256000},
+				{"skill_id":3302,"finished_level":4,"queue_position":0,"start_date":"2026-10-15T10:00:00Z",
+				 "finish_date":"2026-10-17T10:00:00Z","training_start_sp":8000,"level_start_sp":8000,"level_end_sp":45255}]`), entity)
+		},
+	}
+
+	svc := esi.NewEsiService(mClient)
+	queue, err := svc.GetCharacterSkillQueue(context.Background(), 123, &oauth2.Token{AccessToken: "abc"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(queue) != 2 || queue[0].SkillID != 3302 || queue[1].SkillID != 3300 {
+		t.Fatalf("expected the queue in position order, got %+v", queue)
+	}
+	if queue[0].FinishDate == nil || queue[0].FinishDate.Day() != 17 || queue[1].FinishDate != nil {
+		t.Errorf("unexpected finish dates %v, %v", queue[0].FinishDate, queue[1].FinishDate)
+	}
+}
+
+func TestEsiService_GetClones(t *testing.T) {
+	mClient := &mockEsiClient{
+		getJSONFunc: func(ctx context.Context, endpoint string, entity interface{}, token *oauth2.Token, params map[string]string) error {
+			if endpoint != "characters/123/clones/?datasource=tranquility" {
+				return errors.New("unexpected endpoint " + endpoint)
+			}
+			return json.Unmarshal([]byte(`{"home_location":{"location_id":60003760,"location_type":"station"},
+				"jump_clones":[{"jump_clone_id":9,"location_id":1035466617946,"location_type":"structure",
+				"name":"Ratting","implants":[9899,9941]}]}`), entity)
+		},
+	}
+
+	svc := esi.NewEsiService(mClient)
+	clones, err := svc.GetClones(context.Background(), 123, &oauth2.Token{AccessToken: "abc"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if clones.HomeLocation.LocationID != 60003760 || len(clones.JumpClones) != 1 {
+		t.Fatalf("unexpected clones %+v", clones)
+	}
+	jc := clones.JumpClones[0]
+	if jc.Name != "Ratting" || jc.LocationType != "structure" || !reflect.DeepEqual(jc.Implants, []model.TypeID{9899, 9941}) {
+		t.Errorf("unexpected jump clone %+v", jc)
+	}
+}
+
+func TestEsiService_GetJumpFatigue(t *testing.T) {
+	mClient := &mockEsiClient{
+		getJSONFunc: func(ctx context.Context, endpoint string, entity interface{}, token *oauth2.Token, params map[string]string) error {
+			if endpoint != "characters/123/fatigue/?datasource=tranquility" {
+				return errors.New("unexpected endpoint " + endpoint)
+			}
+			return json.Unmarshal([]byte(`{"jump_fatigue_expire_date":"2026-10-16T14:00:00Z",
+				"last_jump_date":"2026-10-16T12:00:00Z","last_update_date":"2026-10-16T12:00:00Z"}`), entity)
+		},
+	}
+
+	svc := esi.NewEsiService(mClient)
+	fatigue, err := svc.GetJumpFatigue(context.Background(), 123, &oauth2.Token{AccessToken: "abc"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2026, 10, 16, 13, 30, 0, 0, time.UTC)
+	if got := fatigue.Remaining(now); got != 30*time.Minute {
+		t.Errorf("expected 30m of fatigue left, got %v", got)
+	}
+	if got := fatigue.Remaining(now.Add(time.Hour)); got != 0 {
+		t.Errorf("expected expired fatigue, got %v", got)
+	}
+	if got := (&model.JumpFatigue{}).Remaining(now); got != 0 {
+		t.Errorf("expected no fatigue without dates, got %v", got)
+	}
+}
+
+func TestEsiService_ValueAssets(t *testing.T) {
+	mClient := &mockEsiClient{
+		getJSONFunc: func(ctx context.Context, endpoint string, entity interface{}, token *oauth2.Token, params map[string]string) error {
+			if endpoint != "markets/prices/" {
+				return errors.New("unexpected endpoint " + endpoint)
+			}
+			prices := entity.(*[]model.MarketPrice)
+			*prices = []model.MarketPrice{
+				{TypeID: 34, AveragePrice: 5, AdjustedPrice: 4},
+				{TypeID: 35, AdjustedPrice: 10},
+			}
+			return nil
+		},
+	}
+
+	svc := esi.NewEsiService(mClient)
+	locs := []model.LocationAssets{
+		{LocID: 1, Items: map[int64]int{34: 100, 35: 2}},
+		{LocID: 2, Items: map[int64]int{34: 10, 99999: 1}},
+	}
+	val, err := svc.ValueAssets(context.Background(), locs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if val.Total != 570 {
+		t.Errorf("expected total 570, got %v", val.Total)
+	}
+	if val.ByLocation[1] != 520 || val.ByLocation[2] != 50 {
+		t.Errorf("unexpected per-location values: %#v", val.ByLocation)
+	}
+	if val.ByType[34] != 550 || val.ByType[35] != 20 {
+		t.Errorf("unexpected per-type values: %#v", val.ByType)
+	}
+	if !reflect.DeepEqual(val.Unpriced, []int64{99999}) {
+		t.Errorf("expected 99999 unpriced, got %v", val.Unpriced)
+	}
+}
@@ -0,0 +1,55 @@
+package esi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// This file focuses on static universe data (systems, positions).
+
+// GetSolarSystem calls ESI’s /universe/systems/{system_id}/
+func (s *esiService) GetSolarSystem(ctx context.Context, systemID model.SystemID) (*model.SolarSystem, error) {
+	endpoint := fmt.Sprintf("universe/systems/%d/", systemID)
+	var sys model.SolarSystem
+	if err := s.esiClient.GetJSON(ctx, endpoint, &sys, nil, nil); err != nil {
+		return nil, err
+	}
+	return &sys, nil
+}
+
+// GetConstellation calls ESI’s /universe/constellations/{constellation_id}/
+func (s *esiService) GetConstellation(ctx context.Context, constellationID int64) (*model.Constellation, error) {
+	endpoint := fmt.Sprintf("universe/constellations/%d/", constellationID)
+	var c model.Constellation
+	if err := s.esiClient.GetJSON(ctx, endpoint, &c, nil, nil); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// GetRoute calls ESI’s /route/{origin}/{destination}/ and returns the
+// systems on the gate route, origin and destination included. flag is
+// "shortest" (the default when empty), "secure" or "insecure".
+func (s *esiService) GetRoute(ctx context.Context, origin, destination int64, flag string) ([]int64, error) {
+	if flag == "" {
+		flag = "shortest"
+	}
+	endpoint := fmt.Sprintf("route/%d/%d/", origin, destination)
+	var route []int64
+	if err := s.esiClient.GetJSON(ctx, endpoint, &route, nil, map[string]string{"flag": flag}); err != nil {
+		return nil, fmt.Errorf("failed to fetch route %d -> %d: %w", origin, destination, err)
+	}
+	return route, nil
+}
+
+// GetType calls ESI’s /universe/types/{type_id}/, including dogma attributes.
+func (s *esiService) GetType(ctx context.Context, typeID model.TypeID) (*model.ItemType, error) {
+	endpoint := fmt.Sprintf("universe/types/%d/", typeID)
+	var t model.ItemType
+	if err := s.esiClient.GetJSON(ctx, endpoint, &t, nil, nil); err != nil {
+		return nil, fmt.Errorf("failed to fetch type %d: %w", typeID, err)
+	}
+	return &t, nil
+}
@@ -0,0 +1,51 @@
+package esi
+
+import (
+	"context"
+	"sort"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// This file focuses on putting an ISK value on asset lists.
+
+// ValueAssets prices the given inventories against /markets/prices/ and returns
+// ISK totals per location and per type.
+func (s *esiService) ValueAssets(ctx context.Context, locations []model.LocationAssets) (*model.AssetValuation, error) {
+	prices, err := s.GetMarketPrices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ValueAssetsWithPrices(locations, model.PriceTable(prices)), nil
+}
+
+// ValueAssetsWithPrices values inventories using a caller-provided price table
+// (typeID -> unit price), e.g. from a region order snapshot.
+// Types without a price are listed in Unpriced and contribute nothing.
+func ValueAssetsWithPrices(locations []model.LocationAssets, prices map[int64]float64) *model.AssetValuation {
+	out := &model.AssetValuation{
+		ByLocation: make(map[int64]float64),
+		ByType:     make(map[int64]float64),
+	}
+	unpriced := make(map[int64]bool)
+
+	for _, loc := range locations {
+		for typeID, qty := range loc.Items {
+			price, ok := prices[typeID]
+			if !ok {
+				unpriced[typeID] = true
+				continue
+			}
+			value := price * float64(qty)
+			out.ByLocation[loc.LocID] += value
+			out.ByType[typeID] += value
+			out.Total += value
+		}
+	}
+
+	for typeID := range unpriced {
+		out.Unpriced = append(out.Unpriced, typeID)
+	}
+	sort.Slice(out.Unpriced, func(i, j int) bool { return out.Unpriced[i] < out.Unpriced[j] })
+	return out
+}
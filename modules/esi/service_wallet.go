@@ -0,0 +1,56 @@
+package esi
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// This file focuses on character and corporation wallet endpoints.
+
+// GetCharacterWallet calls ESI’s /characters/{character_id}/wallet/
+// (requires esi-wallet.read_character_wallet.v1).
+func (s *esiService) GetCharacterWallet(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (float64, error) {
+	endpoint := fmt.Sprintf("characters/%d/wallet/", characterID)
+	var balance float64
+	if err := s.esiClient.GetFreshJSON(ctx, endpoint, &balance, token, nil); err != nil {
+		return 0, fmt.Errorf("failed to fetch wallet of character %d: %w", characterID, err)
+	}
+	return balance, nil
+}
+
+// GetCharacterWalletJournal calls ESI’s /characters/{character_id}/wallet/journal/
+// (requires esi-wallet.read_character_wallet.v1).
+func (s *esiService) GetCharacterWalletJournal(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.WalletJournalEntry, error) {
+	endpoint := fmt.Sprintf("characters/%d/wallet/journal/", characterID)
+	var entries []model.WalletJournalEntry
+	if err := s.esiClient.GetJSONAllPages(ctx, endpoint, &entries, token, nil); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// GetCorporationWallets calls ESI’s /corporations/{corporation_id}/wallets/
+// (requires esi-wallet.read_corporation_wallets.v1).
+func (s *esiService) GetCorporationWallets(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]model.CorporationWallet, error) {
+	endpoint := fmt.Sprintf("corporations/%d/wallets/", corporationID)
+	var wallets []model.CorporationWallet
+	if err := s.esiClient.GetFreshJSON(ctx, endpoint, &wallets, token, nil); err != nil {
+		return nil, fmt.Errorf("failed to fetch wallets of corporation %d: %w", corporationID, err)
+	}
+	return wallets, nil
+}
+
+// GetCorporationWalletJournal calls ESI’s
+// /corporations/{corporation_id}/wallets/{division}/journal/.
+func (s *esiService) GetCorporationWalletJournal(ctx context.Context, corporationID model.CorporationID, division int, token *oauth2.Token) ([]model.WalletJournalEntry, error) {
+	endpoint := fmt.Sprintf("corporations/%d/wallets/%d/journal/", corporationID, division)
+	var entries []model.WalletJournalEntry
+	if err := s.esiClient.GetJSONAllPages(ctx, endpoint, &entries, token, nil); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,36 @@
+package esi
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// This file focuses on the public wars API.
+
+// GetWars calls ESI’s /wars/, returning up to 2000 war IDs in descending
+// order. A non-zero maxWarID only returns wars with a lower ID, for paging
+// backwards.
+func (s *esiService) GetWars(ctx context.Context, maxWarID int64) ([]int64, error) {
+	var params map[string]string
+	if maxWarID > 0 {
+		params = map[string]string{"max_war_id": strconv.FormatInt(maxWarID, 10)}
+	}
+	var ids []int64
+	if err := s.esiClient.GetFreshJSON(ctx, "wars/", &ids, nil, params); err != nil {
+		return nil, fmt.Errorf("failed to fetch wars: %w", err)
+	}
+	return ids, nil
+}
+
+// GetWar calls ESI’s /wars/{war_id}/
+func (s *esiService) GetWar(ctx context.Context, warID int64) (*model.War, error) {
+	endpoint := fmt.Sprintf("wars/%d/", warID)
+	var war model.War
+	if err := s.esiClient.GetFreshJSON(ctx, endpoint, &war, nil, nil); err != nil {
+		return nil, fmt.Errorf("failed to fetch war %d: %w", warID, err)
+	}
+	return &war, nil
+}
@@ -0,0 +1,52 @@
+package esi
+
+import (
+	"path"
+	"strings"
+	"time"
+)
+
+// TTLPolicy maps endpoint patterns to how long the client caches their
+// responses. Patterns are path.Match patterns over the endpoint without its
+// query string, e.g. "characters/*/location/"; when several match, the
+// longest pattern wins. A TTL <= 0 disables caching for the endpoint.
+type TTLPolicy map[string]time.Duration
+
+// DefaultTTLPolicy follows how fast ESI data changes: locations for seconds,
+// entity info for hours, universe data for days and killmails, which never
+// change, effectively forever. Other endpoints use the client's default TTL.
+func DefaultTTLPolicy() TTLPolicy {
+	return TTLPolicy{
+		"characters/*/location/": 5 * time.Second,
+		"characters/*/ship/":     5 * time.Second,
+		"characters/*/online/":   time.Minute,
+		"characters/*/":          6 * time.Hour,
+		"characters/*/portrait/": 24 * time.Hour,
+		"corporations/*/":        6 * time.Hour,
+		"alliances/*/":           24 * time.Hour,
+		"markets/prices/":        time.Hour,
+		"markets/*/orders/":      5 * time.Minute,
+		"markets/*/history/":     12 * time.Hour,
+		"universe/*/*/":          7 * 24 * time.Hour,
+		"killmails/*/*/":         365 * 24 * time.Hour,
+	}
+}
+
+// TTL returns the TTL of the longest pattern matching endpoint.
+func (p TTLPolicy) TTL(endpoint string) (time.Duration, bool) {
+	endpoint, _, _ = strings.Cut(strings.TrimPrefix(endpoint, "/"), "?")
+	var (
+		best  string
+		ttl   time.Duration
+		found bool
+	)
+	for pattern, d := range p {
+		if ok, _ := path.Match(pattern, endpoint); !ok {
+			continue
+		}
+		if !found || len(pattern) > len(best) || (len(pattern) == len(best) && pattern < best) {
+			best, ttl, found = pattern, d, true
+		}
+	}
+	return ttl, found
+}
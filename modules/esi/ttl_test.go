@@ -0,0 +1,73 @@
+package esi_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guarzo/eveapi/modules/esi"
+)
+
+// ttlCache records the TTL of every entry it stores.
+type ttlCache struct {
+	mockCache
+	ttls map[string]time.Duration
+}
+
+func (c *ttlCache) Set(key string, value []byte, ttl time.Duration) {
+	c.mockCache.Set(key, value, ttl)
+	c.ttls[key] = ttl
+}
+
+func TestTTLPolicy_LongestPatternWins(t *testing.T) {
+	p := esi.DefaultTTLPolicy()
+	cases := map[string]time.Duration{
+		"characters/1/location/?datasource=tranquility": 5 * time.Second,
+		"characters/1/":       6 * time.Hour,
+		"/killmails/7/abc/":   365 * 24 * time.Hour,
+		"universe/types/587/": 7 * 24 * time.Hour,
+	}
+	for endpoint, want := range cases {
+		if got, ok := p.TTL(endpoint); !ok || got != want {
+			t.Errorf("%s: expected %s, got %s (matched %v)", endpoint, want, got, ok)
+		}
+	}
+	if _, ok := p.TTL("characters/1/wallet/journal/"); ok {
+		t.Error("expected no pattern to match an unlisted endpoint")
+	}
+}
+
+func TestEsiClient_TTLPolicy(t *testing.T) {
+	calls := 0
+	httpClient := &mockHttpClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			calls++
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(`{}`))}, nil
+		},
+	}
+	cache := &ttlCache{mockCache: mockCache{store: map[string][]byte{}}, ttls: map[string]time.Duration{}}
+	client := esi.NewEsiClient("https://esi.evetech.net/latest/", httpClient, cache, nil,
+		esi.WithTTLPolicy(esi.TTLPolicy{"corporations/*/": 3 * time.Hour, "status/": 0}),
+		esi.WithDefaultTTL(time.Minute))
+
+	ctx := context.Background()
+	for _, endpoint := range []string{"corporations/98000001/", "wars/", "status/", "status/"} {
+		if _, err := client.GetBytes(ctx, endpoint, nil, nil); err != nil {
+			t.Fatalf("%s: unexpected error: %v", endpoint, err)
+		}
+	}
+	got := map[string]time.Duration{}
+	for key, ttl := range cache.ttls {
+		got[strings.SplitN(key, ":", 3)[1]] = ttl
+	}
+	if got["corporations/98000001/"] != 3*time.Hour || got["wars/"] != time.Minute {
+		t.Errorf("unexpected TTLs: %v", got)
+	}
+	if _, cached := got["status/"]; cached || calls != 4 {
+		t.Errorf("expected a zero TTL to bypass the cache, got %v after %d calls", got, calls)
+	}
+}
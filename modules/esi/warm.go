@@ -0,0 +1,83 @@
+package esi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// WarmCache re-fetches character, corporation and alliance info plus
+// character portraits for ids, so interactive lookups find them cached.
+// Portrait failures do not stop the run; they are joined into the result.
+func (s *esiService) WarmCache(ctx context.Context, ids model.Ids) error {
+	if _, err := s.loadESIData(ctx, ids, true); err != nil {
+		return fmt.Errorf("failed to warm ESI data: %w", err)
+	}
+	var errs []error
+	for _, id := range ids.CharacterIDs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, err := s.GetCharacterPortrait(int64(id)); err != nil {
+			errs = append(errs, fmt.Errorf("portrait %d: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CacheWarmer periodically calls WarmCache for a changing set of IDs.
+type CacheWarmer struct {
+	service  EsiService
+	interval time.Duration
+	ids      func() model.Ids
+	onError  func(error)
+	life     common.Lifecycle
+}
+
+// NewCacheWarmer warms the IDs returned by ids every interval. onError, if
+// non-nil, receives the error from each failed run.
+func NewCacheWarmer(service EsiService, interval time.Duration, ids func() model.Ids, onError func(error)) *CacheWarmer {
+	return &CacheWarmer{service: service, interval: interval, ids: ids, onError: onError}
+}
+
+// Run warms once immediately and then every interval until ctx is done or
+// the warmer is closed.
+func (w *CacheWarmer) Run(ctx context.Context) {
+	ctx, stop, done := w.life.Begin(ctx)
+	defer done()
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop: // closed while the ticker fired
+			return
+		default:
+		}
+		if err := w.service.WarmCache(ctx, w.ids()); err != nil && ctx.Err() == nil && w.onError != nil {
+			w.onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close stops Run once the warm run in flight has finished and waits for it
+// to return.
+func (w *CacheWarmer) Close() error {
+	return w.life.Close()
+}
+
+// Shutdown is Close, except that the warm run in flight is cancelled when
+// ctx is done before it finishes.
+func (w *CacheWarmer) Shutdown(ctx context.Context) error {
+	return w.life.Shutdown(ctx)
+}
@@ -0,0 +1,63 @@
+package esi_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/esi"
+)
+
+func TestEsiService_WarmCache(t *testing.T) {
+	var mu sync.Mutex
+	var endpoints []string
+	client := &mockEsiClient{
+		getBytesFunc: func(ctx context.Context, endpoint string, token *oauth2.Token, params map[string]string) ([]byte, error) {
+			mu.Lock()
+			endpoints = append(endpoints, endpoint)
+			mu.Unlock()
+			return []byte(`{"name":"x"}`), nil
+		},
+		getJSONFunc: func(ctx context.Context, endpoint string, entity interface{}, token *oauth2.Token, params map[string]string) error {
+			mu.Lock()
+			endpoints = append(endpoints, endpoint)
+			mu.Unlock()
+			return nil
+		},
+	}
+	cache := &batchCache{mockCache: mockCache{store: make(map[string][]byte)}}
+	svc := esi.NewEsiService(client, esi.WithCache(cache))
+	ids := model.Ids{CharacterIDs: []model.CharacterID{1}, CorporationIDs: []model.CorporationID{10}}
+
+	if err := svc.WarmCache(context.Background(), ids); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	joined := strings.Join(endpoints, ",")
+	for _, want := range []string{"characters/1/", "corporations/10/", "characters/1/portrait/"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("expected %s to be fetched, got %s", want, joined)
+		}
+	}
+	if len(cache.store) != 2 {
+		t.Errorf("expected 2 cached entities, got %d", len(cache.store))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runs := 0
+	w := esi.NewCacheWarmer(svc, time.Millisecond, func() model.Ids {
+		runs++
+		if runs == 3 {
+			cancel()
+		}
+		return ids
+	}, nil)
+	w.Run(ctx)
+	if runs < 3 {
+		t.Errorf("expected warmer to run repeatedly, got %d runs", runs)
+	}
+}
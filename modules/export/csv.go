@@ -0,0 +1,130 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// killmailColumns maps a column name to the function extracting its value.
+var killmailColumns = map[string]func(km *model.FlattenedKillMail) string{
+	"killmail_id":       func(km *model.FlattenedKillMail) string { return strconv.FormatInt(km.KillMailID, 10) },
+	"killmail_time":     func(km *model.FlattenedKillMail) string { return km.KillMailTime.UTC().Format(time.RFC3339) },
+	"solar_system_id":   func(km *model.FlattenedKillMail) string { return strconv.FormatInt(km.SolarSystemID, 10) },
+	"solar_system_name": func(km *model.FlattenedKillMail) string { return km.SolarSystemName },
+	"victim_character":  func(km *model.FlattenedKillMail) string { return strconv.FormatInt(km.Victim.CharacterID, 10) },
+	"victim_corp":       func(km *model.FlattenedKillMail) string { return strconv.FormatInt(km.Victim.CorporationID, 10) },
+	"victim_alliance":   func(km *model.FlattenedKillMail) string { return strconv.FormatInt(km.Victim.AllianceID, 10) },
+	"victim_ship_id":    func(km *model.FlattenedKillMail) string { return strconv.FormatInt(km.Victim.ShipTypeID, 10) },
+	"victim_ship_name":  func(km *model.FlattenedKillMail) string { return km.VictimShipName },
+	"attackers":         func(km *model.FlattenedKillMail) string { return strconv.Itoa(len(km.Attackers)) },
+	"final_blow_ship":   func(km *model.FlattenedKillMail) string { return finalBlow(km).ShipName },
+	"final_blow_weapon": func(km *model.FlattenedKillMail) string { return finalBlow(km).WeaponName },
+	"hash":              func(km *model.FlattenedKillMail) string { return km.Hash },
+	"fitted_value":      func(km *model.FlattenedKillMail) string { return formatISK(km.FittedValue) },
+	"dropped_value":     func(km *model.FlattenedKillMail) string { return formatISK(km.DroppedValue) },
+	"destroyed_value":   func(km *model.FlattenedKillMail) string { return formatISK(km.DestroyedValue) },
+	"total_value":       func(km *model.FlattenedKillMail) string { return formatISK(km.TotalValue) },
+	"points":            func(km *model.FlattenedKillMail) string { return strconv.Itoa(km.Points) },
+	"npc":               func(km *model.FlattenedKillMail) string { return strconv.FormatBool(km.NPC) },
+	"solo":              func(km *model.FlattenedKillMail) string { return strconv.FormatBool(km.Solo) },
+	"awox":              func(km *model.FlattenedKillMail) string { return strconv.FormatBool(km.Awox) },
+}
+
+// DefaultKillmailColumns are written when WriteKillmailsCSV is given no columns.
+var DefaultKillmailColumns = []string{
+	"killmail_id", "killmail_time", "solar_system_id", "solar_system_name",
+	"victim_character", "victim_corp", "victim_alliance", "victim_ship_id", "victim_ship_name",
+	"attackers", "final_blow_ship", "total_value", "points", "npc", "solo", "awox",
+}
+
+// KillmailColumns lists every supported column name.
+func KillmailColumns() []string {
+	out := make([]string, 0, len(killmailColumns))
+	for name := range killmailColumns {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// WriteKillmailsCSV writes a header row and one row per killmail to w.
+// Name columns are empty unless the kills were enriched (killmail.EnrichKillMails).
+func WriteKillmailsCSV(w io.Writer, kills []model.FlattenedKillMail, columns ...string) error {
+	kw, err := NewKillmailCSVWriter(w, columns...)
+	if err != nil {
+		return err
+	}
+	return kw.Write(kills)
+}
+
+// KillmailCSVWriter writes killmails arriving in batches as one CSV file:
+// the header row precedes the first batch.
+type KillmailCSVWriter struct {
+	cw      *csv.Writer
+	columns []string
+	getters []func(*model.FlattenedKillMail) string
+	header  bool
+}
+
+// NewKillmailCSVWriter returns a writer of the given columns
+// (DefaultKillmailColumns if none) to w.
+func NewKillmailCSVWriter(w io.Writer, columns ...string) (*KillmailCSVWriter, error) {
+	if len(columns) == 0 {
+		columns = DefaultKillmailColumns
+	}
+	getters := make([]func(*model.FlattenedKillMail) string, len(columns))
+	for i, c := range columns {
+		g, ok := killmailColumns[c]
+		if !ok {
+			return nil, fmt.Errorf("unknown killmail column %q", c)
+		}
+		getters[i] = g
+	}
+	return &KillmailCSVWriter{cw: csv.NewWriter(w), columns: columns, getters: getters}, nil
+}
+
+func (kw *KillmailCSVWriter) writeHeader() error {
+	if kw.header {
+		return nil
+	}
+	kw.header = true
+	return kw.cw.Write(kw.columns)
+}
+
+// Write writes one row per killmail, preceded by the header row on the
+// first call, and flushes them to the underlying writer.
+func (kw *KillmailCSVWriter) Write(kills []model.FlattenedKillMail) error {
+	if err := kw.writeHeader(); err != nil {
+		return err
+	}
+	row := make([]string, len(kw.columns))
+	for i := range kills {
+		for j, g := range kw.getters {
+			row[j] = g(&kills[i])
+		}
+		if err := kw.cw.Write(row); err != nil {
+			return err
+		}
+	}
+	kw.cw.Flush()
+	return kw.cw.Error()
+}
+
+func finalBlow(km *model.FlattenedKillMail) model.Attacker {
+	for _, a := range km.Attackers {
+		if a.FinalBlow {
+			return a
+		}
+	}
+	return model.Attacker{}
+}
+
+func formatISK(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
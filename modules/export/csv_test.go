@@ -0,0 +1,36 @@
+package export_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/export"
+)
+
+func TestWriteKillmailsCSV(t *testing.T) {
+	kills := []model.FlattenedKillMail{{
+		KillMailID:      42,
+		KillMailTime:    time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC),
+		SolarSystemName: "Jita",
+		VictimShipName:  "Rifter, Republic Fleet",
+		TotalValue:      1234.5,
+		Attackers:       []model.Attacker{{FinalBlow: true, ShipName: "Drake"}},
+	}}
+
+	var buf bytes.Buffer
+	err := export.WriteKillmailsCSV(&buf, kills, "killmail_id", "killmail_time", "solar_system_name", "victim_ship_name", "final_blow_ship", "total_value")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "killmail_id,killmail_time,solar_system_name,victim_ship_name,final_blow_ship,total_value\n" +
+		"42,2024-10-01T12:00:00Z,Jita,\"Rifter, Republic Fleet\",Drake,1234.50\n"
+	if buf.String() != want {
+		t.Errorf("unexpected CSV:\n%s", buf.String())
+	}
+
+	if err := export.WriteKillmailsCSV(&buf, kills, "nope"); err == nil {
+		t.Error("expected error for unknown column")
+	}
+}
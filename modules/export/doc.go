@@ -0,0 +1,3 @@
+// Package export writes killmail and asset datasets to formats used by analysts
+// and downstream tooling, such as CSV and Parquet.
+package export
@@ -0,0 +1,96 @@
+package export
+
+import (
+	"io"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// KillmailRow is the flat Parquet schema written by WriteKillmailsParquet.
+type KillmailRow struct {
+	KillMailID      int64               `parquet:"killmail_id"`
+	KillMailTime    int64               `parquet:"killmail_time,timestamp(millisecond)"`
+	SolarSystemID   model.SystemID      `parquet:"solar_system_id"`
+	SolarSystemName string              `parquet:"solar_system_name,dict"`
+	VictimCharacter model.CharacterID   `parquet:"victim_character"`
+	VictimCorp      model.CorporationID `parquet:"victim_corp"`
+	VictimAlliance  model.AllianceID    `parquet:"victim_alliance"`
+	VictimShipID    model.TypeID        `parquet:"victim_ship_id"`
+	VictimShipName  string              `parquet:"victim_ship_name,dict"`
+	Attackers       int32               `parquet:"attackers"`
+	Hash            string              `parquet:"hash"`
+	FittedValue     float64             `parquet:"fitted_value"`
+	DroppedValue    float64             `parquet:"dropped_value"`
+	DestroyedValue  float64             `parquet:"destroyed_value"`
+	TotalValue      float64             `parquet:"total_value"`
+	Points          int32               `parquet:"points"`
+	NPC             bool                `parquet:"npc"`
+	Solo            bool                `parquet:"solo"`
+	Awox            bool                `parquet:"awox"`
+}
+
+// AssetRow is the Parquet schema for asset snapshots, one row per owner, location and type.
+type AssetRow struct {
+	OwnerID    int64  `parquet:"owner_id"`
+	LocationID int64  `parquet:"location_id"`
+	LocType    string `parquet:"location_type,dict"`
+	TypeID     int64  `parquet:"type_id"`
+	Quantity   int64  `parquet:"quantity"`
+}
+
+// WriteKillmailsParquet writes kills as a single Parquet file to w.
+func WriteKillmailsParquet(w io.Writer, kills []model.FlattenedKillMail) error {
+	rows := make([]KillmailRow, len(kills))
+	for i, km := range kills {
+		rows[i] = KillmailRow{
+			KillMailID:      km.KillMailID,
+			KillMailTime:    km.KillMailTime.UnixMilli(),
+			SolarSystemID:   km.SolarSystemID,
+			SolarSystemName: km.SolarSystemName,
+			VictimCharacter: km.Victim.CharacterID,
+			VictimCorp:      km.Victim.CorporationID,
+			VictimAlliance:  km.Victim.AllianceID,
+			VictimShipID:    km.Victim.ShipTypeID,
+			VictimShipName:  km.VictimShipName,
+			Attackers:       int32(len(km.Attackers)),
+			Hash:            km.Hash,
+			FittedValue:     km.FittedValue,
+			DroppedValue:    km.DroppedValue,
+			DestroyedValue:  km.DestroyedValue,
+			TotalValue:      km.TotalValue,
+			Points:          int32(km.Points),
+			NPC:             km.NPC,
+			Solo:            km.Solo,
+			Awox:            km.Awox,
+		}
+	}
+	return writeParquet(w, rows)
+}
+
+// WriteAssetsParquet writes an asset snapshot (as returned by GetAllCharacterAssets)
+// as a single Parquet file to w.
+func WriteAssetsParquet(w io.Writer, locations []model.LocationAssets) error {
+	var rows []AssetRow
+	for _, loc := range locations {
+		for typeID, qty := range loc.Items {
+			rows = append(rows, AssetRow{
+				OwnerID:    loc.OwnerID,
+				LocationID: loc.LocID,
+				LocType:    loc.LocType,
+				TypeID:     typeID,
+				Quantity:   int64(qty),
+			})
+		}
+	}
+	return writeParquet(w, rows)
+}
+
+func writeParquet[T any](w io.Writer, rows []T) error {
+	pw := parquet.NewGenericWriter[T](w)
+	if _, err := pw.Write(rows); err != nil {
+		return err
+	}
+	return pw.Close()
+}
@@ -0,0 +1,40 @@
+package export_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/export"
+)
+
+func TestWriteKillmailsParquet(t *testing.T) {
+	ts := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	kills := []model.FlattenedKillMail{
+		{KillMailID: 1, KillMailTime: ts, TotalValue: 10, VictimShipName: "Rifter",
+			Victim: model.Victim{CharacterID: 2200000000}},
+		{KillMailID: 2, KillMailTime: ts, TotalValue: 20, Solo: true},
+	}
+
+	var buf bytes.Buffer
+	if err := export.WriteKillmailsParquet(&buf, kills); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rows, err := parquet.Read[export.KillmailRow](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("failed to read back parquet: %v", err)
+	}
+	if len(rows) != 2 || rows[0].VictimShipName != "Rifter" || !rows[1].Solo || rows[1].TotalValue != 20 {
+		t.Errorf("unexpected rows: %#v", rows)
+	}
+	if rows[0].VictimCharacter != 2200000000 {
+		t.Errorf("expected IDs beyond int32 to survive, got %d", rows[0].VictimCharacter)
+	}
+	if rows[0].KillMailTime != ts.UnixMilli() {
+		t.Errorf("unexpected timestamp: %d", rows[0].KillMailTime)
+	}
+}
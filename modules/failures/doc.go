@@ -0,0 +1,3 @@
+// Package failures records IDs that ESI permanently rejects (404/410) so
+// hydration and enrichment can skip them instead of retrying forever.
+package failures
@@ -0,0 +1,238 @@
+package failures
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/guarzo/eveapi/common"
+)
+
+// Record describes one flagged ID.
+type Record struct {
+	ID         int64     `json:"id"`
+	StatusCode int       `json:"status_code"`
+	Reason     string    `json:"reason"`
+	FlaggedAt  time.Time `json:"flagged_at"`
+}
+
+// Store persists flagged IDs.
+type Store interface {
+	Load(ctx context.Context) ([]Record, error)
+	Put(ctx context.Context, r Record) error
+	Delete(ctx context.Context, id int64) error
+}
+
+// IsPermanent reports whether err is an HTTP 404 or 410, i.e. the ID will
+// never resolve.
+func IsPermanent(err error) (int, bool) {
+	var httpErr *common.HTTPError
+	if errors.As(err, &httpErr) &&
+		(httpErr.StatusCode == http.StatusNotFound || httpErr.StatusCode == http.StatusGone) {
+		return httpErr.StatusCode, true
+	}
+	return 0, false
+}
+
+// Tracker keeps the flagged set in memory for fast Skip checks and writes
+// changes through to its Store.
+type Tracker struct {
+	store Store
+
+	mu      sync.RWMutex
+	flagged map[int64]Record
+}
+
+// NewTracker loads the flagged set from store.
+func NewTracker(ctx context.Context, store Store) (*Tracker, error) {
+	records, err := store.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load failed IDs: %w", err)
+	}
+	t := &Tracker{store: store, flagged: make(map[int64]Record, len(records))}
+	for _, r := range records {
+		t.flagged[r.ID] = r
+	}
+	return t, nil
+}
+
+// Skip reports whether id has been flagged.
+func (t *Tracker) Skip(id int64) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	_, ok := t.flagged[id]
+	return ok
+}
+
+// RecordFailure flags id if err is permanent and reports whether it did.
+// Transient errors are ignored.
+func (t *Tracker) RecordFailure(ctx context.Context, id int64, err error) bool {
+	status, ok := IsPermanent(err)
+	if !ok {
+		return false
+	}
+	return t.Flag(ctx, Record{ID: id, StatusCode: status, Reason: err.Error()}) == nil
+}
+
+// Flag marks an ID as permanently failing.
+func (t *Tracker) Flag(ctx context.Context, r Record) error {
+	if r.FlaggedAt.IsZero() {
+		r.FlaggedAt = time.Now()
+	}
+	if err := t.store.Put(ctx, r); err != nil {
+		return fmt.Errorf("failed to flag %d: %w", r.ID, err)
+	}
+	t.mu.Lock()
+	t.flagged[r.ID] = r
+	t.mu.Unlock()
+	return nil
+}
+
+// Unflag removes a manual or automatic flag, e.g. after a character is
+// restored by CCP.
+func (t *Tracker) Unflag(ctx context.Context, id int64) error {
+	if err := t.store.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to unflag %d: %w", id, err)
+	}
+	t.mu.Lock()
+	delete(t.flagged, id)
+	t.mu.Unlock()
+	return nil
+}
+
+// List returns every flagged record ordered by ID.
+func (t *Tracker) List() []Record {
+	t.mu.RLock()
+	out := make([]Record, 0, len(t.flagged))
+	for _, r := range t.flagged {
+		out = append(out, r)
+	}
+	t.mu.RUnlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// ---------------------------------------------------------------------------
+// Stores
+// ---------------------------------------------------------------------------
+
+// NewMemoryStore returns a non-persistent Store, mainly for tests.
+func NewMemoryStore() Store {
+	return &memoryStore{records: make(map[int64]Record)}
+}
+
+type memoryStore struct {
+	mu      sync.Mutex
+	records map[int64]Record
+}
+
+func (s *memoryStore) Load(context.Context) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Record, 0, len(s.records))
+	for _, r := range s.records {
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+func (s *memoryStore) Put(_ context.Context, r Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[r.ID] = r
+	return nil
+}
+
+func (s *memoryStore) Delete(_ context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, id)
+	return nil
+}
+
+// NewFileStore persists flagged IDs as a JSON file at path, rewritten on
+// every change. A missing file is treated as empty.
+func NewFileStore(path string) Store {
+	return &fileStore{path: path}
+}
+
+type fileStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+func (s *fileStore) Load(context.Context) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.read()
+}
+
+func (s *fileStore) Put(_ context.Context, r Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records, err := s.read()
+	if err != nil {
+		return err
+	}
+	replaced := false
+	for i := range records {
+		if records[i].ID == r.ID {
+			records[i] = r
+			replaced = true
+		}
+	}
+	if !replaced {
+		records = append(records, r)
+	}
+	return s.write(records)
+}
+
+func (s *fileStore) Delete(_ context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records, err := s.read()
+	if err != nil {
+		return err
+	}
+	kept := records[:0]
+	for _, r := range records {
+		if r.ID != id {
+			kept = append(kept, r)
+		}
+	}
+	return s.write(kept)
+}
+
+func (s *fileStore) read() ([]Record, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var records []Record
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", s.path, err)
+	}
+	return records, nil
+}
+
+// write replaces the file atomically via a temp file and rename.
+func (s *fileStore) write(records []Record) error {
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
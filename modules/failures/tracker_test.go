@@ -0,0 +1,51 @@
+package failures_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/modules/failures"
+)
+
+func TestTracker_PersistsAcrossRestarts(t *testing.T) {
+	ctx := context.Background()
+	store := failures.NewFileStore(filepath.Join(t.TempDir(), "failed.json"))
+	tr, err := failures.NewTracker(ctx, store)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	notFound := fmt.Errorf("fetch: %w", &common.HTTPError{StatusCode: 404})
+	if !tr.RecordFailure(ctx, 1, notFound) {
+		t.Error("expected 404 to be flagged")
+	}
+	if tr.RecordFailure(ctx, 2, errors.New("timeout")) {
+		t.Error("expected transient error to be ignored")
+	}
+	if tr.RecordFailure(ctx, 3, &common.HTTPError{StatusCode: 410}) != true {
+		t.Error("expected 410 to be flagged")
+	}
+
+	restarted, err := failures.NewTracker(ctx, store)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !restarted.Skip(1) || restarted.Skip(2) || !restarted.Skip(3) {
+		t.Errorf("unexpected flagged set after reload: %+v", restarted.List())
+	}
+
+	if err := restarted.Unflag(ctx, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	again, _ := failures.NewTracker(ctx, store)
+	if again.Skip(1) {
+		t.Error("expected unflag to persist")
+	}
+	if list := again.List(); len(list) != 1 || list[0].StatusCode != 410 {
+		t.Errorf("unexpected list: %+v", list)
+	}
+}
@@ -0,0 +1,169 @@
+package fittings
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// TypeResolver maps type names to IDs and back.
+type TypeResolver interface {
+	TypeIDs(ctx context.Context, names []string) (map[string]int64, error)
+	TypeNames(ctx context.Context, ids []int64) (map[int64]string, error)
+}
+
+// FittingCreator is the part of esi.EsiService that saves fittings.
+type FittingCreator interface {
+	CreateFitting(ctx context.Context, characterID model.CharacterID, fit model.Fitting, token *oauth2.Token) (int64, error)
+}
+
+// flagPrefixes maps module slots to ESI fitting flag prefixes.
+var flagPrefixes = map[Slot]string{
+	SlotLow:       "LoSlot",
+	SlotMid:       "MedSlot",
+	SlotHigh:      "HiSlot",
+	SlotRig:       "RigSlot",
+	SlotSubsystem: "SubSystemSlot",
+}
+
+// ToFitting resolves the names in f and builds an ESI Fitting. Modules get
+// numbered slot flags in fit order. ESI fittings cannot hold loaded charges,
+// so charges are added to the cargo instead.
+func ToFitting(ctx context.Context, r TypeResolver, f *EFT) (*model.Fitting, error) {
+	names := []string{f.Ship}
+	for _, it := range f.Items {
+		names = append(names, it.Name)
+		if it.Charge != "" {
+			names = append(names, it.Charge)
+		}
+	}
+	ids, err := r.TypeIDs(ctx, names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve type names: %w", err)
+	}
+	lookup := func(name string) (int64, error) {
+		id, ok := ids[name]
+		if !ok {
+			return 0, fmt.Errorf("unknown type %q", name)
+		}
+		return id, nil
+	}
+
+	ship, err := lookup(f.Ship)
+	if err != nil {
+		return nil, err
+	}
+	out := &model.Fitting{Name: f.Name, ShipTypeID: ship}
+	next := make(map[Slot]int)
+	charges := make(map[int64]int64)
+	var chargeOrder []int64
+	for _, it := range f.Items {
+		typeID, err := lookup(it.Name)
+		if err != nil {
+			return nil, err
+		}
+		var flag string
+		switch it.Slot {
+		case SlotDrone:
+			flag = "DroneBay"
+		case SlotCargo:
+			flag = "Cargo"
+		default:
+			flag = flagPrefixes[it.Slot] + strconv.Itoa(next[it.Slot])
+			next[it.Slot]++
+		}
+		out.Items = append(out.Items, model.FittingItem{TypeID: typeID, Flag: flag, Quantity: it.Quantity})
+		if it.Charge != "" {
+			chargeID, err := lookup(it.Charge)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := charges[chargeID]; !ok {
+				chargeOrder = append(chargeOrder, chargeID)
+			}
+			charges[chargeID]++
+		}
+	}
+	for _, id := range chargeOrder {
+		out.Items = append(out.Items, model.FittingItem{TypeID: id, Flag: "Cargo", Quantity: charges[id]})
+	}
+	return out, nil
+}
+
+// FromFitting resolves the type IDs in fit and builds an EFT fit, ordering
+// modules by slot number. Unknown flags are treated as cargo.
+func FromFitting(ctx context.Context, r TypeResolver, fit model.Fitting) (*EFT, error) {
+	ids := []int64{fit.ShipTypeID}
+	for _, it := range fit.Items {
+		ids = append(ids, it.TypeID)
+	}
+	names, err := r.TypeNames(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve type IDs: %w", err)
+	}
+	name := func(id int64) string {
+		if n, ok := names[id]; ok {
+			return n
+		}
+		return fmt.Sprintf("Unknown type %d", id)
+	}
+
+	items := append([]model.FittingItem(nil), fit.Items...)
+	sort.SliceStable(items, func(i, j int) bool {
+		si, ni := slotForFlag(items[i].Flag)
+		sj, nj := slotForFlag(items[j].Flag)
+		if si != sj {
+			return slotRank(si) < slotRank(sj)
+		}
+		return ni < nj
+	})
+	out := &EFT{Ship: name(fit.ShipTypeID), Name: fit.Name}
+	for _, it := range items {
+		slot, _ := slotForFlag(it.Flag)
+		out.Items = append(out.Items, Item{Name: name(it.TypeID), Quantity: it.Quantity, Slot: slot})
+	}
+	return out, nil
+}
+
+// Push converts f and saves it to a character's fittings, returning the new
+// fitting ID.
+func Push(ctx context.Context, r TypeResolver, c FittingCreator, characterID model.CharacterID, f *EFT, token *oauth2.Token) (int64, error) {
+	fit, err := ToFitting(ctx, r, f)
+	if err != nil {
+		return 0, err
+	}
+	return c.CreateFitting(ctx, characterID, *fit, token)
+}
+
+func slotForFlag(flag string) (Slot, int) {
+	switch flag {
+	case "DroneBay", "FighterBay":
+		return SlotDrone, 0
+	}
+	for slot, prefix := range flagPrefixes {
+		if n, ok := strings.CutPrefix(flag, prefix); ok {
+			if i, err := strconv.Atoi(n); err == nil {
+				return slot, i
+			}
+		}
+	}
+	return SlotCargo, 0
+}
+
+func slotRank(s Slot) int {
+	for i, m := range moduleSlots {
+		if m == s {
+			return i
+		}
+	}
+	if s == SlotDrone {
+		return len(moduleSlots)
+	}
+	return len(moduleSlots) + 1
+}
@@ -0,0 +1,4 @@
+// Package fittings parses and writes EFT-format fits, converts them to and
+// from the ESI Fitting model and saves them to a character's fittings. It
+// can also rebuild the fit a killmail victim was flying.
+package fittings
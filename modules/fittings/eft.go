@@ -0,0 +1,182 @@
+package fittings
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Slot is a section of an EFT fit.
+type Slot string
+
+const (
+	SlotLow       Slot = "low"
+	SlotMid       Slot = "mid"
+	SlotHigh      Slot = "high"
+	SlotRig       Slot = "rig"
+	SlotSubsystem Slot = "subsystem"
+	SlotDrone     Slot = "drone"
+	SlotCargo     Slot = "cargo"
+)
+
+// moduleSlots is the order EFT lists module sections in.
+var moduleSlots = []Slot{SlotLow, SlotMid, SlotHigh, SlotRig, SlotSubsystem}
+
+// emptyPlaceholders are written for empty module sections so that section
+// positions survive a round trip.
+var emptyPlaceholders = map[Slot]string{
+	SlotLow:  "[Empty Low slot]",
+	SlotMid:  "[Empty Med slot]",
+	SlotHigh: "[Empty High slot]",
+	SlotRig:  "[Empty Rig slot]",
+}
+
+// ErrInvalidEFT is returned when text is not an EFT fit.
+var ErrInvalidEFT = errors.New("invalid EFT fit")
+
+// Item is one line of an EFT fit.
+type Item struct {
+	Name     string `json:"name"`
+	Charge   string `json:"charge,omitempty"` // loaded charge, modules only
+	Quantity int64  `json:"quantity"`
+	Slot     Slot   `json:"slot"`
+}
+
+// EFT is a fit in EFT text form, with types referenced by name.
+type EFT struct {
+	Ship  string `json:"ship"`
+	Name  string `json:"name"`
+	Items []Item `json:"items"`
+}
+
+// BySlot returns the items in slot, in fit order.
+func (f *EFT) BySlot(slot Slot) []Item {
+	var out []Item
+	for _, it := range f.Items {
+		if it.Slot == slot {
+			out = append(out, it)
+		}
+	}
+	return out
+}
+
+var (
+	headerRe   = regexp.MustCompile(`^\[([^,\]]+),\s*(.*)\]$`)
+	quantityRe = regexp.MustCompile(`^(.+?)\s+x(\d+)$`)
+	emptyRe    = regexp.MustCompile(`^\[Empty .+\]$`)
+)
+
+// ParseEFT parses EFT text. Module sections are read in low, mid, high, rig,
+// subsystem order; sections whose lines carry an "xN" quantity are drones
+// (the first such section) and cargo (any later one).
+func ParseEFT(text string) (*EFT, error) {
+	sc := bufio.NewScanner(strings.NewReader(text))
+	var fit *EFT
+	section, stacked := 0, 0
+	inSection, sectionStacked := false, false
+
+	closeSection := func() {
+		if !inSection {
+			return
+		}
+		if sectionStacked {
+			stacked++
+		} else {
+			section++
+		}
+		inSection = false
+	}
+
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if fit == nil {
+			if line == "" {
+				continue
+			}
+			m := headerRe.FindStringSubmatch(line)
+			if m == nil {
+				return nil, fmt.Errorf("%w: missing [Ship, Name] header", ErrInvalidEFT)
+			}
+			fit = &EFT{Ship: strings.TrimSpace(m[1]), Name: strings.TrimSpace(m[2])}
+			continue
+		}
+		if line == "" {
+			closeSection()
+			continue
+		}
+
+		item := Item{Name: line, Quantity: 1}
+		qty := quantityRe.FindStringSubmatch(line)
+		if !inSection {
+			inSection, sectionStacked = true, qty != nil
+		}
+		if emptyRe.MatchString(line) {
+			continue
+		}
+		if qty != nil {
+			n, err := strconv.ParseInt(qty[2], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: bad quantity in %q", ErrInvalidEFT, line)
+			}
+			item.Name, item.Quantity = qty[1], n
+		} else if name, charge, ok := strings.Cut(line, ","); ok {
+			item.Name, item.Charge = strings.TrimSpace(name), strings.TrimSpace(charge)
+		}
+
+		switch {
+		case sectionStacked && stacked == 0:
+			item.Slot = SlotDrone
+		case sectionStacked || section >= len(moduleSlots):
+			item.Slot = SlotCargo
+		default:
+			item.Slot = moduleSlots[section]
+		}
+		fit.Items = append(fit.Items, item)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if fit == nil {
+		return nil, fmt.Errorf("%w: empty text", ErrInvalidEFT)
+	}
+	return fit, nil
+}
+
+// String writes the fit as EFT text. Low, mid, high and rig sections are
+// always written, with an "[Empty ... slot]" line when they hold nothing;
+// subsystem, drone and cargo sections only when non-empty.
+func (f *EFT) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s, %s]\n", f.Ship, f.Name)
+	for _, slot := range moduleSlots {
+		items := f.BySlot(slot)
+		if len(items) == 0 {
+			if slot == SlotSubsystem {
+				continue
+			}
+			b.WriteString(emptyPlaceholders[slot] + "\n")
+		}
+		for _, it := range items {
+			b.WriteString(it.Name)
+			if it.Charge != "" {
+				b.WriteString(", " + it.Charge)
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+	for _, slot := range []Slot{SlotDrone, SlotCargo} {
+		items := f.BySlot(slot)
+		if len(items) == 0 {
+			continue
+		}
+		b.WriteString("\n")
+		for _, it := range items {
+			fmt.Fprintf(&b, "%s x%d\n", it.Name, it.Quantity)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
@@ -0,0 +1,178 @@
+package fittings_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/fittings"
+)
+
+const rifterEFT = `[Rifter, Tackle]
+Damage Control II
+Gyrostabilizer II
+
+5MN Microwarpdrive II
+Warp Scrambler II
+
+200mm AutoCannon II, Republic Fleet EMP S
+200mm AutoCannon II, Republic Fleet EMP S
+[Empty High slot]
+
+[Empty Rig slot]
+
+
+Warrior II x2
+
+Nanite Repair Paste x10
+`
+
+var typeNames = map[int64]string{
+	587: "Rifter", 2048: "Damage Control II", 519: "Gyrostabilizer II",
+	12076: "5MN Microwarpdrive II", 448: "Warp Scrambler II",
+	2873: "200mm AutoCannon II", 21894: "Republic Fleet EMP S",
+	2488: "Warrior II", 28668: "Nanite Repair Paste",
+}
+
+type resolver struct{}
+
+func (resolver) TypeIDs(ctx context.Context, names []string) (map[string]int64, error) {
+	out := make(map[string]int64)
+	for id, n := range typeNames {
+		out[n] = id
+	}
+	return out, nil
+}
+
+func (resolver) TypeNames(ctx context.Context, ids []int64) (map[int64]string, error) {
+	return typeNames, nil
+}
+
+type creator struct{ got model.Fitting }
+
+func (c *creator) CreateFitting(ctx context.Context, characterID model.CharacterID, fit model.Fitting, token *oauth2.Token) (int64, error) {
+	c.got = fit
+	return 42, nil
+}
+
+func TestParseEFT(t *testing.T) {
+	fit, err := fittings.ParseEFT(rifterEFT)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fit.Ship != "Rifter" || fit.Name != "Tackle" {
+		t.Errorf("unexpected header: %q %q", fit.Ship, fit.Name)
+	}
+	counts := map[fittings.Slot]int{}
+	for _, it := range fit.Items {
+		counts[it.Slot]++
+	}
+	want := map[fittings.Slot]int{fittings.SlotLow: 2, fittings.SlotMid: 2, fittings.SlotHigh: 2, fittings.SlotDrone: 1, fittings.SlotCargo: 1}
+	for slot, n := range want {
+		if counts[slot] != n {
+			t.Errorf("slot %s: expected %d items, got %d", slot, n, counts[slot])
+		}
+	}
+	high := fit.BySlot(fittings.SlotHigh)
+	if high[0].Charge != "Republic Fleet EMP S" {
+		t.Errorf("expected loaded charge, got %+v", high[0])
+	}
+	if drones := fit.BySlot(fittings.SlotDrone); drones[0].Quantity != 2 || drones[0].Name != "Warrior II" {
+		t.Errorf("unexpected drones: %+v", drones)
+	}
+
+	again, err := fittings.ParseEFT(fit.String())
+	if err != nil {
+		t.Fatalf("round trip failed: %v", err)
+	}
+	if again.String() != fit.String() {
+		t.Errorf("round trip mismatch:\n%s\n---\n%s", fit.String(), again.String())
+	}
+
+	if _, err := fittings.ParseEFT("Rifter"); !errors.Is(err, fittings.ErrInvalidEFT) {
+		t.Errorf("expected ErrInvalidEFT, got %v", err)
+	}
+}
+
+func TestFittingConversion(t *testing.T) {
+	ctx := context.Background()
+	eft, _ := fittings.ParseEFT(rifterEFT)
+	c := &creator{}
+	id, err := fittings.Push(ctx, resolver{}, c, 90000001, eft, nil)
+	if err != nil || id != 42 {
+		t.Fatalf("unexpected push result %d, %v", id, err)
+	}
+	flags := map[string]int64{}
+	for _, it := range c.got.Items {
+		flags[it.Flag] += it.Quantity
+	}
+	if c.got.ShipTypeID != 587 || flags["HiSlot1"] != 1 || flags["LoSlot1"] != 1 || flags["DroneBay"] != 2 || flags["Cargo"] != 12 {
+		t.Errorf("unexpected fitting: %+v", c.got)
+	}
+
+	back, err := fittings.FromFitting(ctx, resolver{}, c.got)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if low := back.BySlot(fittings.SlotLow); len(low) != 2 || low[0].Name != "Damage Control II" {
+		t.Errorf("unexpected low slots: %+v", low)
+	}
+	if cargo := back.BySlot(fittings.SlotCargo); len(cargo) != 2 {
+		t.Errorf("expected paste and charges in cargo, got %+v", cargo)
+	}
+}
+
+func TestFromKillmail(t *testing.T) {
+	victim := model.Victim{
+		ShipTypeID: 587,
+		Items: []model.VictimItem{
+			{Flag: 27, ItemTypeID: 21894, QuantityDropped: 100},
+			{Flag: 27, ItemTypeID: 2873, QuantityDestroyed: 1},
+			{Flag: 28, ItemTypeID: 2873, QuantityDropped: 1},
+			{Flag: 19, ItemTypeID: 12076, QuantityDestroyed: 1},
+			{Flag: 11, ItemTypeID: 2048, QuantityDestroyed: 1},
+			{Flag: 87, ItemTypeID: 2488, QuantityDestroyed: 1},
+			{Flag: 87, ItemTypeID: 2488, QuantityDropped: 1},
+			{Flag: 5, ItemTypeID: 28668, QuantityDropped: 10},
+			{Flag: 89, ItemTypeID: 519, QuantityDestroyed: 1},
+		},
+	}
+	fit, err := fittings.FromKillmail(context.Background(), resolver{}, victim, "Loss", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	high := fit.BySlot(fittings.SlotHigh)
+	if len(high) != 2 || high[0].Name != "200mm AutoCannon II" || high[0].Charge != "Republic Fleet EMP S" || high[1].Charge != "" {
+		t.Errorf("unexpected high slots: %+v", high)
+	}
+	if drones := fit.BySlot(fittings.SlotDrone); len(drones) != 1 || drones[0].Quantity != 2 {
+		t.Errorf("expected dropped and destroyed drones merged, got %+v", drones)
+	}
+	want := `[Rifter, Loss]
+Damage Control II
+
+5MN Microwarpdrive II
+
+200mm AutoCannon II, Republic Fleet EMP S
+200mm AutoCannon II
+
+[Empty Rig slot]
+
+
+Warrior II x2
+
+Nanite Repair Paste x10
+`
+	if got := fit.String(); got != want {
+		t.Errorf("unexpected EFT:\n%s", got)
+	}
+
+	isCharge := func(id int64) bool { return id == 2873 }
+	fit, _ = fittings.FromKillmail(context.Background(), resolver{}, victim, "Loss", isCharge)
+	if high := fit.BySlot(fittings.SlotHigh); high[0].Name != "Republic Fleet EMP S" {
+		t.Errorf("expected isCharge to decide the module, got %+v", high)
+	}
+}
@@ -0,0 +1,130 @@
+package fittings
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/killmail"
+)
+
+// ChargeFunc reports whether typeID is a charge (ammunition, crystals,
+// scripts) rather than a module.
+type ChargeFunc func(typeID model.TypeID) bool
+
+// killmailSlots maps killmail slot groups to EFT sections. Implants and
+// unknown flags have no EFT section and are left out.
+var killmailSlots = map[killmail.Slot]Slot{
+	killmail.SlotLow:       SlotLow,
+	killmail.SlotMid:       SlotMid,
+	killmail.SlotHigh:      SlotHigh,
+	killmail.SlotRig:       SlotRig,
+	killmail.SlotSubsystem: SlotSubsystem,
+	killmail.SlotDroneBay:  SlotDrone,
+	killmail.SlotFighter:   SlotDrone,
+	killmail.SlotCargo:     SlotCargo,
+}
+
+// FromKillmail rebuilds the fit a killmail victim was flying, naming it name.
+// Modules keep their slot order; quantities count both dropped and destroyed
+// items, and container contents are listed as cargo.
+//
+// A fitted slot can hold a module and its loaded charge under the same flag.
+// isCharge tells them apart; when it is nil, the type with the larger
+// quantity is taken to be the charge, which misreads single crystals and
+// scripts.
+func FromKillmail(ctx context.Context, r TypeResolver, victim model.Victim, name string, isCharge ChargeFunc) (*EFT, error) {
+	var (
+		stacks []*stack
+		index  = make(map[stack]*stack)
+	)
+	add := func(flag int, slot Slot, typeID model.TypeID, qty int64) {
+		if slot == SlotDrone || slot == SlotCargo {
+			flag = 0 // bays and holds are listed per type
+		}
+		key := stack{flag: flag, slot: slot, typeID: typeID}
+		if s, ok := index[key]; ok {
+			s.qty += qty
+			return
+		}
+		s := &stack{flag: flag, slot: slot, typeID: typeID, qty: qty}
+		index[key] = s
+		stacks = append(stacks, s)
+	}
+	var walk func(items []model.VictimItem, nested bool)
+	walk = func(items []model.VictimItem, nested bool) {
+		for _, it := range items {
+			slot, ok := killmailSlots[killmail.SlotForFlag(it.Flag)]
+			if nested {
+				slot, ok = SlotCargo, true
+			}
+			if ok {
+				add(it.Flag, slot, it.ItemTypeID, it.QuantityDestroyed+it.QuantityDropped)
+			}
+			walk(it.Items, true)
+		}
+	}
+	walk(victim.Items, false)
+
+	ids := []int64{victim.ShipTypeID}
+	for _, s := range stacks {
+		ids = append(ids, s.typeID)
+	}
+	names, err := r.TypeNames(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve type IDs: %w", err)
+	}
+	typeName := func(id int64) string {
+		if n, ok := names[id]; ok {
+			return n
+		}
+		return fmt.Sprintf("Unknown type %d", id)
+	}
+
+	sort.SliceStable(stacks, func(i, j int) bool {
+		if stacks[i].slot != stacks[j].slot {
+			return slotRank(stacks[i].slot) < slotRank(stacks[j].slot)
+		}
+		return stacks[i].flag < stacks[j].flag
+	})
+	out := &EFT{Ship: typeName(victim.ShipTypeID), Name: name}
+	for i := 0; i < len(stacks); i++ {
+		s := stacks[i]
+		if s.slot == SlotDrone || s.slot == SlotCargo {
+			out.Items = append(out.Items, Item{Name: typeName(s.typeID), Quantity: s.qty, Slot: s.slot})
+			continue
+		}
+		module, charge := s, (*stack)(nil)
+		if i+1 < len(stacks) && stacks[i+1].slot == s.slot && stacks[i+1].flag == s.flag {
+			charge = stacks[i+1]
+			if s.isChargeOf(charge, isCharge) {
+				module, charge = charge, s
+			}
+			i++
+		}
+		item := Item{Name: typeName(module.typeID), Quantity: 1, Slot: s.slot}
+		if charge != nil {
+			item.Charge = typeName(charge.typeID)
+		}
+		out.Items = append(out.Items, item)
+	}
+	return out, nil
+}
+
+// stack is one item type under one flag, with quantities summed.
+type stack struct {
+	flag   int
+	slot   Slot
+	typeID int64
+	qty    int64
+}
+
+// isChargeOf reports whether s, rather than module, is the charge of a slot
+// holding both.
+func (s *stack) isChargeOf(module *stack, isCharge ChargeFunc) bool {
+	if isCharge != nil {
+		return isCharge(s.typeID) && !isCharge(module.typeID)
+	}
+	return s.qty > module.qty
+}
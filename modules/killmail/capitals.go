@@ -0,0 +1,65 @@
+package killmail
+
+import (
+	"context"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/notify"
+)
+
+// SDE inventory groups of capital ships.
+const (
+	GroupTitan             = 30
+	GroupDreadnought       = 485
+	GroupCarrier           = 547
+	GroupSupercarrier      = 659
+	GroupCapitalIndustrial = 883
+	GroupForceAuxiliary    = 1538
+	GroupLancerDreadnought = 4594
+)
+
+// SupercapitalGroups are titans and supercarriers.
+var SupercapitalGroups = []int{GroupTitan, GroupSupercarrier}
+
+// CapitalGroups are every capital hull, supercapitals included.
+var CapitalGroups = []int{
+	GroupTitan, GroupDreadnought, GroupCarrier, GroupSupercarrier,
+	GroupCapitalIndustrial, GroupForceAuxiliary, GroupLancerDreadnought,
+}
+
+// ByAttackerShipGroup matches kills with at least one attacker flying a ship
+// of groupIDs.
+func ByAttackerShipGroup(lookup GroupLookup, groupIDs ...int) Filter {
+	want := intSet(groupIDs)
+	return func(km *model.FlattenedKillMail) bool {
+		for _, a := range km.Attackers {
+			if g, ok := lookup(a.ShipTypeID); ok && want[g] {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// CapitalKills matches kills where a capital died or was on the attackers.
+func CapitalKills(lookup GroupLookup) Filter {
+	return Or(ByShipGroup(lookup, CapitalGroups...), ByAttackerShipGroup(lookup, CapitalGroups...))
+}
+
+// SupercapitalKills matches kills where a titan or supercarrier died or was
+// on the attackers.
+func SupercapitalKills(lookup GroupLookup) Filter {
+	return Or(ByShipGroup(lookup, SupercapitalGroups...), ByAttackerShipGroup(lookup, SupercapitalGroups...))
+}
+
+// Alert sends an EventKillmail to n for every killmail from in matching f,
+// e.g. Alert(ctx, feed, SupercapitalKills(lookup), discord, log) for "super
+// down" pings. It blocks until in is closed or ctx is done; delivery errors
+// go to onError, if non-nil.
+func Alert(ctx context.Context, in <-chan model.FlattenedKillMail, f Filter, n notify.Notifier, onError func(error)) {
+	for km := range Stream(ctx, in, f) {
+		if err := n.Notify(ctx, notify.KillmailEvent(km)); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package killmail_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/killmail"
+	"github.com/guarzo/eveapi/modules/notify"
+)
+
+func TestAlert_CapitalKills(t *testing.T) {
+	groups := map[model.TypeID]int{587: 25, 23757: killmail.GroupCarrier, 671: killmail.GroupTitan}
+	lookup := func(typeID model.TypeID) (int, bool) {
+		g, ok := groups[typeID]
+		return g, ok
+	}
+	kills := []model.FlattenedKillMail{
+		{KillMailID: 1, Victim: model.Victim{ShipTypeID: 587}, Attackers: []model.Attacker{{ShipTypeID: 587}}},
+		{KillMailID: 2, Victim: model.Victim{ShipTypeID: 23757}},
+		{KillMailID: 3, Victim: model.Victim{ShipTypeID: 587}, Attackers: []model.Attacker{{ShipTypeID: 587}, {ShipTypeID: 671}}},
+	}
+	if out := killmail.Apply(kills, killmail.SupercapitalKills(lookup)); len(out) != 1 || out[0].KillMailID != 3 {
+		t.Errorf("expected only the titan kill, got %#v", out)
+	}
+
+	in := make(chan model.FlattenedKillMail)
+	go func() {
+		defer close(in)
+		for _, km := range kills {
+			in <- km
+		}
+	}()
+	var got []int64
+	n := notify.NotifierFunc(func(ctx context.Context, ev notify.Event) error {
+		got = append(got, ev.Payload.(model.FlattenedKillMail).KillMailID)
+		return nil
+	})
+	killmail.Alert(context.Background(), in, killmail.CapitalKills(lookup), n, nil)
+	if len(got) != 2 || got[0] != 2 || got[1] != 3 {
+		t.Errorf("expected capital kills 2 and 3, got %v", got)
+	}
+}
@@ -0,0 +1,108 @@
+package killmail
+
+import (
+	"encoding/json"
+	"html/template"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// Class is what kind of opponent a kill was against.
+type Class string
+
+const (
+	// ClassPlayer kills have at least one player attacker.
+	ClassPlayer Class = "player"
+	// ClassNPC kills have only NPC attackers.
+	ClassNPC Class = "npc"
+	// ClassAbyssal kills happened in Abyssal Deadspace.
+	ClassAbyssal Class = "abyssal"
+	// ClassTrigEdencom kills have only NPC attackers, at least one of them
+	// Triglavian or EDENCOM.
+	ClassTrigEdencom Class = "trig_edencom"
+)
+
+// classOrder is the order ClassChartEntry lists classes in.
+var classOrder = []Class{ClassPlayer, ClassNPC, ClassTrigEdencom, ClassAbyssal}
+
+// NPC factions fighting in the Triglavian invasion.
+const (
+	FactionTriglavian int64 = 500026
+	FactionEDENCOM    int64 = 500027
+)
+
+// IsAbyssalSystem reports whether id is an Abyssal Deadspace pocket; they are
+// numbered from 32000000.
+func IsAbyssalSystem(id model.SystemID) bool {
+	return id >= 32000000 && id < 33000000
+}
+
+// Classify tags a kill. Abyssal kills are abyssal whoever the attackers were;
+// elsewhere, any player attacker makes it a player kill. zKill's NPC flag
+// counts as no player attackers for kills not yet hydrated from ESI.
+func Classify(km *model.FlattenedKillMail) Class {
+	if IsAbyssalSystem(km.SolarSystemID) {
+		return ClassAbyssal
+	}
+	trig := false
+	for _, a := range km.Attackers {
+		if a.CharacterID != 0 {
+			return ClassPlayer
+		}
+		if a.FactionID == FactionTriglavian || a.FactionID == FactionEDENCOM {
+			trig = true
+		}
+	}
+	switch {
+	case trig:
+		return ClassTrigEdencom
+	case len(km.Attackers) > 0 || km.NPC:
+		return ClassNPC
+	}
+	return ClassPlayer
+}
+
+// ByClass matches kills of one of classes.
+func ByClass(classes ...Class) Filter {
+	want := make(map[Class]bool, len(classes))
+	for _, c := range classes {
+		want[c] = true
+	}
+	return func(km *model.FlattenedKillMail) bool {
+		return want[Classify(km)]
+	}
+}
+
+// CountByClass counts kills per class.
+func CountByClass(kills []model.FlattenedKillMail) map[Class]int {
+	out := make(map[Class]int)
+	for i := range kills {
+		out[Classify(&kills[i])]++
+	}
+	return out
+}
+
+// ClassChartEntry renders the class counts of kills as a pie ChartEntry,
+// leaving out classes without kills.
+func ClassChartEntry(name, id string, kills []model.FlattenedKillMail) (model.ChartEntry, error) {
+	counts := CountByClass(kills)
+	chart := leaderboardChart{Datasets: []leaderboardData{{Label: "Kills"}}}
+	for _, c := range classOrder {
+		if counts[c] == 0 {
+			continue
+		}
+		chart.Labels = append(chart.Labels, string(c))
+		chart.Datasets[0].Data = append(chart.Datasets[0].Data, int64(counts[c]))
+	}
+
+	data, err := json.Marshal(chart)
+	if err != nil {
+		return model.ChartEntry{}, err
+	}
+	return model.ChartEntry{
+		Name: name,
+		ID:   id,
+		Data: template.JS(data),
+		Type: "pie",
+	}, nil
+}
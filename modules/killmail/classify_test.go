@@ -0,0 +1,49 @@
+package killmail_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/killmail"
+)
+
+func TestClassify(t *testing.T) {
+	kills := []model.FlattenedKillMail{
+		{KillMailID: 1, Attackers: []model.Attacker{{CorporationID: 1000125}, {CharacterID: 9}}},
+		{KillMailID: 2, Attackers: []model.Attacker{{CorporationID: 1000125}}},
+		{KillMailID: 3, Attackers: []model.Attacker{{FactionID: killmail.FactionTriglavian}}},
+		{KillMailID: 4, SolarSystemID: 32000083, Attackers: []model.Attacker{{CharacterID: 9}}},
+		{KillMailID: 5, NPC: true},
+		{KillMailID: 6},
+	}
+	want := []killmail.Class{
+		killmail.ClassPlayer, killmail.ClassNPC, killmail.ClassTrigEdencom,
+		killmail.ClassAbyssal, killmail.ClassNPC, killmail.ClassPlayer,
+	}
+	for i := range kills {
+		if got := killmail.Classify(&kills[i]); got != want[i] {
+			t.Errorf("kill %d: expected %s, got %s", kills[i].KillMailID, want[i], got)
+		}
+	}
+
+	npc := killmail.Apply(kills, killmail.ByClass(killmail.ClassNPC, killmail.ClassTrigEdencom))
+	if len(npc) != 3 {
+		t.Errorf("expected 3 NPC kills, got %d", len(npc))
+	}
+
+	entry, err := killmail.ClassChartEntry("Kill classes", "classes", kills)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var chart struct {
+		Labels   []string
+		Datasets []struct{ Data []int64 }
+	}
+	if err := json.Unmarshal([]byte(entry.Data), &chart); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entry.Type != "pie" || len(chart.Labels) != 4 || chart.Labels[0] != "player" || chart.Datasets[0].Data[0] != 2 {
+		t.Errorf("unexpected chart: %+v", chart)
+	}
+}
@@ -0,0 +1,4 @@
+// Package killmail provides analysis helpers that operate on flattened
+// killmails: valuation, slot breakdowns, classification and similar
+// post-processing.
+package killmail
@@ -0,0 +1,75 @@
+package killmail
+
+import (
+	"context"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// NameResolver resolves IDs to names in bulk; esi.EsiService satisfies it.
+type NameResolver interface {
+	ResolveNames(ctx context.Context, ids []int64) ([]model.UniverseName, error)
+}
+
+// EnrichOption customizes EnrichKillMails.
+type EnrichOption func(*enrichConfig)
+
+type enrichConfig struct {
+	skip func(int64) bool
+}
+
+// SkipIDs leaves IDs for which skip returns true out of the lookup, e.g.
+// failures.Tracker.Skip for IDs ESI is known to reject.
+func SkipIDs(skip func(int64) bool) EnrichOption {
+	return func(c *enrichConfig) {
+		c.skip = skip
+	}
+}
+
+// EnrichKillMails resolves the victim ship, attacker ships/weapons and solar system
+// of every killmail into names with a single batched lookup, storing them on the
+// killmails in place.
+func EnrichKillMails(ctx context.Context, r NameResolver, kills []model.FlattenedKillMail, opts ...EnrichOption) error {
+	var cfg enrichConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	var ids []int64
+	add := func(id int64) {
+		if cfg.skip == nil || !cfg.skip(id) {
+			ids = append(ids, id)
+		}
+	}
+	for _, km := range kills {
+		add(km.Victim.ShipTypeID)
+		add(km.SolarSystemID)
+		for _, a := range km.Attackers {
+			add(a.ShipTypeID)
+			add(a.WeaponTypeID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	resolved, err := r.ResolveNames(ctx, ids)
+	if err != nil {
+		return err
+	}
+	names := make(map[int64]string, len(resolved))
+	for _, n := range resolved {
+		names[n.ID] = n.Name
+	}
+
+	for i := range kills {
+		km := &kills[i]
+		km.VictimShipName = names[km.Victim.ShipTypeID]
+		km.SolarSystemName = names[km.SolarSystemID]
+		for j := range km.Attackers {
+			a := &km.Attackers[j]
+			a.ShipName = names[a.ShipTypeID]
+			a.WeaponName = names[a.WeaponTypeID]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package killmail_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/killmail"
+)
+
+type fakeResolver struct {
+	names map[int64]string
+	calls int
+}
+
+func (f *fakeResolver) ResolveNames(ctx context.Context, ids []int64) ([]model.UniverseName, error) {
+	f.calls++
+	var out []model.UniverseName
+	for _, id := range ids {
+		if name, ok := f.names[id]; ok {
+			out = append(out, model.UniverseName{ID: id, Name: name})
+		}
+	}
+	return out, nil
+}
+
+func TestEnrichKillMails(t *testing.T) {
+	r := &fakeResolver{names: map[int64]string{
+		587:      "Rifter",
+		30000142: "Jita",
+		24690:    "Drake",
+		2873:     "125mm Gatling AutoCannon I",
+	}}
+	kills := []model.FlattenedKillMail{
+		{
+			SolarSystemID: 30000142,
+			Victim:        model.Victim{ShipTypeID: 587},
+			Attackers:     []model.Attacker{{ShipTypeID: 24690, WeaponTypeID: 2873}},
+		},
+		{SolarSystemID: 30000142, Victim: model.Victim{ShipTypeID: 24690}},
+	}
+
+	if err := killmail.EnrichKillMails(context.Background(), r, kills); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.calls != 1 {
+		t.Errorf("expected a single batched lookup, got %d", r.calls)
+	}
+	if kills[0].VictimShipName != "Rifter" || kills[0].SolarSystemName != "Jita" {
+		t.Errorf("unexpected victim/system names: %q %q", kills[0].VictimShipName, kills[0].SolarSystemName)
+	}
+	if a := kills[0].Attackers[0]; a.ShipName != "Drake" || a.WeaponName != "125mm Gatling AutoCannon I" {
+		t.Errorf("unexpected attacker names: %#v", a)
+	}
+	if kills[1].VictimShipName != "Drake" {
+		t.Errorf("unexpected second victim name: %q", kills[1].VictimShipName)
+	}
+}
+
+func TestEnrichKillMails_SkipIDs(t *testing.T) {
+	var seen []int64
+	r := resolverFunc(func(ctx context.Context, ids []int64) ([]model.UniverseName, error) {
+		seen = ids
+		return nil, nil
+	})
+	kills := []model.FlattenedKillMail{{SolarSystemID: 30000142, Victim: model.Victim{ShipTypeID: 587}}}
+	err := killmail.EnrichKillMails(context.Background(), r, kills,
+		killmail.SkipIDs(func(id int64) bool { return id == 587 }))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(seen) != 1 || seen[0] != 30000142 {
+		t.Errorf("expected skipped ID to be left out, got %v", seen)
+	}
+}
+
+type resolverFunc func(ctx context.Context, ids []int64) ([]model.UniverseName, error)
+
+func (f resolverFunc) ResolveNames(ctx context.Context, ids []int64) ([]model.UniverseName, error) {
+	return f(ctx, ids)
+}
@@ -0,0 +1,160 @@
+package killmail
+
+import (
+	"context"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// Filter decides whether a killmail is kept.
+type Filter func(km *model.FlattenedKillMail) bool
+
+// GroupLookup maps a ship type ID to its inventory group ID (e.g. from the SDE
+// or ESI /universe/types/).
+type GroupLookup func(typeID model.TypeID) (groupID int, ok bool)
+
+// Apply returns the killmails matching f, preserving order.
+func Apply(kills []model.FlattenedKillMail, f Filter) []model.FlattenedKillMail {
+	var out []model.FlattenedKillMail
+	for i := range kills {
+		if f(&kills[i]) {
+			out = append(out, kills[i])
+		}
+	}
+	return out
+}
+
+// Stream forwards the killmails from in that match f. The returned channel is
+// closed when in is closed or ctx is done.
+func Stream(ctx context.Context, in <-chan model.FlattenedKillMail, f Filter) <-chan model.FlattenedKillMail {
+	out := make(chan model.FlattenedKillMail)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case km, ok := <-in:
+				if !ok {
+					return
+				}
+				if !f(&km) {
+					continue
+				}
+				select {
+				case out <- km:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
+
+// And matches when every filter matches. An empty And matches everything.
+func And(filters ...Filter) Filter {
+	return func(km *model.FlattenedKillMail) bool {
+		for _, f := range filters {
+			if !f(km) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or matches when any filter matches. An empty Or matches nothing.
+func Or(filters ...Filter) Filter {
+	return func(km *model.FlattenedKillMail) bool {
+		for _, f := range filters {
+			if f(km) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not inverts f.
+func Not(f Filter) Filter {
+	return func(km *model.FlattenedKillMail) bool {
+		return !f(km)
+	}
+}
+
+// ByShipGroup matches kills whose victim ship belongs to one of groupIDs.
+func ByShipGroup(lookup GroupLookup, groupIDs ...int) Filter {
+	want := intSet(groupIDs)
+	return func(km *model.FlattenedKillMail) bool {
+		g, ok := lookup(km.Victim.ShipTypeID)
+		return ok && want[g]
+	}
+}
+
+// ByShipType matches kills whose victim ship is one of typeIDs.
+func ByShipType(typeIDs ...model.TypeID) Filter {
+	want := intSet(typeIDs)
+	return func(km *model.FlattenedKillMail) bool {
+		return want[km.Victim.ShipTypeID]
+	}
+}
+
+// ByMinValue matches kills worth at least isk in total.
+func ByMinValue(isk float64) Filter {
+	return func(km *model.FlattenedKillMail) bool {
+		return km.TotalValue >= isk
+	}
+}
+
+// ByTimeRange matches kills at or after start and before end.
+// A zero start or end leaves that side open.
+func ByTimeRange(start, end time.Time) Filter {
+	return func(km *model.FlattenedKillMail) bool {
+		if !start.IsZero() && km.KillMailTime.Before(start) {
+			return false
+		}
+		if !end.IsZero() && !km.KillMailTime.Before(end) {
+			return false
+		}
+		return true
+	}
+}
+
+// BySystem matches kills in one of the given solar systems.
+func BySystem(systemIDs ...model.SystemID) Filter {
+	want := intSet(systemIDs)
+	return func(km *model.FlattenedKillMail) bool {
+		return want[km.SolarSystemID]
+	}
+}
+
+// ByAttackerCorp matches kills with at least one attacker from corpIDs.
+func ByAttackerCorp(corpIDs ...model.CorporationID) Filter {
+	want := intSet(corpIDs)
+	return func(km *model.FlattenedKillMail) bool {
+		for _, a := range km.Attackers {
+			if want[a.CorporationID] {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// ByVictimCorp matches kills where the victim belongs to one of corpIDs.
+func ByVictimCorp(corpIDs ...model.CorporationID) Filter {
+	want := intSet(corpIDs)
+	return func(km *model.FlattenedKillMail) bool {
+		return want[km.Victim.CorporationID]
+	}
+}
+
+func intSet[T int | int64](ids []T) map[T]bool {
+	m := make(map[T]bool, len(ids))
+	for _, id := range ids {
+		m[id] = true
+	}
+	return m
+}
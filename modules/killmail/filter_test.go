@@ -0,0 +1,64 @@
+package killmail_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/killmail"
+)
+
+func sampleKills() []model.FlattenedKillMail {
+	t0 := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	return []model.FlattenedKillMail{
+		{KillMailID: 1, KillMailTime: t0, SolarSystemID: 10, TotalValue: 5e6, Victim: model.Victim{ShipTypeID: 587},
+			Attackers: []model.Attacker{{CorporationID: 100}}},
+		{KillMailID: 2, KillMailTime: t0.Add(24 * time.Hour), SolarSystemID: 20, TotalValue: 2e9, Victim: model.Victim{ShipTypeID: 23757},
+			Attackers: []model.Attacker{{CorporationID: 200}}},
+		{KillMailID: 3, KillMailTime: t0.Add(48 * time.Hour), SolarSystemID: 10, TotalValue: 9e8, Victim: model.Victim{ShipTypeID: 24690},
+			Attackers: []model.Attacker{{CorporationID: 300}, {CorporationID: 100}}},
+	}
+}
+
+func TestFilters_Compose(t *testing.T) {
+	groups := map[model.TypeID]int{587: 25, 23757: 547, 24690: 419}
+	lookup := func(typeID model.TypeID) (int, bool) {
+		g, ok := groups[typeID]
+		return g, ok
+	}
+
+	f := killmail.And(
+		killmail.Or(killmail.BySystem(10), killmail.ByShipGroup(lookup, 547)),
+		killmail.ByMinValue(1e8),
+		killmail.Not(killmail.ByAttackerCorp(200)),
+	)
+	out := killmail.Apply(sampleKills(), f)
+	if len(out) != 1 || out[0].KillMailID != 3 {
+		t.Errorf("expected only kill 3, got %#v", out)
+	}
+
+	start := time.Date(2024, 10, 2, 0, 0, 0, 0, time.UTC)
+	out = killmail.Apply(sampleKills(), killmail.ByTimeRange(start, time.Time{}))
+	if len(out) != 2 {
+		t.Errorf("expected 2 kills after start, got %d", len(out))
+	}
+}
+
+func TestStream(t *testing.T) {
+	in := make(chan model.FlattenedKillMail)
+	go func() {
+		defer close(in)
+		for _, km := range sampleKills() {
+			in <- km
+		}
+	}()
+
+	var ids []int64
+	for km := range killmail.Stream(context.Background(), in, killmail.ByAttackerCorp(100)) {
+		ids = append(ids, km.KillMailID)
+	}
+	if len(ids) != 2 || ids[0] != 1 || ids[1] != 3 {
+		t.Errorf("unexpected streamed ids: %v", ids)
+	}
+}
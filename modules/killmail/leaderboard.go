@@ -0,0 +1,152 @@
+package killmail
+
+import (
+	"context"
+	"encoding/json"
+	"html/template"
+	"sort"
+	"strconv"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// LeaderboardMetric selects what a leaderboard ranks on.
+type LeaderboardMetric int
+
+const (
+	ByFinalBlows LeaderboardMetric = iota
+	ByDamage
+)
+
+func (m LeaderboardMetric) String() string {
+	if m == ByDamage {
+		return "Damage Done"
+	}
+	return "Final Blows"
+}
+
+// LeaderboardEntry is one attacker's totals.
+type LeaderboardEntry struct {
+	CharacterID model.CharacterID `json:"character_id"`
+	Name        string            `json:"name"`
+	FinalBlows  int               `json:"final_blows"`
+	DamageDone  int64             `json:"damage_done"`
+	Kills       int               `json:"kills"`
+}
+
+// BuildLeaderboard ranks attacking characters by metric. Ties are broken by the
+// other metric, then kill count, then character ID. A limit <= 0 returns everyone.
+func BuildLeaderboard(kills []model.FlattenedKillMail, metric LeaderboardMetric, limit int) []LeaderboardEntry {
+	byChar := make(map[model.CharacterID]*LeaderboardEntry)
+	for _, km := range kills {
+		seen := make(map[model.CharacterID]bool)
+		for _, a := range km.Attackers {
+			if a.CharacterID == 0 {
+				continue // NPCs and structures
+			}
+			e, ok := byChar[a.CharacterID]
+			if !ok {
+				e = &LeaderboardEntry{CharacterID: a.CharacterID}
+				byChar[a.CharacterID] = e
+			}
+			e.DamageDone += int64(a.DamageDone)
+			if a.FinalBlow {
+				e.FinalBlows++
+			}
+			if !seen[a.CharacterID] {
+				seen[a.CharacterID] = true
+				e.Kills++
+			}
+		}
+	}
+
+	out := make([]LeaderboardEntry, 0, len(byChar))
+	for _, e := range byChar {
+		out = append(out, *e)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		primaryA, primaryB := int64(a.FinalBlows), int64(b.FinalBlows)
+		secondaryA, secondaryB := a.DamageDone, b.DamageDone
+		if metric == ByDamage {
+			primaryA, primaryB, secondaryA, secondaryB = secondaryA, secondaryB, primaryA, primaryB
+		}
+		switch {
+		case primaryA != primaryB:
+			return primaryA > primaryB
+		case secondaryA != secondaryB:
+			return secondaryA > secondaryB
+		case a.Kills != b.Kills:
+			return a.Kills > b.Kills
+		}
+		return a.CharacterID < b.CharacterID
+	})
+
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+// ResolveLeaderboardNames fills the Name of every entry with one batched lookup.
+func ResolveLeaderboardNames(ctx context.Context, r NameResolver, entries []LeaderboardEntry) error {
+	ids := make([]int64, 0, len(entries))
+	for _, e := range entries {
+		ids = append(ids, e.CharacterID)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	resolved, err := r.ResolveNames(ctx, ids)
+	if err != nil {
+		return err
+	}
+	names := make(map[int64]string, len(resolved))
+	for _, n := range resolved {
+		names[n.ID] = n.Name
+	}
+	for i := range entries {
+		entries[i].Name = names[entries[i].CharacterID]
+	}
+	return nil
+}
+
+// leaderboardChart is the labels/datasets JSON shape consumed by the front-end charts.
+type leaderboardChart struct {
+	Labels   []string          `json:"labels"`
+	Datasets []leaderboardData `json:"datasets"`
+}
+
+type leaderboardData struct {
+	Label string  `json:"label"`
+	Data  []int64 `json:"data"`
+}
+
+// LeaderboardChartEntry renders entries as a bar ChartEntry. Unresolved names
+// fall back to the character ID.
+func LeaderboardChartEntry(name, id string, metric LeaderboardMetric, entries []LeaderboardEntry) (model.ChartEntry, error) {
+	chart := leaderboardChart{Datasets: []leaderboardData{{Label: metric.String()}}}
+	for _, e := range entries {
+		label := e.Name
+		if label == "" {
+			label = strconv.FormatInt(e.CharacterID, 10)
+		}
+		chart.Labels = append(chart.Labels, label)
+		value := int64(e.FinalBlows)
+		if metric == ByDamage {
+			value = e.DamageDone
+		}
+		chart.Datasets[0].Data = append(chart.Datasets[0].Data, value)
+	}
+
+	data, err := json.Marshal(chart)
+	if err != nil {
+		return model.ChartEntry{}, err
+	}
+	return model.ChartEntry{
+		Name: name,
+		ID:   id,
+		Data: template.JS(data),
+		Type: "bar",
+	}, nil
+}
@@ -0,0 +1,54 @@
+package killmail_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/killmail"
+)
+
+func TestBuildLeaderboard(t *testing.T) {
+	kills := []model.FlattenedKillMail{
+		{Attackers: []model.Attacker{
+			{CharacterID: 1, DamageDone: 100, FinalBlow: true},
+			{CharacterID: 2, DamageDone: 900},
+			{CharacterID: 0, DamageDone: 5000, FinalBlow: true}, // NPC
+		}},
+		{Attackers: []model.Attacker{
+			{CharacterID: 2, DamageDone: 50, FinalBlow: true},
+			{CharacterID: 3, DamageDone: 10},
+		}},
+	}
+
+	fb := killmail.BuildLeaderboard(kills, killmail.ByFinalBlows, 0)
+	// 1 and 2 both have one final blow; 2 wins the tie on damage
+	if len(fb) != 3 || fb[0].CharacterID != 2 || fb[1].CharacterID != 1 {
+		t.Fatalf("unexpected final-blow ranking: %#v", fb)
+	}
+	if fb[0].Kills != 2 || fb[0].DamageDone != 950 {
+		t.Errorf("unexpected totals for character 2: %#v", fb[0])
+	}
+
+	dmg := killmail.BuildLeaderboard(kills, killmail.ByDamage, 2)
+	if len(dmg) != 2 || dmg[0].CharacterID != 2 || dmg[1].CharacterID != 1 {
+		t.Errorf("unexpected damage ranking: %#v", dmg)
+	}
+
+	r := &fakeResolver{names: map[int64]string{2: "Pilot Two"}}
+	if err := killmail.ResolveLeaderboardNames(context.Background(), r, dmg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entry, err := killmail.LeaderboardChartEntry("Top Damage", "topDamage_MTD", killmail.ByDamage, dmg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `{"labels":["Pilot Two","1"],"datasets":[{"label":"Damage Done","data":[950,100]}]}`
+	if string(entry.Data) != want || entry.Type != "bar" {
+		t.Errorf("unexpected chart entry: %s", entry.Data)
+	}
+	if !strings.HasPrefix(entry.ID, "topDamage") {
+		t.Errorf("unexpected chart id: %s", entry.ID)
+	}
+}
@@ -0,0 +1,202 @@
+package killmail
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/notify"
+)
+
+// SeenStore remembers which kills were already handled, so a kill delivered
+// twice (a feed replay, a restart, two feeds) is handled once.
+type SeenStore interface {
+	// MarkSeen records killID and reports whether it was new.
+	MarkSeen(ctx context.Context, killID int64) (bool, error)
+}
+
+// memorySeenStore remembers the most recent kill IDs up to a capacity.
+type memorySeenStore struct {
+	mu    sync.Mutex
+	ids   map[int64]bool
+	order []int64
+	limit int
+}
+
+// NewMemorySeenStore returns a SeenStore remembering the last capacity kills.
+func NewMemorySeenStore(capacity int) SeenStore {
+	return &memorySeenStore{ids: make(map[int64]bool), limit: capacity}
+}
+
+func (s *memorySeenStore) MarkSeen(ctx context.Context, killID int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ids[killID] {
+		return false, nil
+	}
+	s.ids[killID] = true
+	s.order = append(s.order, killID)
+	if s.limit > 0 && len(s.order) > s.limit {
+		delete(s.ids, s.order[0])
+		s.order = s.order[1:]
+	}
+	return true, nil
+}
+
+// WatchList names the entities whose kills and losses are of interest.
+type WatchList struct {
+	Characters   []model.CharacterID
+	Corporations []model.CorporationID
+	Alliances    []model.AllianceID
+}
+
+// ByWatchList matches kills with a watched victim or attacker.
+func ByWatchList(w WatchList) Filter {
+	chars, corps, alliances := intSet(w.Characters), intSet(w.Corporations), intSet(w.Alliances)
+	involved := func(char, corp, alliance int64) bool {
+		return chars[char] || corps[corp] || alliances[alliance]
+	}
+	return func(km *model.FlattenedKillMail) bool {
+		v := km.Victim
+		if involved(v.CharacterID, v.CorporationID, v.AllianceID) {
+			return true
+		}
+		for _, a := range km.Attackers {
+			if involved(a.CharacterID, a.CorporationID, a.AllianceID) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Handler receives each kill a Processor matches.
+type Handler func(ctx context.Context, km model.FlattenedKillMail) error
+
+// ProcessorOption customizes a Processor.
+type ProcessorOption func(*Processor)
+
+// WithMinValue only matches kills worth at least isk.
+func WithMinValue(isk float64) ProcessorOption {
+	return func(p *Processor) {
+		p.filters = append(p.filters, ByMinValue(isk))
+	}
+}
+
+// WithWatchList only matches kills involving a watched entity.
+func WithWatchList(w WatchList) ProcessorOption {
+	return func(p *Processor) {
+		p.filters = append(p.filters, ByWatchList(w))
+	}
+}
+
+// WithFilter adds f to the filters a kill must match.
+func WithFilter(f Filter) ProcessorOption {
+	return func(p *Processor) {
+		p.filters = append(p.filters, f)
+	}
+}
+
+// WithSeenStore sets where handled kills are remembered (the last 10000 in
+// memory by default).
+func WithSeenStore(s SeenStore) ProcessorOption {
+	return func(p *Processor) {
+		p.seen = s
+	}
+}
+
+// WithHandler calls fn for every matched kill.
+func WithHandler(fn Handler) ProcessorOption {
+	return func(p *Processor) {
+		p.handlers = append(p.handlers, fn)
+	}
+}
+
+// WithNotifier sends an EventKillmail to n for every matched kill.
+func WithNotifier(n notify.Notifier) ProcessorOption {
+	return WithHandler(func(ctx context.Context, km model.FlattenedKillMail) error {
+		return n.Notify(ctx, notify.KillmailEvent(km))
+	})
+}
+
+// WithProcessorErrorHandler receives handler and seen-store errors from Run.
+func WithProcessorErrorHandler(fn func(error)) ProcessorOption {
+	return func(p *Processor) {
+		p.onError = fn
+	}
+}
+
+// Processor matches kills from a live feed, such as zkill.RedisQ's Stream,
+// against its filters and hands each new match to its handlers.
+type Processor struct {
+	filters  []Filter
+	seen     SeenStore
+	handlers []Handler
+	onError  func(error)
+	life     common.Lifecycle
+}
+
+// NewProcessor constructs a Processor. Without filters every kill matches.
+func NewProcessor(opts ...ProcessorOption) *Processor {
+	p := &Processor{seen: NewMemorySeenStore(10000)}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Process handles one kill and reports whether it matched and was new.
+// Every handler runs even if one fails; their errors are joined.
+func (p *Processor) Process(ctx context.Context, km model.FlattenedKillMail) (bool, error) {
+	if !And(p.filters...)(&km) {
+		return false, nil
+	}
+	fresh, err := p.seen.MarkSeen(ctx, km.KillMailID)
+	if err != nil || !fresh {
+		return false, err
+	}
+	var errs []error
+	for _, h := range p.handlers {
+		if err := h(ctx, km); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return true, errors.Join(errs...)
+}
+
+// Run processes kills from in until it is closed, ctx is done or the
+// processor is closed.
+func (p *Processor) Run(ctx context.Context, in <-chan model.FlattenedKillMail) {
+	ctx, stop, done := p.life.Begin(ctx)
+	defer done()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-stop:
+			return
+		case km, ok := <-in:
+			if !ok {
+				return
+			}
+			if _, err := p.Process(ctx, km); err != nil && p.onError != nil {
+				p.onError(err)
+			}
+		}
+	}
+}
+
+// Close stops Run once the kill in flight has been handled and waits for it
+// to return. To drain a feed, close the feed first: Run returns by itself
+// when the feed's channel closes.
+func (p *Processor) Close() error {
+	return p.life.Close()
+}
+
+// Shutdown is Close, except that the handlers in flight are cancelled when
+// ctx is done before they finish.
+func (p *Processor) Shutdown(ctx context.Context) error {
+	return p.life.Shutdown(ctx)
+}
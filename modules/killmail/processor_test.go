@@ -0,0 +1,50 @@
+package killmail_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/killmail"
+)
+
+func TestProcessor(t *testing.T) {
+	var got []int64
+	p := killmail.NewProcessor(
+		killmail.WithMinValue(1e8),
+		killmail.WithWatchList(killmail.WatchList{Corporations: []model.CorporationID{100}}),
+		killmail.WithHandler(func(ctx context.Context, km model.FlattenedKillMail) error {
+			got = append(got, km.KillMailID)
+			return nil
+		}),
+	)
+
+	in := make(chan model.FlattenedKillMail)
+	go func() {
+		defer close(in)
+		kills := append(sampleKills(), sampleKills()[2]) // kill 3 delivered twice
+		for _, km := range kills {
+			in <- km
+		}
+	}()
+	p.Run(context.Background(), in)
+	if len(got) != 1 || got[0] != 3 {
+		t.Errorf("expected kill 3 once, got %v", got)
+	}
+}
+
+func TestMemorySeenStore_Capacity(t *testing.T) {
+	ctx := context.Background()
+	s := killmail.NewMemorySeenStore(2)
+	for _, id := range []int64{1, 2, 3} {
+		if fresh, _ := s.MarkSeen(ctx, id); !fresh {
+			t.Errorf("expected %d to be new", id)
+		}
+	}
+	if fresh, _ := s.MarkSeen(ctx, 3); fresh {
+		t.Error("expected 3 to be remembered")
+	}
+	if fresh, _ := s.MarkSeen(ctx, 1); !fresh {
+		t.Error("expected 1 to be forgotten past capacity")
+	}
+}
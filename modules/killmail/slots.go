@@ -0,0 +1,105 @@
+package killmail
+
+import "github.com/guarzo/eveapi/common/model"
+
+// Slot is a coarse grouping of inventory flags on a ship.
+type Slot string
+
+const (
+	SlotHigh      Slot = "high"
+	SlotMid       Slot = "mid"
+	SlotLow       Slot = "low"
+	SlotRig       Slot = "rig"
+	SlotSubsystem Slot = "subsystem"
+	SlotDroneBay  Slot = "drone_bay"
+	SlotFighter   Slot = "fighter_bay"
+	SlotImplant   Slot = "implant"
+	SlotCargo     Slot = "cargo"
+	SlotOther     Slot = "other"
+)
+
+// slotOrder is the order SlotBreakdown reports slots in.
+var slotOrder = []Slot{
+	SlotHigh, SlotMid, SlotLow, SlotRig, SlotSubsystem,
+	SlotDroneBay, SlotFighter, SlotImplant, SlotCargo, SlotOther,
+}
+
+// SlotForFlag maps an ESI inventory flag to its Slot.
+func SlotForFlag(flag int) Slot {
+	switch {
+	case flag >= 11 && flag <= 18:
+		return SlotLow
+	case flag >= 19 && flag <= 26:
+		return SlotMid
+	case flag >= 27 && flag <= 34:
+		return SlotHigh
+	case flag >= 92 && flag <= 99:
+		return SlotRig
+	case flag >= 125 && flag <= 132:
+		return SlotSubsystem
+	case flag == 87:
+		return SlotDroneBay
+	case flag >= 158 && flag <= 163:
+		return SlotFighter
+	case flag == 89:
+		return SlotImplant
+	case flag == 5, flag == 90, flag >= 133 && flag <= 157, flag >= 164 && flag <= 180:
+		// cargo, ship hangar, fleet hangar and the specialized holds
+		return SlotCargo
+	}
+	return SlotOther
+}
+
+// IsFittedFlag reports whether an inventory flag is a fitting slot
+// (low, mid, high, rig or subsystem).
+func IsFittedFlag(flag int) bool {
+	switch SlotForFlag(flag) {
+	case SlotHigh, SlotMid, SlotLow, SlotRig, SlotSubsystem:
+		return true
+	}
+	return false
+}
+
+// SlotValue is the ISK lost and dropped in one slot group.
+type SlotValue struct {
+	Slot      Slot    `json:"slot"`
+	Items     int64   `json:"items"`
+	Destroyed float64 `json:"destroyed"`
+	Dropped   float64 `json:"dropped"`
+	Total     float64 `json:"total"`
+}
+
+// SlotBreakdown values the victim's items per slot group using prices
+// (typeID -> unit price). Container contents count towards the container's slot.
+// Slots without items are omitted; the rest follow high/mid/low/rig/... order.
+func SlotBreakdown(victim model.Victim, prices map[int64]float64) []SlotValue {
+	bySlot := make(map[Slot]*SlotValue)
+	for _, it := range victim.Items {
+		slot := SlotForFlag(it.Flag)
+		sv, ok := bySlot[slot]
+		if !ok {
+			sv = &SlotValue{Slot: slot}
+			bySlot[slot] = sv
+		}
+		addSlotItem(sv, it, prices)
+	}
+
+	var out []SlotValue
+	for _, slot := range slotOrder {
+		if sv, ok := bySlot[slot]; ok {
+			sv.Total = sv.Destroyed + sv.Dropped
+			out = append(out, *sv)
+		}
+	}
+	return out
+}
+
+func addSlotItem(sv *SlotValue, it model.VictimItem, prices map[int64]float64) {
+	price := prices[it.ItemTypeID]
+	sv.Items += it.QuantityDestroyed + it.QuantityDropped
+	sv.Destroyed += price * float64(it.QuantityDestroyed)
+	sv.Dropped += price * float64(it.QuantityDropped)
+	for _, child := range it.Items {
+		addSlotItem(sv, child, prices)
+	}
+}
@@ -0,0 +1,34 @@
+package killmail_test
+
+import (
+	"testing"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/killmail"
+)
+
+func TestSlotBreakdown(t *testing.T) {
+	prices := map[int64]float64{2873: 100, 2048: 40, 34: 1}
+	victim := model.Victim{
+		Items: []model.VictimItem{
+			{Flag: 27, ItemTypeID: 2873, QuantityDestroyed: 1},
+			{Flag: 28, ItemTypeID: 2873, QuantityDropped: 1},
+			{Flag: 11, ItemTypeID: 2048, QuantityDestroyed: 1},
+			{Flag: 5, ItemTypeID: 34, QuantityDropped: 10},
+		},
+	}
+
+	out := killmail.SlotBreakdown(victim, prices)
+	if len(out) != 3 {
+		t.Fatalf("expected 3 slot groups, got %#v", out)
+	}
+	if out[0].Slot != killmail.SlotHigh || out[0].Total != 200 || out[0].Dropped != 100 {
+		t.Errorf("unexpected high slot value: %#v", out[0])
+	}
+	if out[1].Slot != killmail.SlotLow || out[1].Destroyed != 40 {
+		t.Errorf("unexpected low slot value: %#v", out[1])
+	}
+	if out[2].Slot != killmail.SlotCargo || out[2].Items != 10 {
+		t.Errorf("unexpected cargo value: %#v", out[2])
+	}
+}
@@ -0,0 +1,196 @@
+package killmail
+
+import (
+	"math"
+	"sort"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// EntityStats summarizes the killmail activity of one character, corporation or alliance.
+type EntityStats struct {
+	ID           int64   `json:"id"`
+	Kills        int     `json:"kills"`
+	Losses       int     `json:"losses"`
+	ISKDestroyed float64 `json:"isk_destroyed"`
+	ISKLost      float64 `json:"isk_lost"`
+	Points       int     `json:"points"`
+	SoloKills    int     `json:"solo_kills"`
+	AvgGangSize  float64 `json:"avg_gang_size"`
+
+	gangTotal int
+}
+
+// Efficiency returns destroyed / (destroyed + lost), or 0 without activity.
+func (s *EntityStats) Efficiency() float64 {
+	total := s.ISKDestroyed + s.ISKLost
+	if total == 0 {
+		return 0
+	}
+	return s.ISKDestroyed / total
+}
+
+// Stats holds EntityStats keyed by ID for each entity kind.
+type Stats struct {
+	Characters   map[int64]*EntityStats `json:"characters"`
+	Corporations map[int64]*EntityStats `json:"corporations"`
+	Alliances    map[int64]*EntityStats `json:"alliances"`
+}
+
+// Aggregate builds per-entity statistics from flattened killmails. Every distinct
+// attacker entity on a kill is credited with the kill, its value and points;
+// the victim's entities are charged with the loss.
+func Aggregate(kills []model.FlattenedKillMail) *Stats {
+	st := &Stats{
+		Characters:   make(map[int64]*EntityStats),
+		Corporations: make(map[int64]*EntityStats),
+		Alliances:    make(map[int64]*EntityStats),
+	}
+
+	for _, km := range kills {
+		gang := len(km.Attackers)
+		chars := make(map[int64]bool)
+		corps := make(map[int64]bool)
+		alliances := make(map[int64]bool)
+		for _, a := range km.Attackers {
+			chars[a.CharacterID] = true
+			corps[a.CorporationID] = true
+			alliances[a.AllianceID] = true
+		}
+		creditKill(st.Characters, chars, km, gang)
+		creditKill(st.Corporations, corps, km, gang)
+		creditKill(st.Alliances, alliances, km, gang)
+
+		chargeLoss(st.Characters, km.Victim.CharacterID, km)
+		chargeLoss(st.Corporations, km.Victim.CorporationID, km)
+		chargeLoss(st.Alliances, km.Victim.AllianceID, km)
+	}
+
+	for _, m := range []map[int64]*EntityStats{st.Characters, st.Corporations, st.Alliances} {
+		for _, es := range m {
+			if es.Kills > 0 {
+				es.AvgGangSize = float64(es.gangTotal) / float64(es.Kills)
+			}
+		}
+	}
+	return st
+}
+
+// Add folds o's activity into s, weighting the average gang sizes by kills.
+func (s *EntityStats) Add(o EntityStats) {
+	s.gangTotal = s.gangSum() + o.gangSum()
+	s.Kills += o.Kills
+	s.Losses += o.Losses
+	s.ISKDestroyed += o.ISKDestroyed
+	s.ISKLost += o.ISKLost
+	s.Points += o.Points
+	s.SoloKills += o.SoloKills
+	s.AvgGangSize = 0
+	if s.Kills > 0 {
+		s.AvgGangSize = float64(s.gangTotal) / float64(s.Kills)
+	}
+}
+
+// gangSum is the total gang size over s's kills, recovered from AvgGangSize
+// for stats not built by Aggregate.
+func (s *EntityStats) gangSum() int {
+	if s.gangTotal == 0 {
+		return int(math.Round(s.AvgGangSize * float64(s.Kills)))
+	}
+	return s.gangTotal
+}
+
+// FromZKillStats converts zKillboard's all-time totals for one entity.
+func FromZKillStats(zs *model.ZKillStats) EntityStats {
+	return EntityStats{
+		ID:           zs.ID,
+		Kills:        zs.ShipsDestroyed,
+		Losses:       zs.ShipsLost,
+		ISKDestroyed: zs.ISKDestroyed,
+		ISKLost:      zs.ISKLost,
+		Points:       zs.PointsDestroyed,
+		SoloKills:    zs.SoloKills,
+		AvgGangSize:  zs.AvgGangSize,
+	}
+}
+
+// FromZKillMonth converts one month of zKillboard's breakdown for entity id.
+// zKill does not break solo kills or gang sizes down by month.
+func FromZKillMonth(id int64, ms model.ZKillMonthStats) EntityStats {
+	return EntityStats{
+		ID:           id,
+		Kills:        ms.ShipsDestroyed,
+		Losses:       ms.ShipsLost,
+		ISKDestroyed: ms.ISKDestroyed,
+		ISKLost:      ms.ISKLost,
+		Points:       ms.PointsDestroyed,
+	}
+}
+
+// MergeZKill adds zs's all-time totals to its entity's entry, e.g. to combine
+// zKill history with locally aggregated kills. It reports false when zs is
+// not a character, corporation or alliance payload.
+func (st *Stats) MergeZKill(zs *model.ZKillStats) bool {
+	var m map[int64]*EntityStats
+	switch zs.Type {
+	case "characterID":
+		m = st.Characters
+	case "corporationID":
+		m = st.Corporations
+	case "allianceID":
+		m = st.Alliances
+	default:
+		return false
+	}
+	entry(m, zs.ID).Add(FromZKillStats(zs))
+	return true
+}
+
+// SortedByDestroyed returns the entries of m ordered by ISK destroyed, highest first.
+func SortedByDestroyed(m map[int64]*EntityStats) []EntityStats {
+	out := make([]EntityStats, 0, len(m))
+	for _, es := range m {
+		out = append(out, *es)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].ISKDestroyed != out[j].ISKDestroyed {
+			return out[i].ISKDestroyed > out[j].ISKDestroyed
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+func entry(m map[int64]*EntityStats, id int64) *EntityStats {
+	es, ok := m[id]
+	if !ok {
+		es = &EntityStats{ID: id}
+		m[id] = es
+	}
+	return es
+}
+
+func creditKill(m map[int64]*EntityStats, ids map[int64]bool, km model.FlattenedKillMail, gang int) {
+	for id := range ids {
+		if id == 0 {
+			continue
+		}
+		es := entry(m, id)
+		es.Kills++
+		es.ISKDestroyed += km.TotalValue
+		es.Points += km.Points
+		es.gangTotal += gang
+		if km.Solo {
+			es.SoloKills++
+		}
+	}
+}
+
+func chargeLoss(m map[int64]*EntityStats, id int64, km model.FlattenedKillMail) {
+	if id == 0 {
+		return
+	}
+	es := entry(m, id)
+	es.Losses++
+	es.ISKLost += km.TotalValue
+}
@@ -0,0 +1,75 @@
+package killmail_test
+
+import (
+	"testing"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/killmail"
+)
+
+func TestAggregate(t *testing.T) {
+	kills := []model.FlattenedKillMail{
+		{
+			TotalValue: 100, Points: 10, Solo: true,
+			Victim:    model.Victim{CharacterID: 9, CorporationID: 90},
+			Attackers: []model.Attacker{{CharacterID: 1, CorporationID: 10, AllianceID: 100}},
+		},
+		{
+			TotalValue: 300, Points: 5,
+			Victim: model.Victim{CharacterID: 1, CorporationID: 10, AllianceID: 100},
+			Attackers: []model.Attacker{
+				{CharacterID: 9, CorporationID: 90},
+				{CharacterID: 8, CorporationID: 90},
+				{CharacterID: 7, CorporationID: 70},
+			},
+		},
+	}
+
+	st := killmail.Aggregate(kills)
+
+	c1 := st.Characters[1]
+	if c1.Kills != 1 || c1.Losses != 1 || c1.ISKDestroyed != 100 || c1.ISKLost != 300 || c1.SoloKills != 1 {
+		t.Errorf("unexpected stats for character 1: %#v", c1)
+	}
+	corp90 := st.Corporations[90]
+	if corp90.Kills != 1 || corp90.Points != 5 || corp90.AvgGangSize != 3 || corp90.Losses != 1 {
+		t.Errorf("unexpected stats for corp 90: %#v", corp90)
+	}
+	if _, ok := st.Alliances[0]; ok {
+		t.Error("entities with ID 0 must not be tracked")
+	}
+	if eff := st.Alliances[100].Efficiency(); eff != 0.25 {
+		t.Errorf("expected alliance efficiency 0.25, got %v", eff)
+	}
+
+	top := killmail.SortedByDestroyed(st.Characters)
+	if top[0].ID != 7 || top[0].ISKDestroyed != 300 {
+		t.Errorf("unexpected leader: %#v", top[0])
+	}
+}
+
+func TestStats_MergeZKill(t *testing.T) {
+	st := killmail.Aggregate([]model.FlattenedKillMail{{
+		TotalValue: 100, Points: 2,
+		Attackers: []model.Attacker{{CharacterID: 1}, {CharacterID: 2}, {CharacterID: 3}, {CharacterID: 4}},
+	}})
+	zs := &model.ZKillStats{
+		ID: 1, Type: "characterID",
+		ShipsDestroyed: 3, ShipsLost: 1, ISKDestroyed: 900, ISKLost: 50, PointsDestroyed: 8, SoloKills: 1, AvgGangSize: 2,
+	}
+	if !st.MergeZKill(zs) {
+		t.Fatal("expected character stats to merge")
+	}
+	c1 := st.Characters[1]
+	if c1.Kills != 4 || c1.Losses != 1 || c1.ISKDestroyed != 1000 || c1.Points != 10 || c1.SoloKills != 1 || c1.AvgGangSize != 2.5 {
+		t.Errorf("unexpected merged stats: %#v", c1)
+	}
+	if st.MergeZKill(&model.ZKillStats{Type: "shipTypeID"}) {
+		t.Error("expected unknown stats types to be rejected")
+	}
+
+	month := killmail.FromZKillMonth(7, model.ZKillMonthStats{ShipsDestroyed: 2, ISKDestroyed: 10, ShipsLost: 1, ISKLost: 30})
+	if month.ID != 7 || month.Kills != 2 || month.Efficiency() != 0.25 {
+		t.Errorf("unexpected month stats: %#v", month)
+	}
+}
@@ -0,0 +1,61 @@
+package killmail
+
+import "github.com/guarzo/eveapi/common/model"
+
+// Values holds the ISK figures zKillboard publishes in its zkb block.
+type Values struct {
+	Fitted    float64
+	Dropped   float64
+	Destroyed float64
+	Total     float64
+}
+
+// ComputeValues prices the victim's hull and items (including nested containers)
+// using prices (typeID -> unit price). Like zKillboard, the hull counts towards
+// fitted, destroyed and total value.
+func ComputeValues(victim model.Victim, prices map[int64]float64) Values {
+	hull := prices[victim.ShipTypeID]
+	v := Values{
+		Fitted:    hull,
+		Destroyed: hull,
+	}
+	sumItems(victim.Items, prices, true, &v)
+	v.Total = v.Destroyed + v.Dropped
+	return v
+}
+
+func sumItems(items []model.VictimItem, prices map[int64]float64, topLevel bool, v *Values) {
+	for _, it := range items {
+		price := prices[it.ItemTypeID]
+		destroyed := price * float64(it.QuantityDestroyed)
+		dropped := price * float64(it.QuantityDropped)
+		v.Destroyed += destroyed
+		v.Dropped += dropped
+		if topLevel && IsFittedFlag(it.Flag) {
+			v.Fitted += destroyed + dropped
+		}
+		sumItems(it.Items, prices, false, v)
+	}
+}
+
+// NeedsBackfill reports whether a killmail is missing zkb values.
+func NeedsBackfill(km *model.FlattenedKillMail) bool {
+	return km.TotalValue == 0
+}
+
+// Backfill fills zero-valued zkb fields on km from computed values.
+// It returns true if anything was changed.
+func Backfill(km *model.FlattenedKillMail, prices map[int64]float64) bool {
+	if !NeedsBackfill(km) {
+		return false
+	}
+	v := ComputeValues(km.Victim, prices)
+	if v.Total == 0 {
+		return false
+	}
+	km.FittedValue = v.Fitted
+	km.DroppedValue = v.Dropped
+	km.DestroyedValue = v.Destroyed
+	km.TotalValue = v.Total
+	return true
+}
@@ -0,0 +1,51 @@
+package killmail_test
+
+import (
+	"testing"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/killmail"
+)
+
+func TestBackfill(t *testing.T) {
+	prices := map[int64]float64{
+		587:  1000, // Rifter hull
+		2873: 100,  // fitted high-slot module
+		34:   1,    // cargo
+		3467: 50,   // container
+	}
+	km := &model.FlattenedKillMail{
+		Victim: model.Victim{
+			ShipTypeID: 587,
+			Items: []model.VictimItem{
+				{Flag: 27, ItemTypeID: 2873, QuantityDestroyed: 1},
+				{Flag: 28, ItemTypeID: 2873, QuantityDropped: 1},
+				{Flag: 5, ItemTypeID: 34, QuantityDropped: 300},
+				{Flag: 5, ItemTypeID: 3467, QuantityDestroyed: 1, Items: []model.VictimItem{
+					{Flag: 0, ItemTypeID: 34, QuantityDestroyed: 200},
+				}},
+			},
+		},
+	}
+
+	if !killmail.Backfill(km, prices) {
+		t.Fatal("expected backfill to change values")
+	}
+	if km.FittedValue != 1200 {
+		t.Errorf("expected fitted 1200, got %v", km.FittedValue)
+	}
+	if km.DroppedValue != 400 {
+		t.Errorf("expected dropped 400, got %v", km.DroppedValue)
+	}
+	if km.DestroyedValue != 1350 {
+		t.Errorf("expected destroyed 1350, got %v", km.DestroyedValue)
+	}
+	if km.TotalValue != 1750 {
+		t.Errorf("expected total 1750, got %v", km.TotalValue)
+	}
+
+	// values already present are never overwritten
+	if killmail.Backfill(km, map[int64]float64{587: 1}) {
+		t.Error("expected no backfill when zkb values are present")
+	}
+}
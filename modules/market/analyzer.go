@@ -0,0 +1,152 @@
+package market
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+const (
+	// TheForgeRegionID is the region containing Jita.
+	TheForgeRegionID int64 = 10000002
+	// Jita44StationID is Jita IV - Moon 4 - Caldari Navy Assembly Plant.
+	Jita44StationID int64 = 60003760
+
+	// DefaultSnapshotTTL is how long an order book snapshot is reused.
+	DefaultSnapshotTTL = 5 * time.Minute
+)
+
+// OrderSource is the part of esi.EsiService the analyzer needs.
+type OrderSource interface {
+	GetRegionOrders(ctx context.Context, regionID int64, typeID model.TypeID, orderType string) ([]model.MarketOrder, error)
+	GetStructureOrders(ctx context.Context, structureID int64, token *oauth2.Token) ([]model.MarketOrder, error)
+}
+
+// Quote summarises an order book.
+type Quote struct {
+	TypeID  int64   `json:"type_id"`
+	BestBid float64 `json:"best_bid"`
+	BestAsk float64 `json:"best_ask"`
+	Spread  float64 `json:"spread"`
+}
+
+// AnalyzerOption configures an Analyzer.
+type AnalyzerOption func(*Analyzer)
+
+// WithSnapshotTTL sets how long order books are cached. Zero disables caching.
+func WithSnapshotTTL(ttl time.Duration) AnalyzerOption {
+	return func(a *Analyzer) { a.ttl = ttl }
+}
+
+// Analyzer fetches order books and caches them in memory for a short TTL.
+type Analyzer struct {
+	source  OrderSource
+	backend AppraisalBackend
+	ttl     time.Duration
+	now     func() time.Time
+
+	mu    sync.Mutex
+	books map[string]*OrderBook
+}
+
+// NewAnalyzer constructs an Analyzer over source.
+func NewAnalyzer(source OrderSource, opts ...AnalyzerOption) *Analyzer {
+	a := &Analyzer{
+		source: source,
+		ttl:    DefaultSnapshotTTL,
+		now:    time.Now,
+		books:  make(map[string]*OrderBook),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// RegionBook returns the order book for typeID across a region. If
+// locationID is non-zero only orders at that location are included.
+func (a *Analyzer) RegionBook(ctx context.Context, regionID, locationID int64, typeID model.TypeID) (*OrderBook, error) {
+	key := fmt.Sprintf("region:%d:%d:%d", regionID, locationID, typeID)
+	return a.book(key, typeID, func() ([]model.MarketOrder, error) {
+		orders, err := a.source.GetRegionOrders(ctx, regionID, typeID, "all")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get orders for region %d: %w", regionID, err)
+		}
+		if locationID == 0 {
+			return orders, nil
+		}
+		filtered := orders[:0:0]
+		for _, o := range orders {
+			if o.LocationID == locationID {
+				filtered = append(filtered, o)
+			}
+		}
+		return filtered, nil
+	})
+}
+
+// StructureBook returns the order book for typeID in a player structure.
+// The structure's full order list is fetched; only typeID is kept.
+func (a *Analyzer) StructureBook(ctx context.Context, structureID int64, typeID model.TypeID, token *oauth2.Token) (*OrderBook, error) {
+	key := fmt.Sprintf("structure:%d:%d", structureID, typeID)
+	return a.book(key, typeID, func() ([]model.MarketOrder, error) {
+		orders, err := a.source.GetStructureOrders(ctx, structureID, token)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get orders for structure %d: %w", structureID, err)
+		}
+		return orders, nil
+	})
+}
+
+// JitaBook returns the order book for typeID at Jita 4-4.
+func (a *Analyzer) JitaBook(ctx context.Context, typeID model.TypeID) (*OrderBook, error) {
+	return a.RegionBook(ctx, TheForgeRegionID, Jita44StationID, typeID)
+}
+
+// JitaPrice returns a quote for typeID at Jita 4-4. Missing sides are zero.
+func (a *Analyzer) JitaPrice(ctx context.Context, typeID model.TypeID) (Quote, error) {
+	book, err := a.JitaBook(ctx, typeID)
+	if err != nil {
+		return Quote{}, err
+	}
+	q := Quote{TypeID: typeID}
+	q.BestBid, _ = book.BestBid()
+	q.BestAsk, _ = book.BestAsk()
+	q.Spread, _ = book.Spread()
+	return q, nil
+}
+
+// Invalidate drops every cached order book.
+func (a *Analyzer) Invalidate() {
+	a.mu.Lock()
+	a.books = make(map[string]*OrderBook)
+	a.mu.Unlock()
+}
+
+func (a *Analyzer) book(key string, typeID model.TypeID, fetch func() ([]model.MarketOrder, error)) (*OrderBook, error) {
+	now := a.now()
+	a.mu.Lock()
+	if b, ok := a.books[key]; ok && a.ttl > 0 && now.Sub(b.FetchedAt) < a.ttl {
+		a.mu.Unlock()
+		return b, nil
+	}
+	a.mu.Unlock()
+
+	orders, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	b := NewOrderBook(typeID, orders)
+	b.FetchedAt = now
+	if a.ttl > 0 {
+		a.mu.Lock()
+		a.books[key] = b
+		a.mu.Unlock()
+	}
+	return b, nil
+}
@@ -0,0 +1,110 @@
+package market
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// AppraisalItem is the valuation of one type in an Appraisal.
+type AppraisalItem struct {
+	TypeID    int64   `json:"type_id"`
+	Quantity  int64   `json:"quantity"`
+	Buy       float64 `json:"buy"`  // best bid per unit
+	Sell      float64 `json:"sell"` // best ask per unit
+	BuyTotal  float64 `json:"buy_total"`
+	SellTotal float64 `json:"sell_total"`
+}
+
+// Appraisal prices an arbitrary item list.
+type Appraisal struct {
+	RegionID  int64           `json:"region_id"`
+	Items     []AppraisalItem `json:"items"` // sorted by type ID
+	TotalBuy  float64         `json:"total_buy"`
+	TotalSell float64         `json:"total_sell"`
+	Unpriced  []int64         `json:"unpriced,omitempty"` // types with no orders on either side
+}
+
+// BuyPrices returns typeID -> best bid, suitable for
+// esi.ValueAssetsWithPrices. Types without bids are omitted.
+func (a *Appraisal) BuyPrices() map[int64]float64 {
+	m := make(map[int64]float64, len(a.Items))
+	for _, it := range a.Items {
+		if it.Buy > 0 {
+			m[it.TypeID] = it.Buy
+		}
+	}
+	return m
+}
+
+// SellPrices returns typeID -> best ask. Types without asks are omitted.
+func (a *Appraisal) SellPrices() map[int64]float64 {
+	m := make(map[int64]float64, len(a.Items))
+	for _, it := range a.Items {
+		if it.Sell > 0 {
+			m[it.TypeID] = it.Sell
+		}
+	}
+	return m
+}
+
+// Appraiser prices item lists (typeID -> quantity); *Analyzer satisfies it.
+// Packages that value items take an Appraiser so tests can stub prices.
+type Appraiser interface {
+	Appraise(ctx context.Context, items map[int64]int64, regionID int64) (*Appraisal, error)
+}
+
+// AppraisalBackend prices item lists with an external service such as
+// Janice or Evepraisal instead of ESI order books.
+type AppraisalBackend interface {
+	Appraise(ctx context.Context, items map[int64]int64, regionID int64) (*Appraisal, error)
+}
+
+// WithAppraisalBackend makes Appraise delegate to backend.
+func WithAppraisalBackend(backend AppraisalBackend) AnalyzerOption {
+	return func(a *Analyzer) { a.backend = backend }
+}
+
+// Appraise prices items (typeID -> quantity) in regionID using cached order
+// book snapshots, or the configured AppraisalBackend. In The Forge only
+// Jita 4-4 orders are considered.
+func (a *Analyzer) Appraise(ctx context.Context, items map[int64]int64, regionID int64) (*Appraisal, error) {
+	if a.backend != nil {
+		out, err := a.backend.Appraise(ctx, items, regionID)
+		if err != nil {
+			return nil, fmt.Errorf("appraisal backend failed: %w", err)
+		}
+		return out, nil
+	}
+
+	var locationID int64
+	if regionID == TheForgeRegionID {
+		locationID = Jita44StationID
+	}
+
+	typeIDs := make([]int64, 0, len(items))
+	for typeID := range items {
+		typeIDs = append(typeIDs, typeID)
+	}
+	sort.Slice(typeIDs, func(i, j int) bool { return typeIDs[i] < typeIDs[j] })
+
+	out := &Appraisal{RegionID: regionID}
+	for _, typeID := range typeIDs {
+		book, err := a.RegionBook(ctx, regionID, locationID, typeID)
+		if err != nil {
+			return nil, err
+		}
+		it := AppraisalItem{TypeID: typeID, Quantity: items[typeID]}
+		it.Buy, _ = book.BestBid()
+		it.Sell, _ = book.BestAsk()
+		if it.Buy == 0 && it.Sell == 0 {
+			out.Unpriced = append(out.Unpriced, typeID)
+		}
+		it.BuyTotal = it.Buy * float64(it.Quantity)
+		it.SellTotal = it.Sell * float64(it.Quantity)
+		out.TotalBuy += it.BuyTotal
+		out.TotalSell += it.SellTotal
+		out.Items = append(out.Items, it)
+	}
+	return out, nil
+}
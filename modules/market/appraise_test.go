@@ -0,0 +1,41 @@
+package market_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/guarzo/eveapi/modules/market"
+)
+
+type stubBackend struct{ called bool }
+
+func (b *stubBackend) Appraise(ctx context.Context, items map[int64]int64, regionID int64) (*market.Appraisal, error) {
+	b.called = true
+	return &market.Appraisal{RegionID: regionID, TotalSell: 1}, nil
+}
+
+func TestAppraise(t *testing.T) {
+	a := market.NewAnalyzer(&orderSource{})
+	out, err := a.Appraise(context.Background(), map[int64]int64{tritanium: 100, 35: 5}, market.TheForgeRegionID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.TotalBuy != 450 || out.TotalSell != 500 {
+		t.Errorf("unexpected totals: buy=%v sell=%v", out.TotalBuy, out.TotalSell)
+	}
+	if len(out.Unpriced) != 1 || out.Unpriced[0] != 35 {
+		t.Errorf("expected type 35 unpriced, got %v", out.Unpriced)
+	}
+	if p := out.SellPrices(); p[tritanium] != 5 || len(p) != 1 {
+		t.Errorf("unexpected sell prices: %v", p)
+	}
+
+	backend := &stubBackend{}
+	a = market.NewAnalyzer(&orderSource{}, market.WithAppraisalBackend(backend))
+	if _, err := a.Appraise(context.Background(), map[int64]int64{tritanium: 1}, market.TheForgeRegionID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !backend.called {
+		t.Error("expected backend to be used")
+	}
+}
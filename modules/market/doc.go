@@ -0,0 +1,4 @@
+// Package market builds order book snapshots from ESI market orders and
+// derives best bid/ask, spread and depth figures for quick price lookups,
+// plus moving averages, VWAP and volatility over daily price history.
+package market
@@ -0,0 +1,119 @@
+package market
+
+import (
+	"math"
+	"sort"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/charts"
+)
+
+// MovingAverage returns the simple moving average of daily average prices
+// over window days. The first window-1 entries average over the days
+// available so far. days must be sorted by date.
+func MovingAverage(days []model.MarketHistoryDay, window int) []float64 {
+	out := make([]float64, len(days))
+	if window <= 0 {
+		return out
+	}
+	var sum float64
+	for i, d := range days {
+		sum += d.Average
+		if i >= window {
+			sum -= days[i-window].Average
+		}
+		n := i + 1
+		if n > window {
+			n = window
+		}
+		out[i] = sum / float64(n)
+	}
+	return out
+}
+
+// VWAP returns the volume-weighted average price over days, or 0 if no
+// volume traded.
+func VWAP(days []model.MarketHistoryDay) float64 {
+	var value float64
+	var volume int64
+	for _, d := range days {
+		value += d.Average * float64(d.Volume)
+		volume += d.Volume
+	}
+	if volume == 0 {
+		return 0
+	}
+	return value / float64(volume)
+}
+
+// Volatility returns the standard deviation of daily log returns of the
+// average price. Days with a non-positive price are skipped.
+func Volatility(days []model.MarketHistoryDay) float64 {
+	var returns []float64
+	for i := 1; i < len(days); i++ {
+		prev, cur := days[i-1].Average, days[i].Average
+		if prev <= 0 || cur <= 0 {
+			continue
+		}
+		returns = append(returns, math.Log(cur/prev))
+	}
+	if len(returns) < 2 {
+		return 0
+	}
+	var mean float64
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+	var variance float64
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	return math.Sqrt(variance / float64(len(returns)-1))
+}
+
+// HistoryStats summarises a price history.
+type HistoryStats struct {
+	VWAP       float64 `json:"vwap"`
+	Volatility float64 `json:"volatility"`
+	MA5        float64 `json:"ma5"`  // latest 5-day moving average
+	MA20       float64 `json:"ma20"` // latest 20-day moving average
+}
+
+// Analyze sorts days by date and computes HistoryStats over them.
+func Analyze(days []model.MarketHistoryDay) HistoryStats {
+	days = sortedDays(days)
+	var s HistoryStats
+	if len(days) == 0 {
+		return s
+	}
+	s.VWAP = VWAP(days)
+	s.Volatility = Volatility(days)
+	s.MA5 = MovingAverage(days, 5)[len(days)-1]
+	s.MA20 = MovingAverage(days, 20)[len(days)-1]
+	return s
+}
+
+// HistoryDataset returns a chart-ready dataset with the daily average price,
+// its 5 and 20-day moving averages and the traded volume.
+func HistoryDataset(days []model.MarketHistoryDay) charts.Dataset {
+	days = sortedDays(days)
+	ds := charts.Dataset{Datasets: []charts.Series{
+		{Label: "Average"},
+		{Label: "5-day MA", Data: MovingAverage(days, 5)},
+		{Label: "20-day MA", Data: MovingAverage(days, 20)},
+		{Label: "Volume"},
+	}}
+	for _, d := range days {
+		ds.Labels = append(ds.Labels, d.Date)
+		ds.Datasets[0].Data = append(ds.Datasets[0].Data, d.Average)
+		ds.Datasets[3].Data = append(ds.Datasets[3].Data, float64(d.Volume))
+	}
+	return ds
+}
+
+func sortedDays(days []model.MarketHistoryDay) []model.MarketHistoryDay {
+	out := append([]model.MarketHistoryDay(nil), days...)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Date < out[j].Date })
+	return out
+}
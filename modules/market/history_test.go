@@ -0,0 +1,40 @@
+package market_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/market"
+)
+
+func TestHistoryAnalytics(t *testing.T) {
+	days := []model.MarketHistoryDay{
+		{Date: "2024-10-03", Average: 12, Volume: 100},
+		{Date: "2024-10-01", Average: 10, Volume: 100},
+		{Date: "2024-10-02", Average: 11, Volume: 200},
+	}
+	stats := market.Analyze(days)
+	if math.Abs(stats.VWAP-11) > 1e-9 {
+		t.Errorf("expected VWAP 11, got %v", stats.VWAP)
+	}
+	if math.Abs(stats.MA5-11) > 1e-9 {
+		t.Errorf("expected MA5 11, got %v", stats.MA5)
+	}
+	if stats.Volatility <= 0 {
+		t.Errorf("expected positive volatility, got %v", stats.Volatility)
+	}
+
+	ma := market.MovingAverage([]model.MarketHistoryDay{{Average: 2}, {Average: 4}, {Average: 6}}, 2)
+	if ma[0] != 2 || ma[1] != 3 || ma[2] != 5 {
+		t.Errorf("unexpected moving average: %v", ma)
+	}
+
+	ds := market.HistoryDataset(days)
+	if len(ds.Labels) != 3 || ds.Labels[0] != "2024-10-01" || len(ds.Datasets) != 4 {
+		t.Fatalf("unexpected dataset: %+v", ds)
+	}
+	if ds.Datasets[0].Data[0] != 10 || ds.Datasets[3].Data[1] != 200 {
+		t.Errorf("unexpected series: %+v", ds.Datasets)
+	}
+}
@@ -0,0 +1,81 @@
+package market_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/market"
+)
+
+const tritanium = 34
+
+var jitaOrders = []model.MarketOrder{
+	{OrderID: 1, TypeID: tritanium, LocationID: market.Jita44StationID, IsBuyOrder: true, Price: 4.00, VolumeRemain: 1000},
+	{OrderID: 2, TypeID: tritanium, LocationID: market.Jita44StationID, IsBuyOrder: true, Price: 4.50, VolumeRemain: 500},
+	{OrderID: 3, TypeID: tritanium, LocationID: market.Jita44StationID, Price: 5.00, VolumeRemain: 200},
+	{OrderID: 4, TypeID: tritanium, LocationID: market.Jita44StationID, Price: 5.04, VolumeRemain: 300},
+	{OrderID: 5, TypeID: tritanium, LocationID: market.Jita44StationID, Price: 6.00, VolumeRemain: 900},
+	{OrderID: 6, TypeID: tritanium, LocationID: 60000001, Price: 1.00, VolumeRemain: 10},
+}
+
+type orderSource struct{ calls int }
+
+func (s *orderSource) GetRegionOrders(ctx context.Context, regionID int64, typeID model.TypeID, orderType string) ([]model.MarketOrder, error) {
+	s.calls++
+	return jitaOrders, nil
+}
+
+func (s *orderSource) GetStructureOrders(ctx context.Context, structureID int64, token *oauth2.Token) ([]model.MarketOrder, error) {
+	s.calls++
+	return jitaOrders, nil
+}
+
+func TestOrderBook(t *testing.T) {
+	book := market.NewOrderBook(tritanium, jitaOrders[:5])
+	if bid, _ := book.BestBid(); bid != 4.50 {
+		t.Errorf("expected best bid 4.50, got %v", bid)
+	}
+	if ask, _ := book.BestAsk(); ask != 5.00 {
+		t.Errorf("expected best ask 5.00, got %v", ask)
+	}
+	if pct, _ := book.SpreadPercent(); math.Abs(pct-10) > 1e-9 {
+		t.Errorf("expected 10%% spread, got %v", pct)
+	}
+	d := book.DepthWithin(1)
+	if d.BidVolume != 500 || d.AskVolume != 500 {
+		t.Errorf("unexpected depth: %+v", d)
+	}
+	if _, ok := market.NewOrderBook(tritanium, nil).Spread(); ok {
+		t.Error("expected no spread for an empty book")
+	}
+}
+
+func TestAnalyzerJitaPrice(t *testing.T) {
+	src := &orderSource{}
+	a := market.NewAnalyzer(src)
+
+	q, err := a.JitaPrice(context.Background(), tritanium)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if q.BestAsk != 5.00 || q.BestBid != 4.50 {
+		t.Errorf("unexpected quote (station filter not applied?): %+v", q)
+	}
+	if _, err := a.JitaPrice(context.Background(), tritanium); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if src.calls != 1 {
+		t.Errorf("expected cached snapshot, got %d calls", src.calls)
+	}
+	a.Invalidate()
+	if _, err := a.JitaBook(context.Background(), tritanium); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if src.calls != 2 {
+		t.Errorf("expected refetch after Invalidate, got %d calls", src.calls)
+	}
+}
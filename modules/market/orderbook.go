@@ -0,0 +1,113 @@
+package market
+
+import (
+	"sort"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// OrderBook is a snapshot of the buy and sell orders for one type.
+type OrderBook struct {
+	TypeID    int64               `json:"type_id"`
+	Bids      []model.MarketOrder `json:"bids"` // buy orders, highest price first
+	Asks      []model.MarketOrder `json:"asks"` // sell orders, lowest price first
+	FetchedAt time.Time           `json:"fetched_at"`
+}
+
+// Depth is the volume and value available within a price band of the best
+// bid or ask.
+type Depth struct {
+	BidVolume int64   `json:"bid_volume"`
+	BidValue  float64 `json:"bid_value"`
+	AskVolume int64   `json:"ask_volume"`
+	AskValue  float64 `json:"ask_value"`
+}
+
+// NewOrderBook splits orders for typeID into sorted bids and asks. Orders
+// for other types are ignored.
+func NewOrderBook(typeID model.TypeID, orders []model.MarketOrder) *OrderBook {
+	book := &OrderBook{TypeID: typeID}
+	for _, o := range orders {
+		if o.TypeID != typeID || o.VolumeRemain <= 0 {
+			continue
+		}
+		if o.IsBuyOrder {
+			book.Bids = append(book.Bids, o)
+		} else {
+			book.Asks = append(book.Asks, o)
+		}
+	}
+	sort.SliceStable(book.Bids, func(i, j int) bool { return book.Bids[i].Price > book.Bids[j].Price })
+	sort.SliceStable(book.Asks, func(i, j int) bool { return book.Asks[i].Price < book.Asks[j].Price })
+	return book
+}
+
+// BestBid returns the highest buy price, or false if there are no bids.
+func (b *OrderBook) BestBid() (float64, bool) {
+	if len(b.Bids) == 0 {
+		return 0, false
+	}
+	return b.Bids[0].Price, true
+}
+
+// BestAsk returns the lowest sell price, or false if there are no asks.
+func (b *OrderBook) BestAsk() (float64, bool) {
+	if len(b.Asks) == 0 {
+		return 0, false
+	}
+	return b.Asks[0].Price, true
+}
+
+// Spread returns best ask minus best bid, or false if either side is empty.
+func (b *OrderBook) Spread() (float64, bool) {
+	bid, ok := b.BestBid()
+	if !ok {
+		return 0, false
+	}
+	ask, ok := b.BestAsk()
+	if !ok {
+		return 0, false
+	}
+	return ask - bid, true
+}
+
+// SpreadPercent returns the spread as a percentage of the best ask.
+func (b *OrderBook) SpreadPercent() (float64, bool) {
+	spread, ok := b.Spread()
+	if !ok {
+		return 0, false
+	}
+	ask, _ := b.BestAsk()
+	if ask == 0 {
+		return 0, false
+	}
+	return spread / ask * 100, true
+}
+
+// DepthWithin sums the bids priced within pct percent below the best bid
+// and the asks priced within pct percent above the best ask.
+func (b *OrderBook) DepthWithin(pct float64) Depth {
+	var d Depth
+	if bid, ok := b.BestBid(); ok {
+		floor := bid * (1 - pct/100)
+		for _, o := range b.Bids {
+			if o.Price < floor {
+				break
+			}
+			d.BidVolume += o.VolumeRemain
+			d.BidValue += o.Price * float64(o.VolumeRemain)
+		}
+	}
+	if ask, ok := b.BestAsk(); ok {
+		ceiling := ask * (1 + pct/100)
+		for _, o := range b.Asks {
+			if o.Price > ceiling {
+				break
+			}
+			d.AskVolume += o.VolumeRemain
+			d.AskValue += o.Price * float64(o.VolumeRemain)
+		}
+	}
+	return d
+}
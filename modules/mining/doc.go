@@ -0,0 +1,3 @@
+// Package mining aggregates corporation moon-observer ledgers by pilot, ore
+// type and observer and values them into monthly payout reports.
+package mining
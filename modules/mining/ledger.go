@@ -0,0 +1,157 @@
+package mining
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/market"
+)
+
+// LedgerSource is the part of esi.EsiService needed to read mining ledgers.
+type LedgerSource interface {
+	GetMiningObservers(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]model.MiningObserver, error)
+	GetMiningObserverLedger(ctx context.Context, corporationID model.CorporationID, observerID int64, token *oauth2.Token) ([]model.MiningLedgerEntry, error)
+}
+
+// Entry is a ledger entry tagged with the observer it was recorded at.
+type Entry struct {
+	ObserverID int64 `json:"observer_id"`
+	model.MiningLedgerEntry
+}
+
+// Totals is an aggregated quantity and ISK value.
+type Totals struct {
+	Quantity int64   `json:"quantity"`
+	Value    float64 `json:"value"`
+}
+
+// Payout is one pilot's share of a monthly report.
+type Payout struct {
+	CharacterID int64   `json:"character_id"`
+	Value       float64 `json:"value"`  // value of ore mined
+	Share       float64 `json:"share"`  // fraction of the report total
+	Payout      float64 `json:"payout"` // value after tax
+}
+
+// Report aggregates one month of mining.
+type Report struct {
+	CorporationID int64            `json:"corporation_id"`
+	Month         string           `json:"month"` // YYYY-MM
+	TaxRate       float64          `json:"tax_rate"`
+	Total         Totals           `json:"total"`
+	ByPilot       map[int64]Totals `json:"by_pilot"`
+	ByType        map[int64]Totals `json:"by_type"`
+	ByObserver    map[int64]Totals `json:"by_observer"`
+	Payouts       []Payout         `json:"payouts"` // highest value first
+	Unpriced      []int64          `json:"unpriced,omitempty"`
+}
+
+// FetchLedger reads every observer's ledger for a corporation.
+func FetchLedger(ctx context.Context, src LedgerSource, corporationID model.CorporationID, token *oauth2.Token) ([]Entry, error) {
+	observers, err := src.GetMiningObservers(ctx, corporationID, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mining observers: %w", err)
+	}
+	var out []Entry
+	for _, obs := range observers {
+		lines, err := src.GetMiningObserverLedger(ctx, corporationID, obs.ObserverID, token)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ledger of observer %d: %w", obs.ObserverID, err)
+		}
+		for _, l := range lines {
+			out = append(out, Entry{ObserverID: obs.ObserverID, MiningLedgerEntry: l})
+		}
+	}
+	return out, nil
+}
+
+// Months returns the distinct YYYY-MM months present in entries, oldest first.
+func Months(entries []Entry) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, e := range entries {
+		m := month(e.LastUpdated)
+		if m != "" && !seen[m] {
+			seen[m] = true
+			out = append(out, m)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// MonthlyReport aggregates the entries recorded in month (YYYY-MM) and values
+// the ore at best buy prices in regionID. Payouts are each pilot's value
+// less taxRate (0..1).
+func MonthlyReport(ctx context.Context, appraiser market.Appraiser, regionID int64, corporationID model.CorporationID, month string, entries []Entry, taxRate float64) (*Report, error) {
+	r := &Report{
+		CorporationID: corporationID,
+		Month:         month,
+		TaxRate:       taxRate,
+		ByPilot:       make(map[int64]Totals),
+		ByType:        make(map[int64]Totals),
+		ByObserver:    make(map[int64]Totals),
+	}
+	quantities := make(map[int64]int64)
+	var lines []Entry
+	for _, e := range entries {
+		if !strings.HasPrefix(e.LastUpdated, month) {
+			continue
+		}
+		lines = append(lines, e)
+		quantities[e.TypeID] += e.Quantity
+	}
+	if len(lines) == 0 {
+		return r, nil
+	}
+
+	appraisal, err := appraiser.Appraise(ctx, quantities, regionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to value mining ledger: %w", err)
+	}
+	r.Unpriced = appraisal.Unpriced
+	prices := appraisal.BuyPrices()
+
+	for _, e := range lines {
+		value := prices[e.TypeID] * float64(e.Quantity)
+		add(r.ByPilot, e.CharacterID, e.Quantity, value)
+		add(r.ByType, e.TypeID, e.Quantity, value)
+		add(r.ByObserver, e.ObserverID, e.Quantity, value)
+		r.Total.Quantity += e.Quantity
+		r.Total.Value += value
+	}
+
+	for id, t := range r.ByPilot {
+		p := Payout{CharacterID: id, Value: t.Value, Payout: t.Value * (1 - taxRate)}
+		if r.Total.Value > 0 {
+			p.Share = t.Value / r.Total.Value
+		}
+		r.Payouts = append(r.Payouts, p)
+	}
+	sort.Slice(r.Payouts, func(i, j int) bool {
+		if r.Payouts[i].Value != r.Payouts[j].Value {
+			return r.Payouts[i].Value > r.Payouts[j].Value
+		}
+		return r.Payouts[i].CharacterID < r.Payouts[j].CharacterID
+	})
+	return r, nil
+}
+
+func add(m map[int64]Totals, key, qty int64, value float64) {
+	t := m[key]
+	t.Quantity += qty
+	t.Value += value
+	m[key] = t
+}
+
+func month(date string) string {
+	if len(date) < 7 {
+		return ""
+	}
+	return date[:7]
+}
@@ -0,0 +1,64 @@
+package mining_test
+
+import (
+	"context"
+	"testing"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/market"
+	"github.com/guarzo/eveapi/modules/mining"
+)
+
+type ledgerSource struct{}
+
+func (ledgerSource) GetMiningObservers(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]model.MiningObserver, error) {
+	return []model.MiningObserver{{ObserverID: 1001}, {ObserverID: 1002}}, nil
+}
+
+func (ledgerSource) GetMiningObserverLedger(ctx context.Context, corporationID model.CorporationID, observerID int64, token *oauth2.Token) ([]model.MiningLedgerEntry, error) {
+	if observerID == 1001 {
+		return []model.MiningLedgerEntry{
+			{CharacterID: 1, TypeID: 45490, Quantity: 1000, LastUpdated: "2024-10-02"},
+			{CharacterID: 2, TypeID: 45490, Quantity: 3000, LastUpdated: "2024-10-03"},
+			{CharacterID: 2, TypeID: 45490, Quantity: 500, LastUpdated: "2024-09-30"},
+		}, nil
+	}
+	return []model.MiningLedgerEntry{{CharacterID: 1, TypeID: 45491, Quantity: 100, LastUpdated: "2024-10-05"}}, nil
+}
+
+type fixedAppraiser map[int64]float64
+
+func (a fixedAppraiser) Appraise(ctx context.Context, items map[int64]int64, regionID int64) (*market.Appraisal, error) {
+	out := &market.Appraisal{RegionID: regionID}
+	for typeID, qty := range items {
+		out.Items = append(out.Items, market.AppraisalItem{TypeID: typeID, Quantity: qty, Buy: a[typeID]})
+	}
+	return out, nil
+}
+
+func TestMonthlyReport(t *testing.T) {
+	ctx := context.Background()
+	entries, err := mining.FetchLedger(ctx, ledgerSource{}, 98000001, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if months := mining.Months(entries); len(months) != 2 || months[0] != "2024-09" {
+		t.Errorf("unexpected months: %v", months)
+	}
+
+	r, err := mining.MonthlyReport(ctx, fixedAppraiser{45490: 10, 45491: 100}, market.TheForgeRegionID, 98000001, "2024-10", entries, 0.1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Total.Value != 50000 || r.Total.Quantity != 4100 {
+		t.Errorf("unexpected total: %+v", r.Total)
+	}
+	if r.ByObserver[1002].Value != 10000 || r.ByType[45490].Quantity != 4000 {
+		t.Errorf("unexpected breakdown: %+v %+v", r.ByObserver, r.ByType)
+	}
+	if len(r.Payouts) != 2 || r.Payouts[0].CharacterID != 2 || r.Payouts[0].Payout != 27000 || r.Payouts[0].Share != 0.6 {
+		t.Errorf("unexpected payouts: %+v", r.Payouts)
+	}
+}
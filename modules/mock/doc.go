@@ -0,0 +1,13 @@
+// Package mock serves generated EVE data in place of ESI, zKillboard and the
+// SSO verify endpoint, so applications can run demos and test suites without
+// network access. Its Transport plugs into any http.Client:
+//
+//	client := mock.NewHttpClient(42)
+//	esiClient := esi.NewEsiClient(eveapi.DefaultESIBaseURL, client, cache, nil)
+//
+// or set eveapi.Config.Mock. Data depends only on the seed: characters belong
+// to corporations and alliances that resolve, zKill pages list kills whose
+// ESI killmails involve the requested entity, and names resolve by ID range.
+// Only the endpoints the library's clients commonly use are generated; others
+// answer 404.
+package mock
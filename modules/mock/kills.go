@@ -0,0 +1,183 @@
+package mock
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// Kill IDs encode the month, whether the kill was listed as a kill or a loss,
+// the entity type, the entity and a sequence number, so that the ESI and zKill
+// answers for a kill agree with the zKill page that listed it:
+//
+//	((monthIndex*10 + role*3 + entityType) * 1e10 + entityID) * 100 + n
+//
+// where monthIndex counts months since January 2003.
+const (
+	firstYear     = 2003
+	entityIDSpace = 10000000000
+	seqSpace      = 100
+)
+
+var entityTypes = []string{"character", "corporation", "alliance"}
+
+// killRef is what a kill ID encodes.
+type killRef struct {
+	when       time.Time // first of the month
+	loss       bool      // the entity is the victim
+	entityType int       // index into entityTypes, -1 when unknown
+	entityID   int64
+}
+
+// KillID is the ID of the nth generated kill (n < 100) of an entity in a
+// month, as listed on its zKill kills page or, if loss, its losses page.
+func KillID(entityType string, entityID int64, loss bool, year, month, n int) int64 {
+	et := 0
+	for i, s := range entityTypes {
+		if s == entityType {
+			et = i
+		}
+	}
+	role := 0
+	if loss {
+		role = 1
+	}
+	monthIndex := int64((year-firstYear)*12 + month - 1)
+	return ((monthIndex*10+int64(role*3+et))*entityIDSpace+entityID)*seqSpace + int64(n)
+}
+
+func decodeKillID(id int64) killRef {
+	rest := id / seqSpace
+	entity := rest % entityIDSpace
+	rest /= entityIDSpace
+	code, monthIndex := int(rest%10), int(rest/10)
+	ref := killRef{when: time.Date(firstYear, time.Month(1+monthIndex), 1, 0, 0, 0, 0, time.UTC), entityType: -1}
+	if code < 6 {
+		ref.loss, ref.entityType, ref.entityID = code >= 3, code%3, entity
+	}
+	return ref
+}
+
+// pilot returns a character, corporation and alliance consistent with the
+// character, corporation and alliance endpoints. With an entity, the pilot
+// belongs to it.
+func pilot(r *rand.Rand, entityType int, entityID int64) (model.CharacterID, model.CorporationID, model.AllianceID) {
+	switch entityType {
+	case 0:
+		corp := CorporationOf(entityID)
+		return entityID, corp, AllianceOf(corp)
+	case 1:
+		char := FirstCharacterID + corporations*int64(r.Intn(2000)) + entityID%corporations
+		return char, entityID, AllianceOf(entityID)
+	case 2:
+		corp := FirstCorporationID + alliances*int64(r.Intn(corporations/alliances)) + entityID%alliances
+		char := FirstCharacterID + corporations*int64(r.Intn(2000)) + corp%corporations
+		return char, corp, entityID
+	}
+	char := FirstCharacterID + int64(r.Intn(100000))
+	corp := CorporationOf(char)
+	return char, corp, AllianceOf(corp)
+}
+
+// kill generates the killmail with the given ID.
+func (t *Transport) kill(id int64) model.EsiKillMail {
+	ref := decodeKillID(id)
+	r := t.rng(8, id)
+	km := model.EsiKillMail{
+		KillMailID:    id,
+		KillMailTime:  ref.when.Add(time.Duration(r.Int63n(int64(28 * 24 * time.Hour)))).Truncate(time.Second),
+		SolarSystemID: systemIDs[r.Intn(len(systemIDs))],
+	}
+
+	victimType := -1
+	if ref.loss {
+		victimType = ref.entityType
+	}
+	v := &km.Victim
+	v.CharacterID, v.CorporationID, v.AllianceID = pilot(r, victimType, ref.entityID)
+	v.ShipTypeID = shipTypes[r.Intn(len(shipTypes))]
+	v.Position.X, v.Position.Y, v.Position.Z = r.NormFloat64()*1e12, r.NormFloat64()*1e12, r.NormFloat64()*1e12
+	for flag, n := 11, 1+r.Intn(6); n > 0; flag, n = flag+1, n-1 {
+		item := model.VictimItem{Flag: flag, ItemTypeID: moduleTypes[r.Intn(len(moduleTypes))], Singleton: 0}
+		if r.Intn(2) == 0 {
+			item.QuantityDropped = 1
+		} else {
+			item.QuantityDestroyed = 1
+		}
+		v.Items = append(v.Items, item)
+	}
+
+	attackers := 1 + r.Intn(12)
+	finalBlow := r.Intn(attackers)
+	for i := 0; i < attackers; i++ {
+		a := model.Attacker{
+			DamageDone:     100 + r.Intn(5000),
+			FinalBlow:      i == finalBlow,
+			SecurityStatus: float64(r.Intn(1000)-500) / 100,
+			ShipTypeID:     shipTypes[r.Intn(len(shipTypes))],
+			WeaponTypeID:   moduleTypes[r.Intn(len(moduleTypes))],
+		}
+		attackerType := -1
+		if i == 0 && !ref.loss {
+			attackerType = ref.entityType
+		}
+		a.CharacterID, a.CorporationID, a.AllianceID = pilot(r, attackerType, ref.entityID)
+		v.DamageTaken += a.DamageDone
+		km.Attackers = append(km.Attackers, a)
+	}
+	return km
+}
+
+// zkb generates zKill's metadata for a kill.
+func (t *Transport) zkb(km model.EsiKillMail) model.ZKB {
+	r := t.rng(9, km.KillMailID)
+	fitted := float64(1+r.Intn(500)) * 1e6
+	dropped := fitted * float64(r.Intn(50)) / 100
+	return model.ZKB{
+		LocationID:     40000000 + int64(r.Intn(500000)),
+		Hash:           fmt.Sprintf("%016x%016x", r.Uint64(), r.Uint64()),
+		FittedValue:    fitted,
+		DroppedValue:   dropped,
+		DestroyedValue: fitted - dropped,
+		TotalValue:     fitted,
+		Points:         1 + r.Intn(50),
+		Solo:           len(km.Attackers) == 1,
+	}
+}
+
+func (t *Transport) esiKillmail(args []string, _ *http.Request) (interface{}, error) {
+	return t.kill(num(args[0])), nil
+}
+
+// zkillPage answers /api/{kills|losses}/{type}ID/{id}/year/{y}/month/{m}/page/{p}/.
+// Only the first page has kills.
+func (t *Transport) zkillPage(args []string, _ *http.Request) (interface{}, error) {
+	loss := args[0] == "losses"
+	entityID, year, month, page := num(args[2]), int(num(args[3])), int(num(args[4])), num(args[5])
+	out := []model.ZkillMail{}
+	if page != 1 || year < firstYear || month < 1 || month > 12 {
+		return out, nil
+	}
+	count := 1 + t.rng(10, entityID, int64(year*100+month)).Intn(killsPerPage)
+	for n := 0; n < count; n++ {
+		km := t.kill(KillID(args[1], entityID, loss, year, month, n))
+		out = append(out, model.ZkillMail{KillMailID: km.KillMailID, ZKB: t.zkb(km)})
+	}
+	return out, nil
+}
+
+// zkillKill answers /api/killID/{id}/.
+func (t *Transport) zkillKill(args []string, _ *http.Request) (interface{}, error) {
+	km := t.kill(num(args[0]))
+	return []model.ZkillMailFeedResponse{{
+		KillmailID:    km.KillMailID,
+		KillMailTime:  km.KillMailTime,
+		SolarSystemID: km.SolarSystemID,
+		Victim:        km.Victim,
+		Attackers:     km.Attackers,
+		ZKB:           t.zkb(km),
+	}}, nil
+}
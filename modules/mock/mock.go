@@ -0,0 +1,320 @@
+package mock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// ID ranges of generated entities, matching the ranges CCP uses.
+const (
+	FirstCharacterID   = 90000000
+	FirstCorporationID = 98000000
+	FirstAllianceID    = 99000000
+
+	corporations = 50 // characters are spread over this many corporations
+	alliances    = 10 // corporations are spread over this many alliances
+	killsPerPage = 10 // kills on the first zKill page of any entity and month
+)
+
+// ships, modules and systems the generated data draws from.
+var (
+	shipTypes   = []model.TypeID{587, 603, 11987, 17738, 24690, 23757, 29984}
+	moduleTypes = []model.TypeID{2048, 519, 12076, 448, 2873, 3841}
+	systemIDs   = []model.SystemID{30000142, 30002187, 30002659, 30003489, 31000005}
+
+	typeNames = map[int64]string{
+		587: "Rifter", 603: "Merlin", 11987: "Guardian", 17738: "Machariel",
+		24690: "Hurricane", 23757: "Archon", 29984: "Tengu",
+		2048: "Damage Control II", 519: "Gyrostabilizer II", 12076: "5MN Microwarpdrive II",
+		448: "Warp Scrambler II", 2873: "200mm AutoCannon II", 3841: "Large Shield Extender II",
+		34: "Tritanium", 35: "Pyerite", 44992: "PLEX",
+	}
+	systemNames = map[int64]string{
+		30000142: "Jita", 30002187: "Amarr", 30002659: "Dodixie", 30003489: "Tama", 31000005: "Thera",
+	}
+	syllables = []string{"ka", "ri", "do", "mar", "vel", "tor", "an", "sel", "qui", "zen", "lo", "bra"}
+)
+
+// Transport is an http.RoundTripper answering ESI, zKillboard and SSO
+// requests with generated data instead of going to the network. The same
+// seed always yields the same data, and every answer is consistent with the
+// others: a character's corporation exists, a zKill kill's hash fetches its
+// ESI killmail, and so on. Unknown endpoints answer 404.
+type Transport struct {
+	seed int64
+}
+
+// NewTransport returns a Transport generating data from seed.
+func NewTransport(seed int64) *Transport {
+	return &Transport{seed: seed}
+}
+
+// NewHttpClient returns a common.HttpClient backed by a Transport, ready to
+// pass to the ESI and zKill clients.
+func NewHttpClient(seed int64) common.HttpClient {
+	return common.NewEveHttpClient("eveapi-mock", &http.Client{Transport: NewTransport(seed)})
+}
+
+// route matches a request path to the handler producing its body.
+type route struct {
+	method string
+	re     *regexp.Regexp
+	handle func(t *Transport, args []string, req *http.Request) (interface{}, error)
+}
+
+var routes = []route{
+	{http.MethodGet, regexp.MustCompile(`/characters/(\d+)/assets/$`), (*Transport).assets},
+	{http.MethodGet, regexp.MustCompile(`/corporations/(\d+)/assets/$`), (*Transport).assets},
+	{http.MethodGet, regexp.MustCompile(`/characters/(\d+)/location/$`), (*Transport).location},
+	{http.MethodGet, regexp.MustCompile(`/characters/(\d+)/ship/$`), (*Transport).ship},
+	{http.MethodGet, regexp.MustCompile(`/characters/(\d+)/$`), (*Transport).character},
+	{http.MethodGet, regexp.MustCompile(`/corporations/(\d+)/$`), (*Transport).corporation},
+	{http.MethodGet, regexp.MustCompile(`/alliances/(\d+)/$`), (*Transport).alliance},
+	{http.MethodGet, regexp.MustCompile(`/killmails/(\d+)/[^/]+/$`), (*Transport).esiKillmail},
+	{http.MethodGet, regexp.MustCompile(`/universe/types/(\d+)/$`), (*Transport).itemType},
+	{http.MethodGet, regexp.MustCompile(`/universe/systems/(\d+)/$`), (*Transport).system},
+	{http.MethodGet, regexp.MustCompile(`/markets/prices/$`), (*Transport).prices},
+	{http.MethodPost, regexp.MustCompile(`/universe/names/$`), (*Transport).names},
+	{http.MethodGet, regexp.MustCompile(`/oauth/verify$`), (*Transport).verify},
+	{http.MethodGet, regexp.MustCompile(`/api/(kills|losses)/(character|corporation|alliance)ID/(\d+)/year/(\d+)/month/(\d+)/page/(\d+)/$`), (*Transport).zkillPage},
+	{http.MethodGet, regexp.MustCompile(`/api/killID/(\d+)/$`), (*Transport).zkillKill},
+}
+
+// RoundTrip answers req from the generated data.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for _, r := range routes {
+		m := r.re.FindStringSubmatch(req.URL.Path)
+		if m == nil || r.method != req.Method {
+			continue
+		}
+		body, err := r.handle(t, m[1:], req)
+		if err != nil {
+			return respond(req, http.StatusNotFound, map[string]string{"error": err.Error()})
+		}
+		return respond(req, http.StatusOK, body)
+	}
+	return respond(req, http.StatusNotFound, map[string]string{"error": "not mocked: " + req.URL.Path})
+}
+
+func respond(req *http.Request, status int, v interface{}) (*http.Response, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	h := http.Header{}
+	h.Set("Content-Type", "application/json")
+	h.Set("Expires", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	return &http.Response{
+		StatusCode: status,
+		Header:     h,
+		Body:       io.NopCloser(bytes.NewReader(data)),
+		Request:    req,
+	}, nil
+}
+
+// num parses a path segment the route's pattern already matched as digits.
+func num(s string) int64 {
+	n, _ := strconv.ParseInt(s, 10, 64)
+	return n
+}
+
+// rng returns a generator determined by the seed and the given keys.
+func (t *Transport) rng(keys ...int64) *rand.Rand {
+	h := t.seed
+	for _, k := range keys {
+		h = h*1000003 ^ k
+	}
+	return rand.New(rand.NewSource(h))
+}
+
+// name builds a pronounceable name of n syllables.
+func name(r *rand.Rand, n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		b.WriteString(syllables[r.Intn(len(syllables))])
+	}
+	s := b.String()
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// CorporationOf is the corporation a generated character belongs to.
+func CorporationOf(characterID int64) int64 {
+	return FirstCorporationID + characterID%corporations
+}
+
+// AllianceOf is the alliance a generated corporation belongs to.
+func AllianceOf(corporationID int64) int64 {
+	return FirstAllianceID + corporationID%alliances
+}
+
+func (t *Transport) characterName(id int64) string {
+	r := t.rng(1, id)
+	return name(r, 2) + " " + name(r, 2)
+}
+
+func (t *Transport) corporationName(id int64) string {
+	return name(t.rng(2, id), 3) + " Industries"
+}
+
+func (t *Transport) allianceName(id int64) string {
+	return "The " + name(t.rng(3, id), 3) + " Coalition"
+}
+
+func ticker(s string) string {
+	return strings.ToUpper(strings.ReplaceAll(s, " ", ""))[:4]
+}
+
+func (t *Transport) character(args []string, _ *http.Request) (interface{}, error) {
+	id := num(args[0])
+	r := t.rng(1, id)
+	return model.EsiCharacter{
+		Birthday:       time.Date(2005+r.Intn(18), time.Month(1+r.Intn(12)), 1+r.Intn(28), 0, 0, 0, 0, time.UTC),
+		BloodlineID:    1 + r.Int63n(8),
+		CorporationID:  CorporationOf(id),
+		Gender:         []string{"male", "female"}[r.Intn(2)],
+		Name:           t.characterName(id),
+		RaceID:         []int64{1, 2, 4, 8}[r.Intn(4)],
+		SecurityStatus: float64(r.Intn(1000)-500) / 100,
+	}, nil
+}
+
+func (t *Transport) corporation(args []string, _ *http.Request) (interface{}, error) {
+	id := num(args[0])
+	r := t.rng(2, id)
+	ceo := FirstCharacterID + id%corporations
+	n := t.corporationName(id)
+	return model.EsiCorporation{
+		AllianceID:  AllianceOf(id),
+		CeoID:       ceo,
+		CreatorID:   ceo,
+		DateFounded: time.Date(2010+r.Intn(12), time.Month(1+r.Intn(12)), 1, 0, 0, 0, 0, time.UTC),
+		MemberCount: 10 + r.Intn(490),
+		Name:        n,
+		TaxRate:     float64(r.Intn(11)) / 100,
+		Ticker:      ticker(n),
+	}, nil
+}
+
+func (t *Transport) alliance(args []string, _ *http.Request) (interface{}, error) {
+	id := num(args[0])
+	r := t.rng(3, id)
+	executor := FirstCorporationID + id%alliances
+	return model.EsiAlliance{
+		CreatorCorporationID:  executor,
+		CreatorID:             FirstCharacterID + executor%corporations,
+		DateFounded:           time.Date(2012+r.Intn(10), time.Month(1+r.Intn(12)), 1, 0, 0, 0, 0, time.UTC),
+		ExecutorCorporationID: executor,
+		Name:                  t.allianceName(id),
+		Ticker:                ticker(t.allianceName(id)[4:]),
+	}, nil
+}
+
+func (t *Transport) assets(args []string, _ *http.Request) (interface{}, error) {
+	r := t.rng(4, num(args[0]))
+	var out []model.Asset
+	for i, n := 0, 5+r.Intn(20); i < n; i++ {
+		out = append(out, model.Asset{
+			TypeID:       pickType(r),
+			Quantity:     1 + r.Intn(100),
+			LocationFlag: "Hangar",
+			LocationType: "station",
+			LocationID:   60003760 + int64(r.Intn(3)),
+		})
+	}
+	return out, nil
+}
+
+func pickType(r *rand.Rand) model.TypeID {
+	all := append(append([]model.TypeID{}, shipTypes...), moduleTypes...)
+	return all[r.Intn(len(all))]
+}
+
+func (t *Transport) location(args []string, _ *http.Request) (interface{}, error) {
+	r := t.rng(5, num(args[0]))
+	return map[string]int64{"solar_system_id": systemIDs[r.Intn(len(systemIDs))]}, nil
+}
+
+func (t *Transport) ship(args []string, _ *http.Request) (interface{}, error) {
+	id := num(args[0])
+	r := t.rng(11, id)
+	return model.CharacterShip{
+		ShipItemID: 1000000000000 + id,
+		ShipName:   fmt.Sprintf("Ship of %d", id),
+		ShipTypeID: shipTypes[r.Intn(len(shipTypes))],
+	}, nil
+}
+
+func (t *Transport) itemType(args []string, _ *http.Request) (interface{}, error) {
+	id := num(args[0])
+	n, ok := typeNames[id]
+	if !ok {
+		return nil, fmt.Errorf("type %d not found", id)
+	}
+	return model.ItemType{TypeID: id, Name: n, GroupID: 25, Volume: 1, Published: true}, nil
+}
+
+func (t *Transport) system(args []string, _ *http.Request) (interface{}, error) {
+	id := num(args[0])
+	n, ok := systemNames[id]
+	if !ok {
+		return nil, fmt.Errorf("system %d not found", id)
+	}
+	sec := float64(t.rng(6, id).Intn(100)) / 100
+	return model.SolarSystem{SystemID: id, Name: n, ConstellationID: 20000020, SecurityStatus: sec}, nil
+}
+
+func (t *Transport) prices(_ []string, _ *http.Request) (interface{}, error) {
+	var out []model.MarketPrice
+	for id := range typeNames {
+		r := t.rng(7, id)
+		p := float64(1+r.Intn(100000)) * 1000
+		out = append(out, model.MarketPrice{TypeID: id, AveragePrice: p, AdjustedPrice: p * 0.97})
+	}
+	return out, nil
+}
+
+func (t *Transport) names(_ []string, req *http.Request) (interface{}, error) {
+	var ids []int64
+	if req.Body != nil {
+		if err := json.NewDecoder(req.Body).Decode(&ids); err != nil {
+			return nil, err
+		}
+	}
+	out := make([]model.UniverseName, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, t.universeName(id))
+	}
+	return out, nil
+}
+
+// universeName names any generated ID by the range it falls in.
+func (t *Transport) universeName(id int64) model.UniverseName {
+	switch {
+	case id >= FirstAllianceID && id < FirstAllianceID+1000000:
+		return model.UniverseName{ID: id, Name: t.allianceName(id), Category: "alliance"}
+	case id >= FirstCorporationID && id < FirstAllianceID:
+		return model.UniverseName{ID: id, Name: t.corporationName(id), Category: "corporation"}
+	case id >= FirstCharacterID && id < FirstCorporationID:
+		return model.UniverseName{ID: id, Name: t.characterName(id), Category: "character"}
+	case systemNames[id] != "":
+		return model.UniverseName{ID: id, Name: systemNames[id], Category: "solar_system"}
+	case typeNames[id] != "":
+		return model.UniverseName{ID: id, Name: typeNames[id], Category: "inventory_type"}
+	}
+	return model.UniverseName{ID: id, Name: fmt.Sprintf("Unknown %d", id), Category: "inventory_type"}
+}
+
+func (t *Transport) verify(_ []string, _ *http.Request) (interface{}, error) {
+	return model.User{CharacterID: FirstCharacterID + 1, CharacterName: t.characterName(FirstCharacterID + 1)}, nil
+}
@@ -0,0 +1,101 @@
+package mock_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/esi"
+	"github.com/guarzo/eveapi/modules/mock"
+	"github.com/guarzo/eveapi/modules/zkill"
+)
+
+func newServices(seed int64) (esi.EsiService, zkill.ZKillService) {
+	client := mock.NewHttpClient(seed)
+	esiSvc := esi.NewEsiService(esi.NewEsiClient("https://esi.evetech.net/latest/", client, common.NewMemoryCache(), nil))
+	zkSvc := zkill.NewKillmailService(esiSvc,
+		zkill.NewZKillService(zkill.NewZkillClient("https://zkillboard.com", client, common.NewMemoryCache())))
+	return esiSvc, zkSvc
+}
+
+func TestTransport_EntitiesAreConsistent(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newServices(1)
+
+	char, err := svc.GetCharacterInfo(ctx, mock.FirstCharacterID+7)
+	if err != nil {
+		t.Fatalf("character: %v", err)
+	}
+	if char.Name == "" || char.CorporationID != mock.CorporationOf(mock.FirstCharacterID+7) {
+		t.Fatalf("unexpected character %+v", char)
+	}
+	corp, err := svc.GetCorporationInfo(ctx, char.CorporationID)
+	if err != nil {
+		t.Fatalf("corporation: %v", err)
+	}
+	if corp.Name == "" || corp.AllianceID == nil || *corp.AllianceID != mock.AllianceOf(char.CorporationID) {
+		t.Fatalf("unexpected corporation %+v", corp)
+	}
+
+	resolved, err := svc.ResolveNames(ctx, []int64{mock.FirstCharacterID + 7, char.CorporationID, 30000142})
+	if err != nil {
+		t.Fatalf("names: %v", err)
+	}
+	names := map[int64]string{}
+	for _, n := range resolved {
+		names[n.ID] = n.Name
+	}
+	if names[mock.FirstCharacterID+7] != char.Name || names[char.CorporationID] != corp.Name || names[30000142] != "Jita" {
+		t.Errorf("names do not match the entities: %v", names)
+	}
+
+	other, _ := newServices(2)
+	char2, err := other.GetCharacterInfo(ctx, mock.FirstCharacterID+7)
+	if err != nil {
+		t.Fatalf("character: %v", err)
+	}
+	if char2.Name == char.Name && char2.Birthday.Equal(char.Birthday) {
+		t.Error("expected another seed to generate another character")
+	}
+}
+
+func TestTransport_KillsInvolveEntity(t *testing.T) {
+	ctx := context.Background()
+	charID := int64(mock.FirstCharacterID + 3)
+	fetch := func() []model.FlattenedKillMail {
+		_, zk := newServices(1)
+		kills, err := zk.GetKillMailDataForMonth(ctx, &model.Params{Characters: []model.CharacterID{charID}}, 2024, 5)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return kills
+	}
+	kills := fetch()
+	if len(kills) == 0 {
+		t.Fatal("expected generated kills")
+	}
+	for _, km := range kills {
+		if km.KillMailTime.Year() != 2024 || km.KillMailTime.Month() != 5 {
+			t.Errorf("kill %d at %v, outside the requested month", km.KillMailID, km.KillMailTime)
+		}
+		involved := km.Victim.CharacterID == charID
+		for _, a := range km.Attackers {
+			involved = involved || a.CharacterID == charID
+		}
+		if !involved || km.TotalValue == 0 || km.Hash == "" {
+			t.Errorf("kill %d does not involve %d or lacks zKill data: %+v", km.KillMailID, charID, km)
+		}
+	}
+	if again := fetch(); !reflect.DeepEqual(kills, again) {
+		t.Error("expected the same seed to generate the same kills")
+	}
+}
+
+func TestTransport_UnknownEndpoint(t *testing.T) {
+	svc, _ := newServices(1)
+	if _, err := svc.GetSolarSystem(context.Background(), 1); err == nil {
+		t.Error("expected an error for an unknown system")
+	}
+}
@@ -0,0 +1,7 @@
+// Package monitor polls ESI for state that changes over time (corporation
+// membership, wars, structures, wallets, character locations, ...), compares
+// it with what was seen before and emits notify events for the differences.
+//
+// Each monitor can run on its own loop or register its checks with a shared
+// Poller, which runs every task from one goroutine with common spacing.
+package monitor
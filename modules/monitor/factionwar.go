@@ -0,0 +1,139 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/charts"
+	"github.com/guarzo/eveapi/modules/storage"
+)
+
+// snapshotKindFW is the storage.Snapshot kind for faction warfare systems.
+const snapshotKindFW = "fw_system"
+
+// FWSource lists faction warfare systems; esi.EsiService satisfies it.
+type FWSource interface {
+	GetFWSystems(ctx context.Context) ([]model.FWSystem, error)
+}
+
+// FWPoint is one recorded state of a faction warfare system.
+type FWPoint struct {
+	At                time.Time `json:"at"`
+	OccupierFactionID int64     `json:"occupier_faction_id"`
+	Contested         string    `json:"contested"`
+	ContestedPercent  float64   `json:"contested_percent"`
+}
+
+// FWTracker records faction warfare system state over time. A snapshot is
+// written for a system only when its occupier, contested state or
+// percentage changed since the last one.
+type FWTracker struct {
+	source FWSource
+	repo   storage.SnapshotRepository
+	now    func() time.Time
+}
+
+// NewFWTracker constructs an FWTracker.
+func NewFWTracker(source FWSource, repo storage.SnapshotRepository) *FWTracker {
+	return &FWTracker{source: source, repo: repo, now: time.Now}
+}
+
+// Check polls every FW system once and returns the systems whose state changed.
+func (t *FWTracker) Check(ctx context.Context) ([]model.FWSystem, error) {
+	systems, err := t.source.GetFWSystems(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := t.now()
+	var changed []model.FWSystem
+	var errs []error
+	for _, sys := range systems {
+		key := strconv.FormatInt(sys.SolarSystemID, 10)
+		point := FWPoint{
+			At:                now,
+			OccupierFactionID: sys.OccupierFactionID,
+			Contested:         sys.Contested,
+			ContestedPercent:  sys.ContestedPercent(),
+		}
+		prev, err := t.repo.Latest(ctx, snapshotKindFW, key)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			errs = append(errs, err)
+			continue
+		}
+		if prev != nil {
+			var last FWPoint
+			if json.Unmarshal(prev.Data, &last) == nil && sameFWState(last, point) {
+				continue
+			}
+		}
+		data, err := json.Marshal(point)
+		if err != nil {
+			return nil, err
+		}
+		if err := t.repo.Save(ctx, storage.Snapshot{Kind: snapshotKindFW, Key: key, At: now, Data: data}); err != nil {
+			errs = append(errs, fmt.Errorf("failed to save FW system %d: %w", sys.SolarSystemID, err))
+			continue
+		}
+		changed = append(changed, sys)
+	}
+	return changed, errors.Join(errs...)
+}
+
+// Run checks every interval until ctx is done.
+func (t *FWTracker) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	Poll(ctx, interval, func(ctx context.Context) error {
+		_, err := t.Check(ctx)
+		return err
+	}, onError)
+}
+
+// Register adds a poller task that runs Check on schedule, or whenever ESI's
+// cached faction warfare data expires if schedule is nil.
+func (t *FWTracker) Register(p *Poller, schedule Schedule) {
+	p.Add("factionwar", schedule, func(ctx context.Context) error {
+		_, err := t.Check(ctx)
+		return err
+	})
+}
+
+// History returns the recorded states of a system in [start, end).
+func (t *FWTracker) History(ctx context.Context, systemID model.SystemID, start, end time.Time) ([]FWPoint, error) {
+	snaps, err := t.repo.History(ctx, snapshotKindFW, strconv.FormatInt(systemID, 10), start, end)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]FWPoint, 0, len(snaps))
+	for _, s := range snaps {
+		var p FWPoint
+		if err := json.Unmarshal(s.Data, &p); err != nil {
+			return nil, fmt.Errorf("failed to decode FW snapshot: %w", err)
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// FWHistoryDataset turns points into a chart Dataset with the contested
+// percentage and occupying faction over time, labeled by timestamp.
+func FWHistoryDataset(points []FWPoint) charts.Dataset {
+	ds := charts.Dataset{Datasets: []charts.Series{
+		{Label: "Contested %"},
+		{Label: "Occupier faction"},
+	}}
+	for _, p := range points {
+		ds.Labels = append(ds.Labels, p.At.UTC().Format("2006-01-02 15:04"))
+		ds.Datasets[0].Data = append(ds.Datasets[0].Data, p.ContestedPercent)
+		ds.Datasets[1].Data = append(ds.Datasets[1].Data, float64(p.OccupierFactionID))
+	}
+	return ds
+}
+
+func sameFWState(a, b FWPoint) bool {
+	return a.OccupierFactionID == b.OccupierFactionID && a.Contested == b.Contested &&
+		a.ContestedPercent == b.ContestedPercent
+}
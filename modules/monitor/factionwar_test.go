@@ -0,0 +1,49 @@
+package monitor_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/monitor"
+	"github.com/guarzo/eveapi/modules/storage"
+)
+
+type fwSource []model.FWSystem
+
+func (f *fwSource) GetFWSystems(ctx context.Context) ([]model.FWSystem, error) {
+	return *f, nil
+}
+
+func TestFWTracker(t *testing.T) {
+	src := &fwSource{
+		{SolarSystemID: 30002813, OccupierFactionID: 500001, Contested: "contested", VictoryPoints: 1500, VictoryPointsThreshold: 3000},
+		{SolarSystemID: 30003067, OccupierFactionID: 500004, Contested: "uncontested", VictoryPointsThreshold: 3000},
+	}
+	tr := monitor.NewFWTracker(src, storage.NewMemorySnapshotRepository())
+	ctx := context.Background()
+
+	if changed, err := tr.Check(ctx); err != nil || len(changed) != 2 {
+		t.Fatalf("expected both systems recorded initially, got %v, %v", changed, err)
+	}
+	if changed, _ := tr.Check(ctx); len(changed) != 0 {
+		t.Errorf("expected no changes, got %v", changed)
+	}
+	(*src)[0].VictoryPoints = 2400
+	if changed, _ := tr.Check(ctx); len(changed) != 1 || changed[0].SolarSystemID != 30002813 {
+		t.Errorf("expected Tama to change, got %v", changed)
+	}
+
+	hist, err := tr.History(ctx, 30002813, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hist) != 2 || hist[0].ContestedPercent != 50 || hist[1].ContestedPercent != 80 {
+		t.Errorf("unexpected history: %+v", hist)
+	}
+	ds := monitor.FWHistoryDataset(hist)
+	if len(ds.Labels) != 2 || ds.Datasets[0].Data[1] != 80 {
+		t.Errorf("unexpected dataset: %+v", ds)
+	}
+}
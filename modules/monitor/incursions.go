@@ -0,0 +1,159 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/notify"
+)
+
+// IncursionSource is the part of esi.EsiService the incursion tracker needs.
+type IncursionSource interface {
+	GetIncursions(ctx context.Context) ([]model.Incursion, error)
+	GetConstellation(ctx context.Context, constellationID int64) (*model.Constellation, error)
+	ResolveNames(ctx context.Context, ids []int64) ([]model.UniverseName, error)
+}
+
+// IncursionView is an incursion expanded for display.
+type IncursionView struct {
+	model.Incursion
+	ConstellationName string           `json:"constellation_name"`
+	StagingSystemName string           `json:"staging_system_name"`
+	Systems           []int64          `json:"systems"`          // every system in the constellation
+	SystemNames       map[int64]string `json:"system_names"`     // names of Systems
+	AffectedTracked   []int64          `json:"affected_tracked"` // tracked systems in the constellation
+}
+
+// IncursionTracker expands incursions into their constellation's systems and
+// flags tracked (home/staging) systems that are affected. A notifier, if
+// set, receives an EventIncursion when a tracked system first becomes affected.
+type IncursionTracker struct {
+	source   IncursionSource
+	notifier notify.Notifier
+	tracked  map[int64]bool
+	now      func() time.Time
+
+	mu       sync.RWMutex
+	current  []IncursionView
+	affected map[int64]bool
+}
+
+// NewIncursionTracker constructs an IncursionTracker. notifier may be nil.
+func NewIncursionTracker(source IncursionSource, notifier notify.Notifier, trackedSystems []int64) *IncursionTracker {
+	t := &IncursionTracker{
+		source:   source,
+		notifier: notifier,
+		tracked:  make(map[int64]bool, len(trackedSystems)),
+		now:      time.Now,
+		affected: make(map[int64]bool),
+	}
+	for _, id := range trackedSystems {
+		t.tracked[id] = true
+	}
+	return t
+}
+
+// Current returns the incursions from the last refresh.
+func (t *IncursionTracker) Current() []IncursionView {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return append([]IncursionView(nil), t.current...)
+}
+
+// Refresh fetches the current incursions, expands and names them.
+func (t *IncursionTracker) Refresh(ctx context.Context) ([]IncursionView, error) {
+	incursions, err := t.source.GetIncursions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]IncursionView, 0, len(incursions))
+	var nameIDs []int64
+	for _, inc := range incursions {
+		c, err := t.source.GetConstellation(ctx, inc.ConstellationID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to expand constellation %d: %w", inc.ConstellationID, err)
+		}
+		v := IncursionView{Incursion: inc, ConstellationName: c.Name, Systems: c.Systems}
+		for _, sys := range c.Systems {
+			if t.tracked[sys] {
+				v.AffectedTracked = append(v.AffectedTracked, sys)
+			}
+		}
+		nameIDs = append(nameIDs, c.Systems...)
+		nameIDs = append(nameIDs, inc.StagingSolarSystemID)
+		views = append(views, v)
+	}
+
+	names := make(map[int64]string)
+	if len(nameIDs) > 0 {
+		resolved, err := t.source.ResolveNames(ctx, nameIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range resolved {
+			names[n.ID] = n.Name
+		}
+	}
+	for i := range views {
+		v := &views[i]
+		v.StagingSystemName = names[v.StagingSolarSystemID]
+		v.SystemNames = make(map[int64]string, len(v.Systems))
+		for _, sys := range v.Systems {
+			v.SystemNames[sys] = names[sys]
+		}
+	}
+	sort.Slice(views, func(i, j int) bool { return views[i].ConstellationID < views[j].ConstellationID })
+
+	var events []notify.Event
+	t.mu.Lock()
+	now := t.now()
+	affected := make(map[int64]bool)
+	for _, v := range views {
+		for _, sys := range v.AffectedTracked {
+			affected[sys] = true
+			if !t.affected[sys] {
+				events = append(events, notify.Event{
+					Type:    notify.EventIncursion,
+					Time:    now,
+					Title:   fmt.Sprintf("Incursion in %s", v.ConstellationName),
+					Message: fmt.Sprintf("Tracked system %s is in an incursion (%s)", v.SystemNames[sys], v.State),
+					Payload: v,
+				})
+			}
+		}
+	}
+	t.affected = affected
+	t.current = views
+	t.mu.Unlock()
+
+	if t.notifier != nil {
+		for _, ev := range events {
+			if err := t.notifier.Notify(ctx, ev); err != nil {
+				return views, err
+			}
+		}
+	}
+	return views, nil
+}
+
+// Run refreshes every interval until ctx is done.
+func (t *IncursionTracker) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	Poll(ctx, interval, func(ctx context.Context) error {
+		_, err := t.Refresh(ctx)
+		return err
+	}, onError)
+}
+
+// Register adds a poller task that runs Refresh on schedule, or whenever
+// ESI's cached incursion list expires if schedule is nil.
+func (t *IncursionTracker) Register(p *Poller, schedule Schedule) {
+	p.Add("incursions", schedule, func(ctx context.Context) error {
+		_, err := t.Refresh(ctx)
+		return err
+	})
+}
@@ -0,0 +1,58 @@
+package monitor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/monitor"
+	"github.com/guarzo/eveapi/modules/notify"
+)
+
+type incursionSource struct{}
+
+func (incursionSource) GetIncursions(ctx context.Context) ([]model.Incursion, error) {
+	return []model.Incursion{{ConstellationID: 20000001, StagingSolarSystemID: 30000001, State: "established"}}, nil
+}
+func (incursionSource) GetConstellation(ctx context.Context, id int64) (*model.Constellation, error) {
+	return &model.Constellation{ConstellationID: id, Name: "San Matar", Systems: []int64{30000001, 30000002, 30000003}}, nil
+}
+func (incursionSource) ResolveNames(ctx context.Context, ids []int64) ([]model.UniverseName, error) {
+	names := map[int64]string{30000001: "Tanoo", 30000002: "Lashesih", 30000003: "Akpivem"}
+	var out []model.UniverseName
+	for _, id := range ids {
+		out = append(out, model.UniverseName{ID: id, Name: names[id]})
+	}
+	return out, nil
+}
+
+func TestIncursionTracker(t *testing.T) {
+	var events []notify.Event
+	n := notify.NotifierFunc(func(ctx context.Context, ev notify.Event) error {
+		events = append(events, ev)
+		return nil
+	})
+	tr := monitor.NewIncursionTracker(incursionSource{}, n, []int64{30000002, 30009999})
+
+	views, err := tr.Refresh(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(views) != 1 || views[0].StagingSystemName != "Tanoo" || len(views[0].Systems) != 3 {
+		t.Fatalf("unexpected views: %+v", views)
+	}
+	if len(views[0].AffectedTracked) != 1 || views[0].AffectedTracked[0] != 30000002 {
+		t.Errorf("expected Lashesih to be flagged, got %v", views[0].AffectedTracked)
+	}
+	if len(events) != 1 || events[0].Type != notify.EventIncursion {
+		t.Errorf("expected one incursion event, got %+v", events)
+	}
+
+	_, _ = tr.Refresh(context.Background())
+	if len(events) != 1 {
+		t.Errorf("expected no repeat event, got %d", len(events))
+	}
+	if len(tr.Current()) != 1 {
+		t.Errorf("expected current incursion list to be kept")
+	}
+}
@@ -0,0 +1,223 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/storage"
+)
+
+// snapshotKindLocation is the storage.Snapshot kind for character locations.
+const snapshotKindLocation = "character_location"
+
+// CharacterLocationInterval is how long ESI caches a character's location
+// and ship.
+const CharacterLocationInterval = 5 * time.Second
+
+// LocationSource reads where a character is and what it flies; esi.EsiService
+// satisfies it.
+type LocationSource interface {
+	GetCharacterLocation(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (model.SystemID, error)
+	GetCharacterShip(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (*model.CharacterShip, error)
+}
+
+// LocationPoint is one recorded location of a character. It holds from At
+// until the next point of the same character.
+type LocationPoint struct {
+	At         time.Time    `json:"at"`
+	SystemID   int64        `json:"system_id"`
+	ShipTypeID model.TypeID `json:"ship_type_id"`
+	ShipItemID int64        `json:"ship_item_id"`
+	ShipName   string       `json:"ship_name"`
+}
+
+// LocationTracker records the solar system and ship of characters over time.
+// A snapshot is written for a character only when its system or ship changed
+// since the last one.
+type LocationTracker struct {
+	source LocationSource
+	tokens TokenSource
+	repo   storage.SnapshotRepository
+	now    func() time.Time
+}
+
+// NewLocationTracker constructs a LocationTracker. tokens is called with
+// character IDs and must return tokens with the esi-location scopes.
+func NewLocationTracker(source LocationSource, tokens TokenSource, repo storage.SnapshotRepository) *LocationTracker {
+	return &LocationTracker{source: source, tokens: tokens, repo: repo, now: time.Now}
+}
+
+// Check samples the location and ship of characterID once. It returns the
+// current point and whether it was recorded as a change.
+func (t *LocationTracker) Check(ctx context.Context, characterID model.CharacterID) (*LocationPoint, bool, error) {
+	token, err := t.tokens(ctx, characterID)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get token for character %d: %w", characterID, err)
+	}
+	systemID, err := t.source.GetCharacterLocation(ctx, characterID, token)
+	if err != nil {
+		return nil, false, err
+	}
+	ship, err := t.source.GetCharacterShip(ctx, characterID, token)
+	if err != nil {
+		return nil, false, err
+	}
+	point := &LocationPoint{At: t.now(), SystemID: systemID}
+	if ship != nil {
+		point.ShipTypeID, point.ShipItemID, point.ShipName = ship.ShipTypeID, ship.ShipItemID, ship.ShipName
+	}
+
+	key := strconv.FormatInt(characterID, 10)
+	prev, err := t.repo.Latest(ctx, snapshotKindLocation, key)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return point, false, fmt.Errorf("failed to load location snapshot: %w", err)
+	}
+	if prev != nil {
+		var last LocationPoint
+		if json.Unmarshal(prev.Data, &last) == nil && sameLocation(last, *point) {
+			return point, false, nil
+		}
+	}
+	data, err := json.Marshal(point)
+	if err != nil {
+		return point, false, err
+	}
+	if err := t.repo.Save(ctx, storage.Snapshot{Kind: snapshotKindLocation, Key: key, At: point.At, Data: data}); err != nil {
+		return point, false, fmt.Errorf("failed to save location of character %d: %w", characterID, err)
+	}
+	return point, true, nil
+}
+
+// Run checks every character each interval until ctx is done.
+func (t *LocationTracker) Run(ctx context.Context, interval time.Duration, characterIDs []model.CharacterID, onError func(error)) {
+	Poll(ctx, interval, func(ctx context.Context) error {
+		var errs []error
+		for _, id := range characterIDs {
+			if _, _, err := t.Check(ctx, id); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}, onError)
+}
+
+// Register adds a poller task per character that runs Check on schedule,
+// or whenever ESI's cached location expires if schedule is nil.
+func (t *LocationTracker) Register(p *Poller, schedule Schedule, characterIDs ...model.CharacterID) {
+	for _, id := range characterIDs {
+		p.Add(fmt.Sprintf("location:%d", id), schedule, func(ctx context.Context) error {
+			_, _, err := t.Check(ctx, id)
+			return err
+		})
+	}
+}
+
+// History returns the recorded points of a character in [start, end).
+func (t *LocationTracker) History(ctx context.Context, characterID model.CharacterID, start, end time.Time) ([]LocationPoint, error) {
+	snaps, err := t.repo.History(ctx, snapshotKindLocation, strconv.FormatInt(characterID, 10), start, end)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]LocationPoint, 0, len(snaps))
+	for _, s := range snaps {
+		var p LocationPoint
+		if err := json.Unmarshal(s.Data, &p); err != nil {
+			return nil, fmt.Errorf("failed to decode location snapshot: %w", err)
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// At returns where a character was at the given time: the last point
+// recorded at or before it, or storage.ErrNotFound if there is none.
+func (t *LocationTracker) At(ctx context.Context, characterID model.CharacterID, at time.Time) (*LocationPoint, error) {
+	points, err := t.History(ctx, characterID, time.Time{}, at.Add(time.Nanosecond))
+	if err != nil {
+		return nil, err
+	}
+	if len(points) == 0 {
+		return nil, storage.ErrNotFound
+	}
+	return &points[len(points)-1], nil
+}
+
+// DwellTimes returns how long a character spent in each solar system during
+// [start, end). A zero end means now.
+func (t *LocationTracker) DwellTimes(ctx context.Context, characterID model.CharacterID, start, end time.Time) ([]SystemDwell, error) {
+	if end.IsZero() {
+		end = t.now()
+	}
+	points, err := t.History(ctx, characterID, time.Time{}, end)
+	if err != nil {
+		return nil, err
+	}
+	return DwellTimes(points, start, end), nil
+}
+
+// SystemDwell is the time spent in one solar system.
+type SystemDwell struct {
+	SystemID int64         `json:"system_id"`
+	Duration time.Duration `json:"duration"`
+	Visits   int           `json:"visits"`
+}
+
+// DwellTimes totals the time covered by points, ordered by time, in each
+// system during [start, end). Each point lasts until the next one and the
+// last until end. Consecutive points in the same system, e.g. after a ship
+// change, count as one visit. The result is ordered by duration, longest
+// first, then by system ID.
+func DwellTimes(points []LocationPoint, start, end time.Time) []SystemDwell {
+	bySystem := make(map[int64]*SystemDwell)
+	var prevSystem int64
+	for i, p := range points {
+		from, to := p.At, end
+		if i+1 < len(points) {
+			to = points[i+1].At
+		}
+		if from.Before(start) {
+			from = start
+		}
+		if to.After(end) {
+			to = end
+		}
+		if !to.After(from) {
+			prevSystem = p.SystemID
+			continue
+		}
+		d, ok := bySystem[p.SystemID]
+		if !ok {
+			d = &SystemDwell{SystemID: p.SystemID}
+			bySystem[p.SystemID] = d
+		}
+		d.Duration += to.Sub(from)
+		if d.Visits == 0 || prevSystem != p.SystemID {
+			d.Visits++
+		}
+		prevSystem = p.SystemID
+	}
+	out := make([]SystemDwell, 0, len(bySystem))
+	for _, d := range bySystem {
+		out = append(out, *d)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Duration != out[j].Duration {
+			return out[i].Duration > out[j].Duration
+		}
+		return out[i].SystemID < out[j].SystemID
+	})
+	return out
+}
+
+func sameLocation(a, b LocationPoint) bool {
+	return a.SystemID == b.SystemID && a.ShipItemID == b.ShipItemID &&
+		a.ShipTypeID == b.ShipTypeID && a.ShipName == b.ShipName
+}
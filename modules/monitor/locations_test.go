@@ -0,0 +1,84 @@
+package monitor_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/monitor"
+	"github.com/guarzo/eveapi/modules/storage"
+)
+
+type locationSource struct {
+	system int64
+	ship   model.CharacterShip
+}
+
+func (l *locationSource) GetCharacterLocation(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (model.SystemID, error) {
+	return l.system, nil
+}
+
+func (l *locationSource) GetCharacterShip(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (*model.CharacterShip, error) {
+	ship := l.ship
+	return &ship, nil
+}
+
+func TestLocationTracker(t *testing.T) {
+	src := &locationSource{system: 30000142, ship: model.CharacterShip{ShipItemID: 1, ShipTypeID: 587, ShipName: "Rifter"}}
+	tr := monitor.NewLocationTracker(src, noToken, storage.NewMemorySnapshotRepository())
+	ctx := context.Background()
+
+	if _, err := tr.At(ctx, 90000001, time.Now()); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("expected ErrNotFound before any check, got %v", err)
+	}
+	if _, changed, err := tr.Check(ctx, 90000001); err != nil || !changed {
+		t.Fatalf("expected first location recorded, got %v, %v", changed, err)
+	}
+	if _, changed, _ := tr.Check(ctx, 90000001); changed {
+		t.Error("expected unchanged location to be skipped")
+	}
+	time.Sleep(time.Millisecond)
+	src.system = 30002187
+	if _, changed, _ := tr.Check(ctx, 90000001); !changed {
+		t.Error("expected system change to be recorded")
+	}
+
+	hist, err := tr.History(ctx, 90000001, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hist) != 2 || hist[0].SystemID != 30000142 || hist[1].ShipName != "Rifter" {
+		t.Fatalf("unexpected history: %+v", hist)
+	}
+	p, err := tr.At(ctx, 90000001, hist[1].At.Add(-time.Nanosecond))
+	if err != nil || p.SystemID != 30000142 {
+		t.Errorf("expected Jita just before the jump, got %+v, %v", p, err)
+	}
+}
+
+func TestDwellTimes(t *testing.T) {
+	t0 := time.Date(2026, 10, 13, 0, 0, 0, 0, time.UTC)
+	points := []monitor.LocationPoint{
+		{At: t0.Add(-time.Hour), SystemID: 1},
+		{At: t0.Add(2 * time.Hour), SystemID: 2},
+		{At: t0.Add(3 * time.Hour), SystemID: 2, ShipItemID: 7},
+		{At: t0.Add(5 * time.Hour), SystemID: 1},
+	}
+	got := monitor.DwellTimes(points, t0, t0.Add(6*time.Hour))
+	want := []monitor.SystemDwell{
+		{SystemID: 1, Duration: 3 * time.Hour, Visits: 2},
+		{SystemID: 2, Duration: 3 * time.Hour, Visits: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("dwell %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
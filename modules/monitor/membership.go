@@ -0,0 +1,155 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/notify"
+	"github.com/guarzo/eveapi/modules/storage"
+)
+
+// snapshotKindMembers is the storage.Snapshot kind for member lists.
+const snapshotKindMembers = "corp_members"
+
+// MemberSource lists a corporation's members; esi.EsiService satisfies it.
+type MemberSource interface {
+	GetCorporationMembers(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]int64, error)
+}
+
+// TokenSource returns a token with the scopes needed for a corporation.
+type TokenSource func(ctx context.Context, corporationID model.CorporationID) (*oauth2.Token, error)
+
+// MembershipDiff is the change between two member snapshots.
+type MembershipDiff struct {
+	CorporationID int64
+	Joined        []int64
+	Left          []int64
+	// Baseline is true when no earlier snapshot existed, so nothing was diffed.
+	Baseline bool
+}
+
+// MembershipTracker snapshots corporation member lists and emits
+// EventMemberJoined / EventMemberLeft for the differences.
+type MembershipTracker struct {
+	source   MemberSource
+	tokens   TokenSource
+	repo     storage.SnapshotRepository
+	notifier notify.Notifier
+	now      func() time.Time
+}
+
+// NewMembershipTracker constructs a MembershipTracker. notifier may be nil.
+func NewMembershipTracker(source MemberSource, tokens TokenSource, repo storage.SnapshotRepository, notifier notify.Notifier) *MembershipTracker {
+	return &MembershipTracker{source: source, tokens: tokens, repo: repo, notifier: notifier, now: time.Now}
+}
+
+// Check takes a new snapshot of corporationID, diffs it against the previous
+// one and notifies about joins and departures. The first snapshot of a
+// corporation only records a baseline.
+func (t *MembershipTracker) Check(ctx context.Context, corporationID model.CorporationID) (*MembershipDiff, error) {
+	token, err := t.tokens(ctx, corporationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token for corporation %d: %w", corporationID, err)
+	}
+	members, err := t.source.GetCorporationMembers(ctx, corporationID, token)
+	if err != nil {
+		return nil, err
+	}
+
+	key := strconv.FormatInt(corporationID, 10)
+	diff := &MembershipDiff{CorporationID: corporationID}
+	prev, err := t.repo.Latest(ctx, snapshotKindMembers, key)
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		diff.Baseline = true
+	case err != nil:
+		return nil, fmt.Errorf("failed to load member snapshot: %w", err)
+	default:
+		var before []int64
+		if err := json.Unmarshal(prev.Data, &before); err != nil {
+			return nil, fmt.Errorf("failed to decode member snapshot: %w", err)
+		}
+		diff.Joined, diff.Left = diffIDs(before, members)
+	}
+
+	now := t.now()
+	data, err := json.Marshal(members)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.repo.Save(ctx, storage.Snapshot{Kind: snapshotKindMembers, Key: key, At: now, Data: data}); err != nil {
+		return nil, fmt.Errorf("failed to save member snapshot: %w", err)
+	}
+
+	if t.notifier != nil {
+		var errs []error
+		if len(diff.Joined) > 0 {
+			errs = append(errs, t.notifier.Notify(ctx, notify.MemberJoinedEvent(now,
+				notify.MembershipChange{CorporationID: corporationID, CharacterIDs: diff.Joined})))
+		}
+		if len(diff.Left) > 0 {
+			errs = append(errs, t.notifier.Notify(ctx, notify.MemberLeftEvent(now,
+				notify.MembershipChange{CorporationID: corporationID, CharacterIDs: diff.Left})))
+		}
+		if err := errors.Join(errs...); err != nil {
+			return diff, err
+		}
+	}
+	return diff, nil
+}
+
+// Run checks every corporation each interval until ctx is done.
+func (t *MembershipTracker) Run(ctx context.Context, interval time.Duration, corporationIDs []model.CorporationID, onError func(error)) {
+	Poll(ctx, interval, func(ctx context.Context) error {
+		var errs []error
+		for _, id := range corporationIDs {
+			if _, err := t.Check(ctx, id); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}, onError)
+}
+
+// Register adds a poller task per corporation that runs Check on schedule,
+// or whenever ESI's cached member list expires if schedule is nil.
+func (t *MembershipTracker) Register(p *Poller, schedule Schedule, corporationIDs ...model.CorporationID) {
+	for _, id := range corporationIDs {
+		p.Add(fmt.Sprintf("membership:%d", id), schedule, func(ctx context.Context) error {
+			_, err := t.Check(ctx, id)
+			return err
+		})
+	}
+}
+
+// diffIDs returns the IDs only in after (added) and only in before (removed),
+// both sorted.
+func diffIDs(before, after []int64) (added, removed []int64) {
+	inBefore := make(map[int64]bool, len(before))
+	for _, id := range before {
+		inBefore[id] = true
+	}
+	inAfter := make(map[int64]bool, len(after))
+	for _, id := range after {
+		inAfter[id] = true
+		if !inBefore[id] {
+			added = append(added, id)
+		}
+	}
+	for _, id := range before {
+		if !inAfter[id] {
+			removed = append(removed, id)
+		}
+	}
+	sort.Slice(added, func(i, j int) bool { return added[i] < added[j] })
+	sort.Slice(removed, func(i, j int) bool { return removed[i] < removed[j] })
+	return added, removed
+}
@@ -0,0 +1,55 @@
+package monitor_test
+
+import (
+	"context"
+	"testing"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/monitor"
+	"github.com/guarzo/eveapi/modules/notify"
+	"github.com/guarzo/eveapi/modules/storage"
+)
+
+type memberSource [][]int64
+
+func (m *memberSource) GetCorporationMembers(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]int64, error) {
+	next := (*m)[0]
+	*m = (*m)[1:]
+	return next, nil
+}
+
+func noToken(ctx context.Context, corporationID model.CorporationID) (*oauth2.Token, error) {
+	return &oauth2.Token{AccessToken: "x"}, nil
+}
+
+func TestMembershipTracker(t *testing.T) {
+	src := &memberSource{{1, 2, 3}, {2, 3, 4, 5}}
+	var events []notify.Event
+	n := notify.NotifierFunc(func(ctx context.Context, ev notify.Event) error {
+		events = append(events, ev)
+		return nil
+	})
+	tr := monitor.NewMembershipTracker(src, noToken, storage.NewMemorySnapshotRepository(), n)
+
+	ctx := context.Background()
+	first, err := tr.Check(ctx, 98000001)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !first.Baseline || len(events) != 0 {
+		t.Errorf("expected silent baseline, got %+v and %d events", first, len(events))
+	}
+
+	diff, err := tr.Check(ctx, 98000001)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(diff.Joined) != 2 || diff.Joined[0] != 4 || len(diff.Left) != 1 || diff.Left[0] != 1 {
+		t.Errorf("unexpected diff: %+v", diff)
+	}
+	if len(events) != 2 || events[0].Type != notify.EventMemberJoined || events[1].Type != notify.EventMemberLeft {
+		t.Errorf("unexpected events: %+v", events)
+	}
+}
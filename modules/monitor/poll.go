@@ -0,0 +1,23 @@
+package monitor
+
+import (
+	"context"
+	"time"
+)
+
+// Poll runs check immediately and then every interval until ctx is done.
+// Errors are passed to onError, if non-nil, and do not stop polling.
+func Poll(ctx context.Context, interval time.Duration, check func(ctx context.Context) error, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := check(ctx); err != nil && ctx.Err() == nil && onError != nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
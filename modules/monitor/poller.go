@@ -0,0 +1,301 @@
+package monitor
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/guarzo/eveapi/common"
+)
+
+// Schedule returns when a task should next run, given when its last run
+// finished and the Expires time its fetches reported (zero if none).
+type Schedule func(now, expires time.Time) time.Time
+
+// Every runs a task at a fixed interval.
+func Every(d time.Duration) Schedule {
+	return func(now, _ time.Time) time.Time {
+		return now.Add(d)
+	}
+}
+
+// UntilExpires runs a task again once the data it fetched expires, or after
+// fallback if no Expires time was reported.
+func UntilExpires(fallback time.Duration) Schedule {
+	return func(now, expires time.Time) time.Time {
+		if expires.After(now) {
+			return expires
+		}
+		return now.Add(fallback)
+	}
+}
+
+// TaskFunc is one run of a polling task. ESI requests made with ctx report
+// their Expires header through common.RecordExpires; other fetches may call
+// it themselves.
+type TaskFunc func(ctx context.Context) error
+
+// task is a registered TaskFunc and its schedule.
+type task struct {
+	name     string
+	schedule Schedule
+	fn       TaskFunc
+	next     time.Time
+}
+
+// Defaults for NewPoller.
+const (
+	// DefaultJitter is the upper bound of the random delay added to each
+	// scheduled run, so tasks sharing an expiry do not all fire at once.
+	DefaultJitter = 5 * time.Second
+	// DefaultFallbackInterval is how often tasks registered without a
+	// schedule run when their fetches report no Expires time.
+	DefaultFallbackInterval = 5 * time.Minute
+)
+
+// PollerOption customizes a Poller.
+type PollerOption func(*Poller)
+
+// WithSpacing sets the minimum time between the start of two task runs,
+// shared by every task of the poller.
+func WithSpacing(d time.Duration) PollerOption {
+	return func(p *Poller) {
+		p.spacing = d
+	}
+}
+
+// WithJitter sets the upper bound of the random delay added to each
+// scheduled run (DefaultJitter by default, 0 to disable).
+func WithJitter(d time.Duration) PollerOption {
+	return func(p *Poller) {
+		p.jitter = d
+	}
+}
+
+// WithFallbackInterval sets how often tasks registered with a nil schedule
+// run when no Expires time is known.
+func WithFallbackInterval(d time.Duration) PollerOption {
+	return func(p *Poller) {
+		p.fallback = d
+	}
+}
+
+// WithTaskErrorHandler sets a function receiving the errors of failed runs.
+// Failed tasks are rescheduled as usual.
+func WithTaskErrorHandler(fn func(name string, err error)) PollerOption {
+	return func(p *Poller) {
+		p.onError = fn
+	}
+}
+
+// Poller runs registered tasks on their schedules from a single goroutine, one
+// at a time, so that every monitor it drives shares one request budget. A
+// task never runs again before the Expires time its last run reported, even
+// if its schedule asks for an earlier run, so polling stays within ESI's
+// server-side cache.
+type Poller struct {
+	spacing  time.Duration
+	jitter   time.Duration
+	fallback time.Duration
+	onError  func(name string, err error)
+	now      func() time.Time
+	life     common.Lifecycle
+
+	mu    sync.Mutex
+	tasks map[string]*task
+	wake  chan struct{}
+}
+
+// NewPoller constructs an empty Poller.
+func NewPoller(opts ...PollerOption) *Poller {
+	p := &Poller{
+		jitter:   DefaultJitter,
+		fallback: DefaultFallbackInterval,
+		now:      time.Now,
+		tasks:    make(map[string]*task),
+		wake:     make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Add registers fn under name, replacing any task with the same name. The
+// task first runs as soon as the poller is free. A nil schedule means
+// UntilExpires with the poller's fallback interval.
+func (p *Poller) Add(name string, schedule Schedule, fn TaskFunc) {
+	p.mu.Lock()
+	if schedule == nil {
+		schedule = UntilExpires(p.fallback)
+	}
+	p.tasks[name] = &task{name: name, schedule: schedule, fn: fn, next: p.now()}
+	p.mu.Unlock()
+	p.signal()
+}
+
+// Remove unregisters the task with the given name.
+func (p *Poller) Remove(name string) {
+	p.mu.Lock()
+	delete(p.tasks, name)
+	p.mu.Unlock()
+	p.signal()
+}
+
+// Tasks returns the names of the registered tasks.
+func (p *Poller) Tasks() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	names := make([]string, 0, len(p.tasks))
+	for name := range p.tasks {
+		names = append(names, name)
+	}
+	return names
+}
+
+func (p *Poller) signal() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run executes due tasks until ctx is done or the poller is closed.
+func (p *Poller) Run(ctx context.Context) {
+	ctx, stop, done := p.life.Begin(ctx)
+	defer done()
+	var lastStart time.Time
+	for {
+		t, wait := p.nextDue()
+		if !lastStart.IsZero() {
+			if gap := p.spacing - p.now().Sub(lastStart); gap > wait {
+				wait = gap
+			}
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-stop:
+			timer.Stop()
+			return
+		case <-p.wake:
+			timer.Stop()
+			continue
+		case <-timer.C:
+		}
+		if t == nil || !p.registered(t) {
+			continue
+		}
+		select {
+		case <-stop: // closed while the timer fired
+			return
+		default:
+		}
+		lastStart = p.now()
+		p.runTask(ctx, t)
+	}
+}
+
+// Close stops Run once the task in flight has finished and waits for it to
+// return. A closed poller cannot be run again.
+func (p *Poller) Close() error {
+	return p.life.Close()
+}
+
+// Shutdown is Close, except that the task in flight is cancelled when ctx is
+// done before it finishes.
+func (p *Poller) Shutdown(ctx context.Context) error {
+	return p.life.Shutdown(ctx)
+}
+
+// idleWait is how long Run sleeps when no task is registered.
+const idleWait = time.Hour
+
+// nextDue returns the task that runs next and how long until it is due.
+func (p *Poller) nextDue() (*task, time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var next *task
+	for _, t := range p.tasks {
+		if next == nil || t.next.Before(next.next) {
+			next = t
+		}
+	}
+	if next == nil {
+		return nil, idleWait
+	}
+	wait := next.next.Sub(p.now())
+	if wait < 0 {
+		wait = 0
+	}
+	return next, wait
+}
+
+func (p *Poller) registered(t *task) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.tasks[t.name] == t
+}
+
+func (p *Poller) runTask(ctx context.Context, t *task) {
+	runCtx, expires := common.WithExpiresRecorder(ctx)
+	err := t.fn(runCtx)
+	if err != nil && ctx.Err() == nil && p.onError != nil {
+		p.onError(t.name, err)
+	}
+	p.mu.Lock()
+	t.next = p.nextRun(t.schedule, expires())
+	p.mu.Unlock()
+}
+
+// nextRun applies schedule, holds the result back to the reported expiry
+// and adds jitter. Callers hold p.mu.
+func (p *Poller) nextRun(schedule Schedule, expires time.Time) time.Time {
+	next := schedule(p.now(), expires)
+	if next.Before(expires) {
+		next = expires
+	}
+	if p.jitter > 0 {
+		next = next.Add(time.Duration(rand.Int63n(int64(p.jitter))))
+	}
+	return next
+}
+
+// Change is passed to a Watch callback when a fetched value differs from the
+// previous one. First is true for the initial fetch, which has no Previous.
+type Change[T any] struct {
+	Name     string
+	Previous T
+	Current  T
+	First    bool
+}
+
+// Watch registers a task that calls fetch on schedule and onChange whenever
+// the result differs from the previous one according to equal
+// (reflect.DeepEqual if nil). Failed fetches keep the previous value.
+func Watch[T any](p *Poller, name string, schedule Schedule, fetch func(ctx context.Context) (T, error),
+	equal func(a, b T) bool, onChange func(ctx context.Context, c Change[T]) error) {
+	if equal == nil {
+		equal = func(a, b T) bool { return reflect.DeepEqual(a, b) }
+	}
+	var (
+		prev T
+		seen bool
+	)
+	p.Add(name, schedule, func(ctx context.Context) error {
+		cur, err := fetch(ctx)
+		if err != nil {
+			return err
+		}
+		if seen && equal(prev, cur) {
+			return nil
+		}
+		c := Change[T]{Name: name, Previous: prev, Current: cur, First: !seen}
+		prev, seen = cur, true
+		return onChange(ctx, c)
+	})
+}
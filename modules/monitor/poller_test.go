@@ -0,0 +1,157 @@
+package monitor_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/modules/monitor"
+)
+
+func TestPoller_WatchReportsChanges(t *testing.T) {
+	values := []int{1, 1, 2, 2, 3}
+	var (
+		mu      sync.Mutex
+		calls   int
+		changes []monitor.Change[int]
+	)
+	p := monitor.NewPoller(monitor.WithJitter(0))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	monitor.Watch(p, "counter", monitor.Every(time.Millisecond), func(ctx context.Context) (int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		v := values[calls]
+		if calls < len(values)-1 {
+			calls++
+		} else {
+			cancel()
+		}
+		return v, nil
+	}, nil, func(ctx context.Context, c monitor.Change[int]) error {
+		mu.Lock()
+		defer mu.Unlock()
+		changes = append(changes, c)
+		return nil
+	})
+	p.Run(ctx)
+
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 changes, got %+v", changes)
+	}
+	if !changes[0].First || changes[0].Current != 1 {
+		t.Errorf("expected first change to be the baseline, got %+v", changes[0])
+	}
+	if changes[2].Previous != 2 || changes[2].Current != 3 {
+		t.Errorf("unexpected last change: %+v", changes[2])
+	}
+}
+
+func TestPoller_SpacingAndExpires(t *testing.T) {
+	p := monitor.NewPoller(monitor.WithSpacing(20*time.Millisecond), monitor.WithJitter(0))
+	var (
+		mu     sync.Mutex
+		starts []time.Time
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 90*time.Millisecond)
+	defer cancel()
+	for _, name := range []string{"a", "b"} {
+		p.Add(name, monitor.Every(0), func(ctx context.Context) error {
+			mu.Lock()
+			starts = append(starts, time.Now())
+			mu.Unlock()
+			return nil
+		})
+	}
+	p.Run(ctx)
+
+	if len(starts) < 2 || len(starts) > 5 {
+		t.Fatalf("expected runs to be spaced out, got %d runs", len(starts))
+	}
+	for i := 1; i < len(starts); i++ {
+		if gap := starts[i].Sub(starts[i-1]); gap < 15*time.Millisecond {
+			t.Errorf("runs %d and %d only %s apart", i-1, i, gap)
+		}
+	}
+
+	now := time.Now()
+	sched := monitor.UntilExpires(time.Minute)
+	if got := sched(now, now.Add(5*time.Minute)); !got.Equal(now.Add(5 * time.Minute)) {
+		t.Errorf("expected next run at expiry, got %s", got.Sub(now))
+	}
+	if got := sched(now, time.Time{}); !got.Equal(now.Add(time.Minute)) {
+		t.Errorf("expected fallback interval, got %s", got.Sub(now))
+	}
+}
+
+func TestPoller_RecordsExpires(t *testing.T) {
+	p := monitor.NewPoller()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	var runs int
+	p.Add("expiring", monitor.UntilExpires(time.Millisecond), func(runCtx context.Context) error {
+		runs++
+		common.RecordExpires(runCtx, time.Now().Add(time.Hour))
+		if runs > 1 {
+			cancel()
+		}
+		return nil
+	})
+	p.Run(ctx)
+	if runs != 1 {
+		t.Errorf("expected the reported expiry to delay the next run, got %d runs", runs)
+	}
+}
+
+func TestPoller_NeverRunsBeforeExpiry(t *testing.T) {
+	p := monitor.NewPoller(monitor.WithJitter(0))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	var runs int
+	p.Add("fixed", monitor.Every(time.Millisecond), func(runCtx context.Context) error {
+		runs++
+		common.RecordExpires(runCtx, time.Now().Add(time.Hour))
+		return nil
+	})
+	p.Run(ctx)
+	if runs != 1 {
+		t.Errorf("expected a fixed interval not to outrun the reported expiry, got %d runs", runs)
+	}
+}
+
+func TestPoller_CloseDrainsTaskInFlight(t *testing.T) {
+	var _ common.Closer = (*monitor.Poller)(nil)
+
+	p := monitor.NewPoller(monitor.WithJitter(0))
+	started, finished := make(chan struct{}), make(chan error, 1)
+	p.Add("slow", monitor.Every(time.Hour), func(ctx context.Context) error {
+		close(started)
+		select {
+		case <-time.After(30 * time.Millisecond):
+			finished <- nil
+		case <-ctx.Done():
+			finished <- ctx.Err()
+		}
+		return nil
+	})
+	returned := make(chan struct{})
+	go func() {
+		p.Run(context.Background())
+		close(returned)
+	}()
+	<-started
+
+	if err := p.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case <-returned:
+	default:
+		t.Fatal("expected Run to have returned when Close did")
+	}
+	if err := <-finished; err != nil {
+		t.Errorf("expected the task in flight to finish, got %v", err)
+	}
+}
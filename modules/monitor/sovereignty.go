@@ -0,0 +1,123 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/notify"
+)
+
+// CampaignSource lists sovereignty campaigns; esi.EsiService satisfies it.
+type CampaignSource interface {
+	GetSovereigntyCampaigns(ctx context.Context) ([]model.SovereigntyCampaign, error)
+}
+
+// SovereigntyMonitor watches campaigns defended by tracked alliances or in
+// tracked systems. Each matching campaign is reported once when first seen
+// (including those already scheduled at the first check) and once more when
+// its start time passes.
+type SovereigntyMonitor struct {
+	source    CampaignSource
+	notifier  notify.Notifier
+	alliances map[int64]bool
+	systems   map[int64]bool
+	now       func() time.Time
+
+	mu      sync.Mutex
+	known   map[int64]model.SovereigntyCampaign
+	started map[int64]bool
+}
+
+// NewSovereigntyMonitor constructs a SovereigntyMonitor. notifier may be nil.
+func NewSovereigntyMonitor(source CampaignSource, notifier notify.Notifier, alliances, systems []int64) *SovereigntyMonitor {
+	m := &SovereigntyMonitor{
+		source:    source,
+		notifier:  notifier,
+		alliances: make(map[int64]bool, len(alliances)),
+		systems:   make(map[int64]bool, len(systems)),
+		now:       time.Now,
+		known:     make(map[int64]model.SovereigntyCampaign),
+		started:   make(map[int64]bool),
+	}
+	for _, id := range alliances {
+		m.alliances[id] = true
+	}
+	for _, id := range systems {
+		m.systems[id] = true
+	}
+	return m
+}
+
+// Campaigns returns the matching campaigns from the last check.
+func (m *SovereigntyMonitor) Campaigns() []model.SovereigntyCampaign {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]model.SovereigntyCampaign, 0, len(m.known))
+	for _, c := range m.known {
+		out = append(out, c)
+	}
+	return out
+}
+
+// Check polls campaigns once and returns the events emitted.
+func (m *SovereigntyMonitor) Check(ctx context.Context) ([]notify.Event, error) {
+	campaigns, err := m.source.GetSovereigntyCampaigns(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	now := m.now()
+	var events []notify.Event
+	current := make(map[int64]model.SovereigntyCampaign)
+	for _, c := range campaigns {
+		if !m.alliances[c.DefenderID] && !m.systems[c.SolarSystemID] {
+			continue
+		}
+		current[c.CampaignID] = c
+		if _, seen := m.known[c.CampaignID]; !seen {
+			events = append(events, notify.SovTimerEvent(now, c))
+		}
+		if !now.Before(c.StartTime) && !m.started[c.CampaignID] {
+			m.started[c.CampaignID] = true
+			events = append(events, notify.SovCampaignStartedEvent(now, c))
+		}
+	}
+	// forget campaigns that have ended
+	for id := range m.started {
+		if _, ok := current[id]; !ok {
+			delete(m.started, id)
+		}
+	}
+	m.known = current
+	m.mu.Unlock()
+
+	if m.notifier == nil {
+		return events, nil
+	}
+	var errs []error
+	for _, ev := range events {
+		errs = append(errs, m.notifier.Notify(ctx, ev))
+	}
+	return events, errors.Join(errs...)
+}
+
+// Run checks every interval until ctx is done.
+func (m *SovereigntyMonitor) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	Poll(ctx, interval, func(ctx context.Context) error {
+		_, err := m.Check(ctx)
+		return err
+	}, onError)
+}
+
+// Register adds a poller task that runs Check on schedule, or whenever ESI's
+// cached sovereignty data expires if schedule is nil.
+func (m *SovereigntyMonitor) Register(p *Poller, schedule Schedule) {
+	p.Add("sovereignty", schedule, func(ctx context.Context) error {
+		_, err := m.Check(ctx)
+		return err
+	})
+}
@@ -0,0 +1,45 @@
+package monitor_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/monitor"
+	"github.com/guarzo/eveapi/modules/notify"
+)
+
+type campaignSource []model.SovereigntyCampaign
+
+func (c *campaignSource) GetSovereigntyCampaigns(ctx context.Context) ([]model.SovereigntyCampaign, error) {
+	return *c, nil
+}
+
+func TestSovereigntyMonitor(t *testing.T) {
+	src := &campaignSource{
+		{CampaignID: 1, DefenderID: 99000001, SolarSystemID: 30000001, EventType: "ihub_defense", StartTime: time.Now().Add(time.Hour)},
+		{CampaignID: 2, DefenderID: 99000002, SolarSystemID: 30000002, EventType: "tcu_defense", StartTime: time.Now().Add(time.Hour)},
+		{CampaignID: 3, DefenderID: 99000003, SolarSystemID: 30000003, EventType: "tcu_defense", StartTime: time.Now().Add(-time.Minute)},
+	}
+	m := monitor.NewSovereigntyMonitor(src, nil, []int64{99000001}, []int64{30000003})
+	ctx := context.Background()
+
+	evs, err := m.Check(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// campaign 1: timer; campaign 3: timer + started; campaign 2 untracked
+	if len(evs) != 3 || evs[0].Type != notify.EventSovTimer || evs[2].Type != notify.EventSovCampaignStarted {
+		t.Fatalf("unexpected events: %+v", evs)
+	}
+
+	(*src)[0].StartTime = time.Now().Add(-time.Second)
+	evs, _ = m.Check(ctx)
+	if len(evs) != 1 || evs[0].Type != notify.EventSovCampaignStarted {
+		t.Errorf("expected start of campaign 1 only, got %+v", evs)
+	}
+	if len(m.Campaigns()) != 2 {
+		t.Errorf("expected 2 tracked campaigns, got %d", len(m.Campaigns()))
+	}
+}
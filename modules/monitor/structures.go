@@ -0,0 +1,140 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/notify"
+)
+
+// DefaultFuelThreshold is how much fuel must remain before a structure is
+// reported as low.
+const DefaultFuelThreshold = 72 * time.Hour
+
+// StructureSource lists a corporation's structures; esi.EsiService satisfies it.
+type StructureSource interface {
+	GetCorporationStructures(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]model.CorporationStructure, error)
+}
+
+// StructureMonitor alerts when a structure's fuel runs low or it enters
+// reinforcement. Each condition is reported once when it starts; a
+// refuelled structure re-arms its fuel alert. Snoozed structures are
+// checked but not reported.
+type StructureMonitor struct {
+	source    StructureSource
+	tokens    TokenSource
+	notifier  notify.Notifier
+	threshold time.Duration
+	now       func() time.Time
+
+	mu         sync.Mutex
+	snoozed    map[int64]time.Time
+	lowFuel    map[int64]bool
+	reinforced map[int64]bool
+}
+
+// NewStructureMonitor constructs a StructureMonitor with DefaultFuelThreshold.
+// notifier may be nil.
+func NewStructureMonitor(source StructureSource, tokens TokenSource, notifier notify.Notifier) *StructureMonitor {
+	return &StructureMonitor{
+		source:     source,
+		tokens:     tokens,
+		notifier:   notifier,
+		threshold:  DefaultFuelThreshold,
+		now:        time.Now,
+		snoozed:    make(map[int64]time.Time),
+		lowFuel:    make(map[int64]bool),
+		reinforced: make(map[int64]bool),
+	}
+}
+
+// SetFuelThreshold changes how much remaining fuel triggers an alert.
+func (m *StructureMonitor) SetFuelThreshold(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.threshold = d
+}
+
+// Snooze silences alerts for a structure until the given time.
+func (m *StructureMonitor) Snooze(structureID int64, until time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.snoozed[structureID] = until
+}
+
+// Unsnooze re-enables alerts for a structure.
+func (m *StructureMonitor) Unsnooze(structureID int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.snoozed, structureID)
+}
+
+// Check polls a corporation's structures once and returns the events emitted.
+func (m *StructureMonitor) Check(ctx context.Context, corporationID model.CorporationID) ([]notify.Event, error) {
+	token, err := m.tokens(ctx, corporationID)
+	if err != nil {
+		return nil, err
+	}
+	structures, err := m.source.GetCorporationStructures(ctx, corporationID, token)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	now := m.now()
+	var events []notify.Event
+	for _, s := range structures {
+		low := s.FuelExpires != nil && s.FuelExpires.Sub(now) < m.threshold
+		reinforced := s.Reinforced()
+		snoozed := now.Before(m.snoozed[s.StructureID])
+
+		if low && !m.lowFuel[s.StructureID] && !snoozed {
+			events = append(events, notify.StructureFuelEvent(now, s))
+		}
+		if reinforced && !m.reinforced[s.StructureID] && !snoozed {
+			events = append(events, notify.StructureReinforcedEvent(now, s))
+		}
+		m.lowFuel[s.StructureID] = low
+		m.reinforced[s.StructureID] = reinforced
+	}
+	m.mu.Unlock()
+
+	if m.notifier != nil {
+		var errs []error
+		for _, ev := range events {
+			errs = append(errs, m.notifier.Notify(ctx, ev))
+		}
+		return events, errors.Join(errs...)
+	}
+	return events, nil
+}
+
+// Run checks every corporation each interval until ctx is done.
+func (m *StructureMonitor) Run(ctx context.Context, interval time.Duration, corporationIDs []model.CorporationID, onError func(error)) {
+	Poll(ctx, interval, func(ctx context.Context) error {
+		var errs []error
+		for _, id := range corporationIDs {
+			if _, err := m.Check(ctx, id); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}, onError)
+}
+
+// Register adds a poller task per corporation that runs Check on schedule,
+// or whenever ESI's cached structure list expires if schedule is nil.
+func (m *StructureMonitor) Register(p *Poller, schedule Schedule, corporationIDs ...model.CorporationID) {
+	for _, id := range corporationIDs {
+		p.Add(fmt.Sprintf("structures:%d", id), schedule, func(ctx context.Context) error {
+			_, err := m.Check(ctx, id)
+			return err
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package monitor_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/monitor"
+	"github.com/guarzo/eveapi/modules/notify"
+)
+
+type structureSource []model.CorporationStructure
+
+func (s *structureSource) GetCorporationStructures(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]model.CorporationStructure, error) {
+	return *s, nil
+}
+
+func TestStructureMonitor(t *testing.T) {
+	soon := time.Now().Add(24 * time.Hour)
+	later := time.Now().Add(30 * 24 * time.Hour)
+	src := &structureSource{
+		{StructureID: 1, Name: "Keepstar", FuelExpires: &soon, State: "shield_vulnerable"},
+		{StructureID: 2, Name: "Astrahus", FuelExpires: &later, State: "shield_vulnerable"},
+	}
+	m := monitor.NewStructureMonitor(src, noToken, nil)
+	ctx := context.Background()
+
+	evs, err := m.Check(ctx, 98000001)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(evs) != 1 || evs[0].Type != notify.EventStructureFuel {
+		t.Fatalf("expected one fuel alert, got %+v", evs)
+	}
+	if evs, _ := m.Check(ctx, 98000001); len(evs) != 0 {
+		t.Errorf("expected alert not to repeat, got %+v", evs)
+	}
+
+	m.Snooze(2, time.Now().Add(time.Hour))
+	(*src)[1].State = model.StructureStateArmorReinforce
+	(*src)[0].State = model.StructureStateHullReinforce
+	evs, _ = m.Check(ctx, 98000001)
+	if len(evs) != 1 || evs[0].Type != notify.EventStructureReinforced {
+		t.Fatalf("expected one reinforcement alert for the unsnoozed structure, got %+v", evs)
+	}
+	if s := evs[0].Payload.(model.CorporationStructure); s.StructureID != 1 {
+		t.Errorf("expected structure 1, got %d", s.StructureID)
+	}
+}
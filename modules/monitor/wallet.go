@@ -0,0 +1,238 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/notify"
+)
+
+// ESI caches wallet balances for these durations, so polling faster only
+// returns the same value.
+const (
+	CharacterWalletInterval   = 120 * time.Second
+	CorporationWalletInterval = 300 * time.Second
+)
+
+// WalletSource reads wallet balances and journals; esi.EsiService satisfies it.
+type WalletSource interface {
+	GetCharacterWallet(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (float64, error)
+	GetCharacterWalletJournal(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.WalletJournalEntry, error)
+	GetCorporationWallets(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]model.CorporationWallet, error)
+	GetCorporationWalletJournal(ctx context.Context, corporationID model.CorporationID, division int, token *oauth2.Token) ([]model.WalletJournalEntry, error)
+}
+
+// walletKey identifies a character wallet (division 0) or a corporation
+// wallet division.
+type walletKey struct {
+	id       int64
+	corp     bool
+	division int
+}
+
+// walletState is what the watcher remembers about one wallet.
+type walletState struct {
+	balance  float64
+	since    time.Time
+	lastJrnl int64
+}
+
+// WalletWatcher polls wallet balances and emits EventWalletCredited /
+// EventWalletDebited when they change, with the journal entries recorded
+// since the previous change attached. The first check of a wallet only
+// records a baseline.
+type WalletWatcher struct {
+	source   WalletSource
+	tokens   TokenSource
+	notifier notify.Notifier
+	now      func() time.Time
+
+	mu      sync.Mutex
+	wallets map[walletKey]*walletState
+}
+
+// NewWalletWatcher constructs a WalletWatcher. tokens is called with the
+// character or corporation ID being checked. notifier may be nil.
+func NewWalletWatcher(source WalletSource, tokens TokenSource, notifier notify.Notifier) *WalletWatcher {
+	return &WalletWatcher{
+		source:   source,
+		tokens:   tokens,
+		notifier: notifier,
+		now:      time.Now,
+		wallets:  make(map[walletKey]*walletState),
+	}
+}
+
+// CheckCharacter polls a character's wallet once and returns the events emitted.
+func (w *WalletWatcher) CheckCharacter(ctx context.Context, characterID model.CharacterID) ([]notify.Event, error) {
+	token, err := w.tokens(ctx, characterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token for character %d: %w", characterID, err)
+	}
+	balance, err := w.source.GetCharacterWallet(ctx, characterID, token)
+	if err != nil {
+		return nil, err
+	}
+	key := walletKey{id: characterID}
+	change, err := w.observe(key, balance, func() ([]model.WalletJournalEntry, error) {
+		return w.source.GetCharacterWalletJournal(ctx, characterID, token)
+	})
+	if err != nil || change == nil {
+		return nil, err
+	}
+	change.CharacterID = characterID
+	return w.emit(ctx, []notify.WalletChange{*change})
+}
+
+// CheckCorporation polls every wallet division of a corporation once and
+// returns the events emitted.
+func (w *WalletWatcher) CheckCorporation(ctx context.Context, corporationID model.CorporationID) ([]notify.Event, error) {
+	token, err := w.tokens(ctx, corporationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token for corporation %d: %w", corporationID, err)
+	}
+	wallets, err := w.source.GetCorporationWallets(ctx, corporationID, token)
+	if err != nil {
+		return nil, err
+	}
+	var changes []notify.WalletChange
+	for _, wallet := range wallets {
+		division := wallet.Division
+		key := walletKey{id: corporationID, corp: true, division: division}
+		change, err := w.observe(key, wallet.Balance, func() ([]model.WalletJournalEntry, error) {
+			return w.source.GetCorporationWalletJournal(ctx, corporationID, division, token)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if change != nil {
+			change.CorporationID = corporationID
+			change.Division = division
+			changes = append(changes, *change)
+		}
+	}
+	return w.emit(ctx, changes)
+}
+
+// observe records balance for key and, if it changed since the last check,
+// returns the change with the new journal entries attached.
+func (w *WalletWatcher) observe(key walletKey, balance float64, journal func() ([]model.WalletJournalEntry, error)) (*notify.WalletChange, error) {
+	w.mu.Lock()
+	st, ok := w.wallets[key]
+	if !ok {
+		w.wallets[key] = &walletState{balance: balance, since: w.now()}
+		w.mu.Unlock()
+		return nil, nil
+	}
+	if st.balance == balance {
+		w.mu.Unlock()
+		return nil, nil
+	}
+	since, lastJrnl := st.since, st.lastJrnl
+	w.mu.Unlock()
+
+	entries, err := journal()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch wallet journal: %w", err)
+	}
+	var fresh []model.WalletJournalEntry
+	for _, e := range entries {
+		if e.ID > lastJrnl && e.Date.After(since) {
+			fresh = append(fresh, e)
+		}
+	}
+	sort.Slice(fresh, func(i, j int) bool { return fresh[i].ID < fresh[j].ID })
+
+	w.mu.Lock()
+	change := &notify.WalletChange{Previous: st.balance, Balance: balance, Entries: fresh}
+	st.balance = balance
+	if len(fresh) > 0 {
+		st.lastJrnl = fresh[len(fresh)-1].ID
+	}
+	w.mu.Unlock()
+	return change, nil
+}
+
+// emit builds events for changes and delivers them to the notifier.
+func (w *WalletWatcher) emit(ctx context.Context, changes []notify.WalletChange) ([]notify.Event, error) {
+	now := w.now()
+	var events []notify.Event
+	for _, c := range changes {
+		if c.Delta() > 0 {
+			events = append(events, notify.WalletCreditedEvent(now, c))
+		} else {
+			events = append(events, notify.WalletDebitedEvent(now, c))
+		}
+	}
+	if w.notifier != nil {
+		var errs []error
+		for _, ev := range events {
+			errs = append(errs, w.notifier.Notify(ctx, ev))
+		}
+		return events, errors.Join(errs...)
+	}
+	return events, nil
+}
+
+// Run checks character wallets every CharacterWalletInterval and corporation
+// wallets every CorporationWalletInterval until ctx is done.
+func (w *WalletWatcher) Run(ctx context.Context, characterIDs []model.CharacterID, corporationIDs []model.CorporationID, onError func(error)) {
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		Poll(ctx, CharacterWalletInterval, func(ctx context.Context) error {
+			var errs []error
+			for _, id := range characterIDs {
+				if _, err := w.CheckCharacter(ctx, id); err != nil {
+					errs = append(errs, err)
+				}
+			}
+			return errors.Join(errs...)
+		}, onError)
+	}()
+	go func() {
+		defer wg.Done()
+		Poll(ctx, CorporationWalletInterval, func(ctx context.Context) error {
+			var errs []error
+			for _, id := range corporationIDs {
+				if _, err := w.CheckCorporation(ctx, id); err != nil {
+					errs = append(errs, err)
+				}
+			}
+			return errors.Join(errs...)
+		}, onError)
+	}()
+	wg.Wait()
+}
+
+// RegisterCharacters adds a poller task per character that runs
+// CheckCharacter on schedule, or whenever ESI's cached balance expires if
+// schedule is nil.
+func (w *WalletWatcher) RegisterCharacters(p *Poller, schedule Schedule, characterIDs ...model.CharacterID) {
+	for _, id := range characterIDs {
+		p.Add(fmt.Sprintf("wallet:character:%d", id), schedule, func(ctx context.Context) error {
+			_, err := w.CheckCharacter(ctx, id)
+			return err
+		})
+	}
+}
+
+// RegisterCorporations adds a poller task per corporation that runs
+// CheckCorporation on schedule, or whenever ESI's cached balances expire if
+// schedule is nil.
+func (w *WalletWatcher) RegisterCorporations(p *Poller, schedule Schedule, corporationIDs ...model.CorporationID) {
+	for _, id := range corporationIDs {
+		p.Add(fmt.Sprintf("wallet:corporation:%d", id), schedule, func(ctx context.Context) error {
+			_, err := w.CheckCorporation(ctx, id)
+			return err
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package monitor_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/monitor"
+	"github.com/guarzo/eveapi/modules/notify"
+)
+
+type walletSource struct {
+	balance  float64
+	journal  []model.WalletJournalEntry
+	corp     []model.CorporationWallet
+	corpJrnl map[int][]model.WalletJournalEntry
+}
+
+func (w *walletSource) GetCharacterWallet(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (float64, error) {
+	return w.balance, nil
+}
+
+func (w *walletSource) GetCharacterWalletJournal(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.WalletJournalEntry, error) {
+	return w.journal, nil
+}
+
+func (w *walletSource) GetCorporationWallets(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]model.CorporationWallet, error) {
+	return w.corp, nil
+}
+
+func (w *walletSource) GetCorporationWalletJournal(ctx context.Context, corporationID model.CorporationID, division int, token *oauth2.Token) ([]model.WalletJournalEntry, error) {
+	return w.corpJrnl[division], nil
+}
+
+func TestWalletWatcher_Character(t *testing.T) {
+	src := &walletSource{
+		balance: 1000,
+		journal: []model.WalletJournalEntry{{ID: 1, Date: time.Now().Add(-time.Hour), Amount: 1000}},
+	}
+	var events []notify.Event
+	n := notify.NotifierFunc(func(ctx context.Context, ev notify.Event) error {
+		events = append(events, ev)
+		return nil
+	})
+	w := monitor.NewWalletWatcher(src, noToken, n)
+	ctx := context.Background()
+
+	if evs, err := w.CheckCharacter(ctx, 90000001); err != nil || len(evs) != 0 {
+		t.Fatalf("expected silent baseline, got %+v, %v", evs, err)
+	}
+
+	src.balance = 1500
+	src.journal = append(src.journal, model.WalletJournalEntry{ID: 2, Date: time.Now().Add(time.Minute), Amount: 500, RefType: "bounty_prizes"})
+	evs, err := w.CheckCharacter(ctx, 90000001)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(evs) != 1 || evs[0].Type != notify.EventWalletCredited || len(events) != 1 {
+		t.Fatalf("expected one credit event, got %+v", evs)
+	}
+	wc := evs[0].Payload.(notify.WalletChange)
+	if wc.Delta() != 500 || len(wc.Entries) != 1 || wc.Entries[0].ID != 2 {
+		t.Errorf("unexpected change: %+v", wc)
+	}
+
+	if evs, _ := w.CheckCharacter(ctx, 90000001); len(evs) != 0 {
+		t.Errorf("expected no event for an unchanged balance, got %+v", evs)
+	}
+
+	src.balance = 1200
+	src.journal = append(src.journal, model.WalletJournalEntry{ID: 3, Date: time.Now().Add(2 * time.Minute), Amount: -300})
+	evs, _ = w.CheckCharacter(ctx, 90000001)
+	if len(evs) != 1 || evs[0].Type != notify.EventWalletDebited {
+		t.Fatalf("expected one debit event, got %+v", evs)
+	}
+	if wc := evs[0].Payload.(notify.WalletChange); len(wc.Entries) != 1 || wc.Entries[0].ID != 3 {
+		t.Errorf("expected only the new journal entry, got %+v", wc.Entries)
+	}
+}
+
+func TestWalletWatcher_Corporation(t *testing.T) {
+	src := &walletSource{corp: []model.CorporationWallet{{Division: 1, Balance: 10}, {Division: 2, Balance: 20}}}
+	w := monitor.NewWalletWatcher(src, noToken, nil)
+	ctx := context.Background()
+	if evs, _ := w.CheckCorporation(ctx, 98000001); len(evs) != 0 {
+		t.Fatalf("expected silent baseline, got %+v", evs)
+	}
+	src.corp[1].Balance = 5
+	evs, err := w.CheckCorporation(ctx, 98000001)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(evs) != 1 || evs[0].Type != notify.EventWalletDebited {
+		t.Fatalf("expected one debit event, got %+v", evs)
+	}
+	if wc := evs[0].Payload.(notify.WalletChange); wc.CorporationID != 98000001 || wc.Division != 2 {
+		t.Errorf("unexpected change: %+v", wc)
+	}
+}
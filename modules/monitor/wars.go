@@ -0,0 +1,203 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/notify"
+)
+
+// WarSource is the part of esi.EsiService the war monitor needs.
+type WarSource interface {
+	GetWars(ctx context.Context, maxWarID int64) ([]int64, error)
+	GetWar(ctx context.Context, warID int64) (*model.War, error)
+	GetCorporationInfo(ctx context.Context, corporationID model.CorporationID) (*model.Corporation, error)
+}
+
+// WarMonitor watches for new and finished wars involving tracked
+// corporations or alliances, and for changes in the war eligibility of
+// tracked corporations.
+type WarMonitor struct {
+	source       WarSource
+	notifier     notify.Notifier
+	corporations []int64
+	alliances    []int64
+	now          func() time.Time
+
+	mu          sync.Mutex
+	lastWarID   int64
+	activeWars  map[int64]model.War
+	eligibility map[int64]bool
+}
+
+// NewWarMonitor constructs a WarMonitor for the tracked corporations and
+// alliances. notifier may be nil.
+func NewWarMonitor(source WarSource, notifier notify.Notifier, corporations, alliances []int64) *WarMonitor {
+	return &WarMonitor{
+		source:       source,
+		notifier:     notifier,
+		corporations: corporations,
+		alliances:    alliances,
+		now:          time.Now,
+		activeWars:   make(map[int64]model.War),
+		eligibility:  make(map[int64]bool),
+	}
+}
+
+// Watch adds an already known war (e.g. loaded from ESI at startup) so its
+// end is reported.
+func (m *WarMonitor) Watch(war model.War) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.activeWars[war.ID] = war
+	if war.ID > m.lastWarID {
+		m.lastWarID = war.ID
+	}
+}
+
+// ActiveWars returns the wars currently tracked as active.
+func (m *WarMonitor) ActiveWars() []model.War {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]model.War, 0, len(m.activeWars))
+	for _, w := range m.activeWars {
+		out = append(out, w)
+	}
+	return out
+}
+
+// Check polls ESI once and returns the events it emitted. The first call
+// only records the newest war ID and current eligibility as a baseline.
+func (m *WarMonitor) Check(ctx context.Context) ([]notify.Event, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var events []notify.Event
+	var errs []error
+	now := m.now()
+
+	// finished first, so wars declared in this pass are not fetched twice
+	events = append(events, m.checkFinished(ctx, now, &errs)...)
+	evs, err := m.checkNewWars(ctx, now)
+	events = append(events, evs...)
+	errs = append(errs, err)
+	events = append(events, m.checkEligibility(ctx, now, &errs)...)
+
+	if m.notifier != nil {
+		for _, ev := range events {
+			errs = append(errs, m.notifier.Notify(ctx, ev))
+		}
+	}
+	return events, errors.Join(errs...)
+}
+
+// Run checks every interval until ctx is done.
+func (m *WarMonitor) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	Poll(ctx, interval, func(ctx context.Context) error {
+		_, err := m.Check(ctx)
+		return err
+	}, onError)
+}
+
+// Register adds a poller task that runs Check on schedule, or whenever ESI's
+// cached war data expires if schedule is nil.
+func (m *WarMonitor) Register(p *Poller, schedule Schedule) {
+	p.Add("wars", schedule, func(ctx context.Context) error {
+		_, err := m.Check(ctx)
+		return err
+	})
+}
+
+func (m *WarMonitor) checkNewWars(ctx context.Context, now time.Time) ([]notify.Event, error) {
+	ids, err := m.source.GetWars(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	if m.lastWarID == 0 {
+		for _, id := range ids {
+			if id > m.lastWarID {
+				m.lastWarID = id
+			}
+		}
+		return nil, nil
+	}
+
+	// oldest first, so a failed fetch leaves lastWarID just below it and the
+	// next poll resumes there without re-announcing wars already processed
+	ids = append([]int64(nil), ids...)
+	slices.Sort(ids)
+
+	var events []notify.Event
+	for _, id := range ids {
+		if id <= m.lastWarID {
+			continue
+		}
+		war, err := m.source.GetWar(ctx, id)
+		if err != nil {
+			return events, err
+		}
+		m.lastWarID = id
+		if m.tracked(war) && war.Active(now) {
+			m.activeWars[id] = *war
+			events = append(events, notify.WarDeclaredEvent(now, *war))
+		}
+	}
+	return events, nil
+}
+
+func (m *WarMonitor) checkFinished(ctx context.Context, now time.Time, errs *[]error) []notify.Event {
+	var events []notify.Event
+	for id := range m.activeWars {
+		war, err := m.source.GetWar(ctx, id)
+		if err != nil {
+			*errs = append(*errs, err)
+			continue
+		}
+		if !war.Active(now) {
+			delete(m.activeWars, id)
+			events = append(events, notify.WarFinishedEvent(now, *war))
+		} else {
+			m.activeWars[id] = *war
+		}
+	}
+	return events
+}
+
+func (m *WarMonitor) checkEligibility(ctx context.Context, now time.Time, errs *[]error) []notify.Event {
+	var events []notify.Event
+	for _, id := range m.corporations {
+		// eligibility flips between polls, so skip the 6h corporation cache
+		corp, err := m.source.GetCorporationInfo(common.WithoutCache(ctx), id)
+		if err != nil {
+			*errs = append(*errs, err)
+			continue
+		}
+		eligible := corp.WarEligible != nil && *corp.WarEligible
+		prev, seen := m.eligibility[id]
+		m.eligibility[id] = eligible
+		if seen && prev != eligible {
+			events = append(events, notify.WarEligibilityEvent(now,
+				notify.WarEligibilityChange{CorporationID: id, Eligible: eligible}))
+		}
+	}
+	return events
+}
+
+func (m *WarMonitor) tracked(war *model.War) bool {
+	for _, id := range m.corporations {
+		if war.Involves(id) {
+			return true
+		}
+	}
+	for _, id := range m.alliances {
+		if war.Involves(id) {
+			return true
+		}
+	}
+	return false
+}
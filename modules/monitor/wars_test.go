@@ -0,0 +1,159 @@
+package monitor_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/monitor"
+	"github.com/guarzo/eveapi/modules/notify"
+)
+
+type fakeWars struct {
+	ids      []int64
+	wars     map[int64]*model.War
+	failing  map[int64]bool
+	eligible bool
+}
+
+func (f *fakeWars) GetWars(ctx context.Context, maxWarID int64) ([]int64, error) {
+	return f.ids, nil
+}
+func (f *fakeWars) GetWar(ctx context.Context, warID int64) (*model.War, error) {
+	if f.failing[warID] {
+		return nil, errors.New("esi unavailable")
+	}
+	w := *f.wars[warID]
+	return &w, nil
+}
+func (f *fakeWars) GetCorporationInfo(ctx context.Context, corporationID model.CorporationID) (*model.Corporation, error) {
+	if !common.CacheBypassed(ctx) {
+		return nil, errors.New("eligibility read through the cache")
+	}
+	eligible := f.eligible
+	return &model.Corporation{WarEligible: &eligible}, nil
+}
+
+func TestWarMonitor(t *testing.T) {
+	const corp = 98000001
+	src := &fakeWars{
+		ids: []int64{100},
+		wars: map[int64]*model.War{
+			100: {ID: 100, Aggressor: model.WarParty{CorporationID: 1}, Defender: model.WarParty{CorporationID: 2}},
+			101: {ID: 101, Aggressor: model.WarParty{AllianceID: 99}, Defender: model.WarParty{CorporationID: corp}},
+			102: {ID: 102, Aggressor: model.WarParty{CorporationID: 3}, Defender: model.WarParty{CorporationID: 4}},
+		},
+	}
+	m := monitor.NewWarMonitor(src, nil, []int64{corp}, nil)
+	ctx := context.Background()
+
+	if evs, err := m.Check(ctx); err != nil || len(evs) != 0 {
+		t.Fatalf("expected silent baseline, got %v, %v", evs, err)
+	}
+
+	src.ids = []int64{102, 101, 100}
+	src.eligible = true
+	evs, err := m.Check(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(evs) != 2 || evs[0].Type != notify.EventWarDeclared || evs[1].Type != notify.EventWarEligibility {
+		t.Fatalf("unexpected events: %+v", evs)
+	}
+	if war := evs[0].Payload.(model.War); war.ID != 101 {
+		t.Errorf("expected war 101, got %d", war.ID)
+	}
+
+	finished := time.Now().Add(-time.Minute)
+	src.wars[101].Finished = &finished
+	evs, _ = m.Check(ctx)
+	if len(evs) != 1 || evs[0].Type != notify.EventWarFinished {
+		t.Errorf("expected war finished event, got %+v", evs)
+	}
+	if len(m.ActiveWars()) != 0 {
+		t.Errorf("expected no active wars, got %d", len(m.ActiveWars()))
+	}
+}
+
+func TestWarMonitor_FailedFetchDoesNotRedeclare(t *testing.T) {
+	const corp = 98000001
+	src := &fakeWars{
+		ids: []int64{100},
+		wars: map[int64]*model.War{
+			101: {ID: 101, Aggressor: model.WarParty{AllianceID: 99}, Defender: model.WarParty{CorporationID: corp}},
+			102: {ID: 102, Aggressor: model.WarParty{CorporationID: corp}, Defender: model.WarParty{CorporationID: 4}},
+		},
+		failing: map[int64]bool{102: true},
+	}
+	m := monitor.NewWarMonitor(src, nil, []int64{corp}, nil)
+	ctx := context.Background()
+	if _, err := m.Check(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	src.ids = []int64{102, 101, 100}
+	evs, err := m.Check(ctx)
+	if err == nil {
+		t.Fatal("expected the failed fetch to be reported")
+	}
+	if len(evs) != 1 || evs[0].Payload.(model.War).ID != 101 {
+		t.Fatalf("expected war 101 to be declared, got %+v", evs)
+	}
+
+	src.failing = nil
+	evs, err = m.Check(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var declared []int64
+	for _, ev := range evs {
+		if ev.Type == notify.EventWarDeclared {
+			declared = append(declared, ev.Payload.(model.War).ID)
+		}
+	}
+	if len(declared) != 1 || declared[0] != 102 {
+		t.Errorf("expected only war 102 to be declared on retry, got %v", declared)
+	}
+}
+
+// countingWars cancels the poller once GetWars has been called n times.
+type countingWars struct {
+	*fakeWars
+	calls  int
+	n      int
+	cancel context.CancelFunc
+}
+
+func (c *countingWars) GetWars(ctx context.Context, maxWarID int64) ([]int64, error) {
+	c.calls++
+	if c.calls == c.n {
+		defer c.cancel()
+	}
+	ids := c.ids
+	c.ids = []int64{101, 100} // the second poll sees the new war
+	return ids, nil
+}
+
+func TestWarMonitor_Register(t *testing.T) {
+	const corp = 98000001
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	src := &countingWars{fakeWars: &fakeWars{
+		ids: []int64{100},
+		wars: map[int64]*model.War{
+			101: {ID: 101, Aggressor: model.WarParty{AllianceID: 99}, Defender: model.WarParty{CorporationID: corp}},
+		},
+	}, n: 2, cancel: cancel}
+	m := monitor.NewWarMonitor(src, nil, []int64{corp}, nil)
+
+	p := monitor.NewPoller(monitor.WithJitter(0))
+	m.Register(p, monitor.Every(time.Millisecond))
+	p.Run(ctx)
+
+	if wars := m.ActiveWars(); len(wars) != 1 || wars[0].ID != 101 {
+		t.Errorf("expected the poller to pick up war 101, got %+v", wars)
+	}
+}
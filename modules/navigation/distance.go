@@ -0,0 +1,74 @@
+package navigation
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// MetersPerLightYear converts ESI system positions (meters) to light-years.
+const MetersPerLightYear = 9460730472580800.0
+
+// SystemDistance pairs a system with its distance from a reference system.
+type SystemDistance struct {
+	SystemID   int64   `json:"system_id"`
+	Name       string  `json:"name"`
+	DistanceLY float64 `json:"distance_ly"`
+}
+
+// DistanceLY returns the straight-line distance between two systems in light-years.
+func DistanceLY(a, b *model.SolarSystem) float64 {
+	dx := a.Position.X - b.Position.X
+	dy := a.Position.Y - b.Position.Y
+	dz := a.Position.Z - b.Position.Z
+	return math.Sqrt(dx*dx+dy*dy+dz*dz) / MetersPerLightYear
+}
+
+// Distance looks both systems up and returns the distance between them in light-years.
+func Distance(ctx context.Context, u Universe, fromID, toID int64) (float64, error) {
+	from, err := u.GetSolarSystem(ctx, fromID)
+	if err != nil {
+		return 0, err
+	}
+	to, err := u.GetSolarSystem(ctx, toID)
+	if err != nil {
+		return 0, err
+	}
+	return DistanceLY(from, to), nil
+}
+
+// WithinRange returns the systems no further than rangeLY from origin, nearest first.
+// The origin itself is excluded.
+func WithinRange(origin *model.SolarSystem, systems []*model.SolarSystem, rangeLY float64) []SystemDistance {
+	var out []SystemDistance
+	for _, sys := range systems {
+		if sys.SystemID == origin.SystemID {
+			continue
+		}
+		if d := DistanceLY(origin, sys); d <= rangeLY {
+			out = append(out, SystemDistance{SystemID: sys.SystemID, Name: sys.Name, DistanceLY: d})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].DistanceLY < out[j].DistanceLY })
+	return out
+}
+
+// SystemsWithin loads origin and candidates through u and returns the candidates
+// within rangeLY of origin, nearest first.
+func SystemsWithin(ctx context.Context, u Universe, originID int64, candidates []int64, rangeLY float64) ([]SystemDistance, error) {
+	origin, err := u.GetSolarSystem(ctx, originID)
+	if err != nil {
+		return nil, err
+	}
+	systems := make([]*model.SolarSystem, 0, len(candidates))
+	for _, id := range candidates {
+		sys, err := u.GetSolarSystem(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		systems = append(systems, sys)
+	}
+	return WithinRange(origin, systems, rangeLY), nil
+}
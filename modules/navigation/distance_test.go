@@ -0,0 +1,30 @@
+package navigation_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/guarzo/eveapi/modules/navigation"
+)
+
+func TestDistance(t *testing.T) {
+	u := line(map[int64]float64{1: 0, 2: 4.5})
+	d, err := navigation.Distance(context.Background(), u, 1, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d < 4.49 || d > 4.51 {
+		t.Errorf("expected ~4.5 ly, got %v", d)
+	}
+}
+
+func TestSystemsWithin(t *testing.T) {
+	u := line(map[int64]float64{1: 0, 2: 6, 3: -2, 4: 8})
+	out, err := navigation.SystemsWithin(context.Background(), u, 1, []int64{1, 2, 3, 4}, 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out) != 2 || out[0].SystemID != 3 || out[1].SystemID != 2 {
+		t.Errorf("expected [3 2] nearest first, got %#v", out)
+	}
+}
@@ -0,0 +1,4 @@
+// Package navigation plans capital jump chains between solar systems using
+// ESI universe data, clone locations and known cyno stashes, and counts
+// stargate jumps via ESI's route planner.
+package navigation
@@ -0,0 +1,263 @@
+package navigation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// ShipClass selects the base jump range used by JumpRange.
+type ShipClass int
+
+const (
+	Carrier ShipClass = iota // carriers, dreadnoughts and FAX
+	Titan
+	BlackOps
+	JumpFreighter
+	Rorqual
+	Supercarrier
+)
+
+// baseRanges holds each class's range in light-years with Jump Drive Calibration at 0.
+var baseRanges = map[ShipClass]float64{
+	Carrier:       3.5,
+	Titan:         3.0,
+	BlackOps:      4.0,
+	JumpFreighter: 5.0,
+	Rorqual:       5.0,
+	Supercarrier:  3.0,
+}
+
+// ErrNoRoute is returned when no chain of cyno systems connects origin and destination.
+var ErrNoRoute = errors.New("no jump route within range")
+
+// JumpRange returns the jump range in light-years for a ship class with the
+// given Jump Drive Calibration level (each level adds 20% of the base range).
+func JumpRange(class ShipClass, jdcLevel int) float64 {
+	if jdcLevel < 0 {
+		jdcLevel = 0
+	}
+	if jdcLevel > 5 {
+		jdcLevel = 5
+	}
+	return baseRanges[class] * (1 + 0.2*float64(jdcLevel))
+}
+
+// Universe is the subset of EsiService the planner needs.
+type Universe interface {
+	GetSolarSystem(ctx context.Context, systemID model.SystemID) (*model.SolarSystem, error)
+}
+
+// LocationResolver is the subset of EsiService used to map stash locations to systems.
+type LocationResolver interface {
+	GetStation(ctx context.Context, stationID int64) (*model.Station, error)
+	GetStructure(ctx context.Context, structureID int64, token *oauth2.Token) (*model.Structure, error)
+}
+
+// Request describes a jump chain to plan.
+type Request struct {
+	// Origins are candidate start systems, e.g. from GetCloneLocations.
+	Origins []int64
+	// Destination is the target system.
+	Destination int64
+	// Midpoints are systems where cyno stock exists.
+	Midpoints []int64
+	// RangeLY is the maximum distance of a single jump.
+	RangeLY float64
+}
+
+// Jump is a single leg of a chain.
+type Jump struct {
+	From       int64   `json:"from"`
+	To         int64   `json:"to"`
+	DistanceLY float64 `json:"distance_ly"`
+}
+
+// Chain is a planned route. Systems lists the origin, midpoints and destination in order.
+type Chain struct {
+	Systems []int64 `json:"systems"`
+	Jumps   []Jump  `json:"jumps"`
+	TotalLY float64 `json:"total_ly"`
+}
+
+// Midpoints returns the systems between origin and destination where a cyno is needed.
+func (c *Chain) Midpoints() []int64 {
+	if len(c.Systems) < 3 {
+		return nil
+	}
+	return c.Systems[1 : len(c.Systems)-1]
+}
+
+// Planner computes jump chains.
+type Planner struct {
+	universe Universe
+}
+
+// NewPlanner constructs a Planner backed by the given universe lookups.
+func NewPlanner(universe Universe) *Planner {
+	return &Planner{universe: universe}
+}
+
+// Plan finds the chain with the fewest jumps from any origin to the destination,
+// breaking ties by total distance. Midpoints in high-sec and w-space are skipped
+// because cynosural fields cannot be lit there.
+func (p *Planner) Plan(ctx context.Context, req Request) (*Chain, error) {
+	if len(req.Origins) == 0 {
+		return nil, fmt.Errorf("no origin systems given")
+	}
+	if req.RangeLY <= 0 {
+		return nil, fmt.Errorf("invalid jump range %.2f", req.RangeLY)
+	}
+
+	systems := make(map[int64]*model.SolarSystem)
+	load := func(id int64) (*model.SolarSystem, error) {
+		if sys, ok := systems[id]; ok {
+			return sys, nil
+		}
+		sys, err := p.universe.GetSolarSystem(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load system %d: %w", id, err)
+		}
+		systems[id] = sys
+		return sys, nil
+	}
+
+	dest, err := load(req.Destination)
+	if err != nil {
+		return nil, err
+	}
+	if !cynoAllowed(dest) {
+		return nil, fmt.Errorf("destination %d cannot hold a cyno: %w", req.Destination, ErrNoRoute)
+	}
+
+	var nodes []int64
+	seen := make(map[int64]bool)
+	for _, id := range req.Midpoints {
+		if seen[id] || id == req.Destination {
+			continue
+		}
+		sys, err := load(id)
+		if err != nil {
+			return nil, err
+		}
+		seen[id] = true
+		if cynoAllowed(sys) {
+			nodes = append(nodes, id)
+		}
+	}
+	for _, id := range req.Origins {
+		if _, err := load(id); err != nil {
+			return nil, err
+		}
+	}
+
+	return shortestChain(systems, req.Origins, nodes, req.Destination, req.RangeLY)
+}
+
+// StashSystems resolves the locations of cyno inventories (from GetCharacterAssets)
+// into distinct system IDs usable as Request.Midpoints.
+func StashSystems(ctx context.Context, r LocationResolver, invs []model.LocationInventory, token *oauth2.Token) ([]int64, error) {
+	seen := make(map[int64]bool)
+	var out []int64
+	for _, inv := range invs {
+		var sysID int64
+		switch inv.LocType {
+		case "solar_system":
+			sysID = inv.LocID
+		case "structure":
+			st, err := r.GetStructure(ctx, inv.LocID, token)
+			if err != nil {
+				return nil, err
+			}
+			sysID = st.SystemID
+		default:
+			stn, err := r.GetStation(ctx, inv.LocID)
+			if err != nil {
+				return nil, err
+			}
+			sysID = stn.SystemID
+		}
+		if !seen[sysID] {
+			seen[sysID] = true
+			out = append(out, sysID)
+		}
+	}
+	return out, nil
+}
+
+// shortestChain runs a fewest-hops search with total distance as the tie-breaker.
+func shortestChain(systems map[int64]*model.SolarSystem, origins, midpoints []int64, dest int64, rangeLY float64) (*Chain, error) {
+	type state struct {
+		hops int
+		ly   float64
+		prev int64
+	}
+	best := make(map[int64]state)
+	var frontier []int64
+	for _, o := range origins {
+		if _, ok := best[o]; !ok {
+			best[o] = state{prev: -1}
+			frontier = append(frontier, o)
+		}
+	}
+
+	targets := append(append([]int64{}, midpoints...), dest)
+	for hops := 1; len(frontier) > 0; hops++ {
+		next := make(map[int64]bool)
+		for _, from := range frontier {
+			for _, to := range targets {
+				if to == from {
+					continue
+				}
+				d := DistanceLY(systems[from], systems[to])
+				if d > rangeLY {
+					continue
+				}
+				cand := state{hops: hops, ly: best[from].ly + d, prev: from}
+				cur, ok := best[to]
+				if !ok || (cur.hops == hops && cand.ly < cur.ly) {
+					best[to] = cand
+					if to != dest {
+						next[to] = true
+					}
+				}
+			}
+		}
+		if _, ok := best[dest]; ok {
+			break
+		}
+		frontier = frontier[:0]
+		for id := range next {
+			frontier = append(frontier, id)
+		}
+		sort.Slice(frontier, func(i, j int) bool { return frontier[i] < frontier[j] })
+	}
+
+	end, ok := best[dest]
+	if !ok {
+		return nil, ErrNoRoute
+	}
+
+	chain := &Chain{TotalLY: end.ly}
+	for id := dest; id != -1; id = best[id].prev {
+		chain.Systems = append([]int64{id}, chain.Systems...)
+		if best[id].prev == -1 {
+			break
+		}
+	}
+	for i := 1; i < len(chain.Systems); i++ {
+		from, to := chain.Systems[i-1], chain.Systems[i]
+		chain.Jumps = append(chain.Jumps, Jump{From: from, To: to, DistanceLY: DistanceLY(systems[from], systems[to])})
+	}
+	return chain, nil
+}
+
+func cynoAllowed(sys *model.SolarSystem) bool {
+	band := Classify(sys)
+	return band == LowSec || band == NullSec
+}
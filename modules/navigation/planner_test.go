@@ -0,0 +1,98 @@
+package navigation_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/navigation"
+)
+
+const ly = 9460730472580800.0
+
+type fakeUniverse map[int64]*model.SolarSystem
+
+func (f fakeUniverse) GetSolarSystem(ctx context.Context, id int64) (*model.SolarSystem, error) {
+	sys, ok := f[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown system %d", id)
+	}
+	return sys, nil
+}
+
+// line places systems along the X axis at the given light-year offsets.
+func line(offsets map[int64]float64, highsec ...int64) fakeUniverse {
+	u := fakeUniverse{}
+	for id, x := range offsets {
+		u[id] = &model.SolarSystem{SystemID: id, SecurityStatus: -0.5, Position: model.Position{X: x * ly}}
+	}
+	for _, id := range highsec {
+		u[id].SecurityStatus = 0.9
+	}
+	return u
+}
+
+func TestJumpRange(t *testing.T) {
+	if got := navigation.JumpRange(navigation.Carrier, 5); got != 7.0 {
+		t.Errorf("expected 7.0, got %v", got)
+	}
+	if got := navigation.JumpRange(navigation.Supercarrier, 5); got != 6.0 {
+		t.Errorf("expected supercarriers to share the titan range of 6.0, got %v", got)
+	}
+	if got := navigation.JumpRange(navigation.JumpFreighter, 4); got != 9.0 {
+		t.Errorf("expected 9.0, got %v", got)
+	}
+}
+
+func TestPlanner_Plan(t *testing.T) {
+	u := line(map[int64]float64{1: 0, 2: 6, 3: 5, 4: 11, 5: 13}, 3)
+	p := navigation.NewPlanner(u)
+
+	chain, err := p.Plan(context.Background(), navigation.Request{
+		Origins:     []int64{1},
+		Destination: 5,
+		Midpoints:   []int64{2, 3, 4},
+		RangeLY:     7,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 3 is high-sec so the route must go 1 -> 2 -> 5 (7 ly) rather than via 3
+	if !reflect.DeepEqual(chain.Systems, []int64{1, 2, 5}) {
+		t.Errorf("unexpected chain: %v", chain.Systems)
+	}
+	if !reflect.DeepEqual(chain.Midpoints(), []int64{2}) {
+		t.Errorf("unexpected midpoints: %v", chain.Midpoints())
+	}
+	if chain.TotalLY < 12.99 || chain.TotalLY > 13.01 {
+		t.Errorf("unexpected total distance: %v", chain.TotalLY)
+	}
+}
+
+func TestPlanner_Plan_NoRoute(t *testing.T) {
+	u := line(map[int64]float64{1: 0, 2: 20})
+	p := navigation.NewPlanner(u)
+
+	_, err := p.Plan(context.Background(), navigation.Request{Origins: []int64{1}, Destination: 2, RangeLY: 7})
+	if !errors.Is(err, navigation.ErrNoRoute) {
+		t.Errorf("expected ErrNoRoute, got %v", err)
+	}
+}
+
+func TestPlanner_Plan_SupercarrierRange(t *testing.T) {
+	u := line(map[int64]float64{1: 0, 2: 6.5, 3: 13})
+	p := navigation.NewPlanner(u)
+	req := navigation.Request{Origins: []int64{1}, Destination: 3, Midpoints: []int64{2}}
+
+	req.RangeLY = navigation.JumpRange(navigation.Carrier, 5)
+	if _, err := p.Plan(context.Background(), req); err != nil {
+		t.Fatalf("expected a carrier route, got %v", err)
+	}
+	req.RangeLY = navigation.JumpRange(navigation.Supercarrier, 5)
+	if _, err := p.Plan(context.Background(), req); !errors.Is(err, navigation.ErrNoRoute) {
+		t.Errorf("expected 6.5 ly legs to be out of supercarrier range, got %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package navigation
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/oauth2"
+)
+
+// RouteSource is the subset of EsiService that computes gate routes.
+type RouteSource interface {
+	GetRoute(ctx context.Context, origin, destination int64, flag string) ([]int64, error)
+}
+
+// GateJumps returns the number of stargate jumps between two systems using
+// ESI's route planner with the given flag ("shortest", "secure", "insecure").
+func GateJumps(ctx context.Context, r RouteSource, from, to int64, flag string) (int, error) {
+	if from == to {
+		return 0, nil
+	}
+	route, err := r.GetRoute(ctx, from, to, flag)
+	if err != nil {
+		return 0, err
+	}
+	if len(route) == 0 {
+		return 0, fmt.Errorf("%w: %d -> %d", ErrNoRoute, from, to)
+	}
+	return len(route) - 1, nil
+}
+
+// SystemOfLocation maps a station, structure or solar system ID to its
+// solar system ID. Structures need a token with esi-universe.read_structures.v1.
+func SystemOfLocation(ctx context.Context, r LocationResolver, locationID int64, token *oauth2.Token) (int64, error) {
+	switch {
+	case locationID >= 30000000 && locationID < 33000000:
+		return locationID, nil
+	case locationID >= 60000000 && locationID < 64000000:
+		stn, err := r.GetStation(ctx, locationID)
+		if err != nil {
+			return 0, err
+		}
+		return stn.SystemID, nil
+	default:
+		st, err := r.GetStructure(ctx, locationID, token)
+		if err != nil {
+			return 0, err
+		}
+		return st.SystemID, nil
+	}
+}
@@ -0,0 +1,159 @@
+package navigation
+
+import (
+	"context"
+	"sync"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// SecurityBand is the coarse security classification of a system.
+type SecurityBand int
+
+const (
+	HighSec SecurityBand = iota
+	LowSec
+	NullSec
+	WSpace
+)
+
+func (b SecurityBand) String() string {
+	switch b {
+	case HighSec:
+		return "highsec"
+	case LowSec:
+		return "lowsec"
+	case NullSec:
+		return "nullsec"
+	case WSpace:
+		return "wspace"
+	}
+	return "unknown"
+}
+
+// Wormhole systems occupy the J-space ID range.
+const (
+	wspaceMinID = 31000000
+	wspaceMaxID = 31999999
+)
+
+// ClassifySecurity returns the band for a system ID and its true security status,
+// using the in-game rounding (0.45 displays as 0.5).
+func ClassifySecurity(systemID model.SystemID, security float64) SecurityBand {
+	switch {
+	case systemID >= wspaceMinID && systemID <= wspaceMaxID:
+		return WSpace
+	case security >= 0.45:
+		return HighSec
+	case security > 0.0:
+		return LowSec
+	default:
+		return NullSec
+	}
+}
+
+// Classify returns the band of a loaded system.
+func Classify(sys *model.SolarSystem) SecurityBand {
+	return ClassifySecurity(sys.SystemID, sys.SecurityStatus)
+}
+
+// SecurityClassifier classifies systems by ID, remembering results so repeated
+// filters over large killmail sets only look each system up once.
+type SecurityClassifier struct {
+	universe Universe
+	mu       sync.RWMutex
+	bands    map[int64]SecurityBand
+}
+
+// NewSecurityClassifier constructs a SecurityClassifier backed by u.
+func NewSecurityClassifier(u Universe) *SecurityClassifier {
+	return &SecurityClassifier{
+		universe: u,
+		bands:    make(map[int64]SecurityBand),
+	}
+}
+
+// Band returns the security band of a system.
+func (c *SecurityClassifier) Band(ctx context.Context, systemID model.SystemID) (SecurityBand, error) {
+	c.mu.RLock()
+	band, ok := c.bands[systemID]
+	c.mu.RUnlock()
+	if ok {
+		return band, nil
+	}
+
+	sys, err := c.universe.GetSolarSystem(ctx, systemID)
+	if err != nil {
+		return 0, err
+	}
+	band = Classify(sys)
+
+	c.mu.Lock()
+	c.bands[systemID] = band
+	c.mu.Unlock()
+	return band, nil
+}
+
+// InBands reports whether a system falls in any of the given bands.
+func (c *SecurityClassifier) InBands(ctx context.Context, systemID model.SystemID, bands ...SecurityBand) (bool, error) {
+	band, err := c.Band(ctx, systemID)
+	if err != nil {
+		return false, err
+	}
+	for _, b := range bands {
+		if b == band {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// FilterSystems keeps the systems (e.g. a route) that fall in the given bands.
+func (c *SecurityClassifier) FilterSystems(ctx context.Context, systems []int64, bands ...SecurityBand) ([]int64, error) {
+	var out []int64
+	for _, id := range systems {
+		ok, err := c.InBands(ctx, id, bands...)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out = append(out, id)
+		}
+	}
+	return out, nil
+}
+
+// FilterKillMails keeps the killmails whose system falls in the given bands.
+func (c *SecurityClassifier) FilterKillMails(ctx context.Context, kills []model.FlattenedKillMail, bands ...SecurityBand) ([]model.FlattenedKillMail, error) {
+	var out []model.FlattenedKillMail
+	for _, km := range kills {
+		ok, err := c.InBands(ctx, km.SolarSystemID, bands...)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out = append(out, km)
+		}
+	}
+	return out, nil
+}
+
+// FilterLocations keeps the asset locations whose system falls in the given bands.
+// systemOf maps a location to its system, e.g. via GetStation/GetStructure.
+func (c *SecurityClassifier) FilterLocations(ctx context.Context, locs []model.LocationAssets, systemOf func(ctx context.Context, loc model.LocationAssets) (int64, error), bands ...SecurityBand) ([]model.LocationAssets, error) {
+	var out []model.LocationAssets
+	for _, loc := range locs {
+		sysID, err := systemOf(ctx, loc)
+		if err != nil {
+			return nil, err
+		}
+		ok, err := c.InBands(ctx, sysID, bands...)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out = append(out, loc)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,49 @@
+package navigation_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/navigation"
+)
+
+func TestClassifySecurity(t *testing.T) {
+	cases := []struct {
+		id   int64
+		sec  float64
+		want navigation.SecurityBand
+	}{
+		{30000142, 0.946, navigation.HighSec},
+		{30002813, 0.46, navigation.HighSec},
+		{30002718, 0.44, navigation.LowSec},
+		{30004759, -0.99, navigation.NullSec},
+		{31000005, -1.0, navigation.WSpace},
+	}
+	for _, c := range cases {
+		if got := navigation.ClassifySecurity(c.id, c.sec); got != c.want {
+			t.Errorf("ClassifySecurity(%d, %v) = %v, want %v", c.id, c.sec, got, c.want)
+		}
+	}
+}
+
+func TestSecurityClassifier_FilterKillMails(t *testing.T) {
+	u := fakeUniverse{
+		1: {SystemID: 1, SecurityStatus: 0.9},
+		2: {SystemID: 2, SecurityStatus: 0.2},
+		3: {SystemID: 3, SecurityStatus: -0.3},
+	}
+	c := navigation.NewSecurityClassifier(u)
+	kills := []model.FlattenedKillMail{
+		{KillMailID: 10, SolarSystemID: 1},
+		{KillMailID: 20, SolarSystemID: 2},
+		{KillMailID: 30, SolarSystemID: 3},
+	}
+	out, err := c.FilterKillMails(context.Background(), kills, navigation.LowSec, navigation.NullSec)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out) != 2 || out[0].KillMailID != 20 || out[1].KillMailID != 30 {
+		t.Errorf("unexpected filtered kills: %#v", out)
+	}
+}
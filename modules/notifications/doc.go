@@ -0,0 +1,3 @@
+// Package notifications parses the YAML text of in-game character
+// notifications into typed payloads and turns them into notify events.
+package notifications
@@ -0,0 +1,214 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/notify"
+)
+
+// Notification types with typed payloads.
+const (
+	TypeStructureUnderAttack = "StructureUnderAttack"
+	TypeStructureLostShields = "StructureLostShields"
+	TypeStructureLostArmor   = "StructureLostArmor"
+	TypeStructureAnchoring   = "StructureAnchoring"
+)
+
+// ErrUnsupported is returned by Parse for notification types without a
+// typed payload.
+var ErrUnsupported = errors.New("notifications: unsupported type")
+
+// fileTimeEpochOffset is the number of 100ns intervals between the Windows
+// FILETIME epoch (1601) and the Unix epoch, used by notification timestamps.
+const fileTimeEpochOffset = 116444736000000000
+
+// fileTime converts a Windows FILETIME to time.Time.
+func fileTime(v int64) time.Time {
+	if v == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, (v-fileTimeEpochOffset)*100).UTC()
+}
+
+// ticks converts a duration in 100ns units to time.Duration.
+func ticks(v int64) time.Duration {
+	return time.Duration(v * 100)
+}
+
+// StructureUnderAttack is the payload of a StructureUnderAttack notification.
+type StructureUnderAttack struct {
+	StructureID      int64   `yaml:"structureID"`
+	StructureTypeID  int64   `yaml:"structureTypeID"`
+	SolarSystemID    int64   `yaml:"solarsystemID"`
+	CharacterID      int64   `yaml:"charID"`
+	CorporationName  string  `yaml:"corpName"`
+	AllianceID       int64   `yaml:"allianceID"`
+	AllianceName     string  `yaml:"allianceName"`
+	ShieldPercentage float64 `yaml:"shieldPercentage"`
+	ArmorPercentage  float64 `yaml:"armorPercentage"`
+	HullPercentage   float64 `yaml:"hullPercentage"`
+
+	// CharacterName is filled in by ResolveNames.
+	CharacterName string `yaml:"-"`
+}
+
+// StructureLostLayer is the payload of StructureLostShields and
+// StructureLostArmor: the structure is reinforced until ReinforceExits.
+type StructureLostLayer struct {
+	Layer           string        `yaml:"-"` // "shield" or "armor"
+	StructureID     int64         `yaml:"structureID"`
+	StructureTypeID int64         `yaml:"structureTypeID"`
+	SolarSystemID   int64         `yaml:"solarsystemID"`
+	TimeLeft        time.Duration `yaml:"-"`
+	VulnerableTime  time.Duration `yaml:"-"`
+	ReinforceExits  time.Time     `yaml:"-"`
+}
+
+// StructureAnchoring is the payload of a StructureAnchoring notification.
+type StructureAnchoring struct {
+	StructureID     int64         `yaml:"structureID"`
+	StructureTypeID int64         `yaml:"structureTypeID"`
+	SolarSystemID   int64         `yaml:"solarsystemID"`
+	OwnerCorpName   string        `yaml:"ownerCorpName"`
+	TimeLeft        time.Duration `yaml:"-"`
+	VulnerableTime  time.Duration `yaml:"-"`
+}
+
+// rawTimers holds the FILETIME-based fields shared by several notifications.
+type rawTimers struct {
+	TimeLeft       int64 `yaml:"timeLeft"`
+	Timestamp      int64 `yaml:"timestamp"`
+	VulnerableTime int64 `yaml:"vulnerableTime"`
+}
+
+// Parse decodes the notification's YAML text into its typed payload:
+// *StructureUnderAttack, *StructureLostLayer or *StructureAnchoring. Other
+// types return ErrUnsupported.
+func Parse(n model.Notification) (interface{}, error) {
+	switch n.Type {
+	case TypeStructureUnderAttack:
+		var p StructureUnderAttack
+		if err := decode(n, &p); err != nil {
+			return nil, err
+		}
+		return &p, nil
+
+	case TypeStructureLostShields, TypeStructureLostArmor:
+		var p StructureLostLayer
+		var t rawTimers
+		if err := decode(n, &p, &t); err != nil {
+			return nil, err
+		}
+		p.Layer = "shield"
+		if n.Type == TypeStructureLostArmor {
+			p.Layer = "armor"
+		}
+		p.TimeLeft = ticks(t.TimeLeft)
+		p.VulnerableTime = ticks(t.VulnerableTime)
+		// timestamp is when the timer was set; fall back to the notification time
+		start := fileTime(t.Timestamp)
+		if start.IsZero() {
+			start = n.Timestamp
+		}
+		p.ReinforceExits = start.Add(p.TimeLeft)
+		return &p, nil
+
+	case TypeStructureAnchoring:
+		var p StructureAnchoring
+		var t rawTimers
+		if err := decode(n, &p, &t); err != nil {
+			return nil, err
+		}
+		p.TimeLeft = ticks(t.TimeLeft)
+		p.VulnerableTime = ticks(t.VulnerableTime)
+		return &p, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupported, n.Type)
+}
+
+func decode(n model.Notification, into ...interface{}) error {
+	for _, v := range into {
+		if err := yaml.Unmarshal([]byte(n.Text), v); err != nil {
+			return fmt.Errorf("failed to parse %s notification %d: %w", n.Type, n.NotificationID, err)
+		}
+	}
+	return nil
+}
+
+// NameResolver resolves IDs to names in bulk; esi.EsiService satisfies it.
+type NameResolver interface {
+	ResolveNames(ctx context.Context, ids []int64) ([]model.UniverseName, error)
+}
+
+// ResolveNames fills the aggressor names of parsed StructureUnderAttack
+// payloads with one batched lookup. Other payloads are left untouched.
+func ResolveNames(ctx context.Context, r NameResolver, payloads []interface{}) error {
+	var ids []int64
+	for _, p := range payloads {
+		if a, ok := p.(*StructureUnderAttack); ok && a.CharacterID != 0 {
+			ids = append(ids, a.CharacterID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	resolved, err := r.ResolveNames(ctx, ids)
+	if err != nil {
+		return err
+	}
+	names := make(map[int64]string, len(resolved))
+	for _, n := range resolved {
+		names[n.ID] = n.Name
+	}
+	for _, p := range payloads {
+		if a, ok := p.(*StructureUnderAttack); ok {
+			a.CharacterName = names[a.CharacterID]
+		}
+	}
+	return nil
+}
+
+// Event converts a parsed payload into a notify.Event timestamped at.
+// Attack notifications become EventStructureAttack; lost layers and
+// anchoring become EventStructureReinforced and EventStructureAnchoring.
+func Event(at time.Time, payload interface{}) (notify.Event, error) {
+	switch p := payload.(type) {
+	case *StructureUnderAttack:
+		ev := notify.StructureAttackEvent(at, notify.StructureAttack{
+			StructureID:      p.StructureID,
+			StructureTypeID:  p.StructureTypeID,
+			SolarSystemID:    p.SolarSystemID,
+			AttackerID:       p.CharacterID,
+			AttackerName:     p.CharacterName,
+			AttackerCorpName: p.CorporationName,
+			AttackerAlliance: p.AllianceName,
+			ShieldPercent:    p.ShieldPercentage,
+			ArmorPercent:     p.ArmorPercentage,
+			HullPercent:      p.HullPercentage,
+		})
+		return ev, nil
+	case *StructureLostLayer:
+		return notify.Event{
+			Type:    notify.EventStructureReinforced,
+			Time:    at,
+			Title:   fmt.Sprintf("Structure %d lost %s", p.StructureID, p.Layer),
+			Message: fmt.Sprintf("Reinforced until %s", p.ReinforceExits.UTC().Format("2006-01-02 15:04 MST")),
+			Payload: p,
+		}, nil
+	case *StructureAnchoring:
+		return notify.Event{
+			Type:    notify.EventStructureAnchoring,
+			Time:    at,
+			Title:   fmt.Sprintf("Structure %d anchoring", p.StructureID),
+			Message: fmt.Sprintf("%s is anchoring a structure, %s left", p.OwnerCorpName, p.TimeLeft.Round(time.Minute)),
+			Payload: p,
+		}, nil
+	}
+	return notify.Event{}, fmt.Errorf("%w: %T", ErrUnsupported, payload)
+}
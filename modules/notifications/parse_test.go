@@ -0,0 +1,104 @@
+package notifications_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/notifications"
+	"github.com/guarzo/eveapi/modules/notify"
+)
+
+const underAttackText = `allianceID: 99000001
+allianceLinkData:
+- showinfo
+- 16159
+- 99000001
+allianceName: Test Alliance
+armorPercentage: 100.0
+charID: 2112000001
+corpLinkData:
+- showinfo
+- 2
+- 98000001
+corpName: Test Corp
+hullPercentage: 100.0
+shieldPercentage: 94.61
+solarsystemID: 30000142
+structureID: &id001 1021000000001
+structureShowInfoData:
+- showinfo
+- 35832
+- *id001
+structureTypeID: 35832
+`
+
+const lostShieldsText = `solarsystemID: 30000142
+structureID: &id001 1021000000001
+structureShowInfoData:
+- showinfo
+- 35832
+- *id001
+structureTypeID: 35832
+timeLeft: 864000000000
+timestamp: 133722144000000000
+vulnerableTime: 9000000000
+`
+
+type names map[int64]string
+
+func (n names) ResolveNames(ctx context.Context, ids []int64) ([]model.UniverseName, error) {
+	var out []model.UniverseName
+	for _, id := range ids {
+		out = append(out, model.UniverseName{ID: id, Name: n[id]})
+	}
+	return out, nil
+}
+
+func TestParse_StructureUnderAttack(t *testing.T) {
+	p, err := notifications.Parse(model.Notification{Type: notifications.TypeStructureUnderAttack, Text: underAttackText})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	attack := p.(*notifications.StructureUnderAttack)
+	if attack.StructureID != 1021000000001 || attack.ShieldPercentage != 94.61 || attack.CharacterID != 2112000001 {
+		t.Errorf("unexpected payload: %+v", attack)
+	}
+
+	payloads := []interface{}{p}
+	if err := notifications.ResolveNames(context.Background(), names{2112000001: "Bad Guy"}, payloads); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ev, err := notifications.Event(time.Now(), p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ev.Type != notify.EventStructureAttack || !strings.Contains(ev.Message, "Bad Guy [Test Corp] <Test Alliance>") {
+		t.Errorf("unexpected event: %+v", ev)
+	}
+}
+
+func TestParse_StructureLostShields(t *testing.T) {
+	p, err := notifications.Parse(model.Notification{Type: notifications.TypeStructureLostShields, Text: lostShieldsText})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lost := p.(*notifications.StructureLostLayer)
+	if lost.Layer != "shield" || lost.TimeLeft != 24*time.Hour || lost.VulnerableTime != 15*time.Minute {
+		t.Errorf("unexpected payload: %+v", lost)
+	}
+	want := time.Date(2024, 10, 2, 0, 0, 0, 0, time.UTC)
+	if !lost.ReinforceExits.Equal(want) {
+		t.Errorf("expected reinforcement to exit at %v, got %v", want, lost.ReinforceExits)
+	}
+}
+
+func TestParse_Unsupported(t *testing.T) {
+	_, err := notifications.Parse(model.Notification{Type: "CharLeftCorpMsg"})
+	if !errors.Is(err, notifications.ErrUnsupported) {
+		t.Errorf("expected ErrUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,3 @@
+// Package notify defines the pluggable delivery layer shared by the alerting
+// features: a Notifier interface, typed events and a few generic sinks.
+package notify
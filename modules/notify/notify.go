@@ -0,0 +1,403 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/stash"
+)
+
+// EventType identifies what happened.
+type EventType string
+
+const (
+	EventKillmail            EventType = "killmail"
+	EventStructureAttack     EventType = "structure_attack"
+	EventStashDeficit        EventType = "stash_deficit"
+	EventTokenExpired        EventType = "token_expired"
+	EventMemberJoined        EventType = "member_joined"
+	EventMemberLeft          EventType = "member_left"
+	EventWarDeclared         EventType = "war_declared"
+	EventWarFinished         EventType = "war_finished"
+	EventWarEligibility      EventType = "war_eligibility"
+	EventStructureFuel       EventType = "structure_fuel"
+	EventStructureReinforced EventType = "structure_reinforced"
+	EventStructureAnchoring  EventType = "structure_anchoring"
+	EventSovTimer            EventType = "sov_timer"
+	EventSovCampaignStarted  EventType = "sov_campaign_started"
+	EventIncursion           EventType = "incursion"
+	EventWalletCredited      EventType = "wallet_credited"
+	EventWalletDebited       EventType = "wallet_debited"
+	EventTimerReminder       EventType = "timer_reminder"
+)
+
+// Event is a single notification. Payload holds the type-specific data, e.g.
+// model.FlattenedKillMail for EventKillmail.
+type Event struct {
+	Type    EventType   `json:"type"`
+	Time    time.Time   `json:"time"`
+	Title   string      `json:"title"`
+	Message string      `json:"message"`
+	Payload interface{} `json:"payload,omitempty"`
+}
+
+// Notifier delivers events somewhere (Discord, Slack, email, a log, ...).
+type Notifier interface {
+	Notify(ctx context.Context, ev Event) error
+}
+
+// NotifierFunc adapts a function to the Notifier interface.
+type NotifierFunc func(ctx context.Context, ev Event) error
+
+// Notify calls f.
+func (f NotifierFunc) Notify(ctx context.Context, ev Event) error {
+	return f(ctx, ev)
+}
+
+// Multi fans an event out to every notifier, returning the joined errors.
+type Multi []Notifier
+
+// Notify delivers ev to every notifier, even if some fail.
+func (m Multi) Notify(ctx context.Context, ev Event) error {
+	var errs []error
+	for _, n := range m {
+		if err := n.Notify(ctx, ev); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Filtered only forwards events of the given types to next.
+func Filtered(next Notifier, types ...EventType) Notifier {
+	want := make(map[EventType]bool, len(types))
+	for _, t := range types {
+		want[t] = true
+	}
+	return NotifierFunc(func(ctx context.Context, ev Event) error {
+		if !want[ev.Type] {
+			return nil
+		}
+		return next.Notify(ctx, ev)
+	})
+}
+
+// webhook POSTs events as JSON.
+type webhook struct {
+	url    string
+	client common.HttpClient
+}
+
+// NewWebhook returns a Notifier that POSTs each event as JSON to url.
+func NewWebhook(url string, client common.HttpClient) Notifier {
+	return &webhook{url: url, client: client}
+}
+
+func (w *webhook) Notify(ctx context.Context, ev Event) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &common.HTTPError{StatusCode: resp.StatusCode}
+	}
+	return nil
+}
+
+// StructureAttack is the payload of EventStructureAttack.
+type StructureAttack struct {
+	StructureID     int64 `json:"structure_id"`
+	StructureTypeID int64 `json:"structure_type_id"`
+	SolarSystemID   int64 `json:"solar_system_id"`
+	AttackerID      int64 `json:"attacker_id,omitempty"`
+	AttackerCorpID  int64 `json:"attacker_corp_id,omitempty"`
+	// Names of the aggressor, when known.
+	AttackerName     string  `json:"attacker_name,omitempty"`
+	AttackerCorpName string  `json:"attacker_corp_name,omitempty"`
+	AttackerAlliance string  `json:"attacker_alliance,omitempty"`
+	ShieldPercent    float64 `json:"shield_percent"`
+	ArmorPercent     float64 `json:"armor_percent"`
+	HullPercent      float64 `json:"hull_percent"`
+}
+
+// TokenExpired is the payload of EventTokenExpired.
+type TokenExpired struct {
+	CharacterID   int64  `json:"character_id"`
+	CharacterName string `json:"character_name"`
+	Reason        string `json:"reason,omitempty"`
+}
+
+// MembershipChange is the payload of EventMemberJoined and EventMemberLeft.
+type MembershipChange struct {
+	CorporationID int64   `json:"corporation_id"`
+	CharacterIDs  []int64 `json:"character_ids"`
+}
+
+// WarEligibilityChange is the payload of EventWarEligibility.
+type WarEligibilityChange struct {
+	CorporationID int64 `json:"corporation_id"`
+	Eligible      bool  `json:"eligible"`
+}
+
+// WalletChange is the payload of EventWalletCredited and EventWalletDebited.
+// CorporationID and Division are set for corporation wallets, CharacterID
+// for character wallets. Entries are the journal entries seen since the
+// previous balance.
+type WalletChange struct {
+	CharacterID   int64                      `json:"character_id,omitempty"`
+	CorporationID int64                      `json:"corporation_id,omitempty"`
+	Division      int                        `json:"division,omitempty"`
+	Previous      float64                    `json:"previous"`
+	Balance       float64                    `json:"balance"`
+	Entries       []model.WalletJournalEntry `json:"entries,omitempty"`
+}
+
+// Delta is the balance change, positive for credits.
+func (wc WalletChange) Delta() float64 {
+	return wc.Balance - wc.Previous
+}
+
+// KillmailEvent builds an EventKillmail for km.
+func KillmailEvent(km model.FlattenedKillMail) Event {
+	ship := km.VictimShipName
+	if ship == "" {
+		ship = fmt.Sprintf("type %d", km.Victim.ShipTypeID)
+	}
+	return Event{
+		Type:    EventKillmail,
+		Time:    km.KillMailTime,
+		Title:   fmt.Sprintf("Killmail %d", km.KillMailID),
+		Message: fmt.Sprintf("%s destroyed (%.0f ISK)", ship, km.TotalValue),
+		Payload: km,
+	}
+}
+
+// StructureAttackEvent builds an EventStructureAttack.
+func StructureAttackEvent(at time.Time, sa StructureAttack) Event {
+	return Event{
+		Type:  EventStructureAttack,
+		Time:  at,
+		Title: fmt.Sprintf("Structure %d under attack", sa.StructureID),
+		Message: fmt.Sprintf("Shield %.0f%%, armor %.0f%%, hull %.0f%%%s",
+			sa.ShieldPercent, sa.ArmorPercent, sa.HullPercent, attackerSuffix(sa)),
+		Payload: sa,
+	}
+}
+
+// attackerSuffix describes the aggressor for StructureAttackEvent messages.
+func attackerSuffix(sa StructureAttack) string {
+	if sa.AttackerName == "" {
+		return ""
+	}
+	who := sa.AttackerName
+	if sa.AttackerCorpName != "" {
+		who += " [" + sa.AttackerCorpName + "]"
+	}
+	if sa.AttackerAlliance != "" {
+		who += " <" + sa.AttackerAlliance + ">"
+	}
+	return " by " + who
+}
+
+// StashDeficitEvent builds an EventStashDeficit.
+func StashDeficitEvent(at time.Time, deficits []stash.Deficit) Event {
+	return Event{
+		Type:    EventStashDeficit,
+		Time:    at,
+		Title:   "Stash below target",
+		Message: fmt.Sprintf("%d stash rule(s) below target", len(deficits)),
+		Payload: deficits,
+	}
+}
+
+// TokenExpiredEvent builds an EventTokenExpired.
+func TokenExpiredEvent(at time.Time, te TokenExpired) Event {
+	return Event{
+		Type:    EventTokenExpired,
+		Time:    at,
+		Title:   "Token expired",
+		Message: fmt.Sprintf("Re-authentication required for %s (%d)", te.CharacterName, te.CharacterID),
+		Payload: te,
+	}
+}
+
+// MemberJoinedEvent builds an EventMemberJoined.
+func MemberJoinedEvent(at time.Time, mc MembershipChange) Event {
+	return Event{
+		Type:    EventMemberJoined,
+		Time:    at,
+		Title:   fmt.Sprintf("Corporation %d: members joined", mc.CorporationID),
+		Message: fmt.Sprintf("%d character(s) joined", len(mc.CharacterIDs)),
+		Payload: mc,
+	}
+}
+
+// MemberLeftEvent builds an EventMemberLeft.
+func MemberLeftEvent(at time.Time, mc MembershipChange) Event {
+	return Event{
+		Type:    EventMemberLeft,
+		Time:    at,
+		Title:   fmt.Sprintf("Corporation %d: members left", mc.CorporationID),
+		Message: fmt.Sprintf("%d character(s) left", len(mc.CharacterIDs)),
+		Payload: mc,
+	}
+}
+
+// WarDeclaredEvent builds an EventWarDeclared for a war involving a tracked entity.
+func WarDeclaredEvent(at time.Time, war model.War) Event {
+	return Event{
+		Type:    EventWarDeclared,
+		Time:    at,
+		Title:   fmt.Sprintf("War %d declared", war.ID),
+		Message: fmt.Sprintf("%s declared war on %s", warPartyName(war.Aggressor), warPartyName(war.Defender)),
+		Payload: war,
+	}
+}
+
+// WarFinishedEvent builds an EventWarFinished.
+func WarFinishedEvent(at time.Time, war model.War) Event {
+	return Event{
+		Type:    EventWarFinished,
+		Time:    at,
+		Title:   fmt.Sprintf("War %d finished", war.ID),
+		Message: fmt.Sprintf("War between %s and %s has ended", warPartyName(war.Aggressor), warPartyName(war.Defender)),
+		Payload: war,
+	}
+}
+
+// WarEligibilityEvent builds an EventWarEligibility.
+func WarEligibilityEvent(at time.Time, ch WarEligibilityChange) Event {
+	state := "no longer war eligible"
+	if ch.Eligible {
+		state = "now war eligible"
+	}
+	return Event{
+		Type:    EventWarEligibility,
+		Time:    at,
+		Title:   "War eligibility changed",
+		Message: fmt.Sprintf("Corporation %d is %s", ch.CorporationID, state),
+		Payload: ch,
+	}
+}
+
+func warPartyName(p model.WarParty) string {
+	if p.AllianceID != 0 {
+		return fmt.Sprintf("alliance %d", p.AllianceID)
+	}
+	return fmt.Sprintf("corporation %d", p.CorporationID)
+}
+
+// StructureFuelEvent builds an EventStructureFuel for a structure whose fuel
+// runs out within the alert threshold.
+func StructureFuelEvent(at time.Time, s model.CorporationStructure) Event {
+	msg := "Fuel has run out"
+	if s.FuelExpires != nil && s.FuelExpires.After(at) {
+		msg = fmt.Sprintf("Fuel expires in %s", s.FuelExpires.Sub(at).Round(time.Hour))
+	}
+	return Event{
+		Type:    EventStructureFuel,
+		Time:    at,
+		Title:   fmt.Sprintf("%s low on fuel", structureName(s)),
+		Message: msg,
+		Payload: s,
+	}
+}
+
+// StructureReinforcedEvent builds an EventStructureReinforced for a structure that
+// entered armor or hull reinforcement.
+func StructureReinforcedEvent(at time.Time, s model.CorporationStructure) Event {
+	msg := fmt.Sprintf("State: %s", s.State)
+	if s.StateTimerEnd != nil {
+		msg = fmt.Sprintf("State: %s, timer ends %s", s.State, s.StateTimerEnd.UTC().Format("2006-01-02 15:04 MST"))
+	}
+	return Event{
+		Type:    EventStructureReinforced,
+		Time:    at,
+		Title:   fmt.Sprintf("%s reinforced", structureName(s)),
+		Message: msg,
+		Payload: s,
+	}
+}
+
+func structureName(s model.CorporationStructure) string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return fmt.Sprintf("Structure %d", s.StructureID)
+}
+
+// SovTimerEvent builds an EventSovTimer for a newly announced campaign.
+func SovTimerEvent(at time.Time, c model.SovereigntyCampaign) Event {
+	return Event{
+		Type:  EventSovTimer,
+		Time:  at,
+		Title: fmt.Sprintf("Sovereignty timer in system %d", c.SolarSystemID),
+		Message: fmt.Sprintf("%s campaign starts %s", c.EventType,
+			c.StartTime.UTC().Format("2006-01-02 15:04 MST")),
+		Payload: c,
+	}
+}
+
+// SovCampaignStartedEvent builds an EventSovCampaignStarted once nodes spawn.
+func SovCampaignStartedEvent(at time.Time, c model.SovereigntyCampaign) Event {
+	return Event{
+		Type:    EventSovCampaignStarted,
+		Time:    at,
+		Title:   fmt.Sprintf("Sovereignty campaign started in system %d", c.SolarSystemID),
+		Message: fmt.Sprintf("%s campaign %d is live", c.EventType, c.CampaignID),
+		Payload: c,
+	}
+}
+
+// WalletCreditedEvent builds an EventWalletCredited.
+func WalletCreditedEvent(at time.Time, wc WalletChange) Event {
+	return Event{
+		Type:    EventWalletCredited,
+		Time:    at,
+		Title:   fmt.Sprintf("%s credited", walletName(wc)),
+		Message: fmt.Sprintf("+%.2f ISK (balance %.2f ISK)", wc.Delta(), wc.Balance),
+		Payload: wc,
+	}
+}
+
+// WalletDebitedEvent builds an EventWalletDebited.
+func WalletDebitedEvent(at time.Time, wc WalletChange) Event {
+	return Event{
+		Type:    EventWalletDebited,
+		Time:    at,
+		Title:   fmt.Sprintf("%s debited", walletName(wc)),
+		Message: fmt.Sprintf("%.2f ISK (balance %.2f ISK)", wc.Delta(), wc.Balance),
+		Payload: wc,
+	}
+}
+
+func walletName(wc WalletChange) string {
+	if wc.CorporationID != 0 {
+		return fmt.Sprintf("Corporation %d wallet division %d", wc.CorporationID, wc.Division)
+	}
+	return fmt.Sprintf("Character %d wallet", wc.CharacterID)
+}
+
+// StashAlert adapts a Notifier to the stash evaluator's alert hook.
+func StashAlert(n Notifier) stash.AlertFunc {
+	return func(ctx context.Context, deficits []stash.Deficit) error {
+		return n.Notify(ctx, StashDeficitEvent(time.Now(), deficits))
+	}
+}
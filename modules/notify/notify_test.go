@@ -0,0 +1,54 @@
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/notify"
+	"github.com/guarzo/eveapi/modules/stash"
+)
+
+func TestMulti_Filtered(t *testing.T) {
+	var got []notify.EventType
+	record := notify.NotifierFunc(func(ctx context.Context, ev notify.Event) error {
+		got = append(got, ev.Type)
+		return nil
+	})
+	failing := notify.NotifierFunc(func(ctx context.Context, ev notify.Event) error {
+		return errors.New("boom")
+	})
+
+	m := notify.Multi{notify.Filtered(record, notify.EventKillmail), failing}
+	if err := m.Notify(context.Background(), notify.KillmailEvent(model.FlattenedKillMail{KillMailID: 1})); err == nil {
+		t.Error("expected joined error from failing notifier")
+	}
+	_ = m.Notify(context.Background(), notify.TokenExpiredEvent(time.Now(), notify.TokenExpired{}))
+	if len(got) != 1 || got[0] != notify.EventKillmail {
+		t.Errorf("expected only the killmail event to pass the filter, got %v", got)
+	}
+}
+
+func TestWebhook_StashAlert(t *testing.T) {
+	var received notify.Event
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	n := notify.NewWebhook(ts.URL, common.NewEveHttpClient("UA", &http.Client{}))
+	alert := notify.StashAlert(n)
+	if err := alert(context.Background(), []stash.Deficit{{SystemID: 1, Missing: 10}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if received.Type != notify.EventStashDeficit {
+		t.Errorf("unexpected event received: %#v", received)
+	}
+}
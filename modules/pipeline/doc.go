@@ -0,0 +1,20 @@
+// Package pipeline composes killmail ingestion from stages: a Source (zKill
+// pages or months, the RedisQ feed, a storage repository), Transforms
+// (hydrate, enrich, filter, dedupe) and Sinks (repository, CSV, notifier,
+// chart dashboard). Stages run concurrently with bounded buffers between
+// them, so slow sinks hold back the source, and an ErrorPolicy decides
+// whether a failure stops the run or only skips what failed.
+//
+// For example, to store a month of hydrated, named kills while writing them
+// to a CSV file:
+//
+//	csvSink, _ := pipeline.CSV(f)
+//	p := pipeline.New(pipeline.ZKillPages(zkillClient, params, 2024, 5),
+//		pipeline.WithTransforms(pipeline.Hydrate(zkill.NewHydrator(esiService)), pipeline.Enrich(esiService)),
+//		pipeline.WithSinks(pipeline.Save(repo), csvSink),
+//		pipeline.WithErrorPolicy(pipeline.SkipOnError))
+//	err := p.Run(ctx)
+//
+// zkill.ZKillService's GetKillMailDataForMonth remains the simple way to get
+// a month of kills.
+package pipeline
@@ -0,0 +1,214 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// ErrStopped is returned by a source's emit function once the pipeline has
+// been closed; the source should return.
+var ErrStopped = errors.New("pipeline: stopped")
+
+// Batch is the unit flowing through a pipeline.
+type Batch []model.FlattenedKillMail
+
+// Source produces batches and hands each to emit until it is exhausted, ctx
+// is done or emit fails. emit blocks while the stages downstream are busy,
+// so a fast source cannot run ahead of a slow sink by more than the buffer.
+// A source returns nil when exhausted or when emit returned ErrStopped.
+type Source func(ctx context.Context, emit func(Batch) error) error
+
+// Transform maps a batch to the batch passed downstream; an empty result
+// passes nothing on. On error, the kills returned alongside it still move on
+// under SkipOnError, so a transform can drop just the kills that failed.
+type Transform func(ctx context.Context, b Batch) (Batch, error)
+
+// Sink consumes batches at the end of the pipeline.
+type Sink func(ctx context.Context, b Batch) error
+
+// ErrorPolicy decides what a failing stage does to the pipeline.
+type ErrorPolicy int
+
+const (
+	// StopOnError cancels the pipeline at the first error, which Run returns.
+	StopOnError ErrorPolicy = iota
+	// SkipOnError reports errors to the error handler and keeps going; what
+	// failed is skipped. Run only returns errors from ctx.
+	SkipOnError
+)
+
+// Option customizes a Pipeline.
+type Option func(*Pipeline)
+
+// WithTransforms appends transforms, applied in order.
+func WithTransforms(t ...Transform) Option {
+	return func(p *Pipeline) {
+		p.transforms = append(p.transforms, t...)
+	}
+}
+
+// WithSinks appends sinks; every batch reaches each of them in order.
+func WithSinks(s ...Sink) Option {
+	return func(p *Pipeline) {
+		p.sinks = append(p.sinks, s...)
+	}
+}
+
+// WithBuffer sets how many batches may wait between two stages (1 by
+// default).
+func WithBuffer(n int) Option {
+	return func(p *Pipeline) {
+		p.buffer = n
+	}
+}
+
+// WithErrorPolicy sets how stage errors are handled (StopOnError by default).
+func WithErrorPolicy(policy ErrorPolicy) Option {
+	return func(p *Pipeline) {
+		p.policy = policy
+	}
+}
+
+// WithErrorHandler receives every stage error, whatever the policy.
+func WithErrorHandler(fn func(error)) Option {
+	return func(p *Pipeline) {
+		p.onError = fn
+	}
+}
+
+// Pipeline moves killmails from a source through transforms into sinks. Each
+// stage runs in its own goroutine, connected by bounded channels.
+type Pipeline struct {
+	source     Source
+	transforms []Transform
+	sinks      []Sink
+	buffer     int
+	policy     ErrorPolicy
+	onError    func(error)
+	life       common.Lifecycle
+}
+
+// New constructs a Pipeline reading from source.
+func New(source Source, opts ...Option) *Pipeline {
+	p := &Pipeline{source: source, buffer: 1}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Run runs the pipeline until the source is exhausted and every batch it
+// emitted has reached the sinks, ctx is done, the pipeline is closed or,
+// under StopOnError, a stage fails.
+func (p *Pipeline) Run(ctx context.Context) error {
+	work, stop, done := p.life.Begin(ctx)
+	defer done()
+	ctx, cancel := context.WithCancel(work)
+	defer cancel()
+
+	var (
+		once     sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		if p.onError != nil {
+			p.onError(err)
+		}
+		if p.policy == StopOnError {
+			once.Do(func() {
+				firstErr = err
+				cancel()
+			})
+		}
+	}
+
+	var wg sync.WaitGroup
+	src := make(chan Batch, p.buffer)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(src)
+		emit := func(b Batch) error {
+			select {
+			case <-stop:
+				return ErrStopped
+			default:
+			}
+			select {
+			case src <- b:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-stop:
+				return ErrStopped
+			}
+		}
+		if err := p.source(ctx, emit); err != nil && !errors.Is(err, ErrStopped) && ctx.Err() == nil {
+			fail(fmt.Errorf("pipeline source: %w", err))
+		}
+	}()
+
+	in := (<-chan Batch)(src)
+	for i, t := range p.transforms {
+		out := make(chan Batch, p.buffer)
+		wg.Add(1)
+		go func(i int, t Transform, in <-chan Batch, out chan<- Batch) {
+			defer wg.Done()
+			defer close(out)
+			for b := range in {
+				if ctx.Err() != nil {
+					continue // drain, so the stage upstream is not stuck
+				}
+				res, err := t(ctx, b)
+				if err != nil {
+					fail(fmt.Errorf("pipeline transform %d: %w", i, err))
+					if p.policy == StopOnError {
+						continue
+					}
+				}
+				if len(res) == 0 {
+					continue
+				}
+				select {
+				case out <- res:
+				case <-ctx.Done():
+				}
+			}
+		}(i, t, in, out)
+		in = out
+	}
+
+	for b := range in {
+		if ctx.Err() != nil {
+			continue
+		}
+		for i, s := range p.sinks {
+			if err := s(ctx, b); err != nil {
+				fail(fmt.Errorf("pipeline sink %d: %w", i, err))
+			}
+		}
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return work.Err()
+}
+
+// Close stops the source and waits for the batches already emitted to reach
+// the sinks and for Run to return.
+func (p *Pipeline) Close() error {
+	return p.life.Close()
+}
+
+// Shutdown is Close, except that the stages are cancelled when ctx is done
+// before the pipeline drains.
+func (p *Pipeline) Shutdown(ctx context.Context) error {
+	return p.life.Shutdown(ctx)
+}
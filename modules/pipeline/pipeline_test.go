@@ -0,0 +1,136 @@
+package pipeline_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/esi"
+	"github.com/guarzo/eveapi/modules/killmail"
+	"github.com/guarzo/eveapi/modules/mock"
+	"github.com/guarzo/eveapi/modules/pipeline"
+	"github.com/guarzo/eveapi/modules/storage"
+	"github.com/guarzo/eveapi/modules/zkill"
+)
+
+func TestPipeline_ZKillToRepositoryAndCSV(t *testing.T) {
+	ctx := context.Background()
+	client := mock.NewHttpClient(3)
+	zk := zkill.NewZkillClient("https://zkillboard.com", client, common.NewMemoryCache())
+	esiSvc := esi.NewEsiService(esi.NewEsiClient("https://esi.evetech.net/latest/", client, common.NewMemoryCache(), nil))
+
+	repo := storage.NewMemoryKillmailRepository()
+	var buf bytes.Buffer
+	csvSink, err := pipeline.CSV(&buf, "killmail_id", "killmail_time")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var collected []model.FlattenedKillMail
+	params := &model.Params{Characters: []model.CharacterID{mock.FirstCharacterID + 1}}
+	p := pipeline.New(
+		pipeline.Concat(pipeline.ZKillPages(zk, params, 2024, 5), pipeline.ZKillPages(zk, params, 2024, 5)),
+		pipeline.WithTransforms(
+			pipeline.Dedupe(killmail.NewMemorySeenStore(100)),
+			pipeline.Hydrate(zkill.NewHydrator(esiSvc, zkill.WithHydrationConcurrency(2))),
+		),
+		pipeline.WithSinks(pipeline.Save(repo), csvSink, pipeline.Collect(&collected)))
+	if err := p.Run(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(collected) == 0 {
+		t.Fatal("expected kills")
+	}
+	stored, _ := repo.Query(ctx, storage.KillmailQuery{})
+	if len(stored) != len(collected) {
+		t.Errorf("expected each kill stored once, got %d stored of %d", len(stored), len(collected))
+	}
+	for _, km := range collected {
+		if km.KillMailTime.IsZero() || km.Hash == "" {
+			t.Errorf("expected hydrated kills with zKill data, got %+v", km)
+		}
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != len(collected)+1 {
+		t.Errorf("expected a header and a row per kill, got %d lines", lines)
+	}
+}
+
+func TestPipeline_ErrorPolicy(t *testing.T) {
+	kills := []model.FlattenedKillMail{{KillMailID: 1}, {KillMailID: 2}, {KillMailID: 3}}
+	source := func(ctx context.Context, emit func(pipeline.Batch) error) error {
+		for _, km := range kills {
+			if err := emit(pipeline.Batch{km}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	boom := errors.New("boom")
+	failOnTwo := func(ctx context.Context, b pipeline.Batch) (pipeline.Batch, error) {
+		if b[0].KillMailID == 2 {
+			return nil, boom
+		}
+		return b, nil
+	}
+
+	var skipped []model.FlattenedKillMail
+	var handled []error
+	err := pipeline.New(source,
+		pipeline.WithTransforms(failOnTwo),
+		pipeline.WithSinks(pipeline.Collect(&skipped)),
+		pipeline.WithErrorPolicy(pipeline.SkipOnError),
+		pipeline.WithErrorHandler(func(err error) { handled = append(handled, err) }),
+	).Run(context.Background())
+	if err != nil || len(skipped) != 2 || len(handled) != 1 || !errors.Is(handled[0], boom) {
+		t.Errorf("expected the failed batch to be skipped, got err=%v kills=%v errors=%v", err, skipped, handled)
+	}
+
+	var stopped []model.FlattenedKillMail
+	err = pipeline.New(source,
+		pipeline.WithTransforms(failOnTwo),
+		pipeline.WithSinks(pipeline.Collect(&stopped)),
+	).Run(context.Background())
+	if !errors.Is(err, boom) || len(stopped) > 1 {
+		t.Errorf("expected the pipeline to stop at the error, got err=%v kills=%v", err, stopped)
+	}
+}
+
+func TestPipeline_CloseDrains(t *testing.T) {
+	emitted := 0
+	source := func(ctx context.Context, emit func(pipeline.Batch) error) error {
+		for id := int64(1); ; id++ {
+			if err := emit(pipeline.Batch{{KillMailID: id}}); err != nil {
+				return err
+			}
+			emitted++
+		}
+	}
+	var got []model.FlattenedKillMail
+	slow := func(ctx context.Context, b pipeline.Batch) error {
+		time.Sleep(5 * time.Millisecond)
+		got = append(got, b...)
+		return nil
+	}
+	p := pipeline.New(source, pipeline.WithSinks(slow))
+	done := make(chan error, 1)
+	go func() { done <- p.Run(context.Background()) }()
+	time.Sleep(30 * time.Millisecond)
+
+	if err := p.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("expected a clean stop, got %v", err)
+	}
+	if len(got) != emitted {
+		t.Errorf("expected every emitted batch to reach the sink, emitted %d, got %d", emitted, len(got))
+	}
+	if emitted > 12 {
+		t.Errorf("expected the slow sink to hold the source back, %d batches emitted", emitted)
+	}
+}
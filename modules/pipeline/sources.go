@@ -0,0 +1,147 @@
+package pipeline
+
+import (
+	"context"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/storage"
+	"github.com/guarzo/eveapi/modules/zkill"
+)
+
+// maxZKillPages bounds how many pages ZKillPages reads per entity and list,
+// as zkill.ZKillService does.
+const maxZKillPages = 100
+
+// Kills emits kills as one batch.
+func Kills(kills []model.FlattenedKillMail) Source {
+	return func(ctx context.Context, emit func(Batch) error) error {
+		if len(kills) == 0 {
+			return nil
+		}
+		return emit(kills)
+	}
+}
+
+// Concat runs sources one after the other.
+func Concat(sources ...Source) Source {
+	return func(ctx context.Context, emit func(Batch) error) error {
+		for _, s := range sources {
+			if err := s(ctx, emit); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// ZKillPages emits every page of kills and losses zKillboard lists for the
+// entities of params in the given month, one batch per page. The kills carry
+// zKill data only; add a Hydrate transform for their ESI details. The
+// NPC, solo and awox flags of params are applied.
+func ZKillPages(client zkill.ZKillClient, params *model.Params, year, month int) Source {
+	return func(ctx context.Context, emit func(Batch) error) error {
+		groups := []struct {
+			kind string
+			ids  []int64
+		}{
+			{"character", params.Characters},
+			{"corporation", params.Corporations},
+			{"alliance", params.Alliances},
+		}
+		lists := []func(ctx context.Context, entityType string, entityID int64, page, year, month int) ([]model.ZkillMail, error){
+			client.GetKillsPageData, client.GetLossPageData,
+		}
+		for _, g := range groups {
+			for _, id := range g.ids {
+				for _, list := range lists {
+					for page := 1; page <= maxZKillPages; page++ {
+						mails, err := list(ctx, g.kind, id, page, year, month)
+						if err != nil {
+							return err
+						}
+						if len(mails) == 0 {
+							break
+						}
+						var b Batch
+						for _, m := range mails {
+							if keepZKB(params, m.ZKB) {
+								b = append(b, model.ConvertToFlattened(model.EsiKillMail{KillMailID: m.KillMailID}, m))
+							}
+						}
+						if len(b) == 0 {
+							continue
+						}
+						if err := emit(b); err != nil {
+							return err
+						}
+					}
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// keepZKB applies the Params flag filters to a kill's zkb block.
+func keepZKB(params *model.Params, zkb model.ZKB) bool {
+	return !(params.ExcludeNPC && zkb.NPC) && !(params.SoloOnly && !zkb.Solo) && !(params.ExcludeAwox && zkb.Awox)
+}
+
+// ZKillMonths emits the hydrated kills of every month from start to end
+// through svc.GetKillMailDataForMonth, one batch per month.
+func ZKillMonths(svc zkill.ZKillService, params *model.Params, start, end time.Time) Source {
+	return func(ctx context.Context, emit func(Batch) error) error {
+		first := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC)
+		for m := first; m.Before(end); m = m.AddDate(0, 1, 0) {
+			kills, err := svc.GetKillMailDataForMonth(ctx, params, m.Year(), int(m.Month()))
+			if err != nil {
+				return err
+			}
+			if len(kills) == 0 {
+				continue
+			}
+			if err := emit(kills); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// RedisQ emits kills from zKillboard's live feed, one per batch, until ctx
+// is done or the pipeline is closed. Feed errors go to onError, if non-nil.
+func RedisQ(q *zkill.RedisQ, onError func(error)) Source {
+	return func(ctx context.Context, emit func(Batch) error) error {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		for km := range q.Stream(ctx, onError) {
+			if err := emit(Batch{km}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// Repository emits the stored kills matching q in batches of batchSize
+// (all at once if batchSize <= 0).
+func Repository(repo storage.KillmailRepository, q storage.KillmailQuery, batchSize int) Source {
+	return func(ctx context.Context, emit func(Batch) error) error {
+		kills, err := repo.Query(ctx, q)
+		if err != nil {
+			return err
+		}
+		if batchSize <= 0 {
+			batchSize = len(kills)
+		}
+		for len(kills) > 0 {
+			n := min(batchSize, len(kills))
+			if err := emit(kills[:n]); err != nil {
+				return err
+			}
+			kills = kills[n:]
+		}
+		return nil
+	}
+}
@@ -0,0 +1,107 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/charts"
+	"github.com/guarzo/eveapi/modules/export"
+	"github.com/guarzo/eveapi/modules/killmail"
+	"github.com/guarzo/eveapi/modules/notify"
+	"github.com/guarzo/eveapi/modules/storage"
+	"github.com/guarzo/eveapi/modules/zkill"
+)
+
+// Hydrate fetches the ESI details of kills that lack them with h, whose
+// options set the concurrency, price backfill and what happens to kills that
+// fail; under FailOnHydrationError the whole batch fails.
+func Hydrate(h *zkill.Hydrator) Transform {
+	return func(ctx context.Context, b Batch) (Batch, error) {
+		return h.HydrateAll(ctx, append(Batch(nil), b...))
+	}
+}
+
+// Enrich resolves ship, weapon and system names with killmail.EnrichKillMails.
+func Enrich(r killmail.NameResolver, opts ...killmail.EnrichOption) Transform {
+	return func(ctx context.Context, b Batch) (Batch, error) {
+		out := append(Batch(nil), b...)
+		if err := killmail.EnrichKillMails(ctx, r, out, opts...); err != nil {
+			return b, err
+		}
+		return out, nil
+	}
+}
+
+// Filter passes on the kills matching f.
+func Filter(f killmail.Filter) Transform {
+	return func(ctx context.Context, b Batch) (Batch, error) {
+		return killmail.Apply(b, f), nil
+	}
+}
+
+// Dedupe passes on kills seen for the first time, remembering them in s.
+func Dedupe(s killmail.SeenStore) Transform {
+	return func(ctx context.Context, b Batch) (Batch, error) {
+		var out Batch
+		for _, km := range b {
+			fresh, err := s.MarkSeen(ctx, km.KillMailID)
+			if err != nil {
+				return out, err
+			}
+			if fresh {
+				out = append(out, km)
+			}
+		}
+		return out, nil
+	}
+}
+
+// Save stores every batch in repo.
+func Save(repo storage.KillmailRepository) Sink {
+	return func(ctx context.Context, b Batch) error {
+		return repo.Save(ctx, b...)
+	}
+}
+
+// CSV writes every batch to w as rows of one CSV file with the given
+// columns (export.DefaultKillmailColumns if none).
+func CSV(w io.Writer, columns ...string) (Sink, error) {
+	kw, err := export.NewKillmailCSVWriter(w, columns...)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, b Batch) error {
+		return kw.Write(b)
+	}, nil
+}
+
+// Notify sends an EventKillmail per kill to n.
+func Notify(n notify.Notifier) Sink {
+	return func(ctx context.Context, b Batch) error {
+		var errs []error
+		for _, km := range b {
+			if err := n.Notify(ctx, notify.KillmailEvent(km)); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}
+}
+
+// Dashboard adds every batch to d, re-preparing the chart frames it touches.
+func Dashboard(d *charts.Dashboard) Sink {
+	return func(ctx context.Context, b Batch) error {
+		_, err := d.Add(b)
+		return err
+	}
+}
+
+// Collect appends every kill to *dst. Read *dst once Run has returned.
+func Collect(dst *[]model.FlattenedKillMail) Sink {
+	return func(ctx context.Context, b Batch) error {
+		*dst = append(*dst, b...)
+		return nil
+	}
+}
@@ -0,0 +1,155 @@
+package skills
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// DefaultSPPerMinute is the training rate assumed when none is configured:
+// 1,800 SP/hour, a character with balanced attributes and no implants.
+const DefaultSPPerMinute = 30.0
+
+// TypeSource is the part of esi.EsiService used to look up dogma attributes.
+type TypeSource interface {
+	GetType(ctx context.Context, typeID model.TypeID) (*model.ItemType, error)
+}
+
+// MissingSkill is a requirement the character has not trained.
+type MissingSkill struct {
+	SkillID      int64         `json:"skill_id"`
+	Required     int           `json:"required"`
+	Trained      int           `json:"trained"`
+	SkillPoints  int64         `json:"skill_points"` // SP still needed
+	TrainingTime time.Duration `json:"training_time"`
+	RequiredBy   []int64       `json:"required_by"` // hull, module or skill type IDs
+}
+
+// FitCheck is the result of CanUseFit.
+type FitCheck struct {
+	CanUse            bool           `json:"can_use"`
+	Missing           []MissingSkill `json:"missing,omitempty"` // sorted by skill ID
+	TotalTrainingTime time.Duration  `json:"total_training_time"`
+}
+
+// Checker compares trained skills with the requirements of fittings.
+// Type lookups are cached for the lifetime of the Checker.
+type Checker struct {
+	types       TypeSource
+	spPerMinute float64
+
+	mu    sync.Mutex
+	cache map[int64]*model.ItemType
+}
+
+// NewChecker constructs a Checker using DefaultSPPerMinute.
+func NewChecker(types TypeSource) *Checker {
+	return &Checker{types: types, spPerMinute: DefaultSPPerMinute, cache: make(map[int64]*model.ItemType)}
+}
+
+// SetTrainingRate sets the SP per minute used for training time estimates.
+func (c *Checker) SetTrainingRate(spPerMinute float64) {
+	if spPerMinute > 0 {
+		c.spPerMinute = spPerMinute
+	}
+}
+
+// CanUseFit checks skills against the requirements of fit's hull and every
+// fitted item, including the prerequisites of the required skills.
+func (c *Checker) CanUseFit(ctx context.Context, skills []model.CharacterSkill, fit model.Fitting) (*FitCheck, error) {
+	typeIDs := []int64{fit.ShipTypeID}
+	for _, it := range fit.Items {
+		typeIDs = append(typeIDs, it.TypeID)
+	}
+
+	required := make(map[int64]int)
+	requiredBy := make(map[int64]map[int64]bool)
+	visited := make(map[int64]bool)
+	var walk func(typeID model.TypeID) error
+	walk = func(typeID model.TypeID) error {
+		if visited[typeID] {
+			return nil
+		}
+		visited[typeID] = true
+		t, err := c.getType(ctx, typeID)
+		if err != nil {
+			return err
+		}
+		for skillID, level := range Requirements(t) {
+			if level > required[skillID] {
+				required[skillID] = level
+			}
+			if requiredBy[skillID] == nil {
+				requiredBy[skillID] = make(map[int64]bool)
+			}
+			requiredBy[skillID][typeID] = true
+			if err := walk(skillID); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, id := range typeIDs {
+		if err := walk(id); err != nil {
+			return nil, err
+		}
+	}
+
+	trained := make(map[int64]model.CharacterSkill, len(skills))
+	for _, s := range skills {
+		trained[s.SkillID] = s
+	}
+
+	out := &FitCheck{CanUse: true}
+	for skillID, level := range required {
+		have := trained[skillID]
+		if have.TrainedSkillLevel >= level {
+			continue
+		}
+		skill, err := c.getType(ctx, skillID)
+		if err != nil {
+			return nil, err
+		}
+		need := SkillPointsForLevel(Rank(skill), level) - have.SkillpointsInSkill
+		if need < 0 {
+			need = 0
+		}
+		m := MissingSkill{
+			SkillID:      skillID,
+			Required:     level,
+			Trained:      have.TrainedSkillLevel,
+			SkillPoints:  need,
+			TrainingTime: time.Duration(float64(need) / c.spPerMinute * float64(time.Minute)),
+		}
+		for id := range requiredBy[skillID] {
+			m.RequiredBy = append(m.RequiredBy, id)
+		}
+		sort.Slice(m.RequiredBy, func(i, j int) bool { return m.RequiredBy[i] < m.RequiredBy[j] })
+		out.Missing = append(out.Missing, m)
+		out.TotalTrainingTime += m.TrainingTime
+	}
+	sort.Slice(out.Missing, func(i, j int) bool { return out.Missing[i].SkillID < out.Missing[j].SkillID })
+	out.CanUse = len(out.Missing) == 0
+	return out, nil
+}
+
+func (c *Checker) getType(ctx context.Context, typeID model.TypeID) (*model.ItemType, error) {
+	c.mu.Lock()
+	t, ok := c.cache[typeID]
+	c.mu.Unlock()
+	if ok {
+		return t, nil
+	}
+	t, err := c.types.GetType(ctx, typeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get type %d: %w", typeID, err)
+	}
+	c.mu.Lock()
+	c.cache[typeID] = t
+	c.mu.Unlock()
+	return t, nil
+}
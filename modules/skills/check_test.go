@@ -0,0 +1,78 @@
+package skills_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/skills"
+)
+
+const (
+	rifter        = 587
+	minmatarFrig  = 3329
+	spaceshipCmd  = 3327
+	gunnery       = 3300
+	smallProjTurr = 3302
+	autocannon    = 484
+)
+
+func req(pairs ...float64) []model.DogmaAttribute {
+	attrs := []int64{182, 277, 183, 278}
+	var out []model.DogmaAttribute
+	for i, v := range pairs {
+		out = append(out, model.DogmaAttribute{AttributeID: attrs[i], Value: v})
+	}
+	return out
+}
+
+type typeSource map[int64]*model.ItemType
+
+func (s typeSource) GetType(ctx context.Context, typeID model.TypeID) (*model.ItemType, error) {
+	if t, ok := s[typeID]; ok {
+		return t, nil
+	}
+	return &model.ItemType{TypeID: typeID}, nil
+}
+
+func TestCanUseFit(t *testing.T) {
+	types := typeSource{
+		rifter:        {TypeID: rifter, DogmaAttributes: req(minmatarFrig, 1)},
+		minmatarFrig:  {TypeID: minmatarFrig, DogmaAttributes: append(req(spaceshipCmd, 1), model.DogmaAttribute{AttributeID: 275, Value: 2})},
+		autocannon:    {TypeID: autocannon, DogmaAttributes: req(smallProjTurr, 1, gunnery, 1)},
+		smallProjTurr: {TypeID: smallProjTurr, DogmaAttributes: req(gunnery, 1)},
+	}
+	fit := model.Fitting{ShipTypeID: rifter, Items: []model.FittingItem{{TypeID: autocannon, Flag: "HiSlot0", Quantity: 1}}}
+	c := skills.NewChecker(types)
+
+	trained := []model.CharacterSkill{
+		{SkillID: spaceshipCmd, TrainedSkillLevel: 1},
+		{SkillID: gunnery, TrainedSkillLevel: 2},
+		{SkillID: smallProjTurr, TrainedSkillLevel: 1},
+	}
+	res, err := c.CanUseFit(context.Background(), trained, fit)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.CanUse || len(res.Missing) != 1 {
+		t.Fatalf("expected one missing skill, got %+v", res)
+	}
+	m := res.Missing[0]
+	if m.SkillID != minmatarFrig || m.SkillPoints != 500 || m.TrainingTime != 500*time.Minute/30 {
+		t.Errorf("unexpected missing skill: %+v", m)
+	}
+
+	trained = append(trained, model.CharacterSkill{SkillID: minmatarFrig, TrainedSkillLevel: 3})
+	if res, _ := c.CanUseFit(context.Background(), trained, fit); !res.CanUse {
+		t.Errorf("expected fit to be usable, got %+v", res)
+	}
+}
+
+func TestSkillPointsForLevel(t *testing.T) {
+	for level, want := range map[int]int64{1: 250, 2: 1415, 3: 8000, 4: 45255, 5: 256000} {
+		if got := skills.SkillPointsForLevel(1, level); got != want {
+			t.Errorf("level %d: expected %d, got %d", level, want, got)
+		}
+	}
+}
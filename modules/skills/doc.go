@@ -0,0 +1,4 @@
+// Package skills resolves the skill requirements of ships and modules from
+// their dogma attributes and checks them against a character's trained
+// skills, estimating the training time for anything missing.
+package skills
@@ -0,0 +1,59 @@
+package skills
+
+import (
+	"math"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// Dogma attribute IDs for skill requirements. Each requiredSkillN attribute
+// holds a skill type ID and is paired with a requiredSkillNLevel attribute.
+const (
+	AttrSkillTimeConstant int64 = 275 // skill rank
+)
+
+var requirementAttrs = [][2]int64{
+	{182, 277},   // requiredSkill1, requiredSkill1Level
+	{183, 278},   // requiredSkill2
+	{184, 279},   // requiredSkill3
+	{1285, 1286}, // requiredSkill4
+	{1289, 1287}, // requiredSkill5
+	{1290, 1288}, // requiredSkill6
+}
+
+// Requirements returns the skills (skill type ID -> level) directly
+// required to use t. Prerequisites of those skills are not included.
+func Requirements(t *model.ItemType) map[int64]int {
+	out := make(map[int64]int)
+	for _, pair := range requirementAttrs {
+		skill, ok := t.Attribute(pair[0])
+		if !ok || skill == 0 {
+			continue
+		}
+		level, _ := t.Attribute(pair[1])
+		if int(level) > out[int64(skill)] {
+			out[int64(skill)] = int(level)
+		}
+	}
+	return out
+}
+
+// Rank returns a skill's training time multiplier, defaulting to 1.
+func Rank(skill *model.ItemType) float64 {
+	if rank, ok := skill.Attribute(AttrSkillTimeConstant); ok && rank > 0 {
+		return rank
+	}
+	return 1
+}
+
+// SkillPointsForLevel returns the total skill points needed to reach level
+// in a skill of the given rank.
+func SkillPointsForLevel(rank float64, level int) int64 {
+	if level <= 0 {
+		return 0
+	}
+	sp := 250 * rank * math.Pow(math.Sqrt(32), float64(level-1))
+	// Round up fractional levels (1414.2 -> 1415) without letting float error
+	// push exact values (8000.000001) over.
+	return int64(math.Ceil(sp - 1e-6))
+}
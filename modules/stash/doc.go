@@ -0,0 +1,3 @@
+// Package stash evaluates stash inventories against target quantities per
+// system and reports deficits, optionally pushing them to an alert hook.
+package stash
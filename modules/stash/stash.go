@@ -0,0 +1,179 @@
+package stash
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// Rule requires Target units of an item to be stocked in a system.
+// Items are matched by ID when set, otherwise by name.
+type Rule struct {
+	SystemID int64
+	Item     model.Item
+}
+
+// Deficit describes a rule that is not met.
+type Deficit struct {
+	SystemID   int64  `json:"system_id"`
+	SystemName string `json:"system_name"`
+	ItemID     int64  `json:"item_id"`
+	ItemName   string `json:"item_name"`
+	Target     int    `json:"target"`
+	Have       int    `json:"have"`
+	Missing    int    `json:"missing"`
+}
+
+// AlertFunc is invoked with the deficits found by Check, when there are any.
+type AlertFunc func(ctx context.Context, deficits []Deficit) error
+
+// Evaluator holds a rule set and an optional alert hook.
+type Evaluator struct {
+	rules   []Rule
+	onAlert AlertFunc
+}
+
+// Option customizes an Evaluator.
+type Option func(*Evaluator)
+
+// WithAlert registers the hook Check calls when deficits are found.
+func WithAlert(fn AlertFunc) Option {
+	return func(e *Evaluator) {
+		e.onAlert = fn
+	}
+}
+
+// NewEvaluator constructs an Evaluator for the given rules.
+func NewEvaluator(rules []Rule, opts ...Option) *Evaluator {
+	e := &Evaluator{rules: rules}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Evaluate compares stashes against the rules and returns every deficit,
+// ordered by system then item. A system with no stash counts as empty.
+func (e *Evaluator) Evaluate(stashes []model.Stash) []Deficit {
+	bySystem := make(map[int64]model.Stash, len(stashes))
+	for _, st := range stashes {
+		existing, ok := bySystem[st.SystemId]
+		if ok {
+			existing.Inventory = slices.Concat(existing.Inventory, st.Inventory)
+			st = existing
+		}
+		bySystem[st.SystemId] = st
+	}
+
+	var out []Deficit
+	for _, r := range e.rules {
+		st := bySystem[r.SystemID]
+		have := 0
+		for _, it := range st.Inventory {
+			if itemMatches(r.Item, it) {
+				have += it.Qty
+			}
+		}
+		if have >= r.Item.Qty {
+			continue
+		}
+		out = append(out, Deficit{
+			SystemID:   r.SystemID,
+			SystemName: st.SystemName,
+			ItemID:     r.Item.ID,
+			ItemName:   r.Item.Name,
+			Target:     r.Item.Qty,
+			Have:       have,
+			Missing:    r.Item.Qty - have,
+		})
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].SystemID != out[j].SystemID {
+			return out[i].SystemID < out[j].SystemID
+		}
+		return out[i].ItemName < out[j].ItemName
+	})
+	return out
+}
+
+// EvaluateInventories converts LocationInventory results (as returned by the ESI
+// asset calls) into per-system stashes using systemOf, then evaluates them.
+func (e *Evaluator) EvaluateInventories(invs []model.LocationInventory, systemOf func(locID int64) int64) []Deficit {
+	return e.Evaluate(StashesFromInventories(invs, systemOf))
+}
+
+// Check evaluates stashes and, when deficits exist and an alert hook is set,
+// passes them to the hook.
+func (e *Evaluator) Check(ctx context.Context, stashes []model.Stash) ([]Deficit, error) {
+	deficits := e.Evaluate(stashes)
+	if len(deficits) == 0 || e.onAlert == nil {
+		return deficits, nil
+	}
+	if err := e.onAlert(ctx, deficits); err != nil {
+		return deficits, fmt.Errorf("stash alert failed: %w", err)
+	}
+	return deficits, nil
+}
+
+// StashesFromInventories groups name-keyed inventories into one Stash per system.
+func StashesFromInventories(invs []model.LocationInventory, systemOf func(locID int64) int64) []model.Stash {
+	bySystem := make(map[int64]*model.Stash)
+	var order []int64
+	for _, inv := range invs {
+		sysID := systemOf(inv.LocID)
+		st, ok := bySystem[sysID]
+		if !ok {
+			st = &model.Stash{SystemId: sysID}
+			bySystem[sysID] = st
+			order = append(order, sysID)
+		}
+		for name, qty := range inv.Items {
+			st.Inventory = append(st.Inventory, model.Item{Name: name, Qty: qty})
+		}
+	}
+
+	out := make([]model.Stash, 0, len(order))
+	for _, id := range order {
+		out = append(out, *bySystem[id])
+	}
+	return out
+}
+
+// WebhookAlert returns an AlertFunc that POSTs the deficits as JSON to url.
+func WebhookAlert(url string, client common.HttpClient) AlertFunc {
+	return func(ctx context.Context, deficits []Deficit) error {
+		body, err := json.Marshal(deficits)
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return &common.HTTPError{StatusCode: resp.StatusCode}
+		}
+		return nil
+	}
+}
+
+func itemMatches(rule, have model.Item) bool {
+	if rule.ID != 0 && have.ID != 0 {
+		return rule.ID == have.ID
+	}
+	return rule.Name != "" && rule.Name == have.Name
+}
@@ -0,0 +1,72 @@
+package stash_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/stash"
+)
+
+func TestEvaluator_Check(t *testing.T) {
+	ozone := model.Item{ID: 16273, Name: "Liquid Ozone", Qty: 200}
+	rules := []stash.Rule{
+		{SystemID: 30000142, Item: ozone},
+		{SystemID: 30002187, Item: ozone},
+	}
+
+	var alerted []stash.Deficit
+	ev := stash.NewEvaluator(rules, stash.WithAlert(func(ctx context.Context, d []stash.Deficit) error {
+		alerted = d
+		return nil
+	}))
+
+	stashes := []model.Stash{
+		{SystemId: 30000142, SystemName: "Jita", Inventory: []model.Item{{ID: 16273, Qty: 250}}},
+		{SystemId: 30002187, SystemName: "Amarr", Inventory: []model.Item{{ID: 16273, Qty: 50}}},
+	}
+
+	deficits, err := ev.Check(context.Background(), stashes)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(deficits) != 1 || len(alerted) != 1 {
+		t.Fatalf("expected 1 deficit and 1 alert, got %d / %d", len(deficits), len(alerted))
+	}
+	if d := deficits[0]; d.SystemName != "Amarr" || d.Have != 50 || d.Missing != 150 {
+		t.Errorf("unexpected deficit: %#v", d)
+	}
+}
+
+func TestEvaluator_EvaluateInventories(t *testing.T) {
+	rules := []stash.Rule{{SystemID: 1, Item: model.Item{Name: "Liquid Ozone", Qty: 200}}}
+	ev := stash.NewEvaluator(rules)
+
+	invs := []model.LocationInventory{
+		{LocID: 100, Items: map[string]int{"Liquid Ozone": 120}},
+		{LocID: 101, Items: map[string]int{"Liquid Ozone": 90}},
+	}
+	deficits := ev.EvaluateInventories(invs, func(locID int64) int64 { return 1 })
+	if len(deficits) != 0 {
+		t.Errorf("expected no deficits when stations in the same system add up, got %#v", deficits)
+	}
+}
+
+func TestEvaluator_EvaluateMergesStashesWithoutAliasing(t *testing.T) {
+	ozone := model.Item{ID: 16273, Name: "Liquid Ozone", Qty: 200}
+	ev := stash.NewEvaluator([]stash.Rule{{SystemID: 30000142, Item: ozone}})
+
+	first := make([]model.Item, 1, 4) // spare capacity an append would write into
+	first[0] = model.Item{ID: 16273, Qty: 120}
+	stashes := []model.Stash{
+		{SystemId: 30000142, SystemName: "Jita", Inventory: first},
+		{SystemId: 30000142, SystemName: "Jita", Inventory: []model.Item{{ID: 16273, Qty: 100}}},
+	}
+
+	if deficits := ev.Evaluate(stashes); len(deficits) != 0 {
+		t.Errorf("expected both stashes to count toward the system, got %+v", deficits)
+	}
+	if spare := first[:2]; spare[1] != (model.Item{}) {
+		t.Errorf("expected the caller's inventory to be left alone, got %+v", spare)
+	}
+}
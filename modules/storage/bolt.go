@@ -0,0 +1,510 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/common/model"
+)
+
+var (
+	cacheBucket        = []byte("cache")
+	killmailBucket     = []byte("killmails")
+	killmailTimeIdx    = []byte("killmails_by_time")
+	snapshotBucket     = []byte("snapshots")
+	timerBucket        = []byte("timers")
+	boltBucketsInOrder = [][]byte{cacheBucket, killmailBucket, killmailTimeIdx, snapshotBucket, timerBucket}
+)
+
+// BoltStore is a single-file embedded store (bbolt) that provides both a
+// CacheRepository and a KillmailRepository, for deployments without Redis or SQL.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// OpenBoltStore opens (or creates) the store at path.
+func OpenBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bolt store: %w", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, b := range boltBucketsInOrder {
+			if _, err := tx.CreateBucketIfNotExists(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &BoltStore{db: db}, nil
+}
+
+// Close releases the underlying file.
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+// Cache returns a CacheRepository view of the store.
+func (s *BoltStore) Cache() common.CacheRepository {
+	return &boltCache{db: s.db}
+}
+
+// Killmails returns a KillmailRepository view of the store.
+func (s *BoltStore) Killmails() KillmailRepository {
+	return &boltKillmails{db: s.db}
+}
+
+// Snapshots returns a SnapshotRepository view of the store.
+func (s *BoltStore) Snapshots() SnapshotRepository {
+	return &boltSnapshots{db: s.db}
+}
+
+// Timers returns a TimerRepository view of the store.
+func (s *BoltStore) Timers() TimerRepository {
+	return &boltTimers{db: s.db}
+}
+
+// ---------------------------------------------------------------------------
+// CacheRepository
+// ---------------------------------------------------------------------------
+
+// boltCache stores each value prefixed with its expiry (unix nanos, 0 = never).
+type boltCache struct {
+	db *bolt.DB
+}
+
+func (c *boltCache) Get(key string) ([]byte, bool) {
+	v, found, err := c.GetCtx(context.Background(), key)
+	return v, found && err == nil
+}
+
+func (c *boltCache) Set(key string, value []byte, expiration time.Duration) {
+	_ = c.SetCtx(context.Background(), key, value, expiration)
+}
+
+func (c *boltCache) Delete(key string) {
+	_ = c.DeleteCtx(context.Background(), key)
+}
+
+// GetCtx implements common.ContextCacheRepository.
+func (c *boltCache) GetCtx(ctx context.Context, key string) ([]byte, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
+	var out []byte
+	expired := false
+	err := c.db.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(cacheBucket).Get([]byte(key))
+		if len(raw) < 8 {
+			return nil
+		}
+		exp := int64(binary.BigEndian.Uint64(raw[:8]))
+		if exp != 0 && time.Now().UnixNano() > exp {
+			expired = true
+			return nil
+		}
+		out = append([]byte(nil), raw[8:]...)
+		return nil
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read cache key %s: %w", key, err)
+	}
+	if expired {
+		return nil, false, c.DeleteCtx(ctx, key)
+	}
+	return out, out != nil, nil
+}
+
+// SetCtx implements common.ContextCacheRepository.
+func (c *boltCache) SetCtx(ctx context.Context, key string, value []byte, expiration time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	var exp int64
+	if expiration > 0 {
+		exp = time.Now().Add(expiration).UnixNano()
+	}
+	raw := make([]byte, 8+len(value))
+	binary.BigEndian.PutUint64(raw[:8], uint64(exp))
+	copy(raw[8:], value)
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(cacheBucket).Put([]byte(key), raw)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write cache key %s: %w", key, err)
+	}
+	return nil
+}
+
+// DeleteCtx implements common.ContextCacheRepository.
+func (c *boltCache) DeleteCtx(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(cacheBucket).Delete([]byte(key))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete cache key %s: %w", key, err)
+	}
+	return nil
+}
+
+// MGet implements common.BatchCacheRepository with a single read transaction.
+func (c *boltCache) MGet(ctx context.Context, keys []string) (map[string][]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	now := time.Now().UnixNano()
+	out := make(map[string][]byte, len(keys))
+	err := c.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(cacheBucket)
+		for _, key := range keys {
+			raw := b.Get([]byte(key))
+			if len(raw) < 8 {
+				continue
+			}
+			if exp := int64(binary.BigEndian.Uint64(raw[:8])); exp != 0 && now > exp {
+				continue
+			}
+			out[key] = append([]byte(nil), raw[8:]...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache keys: %w", err)
+	}
+	return out, nil
+}
+
+// MSet implements common.BatchCacheRepository with a single write transaction.
+func (c *boltCache) MSet(ctx context.Context, entries map[string][]byte, expiration time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	var exp int64
+	if expiration > 0 {
+		exp = time.Now().Add(expiration).UnixNano()
+	}
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(cacheBucket)
+		for key, value := range entries {
+			raw := make([]byte, 8+len(value))
+			binary.BigEndian.PutUint64(raw[:8], uint64(exp))
+			copy(raw[8:], value)
+			if err := b.Put([]byte(key), raw); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write cache keys: %w", err)
+	}
+	return nil
+}
+
+// PurgeExpiredCache removes every expired cache entry and returns how many were dropped.
+func (s *BoltStore) PurgeExpiredCache() (int, error) {
+	now := time.Now().UnixNano()
+	n := 0
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(cacheBucket)
+		var stale [][]byte
+		err := b.ForEach(func(k, v []byte) error {
+			if len(v) >= 8 {
+				if exp := int64(binary.BigEndian.Uint64(v[:8])); exp != 0 && now > exp {
+					stale = append(stale, append([]byte(nil), k...))
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, k := range stale {
+			if err := b.Delete(k); err != nil {
+				return err
+			}
+		}
+		n = len(stale)
+		return nil
+	})
+	return n, err
+}
+
+// ---------------------------------------------------------------------------
+// KillmailRepository
+// ---------------------------------------------------------------------------
+
+// boltKillmails stores JSON-encoded killmails keyed by ID, plus a time index
+// (time millis + ID) used for range queries.
+type boltKillmails struct {
+	db *bolt.DB
+}
+
+func idKey(id int64) []byte {
+	k := make([]byte, 8)
+	binary.BigEndian.PutUint64(k, uint64(id))
+	return k
+}
+
+func timeKey(t time.Time, id int64) []byte {
+	k := make([]byte, 16)
+	binary.BigEndian.PutUint64(k[:8], uint64(t.UnixMilli()))
+	binary.BigEndian.PutUint64(k[8:], uint64(id))
+	return k
+}
+
+func (r *boltKillmails) Save(ctx context.Context, kills ...model.FlattenedKillMail) error {
+	if err := validateKills(kills); err != nil {
+		return err
+	}
+	return r.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(killmailBucket)
+		idx := tx.Bucket(killmailTimeIdx)
+		for i := range kills {
+			km := &kills[i]
+			// drop the old time index entry if the killmail is being replaced
+			if old := b.Get(idKey(km.KillMailID)); old != nil {
+				var prev model.FlattenedKillMail
+				if err := json.Unmarshal(old, &prev); err == nil {
+					if err := idx.Delete(timeKey(prev.KillMailTime, prev.KillMailID)); err != nil {
+						return err
+					}
+				}
+			}
+			data, err := json.Marshal(km)
+			if err != nil {
+				return fmt.Errorf("failed to encode killmail %d: %w", km.KillMailID, err)
+			}
+			if err := b.Put(idKey(km.KillMailID), data); err != nil {
+				return err
+			}
+			if err := idx.Put(timeKey(km.KillMailTime, km.KillMailID), nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *boltKillmails) Get(ctx context.Context, killmailID int64) (*model.FlattenedKillMail, error) {
+	var km *model.FlattenedKillMail
+	err := r.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(killmailBucket).Get(idKey(killmailID))
+		if data == nil {
+			return ErrNotFound
+		}
+		km = &model.FlattenedKillMail{}
+		return json.Unmarshal(data, km)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return km, nil
+}
+
+func (r *boltKillmails) Exists(ctx context.Context, killmailID int64) (bool, error) {
+	found := false
+	err := r.db.View(func(tx *bolt.Tx) error {
+		found = tx.Bucket(killmailBucket).Get(idKey(killmailID)) != nil
+		return nil
+	})
+	return found, err
+}
+
+func (r *boltKillmails) Query(ctx context.Context, q KillmailQuery) ([]model.FlattenedKillMail, error) {
+	var out []model.FlattenedKillMail
+	err := r.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(killmailBucket)
+		c := tx.Bucket(killmailTimeIdx).Cursor()
+
+		var k []byte
+		if q.Start.IsZero() {
+			k, _ = c.First()
+		} else {
+			k, _ = c.Seek(timeKey(q.Start, 0))
+		}
+		for ; k != nil; k, _ = c.Next() {
+			if !q.End.IsZero() && int64(binary.BigEndian.Uint64(k[:8])) >= q.End.UnixMilli() {
+				break
+			}
+			data := b.Get(k[8:])
+			if data == nil {
+				continue
+			}
+			var km model.FlattenedKillMail
+			if err := json.Unmarshal(data, &km); err != nil {
+				return err
+			}
+			if !q.Matches(&km) {
+				continue
+			}
+			out = append(out, km)
+			if q.Limit > 0 && len(out) >= q.Limit {
+				break
+			}
+		}
+		return nil
+	})
+	return out, err
+}
+
+func (r *boltKillmails) Delete(ctx context.Context, killmailID int64) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(killmailBucket)
+		data := b.Get(idKey(killmailID))
+		if data == nil {
+			return nil
+		}
+		var km model.FlattenedKillMail
+		if err := json.Unmarshal(data, &km); err == nil {
+			if err := tx.Bucket(killmailTimeIdx).Delete(timeKey(km.KillMailTime, km.KillMailID)); err != nil {
+				return err
+			}
+		}
+		return b.Delete(idKey(killmailID))
+	})
+}
+
+// ---------------------------------------------------------------------------
+// SnapshotRepository
+// ---------------------------------------------------------------------------
+
+// boltSnapshots keys each snapshot by "kind\x00key\x00" followed by the
+// big-endian unix nanos, so a prefix scan yields one series in time order.
+type boltSnapshots struct {
+	db *bolt.DB
+}
+
+func snapshotPrefix(kind, key string) []byte {
+	return []byte(kind + "\x00" + key + "\x00")
+}
+
+func snapshotDBKey(kind, key string, at time.Time) []byte {
+	k := snapshotPrefix(kind, key)
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], uint64(at.UnixNano()))
+	return append(k, ts[:]...)
+}
+
+func (r *boltSnapshots) Save(ctx context.Context, s Snapshot) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(snapshotBucket).Put(snapshotDBKey(s.Kind, s.Key, s.At), s.Data)
+	})
+}
+
+func (r *boltSnapshots) Latest(ctx context.Context, kind, key string) (*Snapshot, error) {
+	var out *Snapshot
+	err := r.db.View(func(tx *bolt.Tx) error {
+		prefix := snapshotPrefix(kind, key)
+		c := tx.Bucket(snapshotBucket).Cursor()
+		var last, lastV []byte
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			last, lastV = k, v
+		}
+		if last == nil {
+			return ErrNotFound
+		}
+		out = decodeSnapshot(kind, key, last, lastV)
+		return nil
+	})
+	return out, err
+}
+
+func (r *boltSnapshots) History(ctx context.Context, kind, key string, start, end time.Time) ([]Snapshot, error) {
+	var out []Snapshot
+	err := r.db.View(func(tx *bolt.Tx) error {
+		prefix := snapshotPrefix(kind, key)
+		c := tx.Bucket(snapshotBucket).Cursor()
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			s := decodeSnapshot(kind, key, k, v)
+			if inWindow(s.At, start, end) {
+				out = append(out, *s)
+			}
+		}
+		return nil
+	})
+	return out, err
+}
+
+func decodeSnapshot(kind, key string, k, v []byte) *Snapshot {
+	nanos := int64(binary.BigEndian.Uint64(k[len(k)-8:]))
+	return &Snapshot{Kind: kind, Key: key, At: time.Unix(0, nanos).UTC(), Data: append([]byte(nil), v...)}
+}
+
+// ---------------------------------------------------------------------------
+// TimerRepository
+// ---------------------------------------------------------------------------
+
+// boltTimers stores JSON-encoded timers keyed by ID. Timer boards are small,
+// so List decodes and sorts them all.
+type boltTimers struct {
+	db *bolt.DB
+}
+
+func (r *boltTimers) Save(ctx context.Context, timers ...model.ReinforcementTimer) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(timerBucket)
+		for _, t := range timers {
+			data, err := json.Marshal(t)
+			if err != nil {
+				return fmt.Errorf("failed to encode timer %s: %w", t.ID, err)
+			}
+			if err := b.Put([]byte(t.ID), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *boltTimers) Get(ctx context.Context, id string) (*model.ReinforcementTimer, error) {
+	var t *model.ReinforcementTimer
+	err := r.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(timerBucket).Get([]byte(id))
+		if data == nil {
+			return ErrNotFound
+		}
+		t = &model.ReinforcementTimer{}
+		return json.Unmarshal(data, t)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (r *boltTimers) List(ctx context.Context) ([]model.ReinforcementTimer, error) {
+	var out []model.ReinforcementTimer
+	err := r.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(timerBucket).ForEach(func(k, v []byte) error {
+			var t model.ReinforcementTimer
+			if err := json.Unmarshal(v, &t); err != nil {
+				return err
+			}
+			out = append(out, t)
+			return nil
+		})
+	})
+	SortTimers(out)
+	return out, err
+}
+
+func (r *boltTimers) Delete(ctx context.Context, id string) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(timerBucket).Delete([]byte(id))
+	})
+}
@@ -0,0 +1,60 @@
+package storage_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/storage"
+)
+
+func TestBoltStore(t *testing.T) {
+	store, err := storage.OpenBoltStore(filepath.Join(t.TempDir(), "eveapi.db"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer store.Close()
+
+	cache := store.Cache()
+	cache.Set("foo", []byte("bar"), time.Hour)
+	cache.Set("stale", []byte("x"), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if v, ok := cache.Get("foo"); !ok || string(v) != "bar" {
+		t.Errorf("expected cached bar, got %q %v", v, ok)
+	}
+	if _, ok := cache.Get("stale"); ok {
+		t.Error("expected expired entry to be missing")
+	}
+
+	ctx := context.Background()
+	repo := store.Killmails()
+	t0 := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	err = repo.Save(ctx,
+		model.FlattenedKillMail{KillMailID: 1, KillMailTime: t0},
+		model.FlattenedKillMail{KillMailID: 2, KillMailTime: t0.Add(time.Hour)},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// move kill 1 later; the time index must follow
+	if err := repo.Save(ctx, model.FlattenedKillMail{KillMailID: 1, KillMailTime: t0.Add(2 * time.Hour)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out, err := repo.Query(ctx, storage.KillmailQuery{Start: t0.Add(30 * time.Minute)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out) != 2 || out[0].KillMailID != 2 || out[1].KillMailID != 1 {
+		t.Errorf("unexpected query result: %#v", out)
+	}
+
+	if err := repo.Delete(ctx, 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok, _ := repo.Exists(ctx, 2); ok {
+		t.Error("expected kill 2 to be deleted")
+	}
+}
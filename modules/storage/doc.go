@@ -0,0 +1,3 @@
+// Package storage persists killmails and other historical data so that
+// aggregation runs are resumable and past data can be queried offline.
+package storage
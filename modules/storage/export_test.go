@@ -0,0 +1,9 @@
+package storage
+
+// Rebind exposes rebind to the external tests.
+var Rebind = rebind
+
+// BuildQuery exposes SQLKillmailRepository.buildQuery to the external tests.
+func BuildQuery(d Dialect, q KillmailQuery) (string, []interface{}) {
+	return (&SQLKillmailRepository{dialect: d}).buildQuery(q)
+}
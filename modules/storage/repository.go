@@ -0,0 +1,180 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// ErrNotFound is returned when a record does not exist.
+var ErrNotFound = errors.New("storage: not found")
+
+// Entity types accepted by KillmailQuery.EntityType.
+const (
+	EntityCharacter   = "character"
+	EntityCorporation = "corporation"
+	EntityAlliance    = "alliance"
+)
+
+// KillmailQuery selects stored killmails. Zero values leave a filter open.
+type KillmailQuery struct {
+	Start      time.Time // inclusive
+	End        time.Time // exclusive
+	EntityType string    // one of the Entity* constants; requires EntityIDs
+	EntityIDs  []int64   // matches victims and attackers
+	Limit      int
+}
+
+// KillmailRepository persists flattened killmails.
+type KillmailRepository interface {
+	// Save inserts or replaces killmails by ID.
+	Save(ctx context.Context, kills ...model.FlattenedKillMail) error
+	// Get returns a killmail or ErrNotFound.
+	Get(ctx context.Context, killmailID int64) (*model.FlattenedKillMail, error)
+	// Exists reports whether a killmail is stored.
+	Exists(ctx context.Context, killmailID int64) (bool, error)
+	// Query returns matching killmails ordered by time, then ID.
+	Query(ctx context.Context, q KillmailQuery) ([]model.FlattenedKillMail, error)
+	// Delete removes a killmail; deleting a missing one is not an error.
+	Delete(ctx context.Context, killmailID int64) error
+}
+
+// memoryKillmailRepository is a map-backed KillmailRepository.
+type memoryKillmailRepository struct {
+	mu    sync.RWMutex
+	kills map[int64]model.FlattenedKillMail
+}
+
+// NewMemoryKillmailRepository returns a KillmailRepository held in memory,
+// useful for tests and short-lived tools.
+func NewMemoryKillmailRepository() KillmailRepository {
+	return &memoryKillmailRepository{kills: make(map[int64]model.FlattenedKillMail)}
+}
+
+func (r *memoryKillmailRepository) Save(ctx context.Context, kills ...model.FlattenedKillMail) error {
+	if err := validateKills(kills); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, km := range kills {
+		r.kills[km.KillMailID] = km
+	}
+	return nil
+}
+
+func (r *memoryKillmailRepository) Get(ctx context.Context, killmailID int64) (*model.FlattenedKillMail, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	km, ok := r.kills[killmailID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &km, nil
+}
+
+func (r *memoryKillmailRepository) Exists(ctx context.Context, killmailID int64) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.kills[killmailID]
+	return ok, nil
+}
+
+func (r *memoryKillmailRepository) Query(ctx context.Context, q KillmailQuery) ([]model.FlattenedKillMail, error) {
+	r.mu.RLock()
+	var out []model.FlattenedKillMail
+	for _, km := range r.kills {
+		if q.Matches(&km) {
+			out = append(out, km)
+		}
+	}
+	r.mu.RUnlock()
+
+	SortKillmails(out)
+	if q.Limit > 0 && len(out) > q.Limit {
+		out = out[:q.Limit]
+	}
+	return out, nil
+}
+
+func (r *memoryKillmailRepository) Delete(ctx context.Context, killmailID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.kills, killmailID)
+	return nil
+}
+
+// Matches reports whether km satisfies the query's time and entity filters.
+func (q KillmailQuery) Matches(km *model.FlattenedKillMail) bool {
+	if !q.Start.IsZero() && km.KillMailTime.Before(q.Start) {
+		return false
+	}
+	if !q.End.IsZero() && !km.KillMailTime.Before(q.End) {
+		return false
+	}
+	if q.EntityType == "" || len(q.EntityIDs) == 0 {
+		return true
+	}
+	for _, id := range q.EntityIDs {
+		for _, e := range killmailEntities(km) {
+			if e.kind == q.EntityType && e.id == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SortKillmails orders kills by time, then ID.
+func SortKillmails(kills []model.FlattenedKillMail) {
+	sort.Slice(kills, func(i, j int) bool {
+		if !kills[i].KillMailTime.Equal(kills[j].KillMailTime) {
+			return kills[i].KillMailTime.Before(kills[j].KillMailTime)
+		}
+		return kills[i].KillMailID < kills[j].KillMailID
+	})
+}
+
+// validateKills rejects the batch if any killmail fails Validate, before
+// anything is written.
+func validateKills(kills []model.FlattenedKillMail) error {
+	for i := range kills {
+		if err := kills[i].Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type entityRef struct {
+	kind     string
+	id       int64
+	isVictim bool
+}
+
+// killmailEntities lists the distinct characters, corporations and alliances on a kill.
+func killmailEntities(km *model.FlattenedKillMail) []entityRef {
+	seen := make(map[entityRef]bool)
+	var out []entityRef
+	add := func(kind string, id int64, victim bool) {
+		ref := entityRef{kind: kind, id: id, isVictim: victim}
+		if id == 0 || seen[ref] {
+			return
+		}
+		seen[ref] = true
+		out = append(out, ref)
+	}
+	add(EntityCharacter, km.Victim.CharacterID, true)
+	add(EntityCorporation, km.Victim.CorporationID, true)
+	add(EntityAlliance, km.Victim.AllianceID, true)
+	for _, a := range km.Attackers {
+		add(EntityCharacter, a.CharacterID, false)
+		add(EntityCorporation, a.CorporationID, false)
+		add(EntityAlliance, a.AllianceID, false)
+	}
+	return out
+}
@@ -0,0 +1,50 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/storage"
+)
+
+func TestMemoryKillmailRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := storage.NewMemoryKillmailRepository()
+	t0 := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	kills := []model.FlattenedKillMail{
+		{KillMailID: 2, KillMailTime: t0.Add(time.Hour), Victim: model.Victim{CorporationID: 60}},
+		{KillMailID: 1, KillMailTime: t0, Victim: model.Victim{CorporationID: 50},
+			Attackers: []model.Attacker{{CorporationID: 60}}},
+		{KillMailID: 3, KillMailTime: t0.Add(48 * time.Hour), Victim: model.Victim{CorporationID: 70}},
+	}
+	if err := repo.Save(ctx, kills...); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out, err := repo.Query(ctx, storage.KillmailQuery{EntityType: storage.EntityCorporation, EntityIDs: []int64{60}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out) != 2 || out[0].KillMailID != 1 || out[1].KillMailID != 2 {
+		t.Errorf("expected kills 1 and 2 in time order, got %#v", out)
+	}
+
+	out, _ = repo.Query(ctx, storage.KillmailQuery{Start: t0.Add(time.Minute), End: t0.Add(24 * time.Hour)})
+	if len(out) != 1 || out[0].KillMailID != 2 {
+		t.Errorf("expected only kill 2 in range, got %#v", out)
+	}
+
+	if err := repo.Delete(ctx, 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := repo.Get(ctx, 2); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if ok, _ := repo.Exists(ctx, 3); !ok {
+		t.Error("expected kill 3 to exist")
+	}
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Snapshot is one point-in-time capture of some tracked state, such as a
+// corporation's member list. Data is opaque to the repository; trackers
+// encode it themselves.
+type Snapshot struct {
+	Kind string    // what is tracked, e.g. "corp_members"
+	Key  string    // which instance, e.g. the corporation ID
+	At   time.Time // when it was taken
+	Data []byte
+}
+
+// SnapshotRepository keeps a time series of snapshots per (Kind, Key).
+type SnapshotRepository interface {
+	// Save stores a snapshot; one with the same Kind, Key and At is replaced.
+	Save(ctx context.Context, s Snapshot) error
+	// Latest returns the most recent snapshot or ErrNotFound.
+	Latest(ctx context.Context, kind, key string) (*Snapshot, error)
+	// History returns snapshots in [start, end) ordered by time. Zero bounds
+	// leave that side open.
+	History(ctx context.Context, kind, key string, start, end time.Time) ([]Snapshot, error)
+}
+
+type snapshotKey struct {
+	kind, key string
+}
+
+// memorySnapshotRepository is a map-backed SnapshotRepository.
+type memorySnapshotRepository struct {
+	mu     sync.RWMutex
+	series map[snapshotKey][]Snapshot
+}
+
+// NewMemorySnapshotRepository returns a SnapshotRepository held in memory.
+func NewMemorySnapshotRepository() SnapshotRepository {
+	return &memorySnapshotRepository{series: make(map[snapshotKey][]Snapshot)}
+}
+
+func (r *memorySnapshotRepository) Save(ctx context.Context, s Snapshot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	k := snapshotKey{s.Kind, s.Key}
+	series := r.series[k]
+	i := sort.Search(len(series), func(i int) bool { return !series[i].At.Before(s.At) })
+	if i < len(series) && series[i].At.Equal(s.At) {
+		series[i] = s
+		return nil
+	}
+	series = append(series, Snapshot{})
+	copy(series[i+1:], series[i:])
+	series[i] = s
+	r.series[k] = series
+	return nil
+}
+
+func (r *memorySnapshotRepository) Latest(ctx context.Context, kind, key string) (*Snapshot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	series := r.series[snapshotKey{kind, key}]
+	if len(series) == 0 {
+		return nil, ErrNotFound
+	}
+	s := series[len(series)-1]
+	return &s, nil
+}
+
+func (r *memorySnapshotRepository) History(ctx context.Context, kind, key string, start, end time.Time) ([]Snapshot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var out []Snapshot
+	for _, s := range r.series[snapshotKey{kind, key}] {
+		if inWindow(s.At, start, end) {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+// inWindow reports whether t is in [start, end), treating zero bounds as open.
+func inWindow(t, start, end time.Time) bool {
+	return (start.IsZero() || !t.Before(start)) && (end.IsZero() || t.Before(end))
+}
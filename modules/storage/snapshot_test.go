@@ -0,0 +1,49 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/guarzo/eveapi/modules/storage"
+)
+
+func TestSnapshotRepositories(t *testing.T) {
+	bolt, err := storage.OpenBoltStore(filepath.Join(t.TempDir(), "eveapi.db"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer bolt.Close()
+
+	repos := map[string]storage.SnapshotRepository{
+		"memory": storage.NewMemorySnapshotRepository(),
+		"bolt":   bolt.Snapshots(),
+	}
+	t0 := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	for name, repo := range repos {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if _, err := repo.Latest(ctx, "k", "1"); !errors.Is(err, storage.ErrNotFound) {
+				t.Errorf("expected ErrNotFound, got %v", err)
+			}
+			for i, data := range []string{"b", "a", "c"} {
+				at := t0.Add(time.Duration([]int{1, 0, 2}[i]) * time.Hour)
+				if err := repo.Save(ctx, storage.Snapshot{Kind: "k", Key: "1", At: at, Data: []byte(data)}); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			_ = repo.Save(ctx, storage.Snapshot{Kind: "k", Key: "2", At: t0.Add(5 * time.Hour), Data: []byte("other")})
+
+			latest, err := repo.Latest(ctx, "k", "1")
+			if err != nil || string(latest.Data) != "c" {
+				t.Errorf("expected latest c, got %+v, %v", latest, err)
+			}
+			hist, err := repo.History(ctx, "k", "1", t0, t0.Add(2*time.Hour))
+			if err != nil || len(hist) != 2 || string(hist[0].Data) != "a" || !hist[1].At.Equal(t0.Add(time.Hour)) {
+				t.Errorf("unexpected history: %+v, %v", hist, err)
+			}
+		})
+	}
+}
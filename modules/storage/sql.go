@@ -0,0 +1,286 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// Dialect selects the SQL flavor used by the SQL repositories.
+type Dialect int
+
+const (
+	SQLite Dialect = iota
+	Postgres
+)
+
+// migration is one schema step; steps are applied in order and recorded in schema_migrations.
+type migration struct {
+	version    int
+	statements []string
+}
+
+var killmailMigrations = []migration{
+	{
+		version: 1,
+		statements: []string{
+			`CREATE TABLE IF NOT EXISTS killmails (
+				killmail_id     BIGINT PRIMARY KEY,
+				killmail_time   BIGINT NOT NULL,
+				solar_system_id BIGINT NOT NULL,
+				total_value     DOUBLE PRECISION NOT NULL,
+				data            TEXT NOT NULL
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_killmails_time ON killmails (killmail_time)`,
+			`CREATE TABLE IF NOT EXISTS killmail_entities (
+				killmail_id BIGINT NOT NULL,
+				entity_type VARCHAR(16) NOT NULL,
+				entity_id   BIGINT NOT NULL,
+				is_victim   BOOLEAN NOT NULL,
+				PRIMARY KEY (killmail_id, entity_type, entity_id, is_victim)
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_killmail_entities_entity ON killmail_entities (entity_type, entity_id)`,
+		},
+	},
+}
+
+// SQLKillmailRepository stores killmails as JSON alongside indexed columns.
+type SQLKillmailRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewSQLKillmailRepository returns a KillmailRepository backed by db. The caller
+// registers the driver (e.g. modernc.org/sqlite or github.com/jackc/pgx/v5/stdlib)
+// and should call Migrate before first use.
+func NewSQLKillmailRepository(db *sql.DB, dialect Dialect) *SQLKillmailRepository {
+	return &SQLKillmailRepository{db: db, dialect: dialect}
+}
+
+// Migrate applies any schema migrations that have not run yet.
+func (r *SQLKillmailRepository) Migrate(ctx context.Context) error {
+	return migrate(ctx, r.db, r.dialect, "killmails", killmailMigrations)
+}
+
+func (r *SQLKillmailRepository) Save(ctx context.Context, kills ...model.FlattenedKillMail) error {
+	if err := validateKills(kills); err != nil {
+		return err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	upsert := r.rebind(`INSERT INTO killmails (killmail_id, killmail_time, solar_system_id, total_value, data)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (killmail_id) DO UPDATE SET
+			killmail_time = excluded.killmail_time,
+			solar_system_id = excluded.solar_system_id,
+			total_value = excluded.total_value,
+			data = excluded.data`)
+	clearEntities := r.rebind(`DELETE FROM killmail_entities WHERE killmail_id = ?`)
+	insertEntity := r.rebind(`INSERT INTO killmail_entities (killmail_id, entity_type, entity_id, is_victim) VALUES (?, ?, ?, ?)`)
+
+	for i := range kills {
+		km := &kills[i]
+		data, err := json.Marshal(km)
+		if err != nil {
+			return fmt.Errorf("failed to encode killmail %d: %w", km.KillMailID, err)
+		}
+		if _, err := tx.ExecContext(ctx, upsert, km.KillMailID, km.KillMailTime.UnixMilli(), km.SolarSystemID, km.TotalValue, string(data)); err != nil {
+			return fmt.Errorf("failed to save killmail %d: %w", km.KillMailID, err)
+		}
+		if _, err := tx.ExecContext(ctx, clearEntities, km.KillMailID); err != nil {
+			return err
+		}
+		for _, e := range killmailEntities(km) {
+			if _, err := tx.ExecContext(ctx, insertEntity, km.KillMailID, e.kind, e.id, e.isVictim); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *SQLKillmailRepository) Get(ctx context.Context, killmailID int64) (*model.FlattenedKillMail, error) {
+	var data string
+	err := r.db.QueryRowContext(ctx, r.rebind(`SELECT data FROM killmails WHERE killmail_id = ?`), killmailID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var km model.FlattenedKillMail
+	if err := json.Unmarshal([]byte(data), &km); err != nil {
+		return nil, fmt.Errorf("failed to decode killmail %d: %w", killmailID, err)
+	}
+	return &km, nil
+}
+
+func (r *SQLKillmailRepository) Exists(ctx context.Context, killmailID int64) (bool, error) {
+	var one int
+	err := r.db.QueryRowContext(ctx, r.rebind(`SELECT 1 FROM killmails WHERE killmail_id = ?`), killmailID).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *SQLKillmailRepository) Query(ctx context.Context, q KillmailQuery) ([]model.FlattenedKillMail, error) {
+	query, args := r.buildQuery(q)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []model.FlattenedKillMail
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var km model.FlattenedKillMail
+		if err := json.Unmarshal([]byte(data), &km); err != nil {
+			return nil, err
+		}
+		out = append(out, km)
+	}
+	return out, rows.Err()
+}
+
+func (r *SQLKillmailRepository) Delete(ctx context.Context, killmailID int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, r.rebind(`DELETE FROM killmail_entities WHERE killmail_id = ?`), killmailID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, r.rebind(`DELETE FROM killmails WHERE killmail_id = ?`), killmailID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// buildQuery renders q into SQL and arguments.
+func (r *SQLKillmailRepository) buildQuery(q KillmailQuery) (string, []interface{}) {
+	var where []string
+	var args []interface{}
+	if !q.Start.IsZero() {
+		where = append(where, "k.killmail_time >= ?")
+		args = append(args, q.Start.UnixMilli())
+	}
+	if !q.End.IsZero() {
+		where = append(where, "k.killmail_time < ?")
+		args = append(args, q.End.UnixMilli())
+	}
+	if q.EntityType != "" && len(q.EntityIDs) > 0 {
+		marks := make([]string, len(q.EntityIDs))
+		args = append(args, q.EntityType)
+		for i, id := range q.EntityIDs {
+			marks[i] = "?"
+			args = append(args, id)
+		}
+		where = append(where, fmt.Sprintf(
+			"k.killmail_id IN (SELECT e.killmail_id FROM killmail_entities e WHERE e.entity_type = ? AND e.entity_id IN (%s))",
+			strings.Join(marks, ", ")))
+	}
+
+	query := "SELECT k.data FROM killmails k"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY k.killmail_time, k.killmail_id"
+	if q.Limit > 0 {
+		query += " LIMIT " + strconv.Itoa(q.Limit)
+	}
+	return r.rebind(query), args
+}
+
+func (r *SQLKillmailRepository) rebind(query string) string {
+	return rebind(r.dialect, query)
+}
+
+// rebind rewrites ? placeholders to $n for Postgres.
+func rebind(d Dialect, query string) string {
+	if d != Postgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, ch := range query {
+		if ch == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(ch)
+	}
+	return b.String()
+}
+
+// migrate applies the pending steps of a named migration set.
+func migrate(ctx context.Context, db *sql.DB, d Dialect, set string, steps []migration) error {
+	if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		migration_set VARCHAR(64) NOT NULL,
+		version       INTEGER NOT NULL,
+		applied_at    BIGINT NOT NULL,
+		PRIMARY KEY (migration_set, version)
+	)`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	applied := make(map[int]bool)
+	rows, err := db.QueryContext(ctx, rebind(d, `SELECT version FROM schema_migrations WHERE migration_set = ?`), set)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var v int
+		if err := rows.Scan(&v); err != nil {
+			rows.Close()
+			return err
+		}
+		applied[v] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, m := range steps {
+		if applied[m.version] {
+			continue
+		}
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		for _, stmt := range m.statements {
+			if _, err := tx.ExecContext(ctx, stmt); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("migration %s/%d failed: %w", set, m.version, err)
+			}
+		}
+		if _, err := tx.ExecContext(ctx, rebind(d, `INSERT INTO schema_migrations (migration_set, version, applied_at) VALUES (?, ?, ?)`),
+			set, m.version, time.Now().UnixMilli()); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package storage_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/storage"
+)
+
+func TestRebind(t *testing.T) {
+	cases := []struct {
+		dialect storage.Dialect
+		in      string
+		want    string
+	}{
+		{storage.SQLite, "SELECT 1 WHERE a = ? AND b = ?", "SELECT 1 WHERE a = ? AND b = ?"},
+		{storage.Postgres, "SELECT 1 WHERE a = ? AND b = ?", "SELECT 1 WHERE a = $1 AND b = $2"},
+		{storage.Postgres, "SELECT 1", "SELECT 1"},
+		{storage.Postgres, "VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", "VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)"},
+	}
+	for _, c := range cases {
+		if got := storage.Rebind(c.dialect, c.in); got != c.want {
+			t.Errorf("Rebind(%d, %q) = %q, want %q", c.dialect, c.in, got, c.want)
+		}
+	}
+}
+
+func TestBuildQuery(t *testing.T) {
+	start := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+	const entities = "k.killmail_id IN (SELECT e.killmail_id FROM killmail_entities e WHERE e.entity_type = %s AND e.entity_id IN (%s, %s))"
+	cases := []struct {
+		name    string
+		dialect storage.Dialect
+		q       storage.KillmailQuery
+		want    string
+		args    []interface{}
+	}{
+		{
+			name: "everything",
+			q:    storage.KillmailQuery{},
+			want: "SELECT k.data FROM killmails k ORDER BY k.killmail_time, k.killmail_id",
+		},
+		{
+			name: "time range with limit",
+			q:    storage.KillmailQuery{Start: start, End: end, Limit: 10},
+			want: "SELECT k.data FROM killmails k WHERE k.killmail_time >= ? AND k.killmail_time < ? ORDER BY k.killmail_time, k.killmail_id LIMIT 10",
+			args: []interface{}{start.UnixMilli(), end.UnixMilli()},
+		},
+		{
+			name:    "time range on postgres",
+			dialect: storage.Postgres,
+			q:       storage.KillmailQuery{Start: start, End: end},
+			want:    "SELECT k.data FROM killmails k WHERE k.killmail_time >= $1 AND k.killmail_time < $2 ORDER BY k.killmail_time, k.killmail_id",
+			args:    []interface{}{start.UnixMilli(), end.UnixMilli()},
+		},
+		{
+			name: "entities",
+			q:    storage.KillmailQuery{Start: start, EntityType: storage.EntityCorporation, EntityIDs: []int64{50, 60}},
+			want: "SELECT k.data FROM killmails k WHERE k.killmail_time >= ? AND " +
+				sprintf(entities, "?", "?", "?") + " ORDER BY k.killmail_time, k.killmail_id",
+			args: []interface{}{start.UnixMilli(), storage.EntityCorporation, int64(50), int64(60)},
+		},
+		{
+			name:    "entities on postgres",
+			dialect: storage.Postgres,
+			q:       storage.KillmailQuery{Start: start, EntityType: storage.EntityCorporation, EntityIDs: []int64{50, 60}},
+			want: "SELECT k.data FROM killmails k WHERE k.killmail_time >= $1 AND " +
+				sprintf(entities, "$2", "$3", "$4") + " ORDER BY k.killmail_time, k.killmail_id",
+			args: []interface{}{start.UnixMilli(), storage.EntityCorporation, int64(50), int64(60)},
+		},
+		{
+			name: "entity type without IDs is ignored",
+			q:    storage.KillmailQuery{EntityType: storage.EntityAlliance},
+			want: "SELECT k.data FROM killmails k ORDER BY k.killmail_time, k.killmail_id",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			query, args := storage.BuildQuery(c.dialect, c.q)
+			if query != c.want {
+				t.Errorf("query\n got %s\nwant %s", query, c.want)
+			}
+			if !reflect.DeepEqual(args, c.args) {
+				t.Errorf("args got %v, want %v", args, c.args)
+			}
+		})
+	}
+}
+
+func sprintf(format string, marks ...string) string {
+	args := make([]interface{}, len(marks))
+	for i, m := range marks {
+		args[i] = m
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+func TestSQLKillmailRepository_SQLite(t *testing.T) {
+	ctx := context.Background()
+	db, err := sql.Open("sqlite", "file:"+t.TempDir()+"/kills.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	repo := storage.NewSQLKillmailRepository(db, storage.SQLite)
+	for i := 0; i < 2; i++ { // migrations run once
+		if err := repo.Migrate(ctx); err != nil {
+			t.Fatalf("migration %d failed: %v", i, err)
+		}
+	}
+
+	t0 := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	kills := []model.FlattenedKillMail{
+		{KillMailID: 2, KillMailTime: t0.Add(time.Hour), Victim: model.Victim{CorporationID: 60}},
+		{KillMailID: 1, KillMailTime: t0, Victim: model.Victim{CorporationID: 50},
+			Attackers: []model.Attacker{{CorporationID: 60}}, TotalValue: 100},
+		{KillMailID: 3, KillMailTime: t0.Add(48 * time.Hour), Victim: model.Victim{CorporationID: 70}},
+	}
+	if err := repo.Save(ctx, kills...); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out, err := repo.Query(ctx, storage.KillmailQuery{EntityType: storage.EntityCorporation, EntityIDs: []int64{60}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out) != 2 || out[0].KillMailID != 1 || out[1].KillMailID != 2 {
+		t.Errorf("expected kills 1 and 2 in time order, got %+v", out)
+	}
+	out, _ = repo.Query(ctx, storage.KillmailQuery{Start: t0.Add(time.Minute), End: t0.Add(24 * time.Hour)})
+	if len(out) != 1 || out[0].KillMailID != 2 {
+		t.Errorf("expected only kill 2 in range, got %+v", out)
+	}
+	if out, _ = repo.Query(ctx, storage.KillmailQuery{Limit: 2}); len(out) != 2 {
+		t.Errorf("expected the limit to apply, got %d kills", len(out))
+	}
+
+	// saving again replaces the kill and its entities
+	updated := kills[1]
+	updated.TotalValue = 250
+	updated.Attackers = []model.Attacker{{CorporationID: 80}}
+	if err := repo.Save(ctx, updated); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	km, err := repo.Get(ctx, 1)
+	if err != nil || km.TotalValue != 250 || !km.KillMailTime.Equal(t0) {
+		t.Errorf("expected the upserted kill, got %+v, %v", km, err)
+	}
+	if out, _ = repo.Query(ctx, storage.KillmailQuery{EntityType: storage.EntityCorporation, EntityIDs: []int64{80}}); len(out) != 1 {
+		t.Errorf("expected the new attacker to be indexed, got %+v", out)
+	}
+	if out, _ = repo.Query(ctx, storage.KillmailQuery{EntityType: storage.EntityCorporation, EntityIDs: []int64{60}}); len(out) != 1 {
+		t.Errorf("expected the old attacker to be unindexed, got %+v", out)
+	}
+
+	if err := repo.Delete(ctx, 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := repo.Get(ctx, 2); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if ok, _ := repo.Exists(ctx, 2); ok {
+		t.Error("expected kill 2 to be gone")
+	}
+	var entities int
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM killmail_entities WHERE killmail_id = 2`).Scan(&entities); err != nil || entities != 0 {
+		t.Errorf("expected kill 2's entities to be deleted, got %d, %v", entities, err)
+	}
+	if ok, _ := repo.Exists(ctx, 3); !ok {
+		t.Error("expected kill 3 to exist")
+	}
+}
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// TimerRepository persists reinforcement timers by ID.
+type TimerRepository interface {
+	// Save inserts or replaces timers by ID.
+	Save(ctx context.Context, timers ...model.ReinforcementTimer) error
+	// Get returns a timer or ErrNotFound.
+	Get(ctx context.Context, id string) (*model.ReinforcementTimer, error)
+	// List returns every timer ordered by exit time, then ID.
+	List(ctx context.Context) ([]model.ReinforcementTimer, error)
+	// Delete removes a timer; deleting a missing one is not an error.
+	Delete(ctx context.Context, id string) error
+}
+
+// memoryTimerRepository is a map-backed TimerRepository.
+type memoryTimerRepository struct {
+	mu     sync.RWMutex
+	timers map[string]model.ReinforcementTimer
+}
+
+// NewMemoryTimerRepository returns a TimerRepository held in memory.
+func NewMemoryTimerRepository() TimerRepository {
+	return &memoryTimerRepository{timers: make(map[string]model.ReinforcementTimer)}
+}
+
+func (r *memoryTimerRepository) Save(ctx context.Context, timers ...model.ReinforcementTimer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range timers {
+		r.timers[t.ID] = t
+	}
+	return nil
+}
+
+func (r *memoryTimerRepository) Get(ctx context.Context, id string) (*model.ReinforcementTimer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.timers[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &t, nil
+}
+
+func (r *memoryTimerRepository) List(ctx context.Context) ([]model.ReinforcementTimer, error) {
+	r.mu.RLock()
+	out := make([]model.ReinforcementTimer, 0, len(r.timers))
+	for _, t := range r.timers {
+		out = append(out, t)
+	}
+	r.mu.RUnlock()
+
+	SortTimers(out)
+	return out, nil
+}
+
+func (r *memoryTimerRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.timers, id)
+	return nil
+}
+
+// SortTimers orders timers by exit time, then ID.
+func SortTimers(timers []model.ReinforcementTimer) {
+	sort.Slice(timers, func(i, j int) bool {
+		if !timers[i].ExitsAt.Equal(timers[j].ExitsAt) {
+			return timers[i].ExitsAt.Before(timers[j].ExitsAt)
+		}
+		return timers[i].ID < timers[j].ID
+	})
+}
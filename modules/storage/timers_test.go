@@ -0,0 +1,52 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/storage"
+)
+
+func TestTimerRepositories(t *testing.T) {
+	bolt, err := storage.OpenBoltStore(filepath.Join(t.TempDir(), "eveapi.db"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer bolt.Close()
+
+	repos := map[string]storage.TimerRepository{
+		"memory": storage.NewMemoryTimerRepository(),
+		"bolt":   bolt.Timers(),
+	}
+	t0 := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	for name, repo := range repos {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			err := repo.Save(ctx,
+				model.ReinforcementTimer{ID: "sov:1", ExitsAt: t0.Add(2 * time.Hour)},
+				model.ReinforcementTimer{ID: "structure:2", ExitsAt: t0.Add(time.Hour), RemindedLeads: []time.Duration{time.Hour}},
+			)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			list, err := repo.List(ctx)
+			if err != nil || len(list) != 2 || list[0].ID != "structure:2" || list[1].ID != "sov:1" {
+				t.Errorf("expected timers by exit time, got %+v, %v", list, err)
+			}
+			got, err := repo.Get(ctx, "structure:2")
+			if err != nil || len(got.RemindedLeads) != 1 {
+				t.Errorf("unexpected timer: %+v, %v", got, err)
+			}
+			if err := repo.Delete(ctx, "structure:2"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, err := repo.Get(ctx, "structure:2"); !errors.Is(err, storage.ErrNotFound) {
+				t.Errorf("expected ErrNotFound, got %v", err)
+			}
+		})
+	}
+}
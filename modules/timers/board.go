@@ -0,0 +1,274 @@
+package timers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/monitor"
+	"github.com/guarzo/eveapi/modules/notifications"
+	"github.com/guarzo/eveapi/modules/notify"
+	"github.com/guarzo/eveapi/modules/storage"
+)
+
+// DefaultLeadTimes are the reminders sent before a timer exits.
+var DefaultLeadTimes = []time.Duration{time.Hour, 15 * time.Minute}
+
+// BoardOption customizes a Board.
+type BoardOption func(*Board)
+
+// WithLeadTimes sets how long before a timer exits reminders are sent.
+func WithLeadTimes(leads ...time.Duration) BoardOption {
+	return func(b *Board) {
+		b.leads = leads
+	}
+}
+
+// WithClock replaces time.Now, e.g. in tests.
+func WithClock(now func() time.Time) BoardOption {
+	return func(b *Board) {
+		b.now = now
+	}
+}
+
+// Board tracks reinforcement timers and reminds of them at each lead time.
+type Board struct {
+	repo     storage.TimerRepository
+	notifier notify.Notifier
+	leads    []time.Duration
+	now      func() time.Time
+
+	mu sync.Mutex // serializes read-modify-write cycles on repo
+}
+
+// NewBoard constructs a Board storing timers in repo. notifier may be nil.
+func NewBoard(repo storage.TimerRepository, notifier notify.Notifier, opts ...BoardOption) *Board {
+	b := &Board{
+		repo:     repo,
+		notifier: notifier,
+		leads:    DefaultLeadTimes,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	// longest lead first, so reminders go out in order
+	b.leads = slices.Clone(b.leads)
+	sort.Slice(b.leads, func(i, j int) bool { return b.leads[i] > b.leads[j] })
+	return b
+}
+
+// FromNotification extracts the timer set by a structure losing its shields
+// or armor. Other notifications report false.
+func FromNotification(n model.Notification) (model.ReinforcementTimer, bool, error) {
+	if n.Type != notifications.TypeStructureLostShields && n.Type != notifications.TypeStructureLostArmor {
+		return model.ReinforcementTimer{}, false, nil
+	}
+	payload, err := notifications.Parse(n)
+	if err != nil {
+		return model.ReinforcementTimer{}, false, err
+	}
+	p := payload.(*notifications.StructureLostLayer)
+	kind := model.TimerStructureShield
+	if p.Layer == "armor" {
+		kind = model.TimerStructureArmor
+	}
+	return model.ReinforcementTimer{
+		ID:              fmt.Sprintf("structure:%d", p.StructureID),
+		Kind:            kind,
+		StructureID:     p.StructureID,
+		StructureTypeID: p.StructureTypeID,
+		SolarSystemID:   p.SolarSystemID,
+		ExitsAt:         p.ReinforceExits,
+	}, true, nil
+}
+
+// FromCampaign converts a sovereignty campaign to the timer of its start.
+func FromCampaign(c model.SovereigntyCampaign) model.ReinforcementTimer {
+	return model.ReinforcementTimer{
+		ID:            fmt.Sprintf("sov:%d", c.CampaignID),
+		Kind:          model.TimerSovereignty,
+		StructureID:   c.StructureID,
+		SolarSystemID: c.SolarSystemID,
+		CampaignID:    c.CampaignID,
+		EventType:     c.EventType,
+		ExitsAt:       c.StartTime,
+	}
+}
+
+// AddNotifications records the timers set by the notifications and returns
+// how many were added or moved. A structure has one timer at a time, so a
+// later layer loss replaces the earlier timer; notifications setting a timer
+// that has already exited are ignored.
+func (b *Board) AddNotifications(ctx context.Context, ns []model.Notification) (int, error) {
+	var timers []model.ReinforcementTimer
+	var errs []error
+	for _, n := range ns {
+		t, ok, err := FromNotification(n)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if ok {
+			timers = append(timers, t)
+		}
+	}
+	added, err := b.add(ctx, timers)
+	return added, errors.Join(append(errs, err)...)
+}
+
+// AddCampaigns records the start of each campaign and returns how many
+// timers were added or moved.
+func (b *Board) AddCampaigns(ctx context.Context, cs []model.SovereigntyCampaign) (int, error) {
+	timers := make([]model.ReinforcementTimer, 0, len(cs))
+	for _, c := range cs {
+		timers = append(timers, FromCampaign(c))
+	}
+	return b.add(ctx, timers)
+}
+
+// add saves the upcoming timers that are new or have moved, keeping the
+// reminders already sent for unchanged ones.
+func (b *Board) add(ctx context.Context, timers []model.ReinforcementTimer) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	latest := make(map[string]model.ReinforcementTimer)
+	var order []string
+	for _, t := range timers {
+		if !t.ExitsAt.After(now) {
+			continue
+		}
+		prev, seen := latest[t.ID]
+		if !seen {
+			order = append(order, t.ID)
+		}
+		if !seen || t.ExitsAt.After(prev.ExitsAt) {
+			latest[t.ID] = t
+		}
+	}
+
+	var changed []model.ReinforcementTimer
+	for _, id := range order {
+		t := latest[id]
+		old, err := b.repo.Get(ctx, id)
+		switch {
+		case errors.Is(err, storage.ErrNotFound):
+		case err != nil:
+			return 0, err
+		case old.ExitsAt.Equal(t.ExitsAt):
+			continue
+		}
+		changed = append(changed, t)
+	}
+	if len(changed) == 0 {
+		return 0, nil
+	}
+	if err := b.repo.Save(ctx, changed...); err != nil {
+		return 0, err
+	}
+	return len(changed), nil
+}
+
+// Upcoming returns the timers that have not exited yet, soonest first.
+func (b *Board) Upcoming(ctx context.Context) ([]model.ReinforcementTimer, error) {
+	all, err := b.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := b.now()
+	out := make([]model.ReinforcementTimer, 0, len(all))
+	for _, t := range all {
+		if t.ExitsAt.After(now) {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+// Check sends the reminders that are due, deletes exited timers and returns
+// the events emitted. A timer gets one reminder per lead time; when several
+// lead times have passed at once, only the shortest is sent.
+func (b *Board) Check(ctx context.Context) ([]notify.Event, error) {
+	b.mu.Lock()
+	all, err := b.repo.List(ctx)
+	if err != nil {
+		b.mu.Unlock()
+		return nil, err
+	}
+	now := b.now()
+	var events []notify.Event
+	var errs []error
+	for _, t := range all {
+		left := t.ExitsAt.Sub(now)
+		if left <= 0 {
+			errs = append(errs, b.repo.Delete(ctx, t.ID))
+			continue
+		}
+		due := false
+		for _, lead := range b.leads {
+			if left <= lead && !slices.Contains(t.RemindedLeads, lead) {
+				t.RemindedLeads = append(t.RemindedLeads, lead)
+				due = true
+			}
+		}
+		if !due {
+			continue
+		}
+		events = append(events, ReminderEvent(now, t))
+		errs = append(errs, b.repo.Save(ctx, t))
+	}
+	b.mu.Unlock()
+
+	if b.notifier != nil {
+		for _, ev := range events {
+			errs = append(errs, b.notifier.Notify(ctx, ev))
+		}
+	}
+	return events, errors.Join(errs...)
+}
+
+// Run checks every interval until ctx is done. The interval bounds how late
+// a reminder can be.
+func (b *Board) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	monitor.Poll(ctx, interval, func(ctx context.Context) error {
+		_, err := b.Check(ctx)
+		return err
+	}, onError)
+}
+
+// Register adds a poller task that runs Check on schedule. The schedule
+// bounds how late a reminder can be; monitor.Every suits it, as a nil
+// schedule falls back to the poller's fallback interval.
+func (b *Board) Register(p *monitor.Poller, schedule monitor.Schedule) {
+	p.Add("timers", schedule, func(ctx context.Context) error {
+		_, err := b.Check(ctx)
+		return err
+	})
+}
+
+// ReminderEvent builds an EventTimerReminder for t, timestamped at.
+func ReminderEvent(at time.Time, t model.ReinforcementTimer) notify.Event {
+	var title string
+	switch t.Kind {
+	case model.TimerSovereignty:
+		title = fmt.Sprintf("%s campaign in system %d", t.EventType, t.SolarSystemID)
+	case model.TimerStructureArmor:
+		title = fmt.Sprintf("Structure %d hull timer", t.StructureID)
+	default:
+		title = fmt.Sprintf("Structure %d armor timer", t.StructureID)
+	}
+	return notify.Event{
+		Type:  notify.EventTimerReminder,
+		Time:  at,
+		Title: title,
+		Message: fmt.Sprintf("Exits %s, in %s", t.ExitsAt.UTC().Format("2006-01-02 15:04 MST"),
+			t.ExitsAt.Sub(at).Round(time.Minute)),
+		Payload: t,
+	}
+}
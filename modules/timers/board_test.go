@@ -0,0 +1,87 @@
+package timers_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/notify"
+	"github.com/guarzo/eveapi/modules/storage"
+	"github.com/guarzo/eveapi/modules/timers"
+)
+
+// lostShieldsText sets a 24h timer from 2024-10-01 00:00 UTC.
+const lostShieldsText = `solarsystemID: 30000142
+structureID: 1021000000001
+structureTypeID: 35832
+timeLeft: 864000000000
+timestamp: 133722144000000000
+vulnerableTime: 9000000000
+`
+
+func TestBoard(t *testing.T) {
+	ctx := context.Background()
+	exits := time.Date(2024, 10, 2, 0, 0, 0, 0, time.UTC)
+	now := exits.Add(-3 * time.Hour)
+	var sent []notify.Event
+	board := timers.NewBoard(storage.NewMemoryTimerRepository(),
+		notify.NotifierFunc(func(ctx context.Context, ev notify.Event) error {
+			sent = append(sent, ev)
+			return nil
+		}),
+		timers.WithLeadTimes(15*time.Minute, time.Hour),
+		timers.WithClock(func() time.Time { return now }),
+	)
+
+	added, err := board.AddNotifications(ctx, []model.Notification{
+		{Type: "StructureLostShields", Text: lostShieldsText},
+		{Type: "StructureUnderAttack", Text: "structureID: 1"},
+	})
+	if err != nil || added != 1 {
+		t.Fatalf("expected one timer, got %d, %v", added, err)
+	}
+	campaigns := []model.SovereigntyCampaign{
+		{CampaignID: 7, SolarSystemID: 30000001, EventType: "ihub_defense", StartTime: exits.Add(-time.Hour)},
+		{CampaignID: 8, StartTime: now.Add(-time.Minute)},
+	}
+	if added, err := board.AddCampaigns(ctx, campaigns); err != nil || added != 1 {
+		t.Fatalf("expected one upcoming campaign, got %d, %v", added, err)
+	}
+	if added, _ := board.AddCampaigns(ctx, campaigns); added != 0 {
+		t.Errorf("expected unchanged campaigns to be skipped, got %d", added)
+	}
+
+	up, err := board.Upcoming(ctx)
+	if err != nil || len(up) != 2 || up[0].ID != "sov:7" || up[1].Kind != model.TimerStructureShield || !up[1].ExitsAt.Equal(exits) {
+		t.Fatalf("unexpected upcoming timers: %+v, %v", up, err)
+	}
+
+	// both lead times of the campaign pass at once: one reminder
+	now = exits.Add(-70 * time.Minute)
+	if evs, err := board.Check(ctx); err != nil || len(evs) != 1 || evs[0].Payload.(model.ReinforcementTimer).ID != "sov:7" {
+		t.Fatalf("expected the campaign reminder, got %+v, %v", evs, err)
+	}
+	now = exits.Add(-50 * time.Minute)
+	if evs, _ := board.Check(ctx); len(evs) != 1 || evs[0].Type != notify.EventTimerReminder {
+		t.Fatalf("expected the structure's one hour reminder, got %+v", evs)
+	}
+	if evs, _ := board.Check(ctx); len(evs) != 0 {
+		t.Errorf("expected reminders to be sent once, got %+v", evs)
+	}
+	now = exits.Add(-10 * time.Minute)
+	if evs, _ := board.Check(ctx); len(evs) != 1 {
+		t.Errorf("expected the structure's 15 minute reminder, got %+v", evs)
+	}
+	if len(sent) != 3 {
+		t.Errorf("expected 3 notifications, got %d", len(sent))
+	}
+
+	now = exits.Add(time.Minute)
+	if _, err := board.Check(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if up, _ := board.Upcoming(ctx); len(up) != 0 {
+		t.Errorf("expected exited timers to be removed, got %+v", up)
+	}
+}
@@ -0,0 +1,5 @@
+// Package timers keeps a board of reinforcement timers: when reinforced
+// structures come out of reinforcement and when sovereignty campaigns open.
+// Timers are taken from parsed notifications and ESI campaigns, stored in a
+// storage.TimerRepository and announced through a notifier ahead of time.
+package timers
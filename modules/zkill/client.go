@@ -2,10 +2,9 @@ package zkill
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/guarzo/eveapi/common"
@@ -14,11 +13,12 @@ import (
 
 // ZKillClient is a lower-level interface for fetching from zKillboard’s API.
 type ZKillClient interface {
-	GetKillsPageData(ctx context.Context, entityType string, entityID, page, year, month int) ([]model.ZkillMail, error)
-	GetLossPageData(ctx context.Context, entityType string, entityID, page, year, month int) ([]model.ZkillMail, error)
+	GetKillsPageData(ctx context.Context, entityType string, entityID int64, page, year, month int) ([]model.ZkillMail, error)
+	GetLossPageData(ctx context.Context, entityType string, entityID int64, page, year, month int) ([]model.ZkillMail, error)
 	RemoveCacheEntry(cacheKey string)
-	GetSingleKillmail(ctx context.Context, killID int) (model.ZkillMailFeedResponse, error)
-	BuildCacheKey(apiType, entityType string, entityID, year, month, page int) string
+	GetSingleKillmail(ctx context.Context, killID int64) (model.ZkillMailFeedResponse, error)
+	BuildCacheKey(apiType, entityType string, entityID int64, year, month, page int) string
+	Stats() common.ClientStats
 }
 
 // zKillClient implements ZKillClient.
@@ -26,15 +26,93 @@ type zKillClient struct {
 	BaseURL string
 	Client  common.HttpClient
 	Cache   common.CacheRepository
+	cache   common.ContextCacheRepository
+	codec   common.Codec
+	stats   *common.StatsCounter
+	metrics common.MetricsRecorder
+	pause   *common.PauseGate
+	limiter common.Limiter
+	decoder common.JSONDecoder
+	maxBody int64
+}
+
+// ClientOption customizes a ZKillClient.
+type ClientOption func(*zKillClient)
+
+// WithMetricsRecorder forwards request metrics to r in addition to the
+// client's own counters reported by Stats.
+func WithMetricsRecorder(r common.MetricsRecorder) ClientOption {
+	return func(zk *zKillClient) {
+		zk.metrics = common.MultiRecorder(zk.stats, r)
+	}
+}
+
+// WithCodec sets the serialization used for cached killmail pages.
+func WithCodec(codec common.Codec) ClientOption {
+	return func(zk *zKillClient) {
+		zk.codec = codec
+	}
+}
+
+// WithPauseGate shares a rate-limit pause with other clients. By default each
+// client has its own gate, shared by every goroutine using it.
+func WithPauseGate(g *common.PauseGate) ClientOption {
+	return func(zk *zKillClient) {
+		zk.pause = g
+	}
+}
+
+// WithLimiter paces every request through l, e.g. a common.NewRedisLimiter
+// shared by several instances of an application.
+func WithLimiter(l common.Limiter) ClientOption {
+	return func(zk *zKillClient) {
+		zk.limiter = l
+	}
+}
+
+// WithStrictDecoding makes fetches fail when zKillboard returns fields the
+// killmail models do not declare.
+func WithStrictDecoding() ClientOption {
+	return func(zk *zKillClient) {
+		zk.decoder.Strict = true
+	}
+}
+
+// WithUnknownFieldHandler reports response fields missing from the killmail
+// models, with the request URL as source.
+func WithUnknownFieldHandler(fn common.UnknownFieldFunc) ClientOption {
+	return func(zk *zKillClient) {
+		zk.decoder.OnUnknownField = fn
+	}
+}
+
+// WithMaxResponseSize bounds the response bodies the client reads
+// (common.DefaultMaxResponseSize by default, <= 0 for no bound). Larger
+// responses fail with common.ErrResponseTooLarge.
+func WithMaxResponseSize(n int64) ClientOption {
+	return func(zk *zKillClient) {
+		zk.maxBody = n
+	}
 }
 
 // NewZkillClient constructs a zKillClient. The baseURL is typically "https://zkillboard.com".
-func NewZkillClient(baseURL string, client common.HttpClient, cache common.CacheRepository) ZKillClient {
-	return &zKillClient{
+func NewZkillClient(baseURL string, client common.HttpClient, cache common.CacheRepository, opts ...ClientOption) ZKillClient {
+	stats := &common.StatsCounter{}
+	zk := &zKillClient{
 		BaseURL: baseURL,
 		Client:  client,
 		Cache:   cache,
+		cache:   common.AsContextCache(cache),
+		codec:   common.DefaultCodec,
+		stats:   stats,
+		metrics: stats,
+		pause:   common.NewPauseGate(),
+		maxBody: common.DefaultMaxResponseSize,
+	}
+	for _, opt := range opts {
+		opt(zk)
 	}
+	return zk
 }
 
 const zkillCacheExpiration = 770 * time.Hour // Example expiration (~1 month)
@@ -44,24 +122,29 @@ func (zk *zKillClient) RemoveCacheEntry(cacheKey string) {
 	zk.Cache.Delete(cacheKey)
 }
 
+// Stats returns a snapshot of this client's request counters.
+func (zk *zKillClient) Stats() common.ClientStats {
+	return zk.stats.Snapshot()
+}
+
 // BuildCacheKey composes a string to store/fetch data in the CacheRepository.
-func (zk *zKillClient) BuildCacheKey(apiType, entityType string, entityID, year, month, page int) string {
+func (zk *zKillClient) BuildCacheKey(apiType, entityType string, entityID int64, year, month, page int) string {
 	// E.g. "zkill:kills:corporationID:9000000:2023:10:1"
 	return fmt.Sprintf("zkill:%s:%sID:%d:%d:%02d:%d", apiType, entityType, entityID, year, month, page)
 }
 
 // GetKillsPageData fetches killmails (where entity is an attacker).
-func (zk *zKillClient) GetKillsPageData(ctx context.Context, entityType string, entityID, page, year, month int) ([]model.ZkillMail, error) {
+func (zk *zKillClient) GetKillsPageData(ctx context.Context, entityType string, entityID int64, page, year, month int) ([]model.ZkillMail, error) {
 	return zk.fetchPageData(ctx, "kills", entityType, entityID, page, year, month)
 }
 
 // GetLossPageData fetches killmails (where entity is a victim).
-func (zk *zKillClient) GetLossPageData(ctx context.Context, entityType string, entityID, page, year, month int) ([]model.ZkillMail, error) {
+func (zk *zKillClient) GetLossPageData(ctx context.Context, entityType string, entityID int64, page, year, month int) ([]model.ZkillMail, error) {
 	return zk.fetchPageData(ctx, "losses", entityType, entityID, page, year, month)
 }
 
 // Private method that constructs the request URL and fetches data from zKillboard.
-func (zk *zKillClient) fetchPageData(ctx context.Context, apiType, entityType string, entityID, page, year, month int) ([]model.ZkillMail, error) {
+func (zk *zKillClient) fetchPageData(ctx context.Context, apiType, entityType string, entityID int64, page, year, month int) ([]model.ZkillMail, error) {
 	requestURL := fmt.Sprintf("%s/api/%s/%sID/%d/year/%d/month/%d/page/%d/",
 		zk.BaseURL, apiType, entityType, entityID, year, month, page)
 	cacheKey := zk.BuildCacheKey(apiType, entityType, entityID, year, month, page)
@@ -71,12 +154,14 @@ func (zk *zKillClient) fetchPageData(ctx context.Context, apiType, entityType st
 	isCurrentMonth := (year == currentYear && month == int(currentMonth))
 
 	// Try cache first
-	if cachedData, found := zk.Cache.Get(cacheKey); found {
+	if cachedData, found, err := zk.cache.GetCtx(ctx, cacheKey); err == nil && found {
 		var kills []model.ZkillMail
-		if err := json.Unmarshal(cachedData, &kills); err == nil {
+		if err := zk.codec.Unmarshal(cachedData, &kills); err == nil {
+			zk.metrics.RecordCache(true)
 			return kills, nil
 		}
 	}
+	zk.metrics.RecordCache(false)
 
 	// We either had no cache or invalid data. Make an HTTP GET request.
 	kills, err := zk.doGetKillMails(ctx, requestURL)
@@ -91,9 +176,9 @@ func (zk *zKillClient) fetchPageData(ctx context.Context, apiType, entityType st
 	}
 
 	// Save result to cache
-	bytes, err := json.Marshal(kills)
+	bytes, err := zk.codec.Marshal(kills)
 	if err == nil {
-		zk.Cache.Set(cacheKey, bytes, exp)
+		_ = zk.cache.SetCtx(ctx, cacheKey, bytes, exp)
 	}
 
 	return kills, nil
@@ -106,18 +191,31 @@ func (zk *zKillClient) doGetKillMails(ctx context.Context, url string) ([]model.
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
+	if err := zk.wait(ctx); err != nil {
+		return nil, err
+	}
+	start := time.Now()
 	resp, err := zk.Client.Do(req)
 	if err != nil {
+		zk.metrics.RecordResponse(0, time.Since(start))
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
+	zk.metrics.RecordResponse(resp.StatusCode, time.Since(start))
+	if delay, limited := common.RateLimitDelay(resp, common.DefaultRateLimitPause); limited {
+		zk.pause.PauseUntil(time.Now().Add(delay))
+	}
 
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("non-200 response from zKill: %d", resp.StatusCode)
 	}
 
+	body, err := common.ReadLimited(resp.Body, zk.maxBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read zkill response: %w", err)
+	}
 	var kills []model.ZkillMail
-	if err = json.NewDecoder(resp.Body).Decode(&kills); err != nil {
+	if err = zk.decoder.Decode(url, body, &kills); err != nil {
 		return nil, fmt.Errorf("failed to decode zkill JSON: %w", err)
 	}
 	return kills, nil
@@ -129,7 +227,7 @@ func (zk *zKillClient) doGetKillMails(ctx context.Context, url string) ([]model.
 
 // GetSingleKillmail fetches the single kill’s details from zKill at /api/killID/<killID>/.
 // zKill normally returns an array of length 1 with the kill’s victim/attackers data.
-func (zk *zKillClient) GetSingleKillmail(ctx context.Context, killID int) (model.ZkillMailFeedResponse, error) {
+func (zk *zKillClient) GetSingleKillmail(ctx context.Context, killID int64) (model.ZkillMailFeedResponse, error) {
 	// We'll define a specialized endpoint: /api/killID/<killID>/
 	requestURL := fmt.Sprintf("%s/api/killID/%d/", zk.BaseURL, killID)
 
@@ -137,12 +235,14 @@ func (zk *zKillClient) GetSingleKillmail(ctx context.Context, killID int) (model
 	cacheKey := fmt.Sprintf("zkill:single:killID:%d", killID)
 
 	// Attempt to fetch from cache
-	if cachedData, found := zk.Cache.Get(cacheKey); found {
+	if cachedData, found, err := zk.cache.GetCtx(ctx, cacheKey); err == nil && found {
 		var kills []model.ZkillMailFeedResponse
-		if err := json.Unmarshal(cachedData, &kills); err == nil && len(kills) > 0 {
+		if err := zk.codec.Unmarshal(cachedData, &kills); err == nil && len(kills) > 0 {
+			zk.metrics.RecordCache(true)
 			return kills[0], nil
 		}
 	}
+	zk.metrics.RecordCache(false)
 
 	// If not in cache, fetch from zKill
 	kills, err := zk.doGetSingleKillMails(ctx, requestURL)
@@ -154,9 +254,9 @@ func (zk *zKillClient) GetSingleKillmail(ctx context.Context, killID int) (model
 	}
 
 	// Cache it
-	jsonBytes, err := json.Marshal(kills)
+	encoded, err := zk.codec.Marshal(kills)
 	if err == nil {
-		zk.Cache.Set(cacheKey, jsonBytes, zkillCacheExpiration)
+		_ = zk.cache.SetCtx(ctx, cacheKey, encoded, zkillCacheExpiration)
 	}
 
 	// Return the first (and typically only) kill
@@ -171,10 +271,9 @@ func (zk *zKillClient) doGetSingleKillMails(ctx context.Context, url string) ([]
 	backoff := 1 * time.Second
 
 	for attempt := 1; attempt <= maxAttempts; attempt++ {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
+		// a 429 seen by any goroutine holds every request back
+		if err := zk.wait(ctx); err != nil {
+			return nil, err
 		}
 
 		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
@@ -182,7 +281,9 @@ func (zk *zKillClient) doGetSingleKillMails(ctx context.Context, url string) ([]
 			return nil, fmt.Errorf("failed to create request: %w", err)
 		}
 
+		start := time.Now()
 		resp, err := zk.Client.Do(req)
+		zk.metrics.RecordResponse(statusOf(resp), time.Since(start))
 		if err != nil {
 			// HTTP request failed; sleep & retry
 			time.Sleep(backoff)
@@ -190,34 +291,39 @@ func (zk *zKillClient) doGetSingleKillMails(ctx context.Context, url string) ([]
 			continue
 		}
 
+		var decodeErr error
 		func() {
 			defer resp.Body.Close()
 			switch resp.StatusCode {
 			case http.StatusOK:
 				// Decode the JSON
-				if decodeErr := json.NewDecoder(resp.Body).Decode(&kills); decodeErr != nil {
-					// If decode fails, we can log or handle the error
-					// but we won't set 'kills' so we'll retry
+				body, readErr := common.ReadLimited(resp.Body, zk.maxBody)
+				if readErr != nil {
+					decodeErr = readErr
+					return
 				}
-			case http.StatusTooManyRequests:
-				// 429: handle backoff logic
-				retryAfter := resp.Header.Get("Retry-After")
-				if retryAfter != "" {
-					if secs, errConv := strconv.Atoi(retryAfter); errConv == nil {
-						time.Sleep(time.Duration(secs) * time.Second)
-					} else {
-						time.Sleep(backoff)
-						backoff *= 2
-					}
-				} else {
-					time.Sleep(backoff)
-					backoff *= 2
+				if decodeErr = zk.decoder.Decode(url, body, &kills); decodeErr != nil {
+					// If decode fails, we won't keep 'kills' so we'll retry
+					kills = nil
 				}
+			case http.StatusTooManyRequests:
+				// 429: pause every request of this client, then retry
+				delay, _ := common.RateLimitDelay(resp, backoff)
+				zk.pause.PauseUntil(time.Now().Add(delay))
+				backoff *= 2
 			default:
 				// e.g. 404 or 500 - we can decide to retry or break
 			}
 		}()
 
+		// an oversized body or a strict decode failure will not go away on retry
+		if errors.Is(decodeErr, common.ErrResponseTooLarge) {
+			return nil, fmt.Errorf("failed to read zkill response: %w", decodeErr)
+		}
+		if decodeErr != nil && zk.decoder.Strict {
+			return nil, fmt.Errorf("failed to decode zkill JSON: %w", decodeErr)
+		}
+
 		// If we successfully decoded kills, return immediately
 		if len(kills) > 0 {
 			return kills, nil
@@ -232,3 +338,22 @@ func (zk *zKillClient) doGetSingleKillMails(ctx context.Context, url string) ([]
 
 	return nil, fmt.Errorf("all %d attempts failed for single kill URL %s", maxAttempts, url)
 }
+
+// wait blocks until the client may send its next request.
+func (zk *zKillClient) wait(ctx context.Context) error {
+	if err := zk.pause.Wait(ctx); err != nil {
+		return err
+	}
+	if zk.limiter != nil {
+		return zk.limiter.Wait(ctx)
+	}
+	return nil
+}
+
+// statusOf returns the response status code, or 0 when there is no response.
+func statusOf(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+	return resp.StatusCode
+}
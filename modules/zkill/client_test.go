@@ -27,6 +27,9 @@ type mockEsiClient struct {
 func (m *mockEsiClient) GetJSON(ctx context.Context, endpoint string, entity interface{}, token *oauth2.Token, params map[string]string) error {
 	return m.getJSONFunc(ctx, endpoint, entity, token, params)
 }
+func (m *mockEsiClient) GetFreshJSON(ctx context.Context, endpoint string, entity interface{}, token *oauth2.Token, params map[string]string) error {
+	return m.getJSONFunc(ctx, endpoint, entity, token, params)
+}
 func (m *mockEsiClient) GetBytes(ctx context.Context, endpoint string, token *oauth2.Token, params map[string]string) ([]byte, error) {
 	return m.getBytesFunc(ctx, endpoint, token, params)
 }
@@ -90,3 +93,32 @@ func TestZKillClient_GetKillsPageData_Cached(t *testing.T) {
 		t.Errorf("expected 1 from cache, got %d", len(res2))
 	}
 }
+
+func TestZKillClient_GobCodec(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, `[{"killmail_id":42,"zkb":{"hash":"abc","totalValue":1000}}]`)
+	}))
+	defer ts.Close()
+
+	c := &mockCache{store: make(map[string][]byte)}
+	cli := zkill.NewZkillClient(ts.URL, common.NewEveHttpClient("UA", &http.Client{}), c, zkill.WithCodec(common.GobCodec{}))
+
+	ctx := context.Background()
+	if _, err := cli.GetKillsPageData(ctx, "character", 999, 1, 2023, 10); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cached := c.store[cli.BuildCacheKey("kills", "character", 999, 2023, 10, 1)]
+	if json.Valid(cached) {
+		t.Error("expected gob-encoded cache entry, got JSON")
+	}
+
+	res, err := cli.GetKillsPageData(ctx, "character", 999, 1, 2023, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 || len(res) != 1 || res[0].ZKB.Hash != "abc" {
+		t.Errorf("expected decoded cache hit, got %d calls and %+v", calls, res)
+	}
+}
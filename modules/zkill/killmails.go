@@ -0,0 +1,262 @@
+package zkill
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/killmail"
+)
+
+// EsiKillmailSource fetches full killmails from ESI; esi.EsiService satisfies it.
+type EsiKillmailSource interface {
+	GetEsiKillMail(ctx context.Context, killID int64, hash string) (*model.EsiKillMail, error)
+}
+
+// PriceSource supplies market prices for value backfill; esi.EsiService satisfies it.
+type PriceSource interface {
+	GetMarketPrices(ctx context.Context) ([]model.MarketPrice, error)
+}
+
+// HydrationErrorPolicy decides what happens to a kill whose ESI details
+// cannot be fetched.
+type HydrationErrorPolicy int
+
+const (
+	// SkipFailedKills drops the kill from the results.
+	SkipFailedKills HydrationErrorPolicy = iota
+	// KeepUnhydratedKills keeps the kill with only its zKill data.
+	KeepUnhydratedKills
+	// FailOnHydrationError aborts the call with the error.
+	FailOnHydrationError
+)
+
+// KillmailServiceOption customizes the service built by NewKillmailService
+// or the Hydrator built by NewHydrator.
+type KillmailServiceOption func(*Hydrator)
+
+// WithHydrationConcurrency sets how many ESI killmails are fetched at once
+// (common.DefaultConcurrency by default).
+func WithHydrationConcurrency(n int) KillmailServiceOption {
+	return func(h *Hydrator) {
+		h.workers = n
+	}
+}
+
+// WithHydrationErrorPolicy sets what happens to kills ESI fails to return
+// (SkipFailedKills by default).
+func WithHydrationErrorPolicy(p HydrationErrorPolicy) KillmailServiceOption {
+	return func(h *Hydrator) {
+		h.policy = p
+	}
+}
+
+// WithHydrationErrorHandler receives every failed ESI fetch, whatever the
+// policy, e.g. for logging.
+func WithHydrationErrorHandler(fn func(killID int64, err error)) KillmailServiceOption {
+	return func(h *Hydrator) {
+		h.onError = fn
+	}
+}
+
+// WithHydratedPriceBackfill computes fitted/dropped/destroyed/total values
+// from the hydrated victim items when zKill reports none.
+func WithHydratedPriceBackfill(src PriceSource) KillmailServiceOption {
+	return func(h *Hydrator) {
+		h.prices = &priceCache{src: src}
+	}
+}
+
+// Hydrator fetches the ESI details of kills listed by zKillboard, applying
+// its error policy and optional price backfill.
+type Hydrator struct {
+	esi     EsiKillmailSource
+	workers int
+	policy  HydrationErrorPolicy
+	onError func(killID int64, err error)
+	prices  *priceCache
+}
+
+// NewHydrator constructs a Hydrator fetching from esiSvc.
+func NewHydrator(esiSvc EsiKillmailSource, opts ...KillmailServiceOption) *Hydrator {
+	h := &Hydrator{
+		esi:     esiSvc,
+		workers: common.DefaultConcurrency,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// killmailService is a ZKillService whose killmails carry their ESI details.
+type killmailService struct {
+	ZKillService
+	hydrator *Hydrator
+}
+
+// NewKillmailService combines zKillboard listings from zkillSvc with ESI
+// killmail details from esiSvc: every kill returned by GetKillMailDataForMonth
+// and AddEsiKillMail is fetched from ESI with its hash, so KillMailTime,
+// Victim and Attackers are populated.
+func NewKillmailService(esiSvc EsiKillmailSource, zkillSvc ZKillService, opts ...KillmailServiceOption) ZKillService {
+	return &killmailService{
+		ZKillService: zkillSvc,
+		hydrator:     NewHydrator(esiSvc, opts...),
+	}
+}
+
+// GetKillMailDataForMonth lists the month's kills through the wrapped service
+// and hydrates the ones lacking ESI details.
+func (s *killmailService) GetKillMailDataForMonth(ctx context.Context, params *model.Params, year, month int) ([]model.FlattenedKillMail, error) {
+	kills, err := s.ZKillService.GetKillMailDataForMonth(ctx, params, year, month)
+	if err != nil {
+		return nil, err
+	}
+	return s.hydrator.HydrateAll(ctx, kills)
+}
+
+// AddEsiKillMail fetches mail from ESI, flattens it and appends it to aggregated.
+func (s *killmailService) AddEsiKillMail(ctx context.Context, mail model.ZkillMail, aggregated []model.FlattenedKillMail) ([]model.FlattenedKillMail, error) {
+	kills, err := s.hydrator.HydrateAll(ctx, []model.FlattenedKillMail{model.ConvertToFlattened(model.EsiKillMail{KillMailID: mail.KillMailID}, mail)})
+	if err != nil {
+		return aggregated, err
+	}
+	return append(aggregated, kills...), nil
+}
+
+// HydrateAll fetches the ESI details of every kill with no KillMailTime,
+// applying the error policy, and returns the kills in their original order.
+// kills is updated in place.
+func (h *Hydrator) HydrateAll(ctx context.Context, kills []model.FlattenedKillMail) ([]model.FlattenedKillMail, error) {
+	var pending []int
+	for i := range kills {
+		if kills[i].KillMailTime.IsZero() {
+			pending = append(pending, i)
+		}
+	}
+	var (
+		mu     sync.Mutex
+		failed = make(map[int]bool)
+	)
+	err := common.Batch(ctx, pending, func(ctx context.Context, i int) error {
+		err := h.hydrate(ctx, &kills[i])
+		if err == nil {
+			return nil
+		}
+		if h.onError != nil {
+			h.onError(kills[i].KillMailID, err)
+		}
+		if h.policy == FailOnHydrationError {
+			return err
+		}
+		mu.Lock()
+		failed[i] = true
+		mu.Unlock()
+		return nil
+	}, h.workers)
+	if err != nil {
+		return nil, err
+	}
+	if len(failed) == 0 || h.policy == KeepUnhydratedKills {
+		return kills, nil
+	}
+	out := make([]model.FlattenedKillMail, 0, len(kills)-len(failed))
+	for i := range kills {
+		if !failed[i] {
+			out = append(out, kills[i])
+		}
+	}
+	return out, nil
+}
+
+// hydrate replaces km with the flattened ESI killmail merged with km's zKill data.
+func (h *Hydrator) hydrate(ctx context.Context, km *model.FlattenedKillMail) error {
+	merged := *km
+	if err := HydrateKillmail(ctx, h.esi, &merged); err != nil {
+		return err
+	}
+	if err := h.prices.backfill(ctx, &merged); err != nil {
+		return err
+	}
+	*km = merged
+	return nil
+}
+
+// HydrateKillmail fetches the ESI details of a kill listed by zKillboard,
+// using its hash, and merges them into km.
+func HydrateKillmail(ctx context.Context, src EsiKillmailSource, km *model.FlattenedKillMail) error {
+	if km.Hash == "" {
+		return fmt.Errorf("killmail %d has no hash", km.KillMailID)
+	}
+	full, err := src.GetEsiKillMail(ctx, km.KillMailID, km.Hash)
+	if err != nil {
+		return err
+	}
+	*km = model.ConvertToFlattened(*full, zkillPart(km))
+	return nil
+}
+
+// zkillPart recovers the zKill entry a flattened killmail was built from.
+func zkillPart(km *model.FlattenedKillMail) model.ZkillMail {
+	return model.ZkillMail{
+		KillMailID: km.KillMailID,
+		ZKB: model.ZKB{
+			LocationID:     km.LocationID,
+			Hash:           km.Hash,
+			FittedValue:    km.FittedValue,
+			DroppedValue:   km.DroppedValue,
+			DestroyedValue: km.DestroyedValue,
+			TotalValue:     km.TotalValue,
+			Points:         km.Points,
+			NPC:            km.NPC,
+			Solo:           km.Solo,
+			Awox:           km.Awox,
+		},
+	}
+}
+
+// priceRefreshInterval matches the update cadence of /markets/prices/.
+const priceRefreshInterval = time.Hour
+
+// priceCache holds the market price table used for value backfill.
+type priceCache struct {
+	src PriceSource
+
+	mu     sync.Mutex
+	table  map[int64]float64
+	loaded time.Time
+}
+
+// backfill fills missing zkb values of km; a nil cache does nothing.
+func (c *priceCache) backfill(ctx context.Context, km *model.FlattenedKillMail) error {
+	if c == nil || !killmail.NeedsBackfill(km) {
+		return nil
+	}
+	table, err := c.load(ctx)
+	if err != nil {
+		return err
+	}
+	killmail.Backfill(km, table)
+	return nil
+}
+
+// load returns the cached price table, refreshing it when stale.
+func (c *priceCache) load(ctx context.Context) (map[int64]float64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.table != nil && time.Since(c.loaded) < priceRefreshInterval {
+		return c.table, nil
+	}
+	prices, err := c.src.GetMarketPrices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.table = model.PriceTable(prices)
+	c.loaded = time.Now()
+	return c.table, nil
+}
@@ -0,0 +1,128 @@
+package zkill_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/zkill"
+	"github.com/guarzo/eveapi/testsupport"
+)
+
+func killmailSources(failID int64) (*testsupport.EsiService, *testsupport.ZKillService) {
+	esiSvc := &testsupport.EsiService{
+		GetEsiKillMailFunc: func(ctx context.Context, killID int64, hash string) (*model.EsiKillMail, error) {
+			if killID == failID {
+				return nil, errors.New("esi unavailable")
+			}
+			return &model.EsiKillMail{
+				KillMailID:    killID,
+				KillMailTime:  time.Date(2024, 5, int(killID), 0, 0, 0, 0, time.UTC),
+				SolarSystemID: 30000142,
+				Victim:        model.Victim{CharacterID: 10, ShipTypeID: 587},
+				Attackers:     []model.Attacker{{CharacterID: 20, FinalBlow: true}},
+			}, nil
+		},
+	}
+	zkillSvc := &testsupport.ZKillService{
+		GetKillMailDataForMonthFunc: func(ctx context.Context, params *model.Params, year, month int) ([]model.FlattenedKillMail, error) {
+			return []model.FlattenedKillMail{
+				{KillMailID: 1, Hash: "a", TotalValue: 100, Solo: true},
+				{KillMailID: 2, Hash: "b", TotalValue: 200},
+				{KillMailID: 3, Hash: "c", TotalValue: 300},
+			}, nil
+		},
+	}
+	return esiSvc, zkillSvc
+}
+
+func TestKillmailService_HydratesMonth(t *testing.T) {
+	esiSvc, zkillSvc := killmailSources(2)
+	var failures []int64
+	svc := zkill.NewKillmailService(esiSvc, zkillSvc,
+		zkill.WithHydrationErrorHandler(func(id int64, err error) { failures = append(failures, id) }))
+
+	out, err := svc.GetKillMailDataForMonth(context.Background(), &model.Params{}, 2024, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out) != 2 || out[0].KillMailID != 1 || out[1].KillMailID != 3 {
+		t.Fatalf("expected kills 1 and 3 in order, got %+v", out)
+	}
+	km := out[0]
+	if km.KillMailTime.IsZero() || km.Victim.ShipTypeID != 587 || len(km.Attackers) != 1 {
+		t.Errorf("expected ESI details, got %+v", km)
+	}
+	if km.TotalValue != 100 || !km.Solo || km.Hash != "a" {
+		t.Errorf("expected zKill data to be kept, got %+v", km)
+	}
+	if len(failures) != 1 || failures[0] != 2 {
+		t.Errorf("expected kill 2 to be reported, got %v", failures)
+	}
+}
+
+func TestKillmailService_ErrorPolicies(t *testing.T) {
+	esiSvc, zkillSvc := killmailSources(2)
+
+	keep := zkill.NewKillmailService(esiSvc, zkillSvc, zkill.WithHydrationErrorPolicy(zkill.KeepUnhydratedKills))
+	out, err := keep.GetKillMailDataForMonth(context.Background(), &model.Params{}, 2024, 5)
+	if err != nil || len(out) != 3 || !out[1].KillMailTime.IsZero() {
+		t.Errorf("expected kill 2 to be kept unhydrated, got %+v, %v", out, err)
+	}
+
+	fail := zkill.NewKillmailService(esiSvc, zkillSvc, zkill.WithHydrationErrorPolicy(zkill.FailOnHydrationError))
+	if _, err := fail.GetKillMailDataForMonth(context.Background(), &model.Params{}, 2024, 5); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestKillmailService_AddEsiKillMail(t *testing.T) {
+	esiSvc, zkillSvc := killmailSources(0)
+	svc := zkill.NewKillmailService(esiSvc, zkillSvc)
+
+	out, err := svc.AddEsiKillMail(context.Background(), model.ZkillMail{KillMailID: 4, ZKB: model.ZKB{Hash: "d", Points: 7}}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out) != 1 || out[0].SolarSystemID != 30000142 || out[0].Points != 7 {
+		t.Errorf("expected a hydrated kill, got %+v", out)
+	}
+}
+
+func TestKillmailService_HydratedPriceBackfill(t *testing.T) {
+	esiSvc, _ := killmailSources(0)
+	hydrate := esiSvc.GetEsiKillMailFunc
+	esiSvc.GetEsiKillMailFunc = func(ctx context.Context, killID int64, hash string) (*model.EsiKillMail, error) {
+		km, err := hydrate(ctx, killID, hash)
+		if err == nil {
+			km.Victim.Items = []model.VictimItem{
+				{Flag: 27, ItemTypeID: 484, QuantityDestroyed: 1}, // high slot
+				{Flag: 5, ItemTypeID: 34, QuantityDropped: 100},   // cargo
+			}
+		}
+		return km, err
+	}
+	esiSvc.GetMarketPricesFunc = func(ctx context.Context) ([]model.MarketPrice, error) {
+		return []model.MarketPrice{
+			{TypeID: 587, AveragePrice: 400000},
+			{TypeID: 484, AveragePrice: 20000},
+			{TypeID: 34, AveragePrice: 5},
+		}, nil
+	}
+	svc := zkill.NewKillmailService(esiSvc, zkill.NewZKillService(nil), zkill.WithHydratedPriceBackfill(esiSvc))
+
+	out, err := svc.AddEsiKillMail(context.Background(), model.ZkillMail{KillMailID: 4, ZKB: model.ZKB{Hash: "d"}}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out) != 1 {
+		t.Fatalf("expected one kill, got %d", len(out))
+	}
+	km := out[0]
+	if km.TotalValue != 420500 || km.FittedValue != 420000 || km.DroppedValue != 500 || km.DestroyedValue != 420000 {
+		t.Errorf("expected values priced from the victim's items, got fitted %v dropped %v destroyed %v total %v",
+			km.FittedValue, km.DroppedValue, km.DestroyedValue, km.TotalValue)
+	}
+}
@@ -0,0 +1,176 @@
+package zkill
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// DefaultRedisQURL is zKillboard's RedisQ listen endpoint.
+const DefaultRedisQURL = "https://zkillredisq.stream/listen.php"
+
+// RedisQOption customizes a RedisQ.
+type RedisQOption func(*RedisQ)
+
+// WithTimeToWait sets how long RedisQ holds a request open waiting for a
+// kill (10s by default, at most 10s).
+func WithTimeToWait(d time.Duration) RedisQOption {
+	return func(q *RedisQ) {
+		q.ttw = d
+	}
+}
+
+// WithRedisQRetryDelay sets the pause after a failed request (5s by default).
+func WithRedisQRetryDelay(d time.Duration) RedisQOption {
+	return func(q *RedisQ) {
+		q.retry = d
+	}
+}
+
+// RedisQ reads zKillboard's RedisQ live feed. Each queue ID gets every kill
+// once, so the feed resumes where it left off after a restart within the
+// queue's retention (about three hours).
+type RedisQ struct {
+	url     string
+	queueID string
+	client  common.HttpClient
+	ttw     time.Duration
+	retry   time.Duration
+	pause   *common.PauseGate
+	decoder common.JSONDecoder
+	maxBody int64
+	life    common.Lifecycle
+}
+
+// NewRedisQ constructs a RedisQ reading queueID from listenURL, typically
+// DefaultRedisQURL.
+func NewRedisQ(listenURL string, client common.HttpClient, queueID string, opts ...RedisQOption) *RedisQ {
+	q := &RedisQ{
+		url:     listenURL,
+		queueID: queueID,
+		client:  client,
+		ttw:     10 * time.Second,
+		retry:   5 * time.Second,
+		pause:   common.NewPauseGate(),
+		maxBody: common.DefaultMaxResponseSize,
+	}
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
+}
+
+// redisQPackage is one kill of the feed.
+type redisQPackage struct {
+	KillID   int64             `json:"killID"`
+	Killmail model.EsiKillMail `json:"killmail"`
+	ZKB      model.ZKB         `json:"zkb"`
+}
+
+// Next waits for the next kill. It returns nil without error when none
+// arrived within the time to wait.
+func (q *RedisQ) Next(ctx context.Context) (*model.FlattenedKillMail, error) {
+	if err := q.pause.Wait(ctx); err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	params.Set("queueID", q.queueID)
+	params.Set("ttw", fmt.Sprint(int(q.ttw/time.Second)))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, q.url+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := q.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("redisq request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if delay, limited := common.RateLimitDelay(resp, common.DefaultRateLimitPause); limited {
+		q.pause.PauseUntil(time.Now().Add(delay))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &common.HTTPError{StatusCode: resp.StatusCode}
+	}
+
+	body, err := common.ReadLimited(resp.Body, q.maxBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read redisq response: %w", err)
+	}
+	var out struct {
+		Package *redisQPackage `json:"package"`
+	}
+	if err := q.decoder.Decode("redisq", body, &out); err != nil {
+		return nil, fmt.Errorf("failed to decode redisq JSON: %w", err)
+	}
+	if out.Package == nil {
+		return nil, nil
+	}
+	p := out.Package
+	km := model.ConvertToFlattened(p.Killmail, model.ZkillMail{KillMailID: p.KillID, ZKB: p.ZKB})
+	return &km, nil
+}
+
+// Stream reads the feed until ctx is done or the RedisQ is closed, sending
+// every kill to the returned channel, which is closed when reading stops.
+// Failed requests go to onError, if non-nil, and are retried after the retry
+// delay.
+func (q *RedisQ) Stream(ctx context.Context, onError func(error)) <-chan model.FlattenedKillMail {
+	out := make(chan model.FlattenedKillMail)
+	ctx, stop, done := q.life.Begin(ctx)
+	go func() {
+		defer done()
+		defer close(out)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			km, err := q.Next(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				if onError != nil {
+					onError(err)
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case <-stop:
+					return
+				case <-time.After(q.retry):
+				}
+				continue
+			}
+			if km == nil {
+				continue
+			}
+			// a kill read from the queue is gone from it, so it is delivered
+			// even while closing
+			select {
+			case out <- *km:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Close stops Stream once the request in flight has finished and its kill,
+// if any, has been received, and waits for the stream's channel to close.
+func (q *RedisQ) Close() error {
+	return q.life.Close()
+}
+
+// Shutdown is Close, except that the request in flight is cancelled when ctx
+// is done before the stream drains.
+func (q *RedisQ) Shutdown(ctx context.Context) error {
+	return q.life.Shutdown(ctx)
+}
@@ -0,0 +1,54 @@
+package zkill_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/modules/zkill"
+)
+
+const redisQPackage = `{"package": {"killID": 42, "killmail": {"killmail_id": 42, "killmail_time": "2024-10-01T12:00:00Z",
+	"solar_system_id": 30000142, "victim": {"character_id": 1, "ship_type_id": 587}, "attackers": []},
+	"zkb": {"hash": "abc", "totalValue": 1000000}}}`
+
+func TestRedisQ(t *testing.T) {
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("queueID") != "my-app" || r.URL.Query().Get("ttw") != "10" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+		if calls.Add(1)%2 == 1 {
+			fmt.Fprint(w, redisQPackage)
+			return
+		}
+		fmt.Fprint(w, `{"package": null}`)
+	}))
+	defer ts.Close()
+
+	q := zkill.NewRedisQ(ts.URL, common.NewEveHttpClient("UA", &http.Client{}), "my-app")
+	ctx := context.Background()
+	km, err := q.Next(ctx)
+	if err != nil || km == nil {
+		t.Fatalf("expected a kill, got %+v, %v", km, err)
+	}
+	if km.KillMailID != 42 || km.Hash != "abc" || km.TotalValue != 1e6 || km.Victim.ShipTypeID != 587 {
+		t.Errorf("unexpected kill: %+v", km)
+	}
+	if km, err := q.Next(ctx); err != nil || km != nil {
+		t.Errorf("expected an empty poll, got %+v, %v", km, err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	feed := q.Stream(ctx, nil)
+	if got := <-feed; got.KillMailID != 42 {
+		t.Errorf("unexpected streamed kill: %+v", got)
+	}
+	cancel()
+	for range feed {
+	}
+}
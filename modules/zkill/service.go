@@ -2,6 +2,7 @@ package zkill
 
 import (
 	"context"
+
 	"github.com/guarzo/eveapi/common/model"
 )
 
@@ -11,7 +12,7 @@ type ZKillService interface {
 	GetKillMailDataForMonth(ctx context.Context, params *model.Params, year, month int) ([]model.FlattenedKillMail, error)
 	AggregateKillMailDumps(base, addition []model.FlattenedKillMail) []model.FlattenedKillMail
 	AddEsiKillMail(ctx context.Context, mail model.ZkillMail, aggregated []model.FlattenedKillMail) ([]model.FlattenedKillMail, error)
-	GetSingleKillmail(ctx context.Context, killID int) (model.ZkillMailFeedResponse, error)
+	GetSingleKillmail(ctx context.Context, killID int64) (model.ZkillMailFeedResponse, error)
 }
 
 // zKillService is the concrete struct implementing ZKillService.
@@ -19,11 +20,18 @@ type zKillService struct {
 	ZKillClient
 }
 
+// ServiceOption customizes a zKillService at construction time.
+type ServiceOption func(*zKillService)
+
 // NewZKillService constructs a zKillService using the given client.
-func NewZKillService(client ZKillClient) ZKillService {
-	return &zKillService{
+func NewZKillService(client ZKillClient, opts ...ServiceOption) ZKillService {
+	s := &zKillService{
 		ZKillClient: client,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // GetKillMailDataForMonth is an example method: fetch kills/losses for a given month.
@@ -36,7 +44,7 @@ func (svc *zKillService) GetKillMailDataForMonth(
 	var aggregated []model.FlattenedKillMail
 	killMailIDs := make(map[int64]bool)
 
-	entityGroups := map[string][]int{
+	entityGroups := map[string][]int64{
 		"corporation": params.Corporations,
 		"alliance":    params.Alliances,
 		"character":   params.Characters,
@@ -54,7 +62,7 @@ func (svc *zKillService) GetKillMailDataForMonth(
 				if len(kills) == 0 {
 					break
 				}
-				updated, err := svc.processKillMails(ctx, kills, killMailIDs, aggregated)
+				updated, err := svc.processKillMails(ctx, params, kills, killMailIDs, aggregated)
 				if err != nil {
 					break
 				}
@@ -70,7 +78,7 @@ func (svc *zKillService) GetKillMailDataForMonth(
 				if len(losses) == 0 {
 					break
 				}
-				updated, err := svc.processKillMails(ctx, losses, killMailIDs, aggregated)
+				updated, err := svc.processKillMails(ctx, params, losses, killMailIDs, aggregated)
 				if err != nil {
 					break
 				}
@@ -85,6 +93,7 @@ func (svc *zKillService) GetKillMailDataForMonth(
 // processKillMails is an internal helper to flatten & deduplicate killmails.
 func (svc *zKillService) processKillMails(
 	ctx context.Context,
+	params *model.Params,
 	mails []model.ZkillMail,
 	killMailIDs map[int64]bool,
 	aggregated []model.FlattenedKillMail,
@@ -94,6 +103,9 @@ func (svc *zKillService) processKillMails(
 		if _, exists := killMailIDs[m.KillMailID]; exists {
 			continue // skip duplicates
 		}
+		if !keepZKB(params, m.ZKB) {
+			continue
+		}
 		updated, err := svc.AddEsiKillMail(ctx, m, aggregated)
 		if err != nil {
 			continue
@@ -104,6 +116,20 @@ func (svc *zKillService) processKillMails(
 	return aggregated, nil
 }
 
+// keepZKB applies the Params flag filters to a kill's zkb block.
+func keepZKB(params *model.Params, zkb model.ZKB) bool {
+	if params.ExcludeNPC && zkb.NPC {
+		return false
+	}
+	if params.SoloOnly && !zkb.Solo {
+		return false
+	}
+	if params.ExcludeAwox && zkb.Awox {
+		return false
+	}
+	return true
+}
+
 // AggregateKillMailDumps merges two slices of FlattenedKillMail
 func (svc *zKillService) AggregateKillMailDumps(base, addition []model.FlattenedKillMail) []model.FlattenedKillMail {
 	if base == nil {
@@ -115,24 +141,16 @@ func (svc *zKillService) AggregateKillMailDumps(base, addition []model.Flattened
 	return append(base, addition...)
 }
 
-// AddEsiKillMail is a stub showing how you might fetch from ESI, then flatten it.
+// AddEsiKillMail flattens the zKill data of mail and appends it to aggregated.
+// It does not fetch the ESI details (KillMailTime, Victim, Attackers), so it
+// cannot price kills either; wrap the service with NewKillmailService and
+// WithHydratedPriceBackfill for that.
 func (svc *zKillService) AddEsiKillMail(
 	ctx context.Context,
 	mail model.ZkillMail,
 	aggregated []model.FlattenedKillMail,
 ) ([]model.FlattenedKillMail, error) {
-	// Example: If you have an EsiService, you'd do:
-	//   fullKill, err := svc.esiService.GetEsiKillMail(ctx, mail.KillMailID, mail.ZKB.Hash)
-	//   if err != nil { return aggregated, err }
-	//   flatten it -> FlattenedKillMail
-
-	flattened := model.FlattenedKillMail{
-		KillMailID:   mail.KillMailID,
-		Hash:         mail.ZKB.Hash,
-		TotalValue:   mail.ZKB.TotalValue,
-		DroppedValue: mail.ZKB.DroppedValue,
-		// etc.
-	}
+	flattened := model.ConvertToFlattened(model.EsiKillMail{KillMailID: mail.KillMailID}, mail)
 	aggregated = append(aggregated, flattened)
 	return aggregated, nil
 }
@@ -4,28 +4,34 @@ import (
 	"context"
 	"testing"
 
+	"github.com/guarzo/eveapi/common"
 	"github.com/guarzo/eveapi/common/model"
 	"github.com/guarzo/eveapi/modules/zkill"
 )
 
 type mockZKillClient struct {
-	killsFunc func(ctx context.Context, entityType string, entityID, page, year, month int) ([]model.ZkillMail, error)
-	lossFunc  func(ctx context.Context, entityType string, entityID, page, year, month int) ([]model.ZkillMail, error)
+	killsFunc func(ctx context.Context, entityType string, entityID int64, page, year, month int) ([]model.ZkillMail, error)
+	lossFunc  func(ctx context.Context, entityType string, entityID int64, page, year, month int) ([]model.ZkillMail, error)
 }
 
-func (m *mockZKillClient) GetKillsPageData(ctx context.Context, eType string, eID, page, year, month int) ([]model.ZkillMail, error) {
+func (m *mockZKillClient) GetKillsPageData(ctx context.Context, eType string, eID int64, page, year, month int) ([]model.ZkillMail, error) {
 	return m.killsFunc(ctx, eType, eID, page, year, month)
 }
-func (m *mockZKillClient) GetLossPageData(ctx context.Context, eType string, eID, page, year, month int) ([]model.ZkillMail, error) {
+func (m *mockZKillClient) GetLossPageData(ctx context.Context, eType string, eID int64, page, year, month int) ([]model.ZkillMail, error) {
 	return m.lossFunc(ctx, eType, eID, page, year, month)
 }
-func (m *mockZKillClient) RemoveCacheEntry(k string)                        {}
-func (m *mockZKillClient) BuildCacheKey(a, b string, c, d, e, f int) string { return "dummyKey" }
+func (m *mockZKillClient) RemoveCacheEntry(k string)                              {}
+func (m *mockZKillClient) BuildCacheKey(a, b string, c int64, d, e, f int) string { return "dummyKey" }
+func (m *mockZKillClient) Stats() common.ClientStats                              { return common.ClientStats{} }
+func (m *mockZKillClient) GetSingleKillmail(ctx context.Context, killID int64) (model.ZkillMailFeedResponse, error) {
+	return model.ZkillMailFeedResponse{}, nil
+}
+
 func TestZKillService_GetKillMailDataForMonth(t *testing.T) {
 	calls := 0
 
 	mockClient := &mockZKillClient{
-		killsFunc: func(ctx context.Context, etype string, eID, page, year, month int) ([]model.ZkillMail, error) {
+		killsFunc: func(ctx context.Context, etype string, eID int64, page, year, month int) ([]model.ZkillMail, error) {
 			calls++
 			// Return 1 killmail on page=1, then empty on page>1 (forces 1-page usage)
 			if page > 1 {
@@ -33,7 +39,7 @@ func TestZKillService_GetKillMailDataForMonth(t *testing.T) {
 			}
 			return []model.ZkillMail{{KillMailID: 111}}, nil
 		},
-		lossFunc: func(ctx context.Context, etype string, eID, page, year, month int) ([]model.ZkillMail, error) {
+		lossFunc: func(ctx context.Context, etype string, eID int64, page, year, month int) ([]model.ZkillMail, error) {
 			calls++
 			if page > 1 {
 				return nil, nil
@@ -46,9 +52,9 @@ func TestZKillService_GetKillMailDataForMonth(t *testing.T) {
 
 	// Suppose each "group" has 1 ID => total 3 IDs across character/corp/alliance
 	params := &model.Params{
-		Corporations: []int{111},
-		Alliances:    []int{222},
-		Characters:   []int{333},
+		Corporations: []model.CorporationID{111},
+		Alliances:    []model.AllianceID{222},
+		Characters:   []model.CharacterID{333},
 		Year:         2023,
 	}
 
@@ -93,3 +99,33 @@ func TestZKillService_AggregateKillMailDumps(t *testing.T) {
 		t.Errorf("expected 2, got %d", len(combined))
 	}
 }
+
+func TestZKillService_GetKillMailDataForMonth_FlagFilters(t *testing.T) {
+	page := []model.ZkillMail{
+		{KillMailID: 1, ZKB: model.ZKB{Solo: true}},
+		{KillMailID: 2, ZKB: model.ZKB{NPC: true}},
+		{KillMailID: 3, ZKB: model.ZKB{Solo: true, Awox: true}},
+		{KillMailID: 4},
+	}
+	mockClient := &mockZKillClient{
+		killsFunc: func(ctx context.Context, etype string, eID int64, p, year, month int) ([]model.ZkillMail, error) {
+			if p > 1 {
+				return nil, nil
+			}
+			return page, nil
+		},
+		lossFunc: func(ctx context.Context, etype string, eID int64, p, year, month int) ([]model.ZkillMail, error) {
+			return nil, nil
+		},
+	}
+
+	svc := zkill.NewZKillService(mockClient)
+	params := &model.Params{Characters: []model.CharacterID{1}, ExcludeNPC: true, SoloOnly: true, ExcludeAwox: true}
+	out, err := svc.GetKillMailDataForMonth(context.Background(), params, 2023, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out) != 1 || out[0].KillMailID != 1 {
+		t.Errorf("expected only kill 1 to survive the filters, got %#v", out)
+	}
+}
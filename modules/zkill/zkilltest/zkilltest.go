@@ -0,0 +1,189 @@
+// Package zkilltest provides a fake zKillboard server for integration-testing
+// code built on the zkill client, including its retry, rate-limit and caching
+// paths.
+//
+//	srv := zkilltest.NewServer()
+//	defer srv.Close()
+//	srv.SetKillsPage("corporation", 98000001, 2024, 5, 1, kills)
+//	client := zkill.NewZkillClient(srv.URL, common.NewEveHttpClient("test", &http.Client{}), cache)
+package zkilltest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+)
+
+// MalformedBody is the payload served by MalformNext.
+const MalformedBody = `[{"killmail_id": "not-a-number",`
+
+// fault is a canned response served instead of the configured data.
+type fault struct {
+	status     int
+	retryAfter string
+	body       string
+}
+
+// Server is a fake zKillboard serving /api/{kills,losses}/.../page/N/ and
+// /api/killID/N/. Pages and kills that were not configured are served as an
+// empty array, as zKillboard does.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	pages    map[string][]model.ZkillMail
+	kills    map[int64]model.ZkillMailFeedResponse
+	faults   []fault
+	requests []string
+}
+
+// NewServer starts a Server. Callers must Close it.
+func NewServer() *Server {
+	s := &Server{
+		pages: make(map[string][]model.ZkillMail),
+		kills: make(map[int64]model.ZkillMailFeedResponse),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// PagePath returns the path the zkill client requests for one page of kills
+// ("kills") or losses ("losses") of an entity.
+func PagePath(apiType, entityType string, entityID int64, year, month, page int) string {
+	return fmt.Sprintf("/api/%s/%sID/%d/year/%d/month/%d/page/%d/", apiType, entityType, entityID, year, month, page)
+}
+
+// KillPath returns the path the zkill client requests for a single kill.
+func KillPath(killID int64) string {
+	return fmt.Sprintf("/api/killID/%d/", killID)
+}
+
+// SetKillsPage serves kills for one page of an entity's kills.
+func (s *Server) SetKillsPage(entityType string, entityID int64, year, month, page int, kills []model.ZkillMail) {
+	s.setPage(PagePath("kills", entityType, entityID, year, month, page), kills)
+}
+
+// SetLossesPage serves kills for one page of an entity's losses.
+func (s *Server) SetLossesPage(entityType string, entityID int64, year, month, page int, kills []model.ZkillMail) {
+	s.setPage(PagePath("losses", entityType, entityID, year, month, page), kills)
+}
+
+func (s *Server) setPage(path string, kills []model.ZkillMail) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pages[path] = kills
+}
+
+// SetKill serves kill from the single-kill endpoint.
+func (s *Server) SetKill(kill model.ZkillMailFeedResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.kills[kill.KillmailID] = kill
+}
+
+// RateLimitNext answers the next n requests with 429 and a Retry-After of
+// retryAfter, rounded down to whole seconds.
+func (s *Server) RateLimitNext(n int, retryAfter time.Duration) {
+	s.queue(n, fault{
+		status:     http.StatusTooManyRequests,
+		retryAfter: strconv.Itoa(int(retryAfter / time.Second)),
+		body:       `{"error":"rate limited"}`,
+	})
+}
+
+// MalformNext answers the next n requests with 200 and MalformedBody.
+func (s *Server) MalformNext(n int) {
+	s.queue(n, fault{status: http.StatusOK, body: MalformedBody})
+}
+
+// FailNext answers the next n requests with status and an empty body.
+func (s *Server) FailNext(n int, status int) {
+	s.queue(n, fault{status: status})
+}
+
+func (s *Server) queue(n int, f fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i < n; i++ {
+		s.faults = append(s.faults, f)
+	}
+}
+
+// Requests returns the paths requested so far, in order.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// Hits returns how many times path was requested.
+func (s *Server) Hits(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, r := range s.requests {
+		if r == path {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, r.URL.Path)
+	var f *fault
+	if len(s.faults) > 0 {
+		f = &s.faults[0]
+		s.faults = s.faults[1:]
+	}
+	var payload interface{}
+	if f == nil {
+		payload = s.lookup(r.URL.Path)
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if f != nil {
+		if f.retryAfter != "" {
+			w.Header().Set("Retry-After", f.retryAfter)
+		}
+		w.WriteHeader(f.status)
+		_, _ = w.Write([]byte(f.body))
+		return
+	}
+	if payload == nil {
+		http.NotFound(w, r)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+// lookup returns the payload for path, or nil if it is not a zKillboard API
+// path. Callers hold s.mu.
+func (s *Server) lookup(path string) interface{} {
+	if rest, ok := strings.CutPrefix(path, "/api/killID/"); ok {
+		id, err := strconv.ParseInt(strings.TrimSuffix(rest, "/"), 10, 64)
+		if err != nil {
+			return nil
+		}
+		if kill, found := s.kills[id]; found {
+			return []model.ZkillMailFeedResponse{kill}
+		}
+		return []model.ZkillMailFeedResponse{}
+	}
+	if !strings.HasPrefix(path, "/api/kills/") && !strings.HasPrefix(path, "/api/losses/") {
+		return nil
+	}
+	if kills, found := s.pages[path]; found {
+		return kills
+	}
+	return []model.ZkillMail{}
+}
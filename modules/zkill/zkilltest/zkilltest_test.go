@@ -0,0 +1,103 @@
+package zkilltest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/zkill"
+	"github.com/guarzo/eveapi/modules/zkill/zkilltest"
+	"github.com/guarzo/eveapi/testsupport"
+)
+
+func newClient(srv *zkilltest.Server, opts ...zkill.ClientOption) zkill.ZKillClient {
+	return zkill.NewZkillClient(srv.URL, common.NewEveHttpClient("zkilltest", &http.Client{}), testsupport.NewMemoryCache(), opts...)
+}
+
+func TestServer_PagesAreCached(t *testing.T) {
+	srv := zkilltest.NewServer()
+	defer srv.Close()
+	srv.SetKillsPage("corporation", 98000001, 2024, 5, 1, []model.ZkillMail{{KillMailID: 1}, {KillMailID: 2}})
+	client := newClient(srv)
+
+	for i := 0; i < 2; i++ {
+		kills, err := client.GetKillsPageData(context.Background(), "corporation", 98000001, 1, 2024, 5)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(kills) != 2 {
+			t.Fatalf("expected 2 kills, got %d", len(kills))
+		}
+	}
+	if hits := srv.Hits(zkilltest.PagePath("kills", "corporation", 98000001, 2024, 5, 1)); hits != 1 {
+		t.Errorf("expected the second fetch to be served from cache, got %d requests", hits)
+	}
+
+	losses, err := client.GetLossPageData(context.Background(), "corporation", 98000001, 1, 2024, 5)
+	if err != nil || len(losses) != 0 {
+		t.Errorf("expected an empty unconfigured page, got %v, %v", losses, err)
+	}
+}
+
+func TestServer_MalformedPage(t *testing.T) {
+	srv := zkilltest.NewServer()
+	defer srv.Close()
+	srv.MalformNext(1)
+
+	if _, err := newClient(srv).GetKillsPageData(context.Background(), "character", 1, 1, 2024, 5); err == nil {
+		t.Fatal("expected a decode error")
+	}
+}
+
+func TestServer_RateLimitPausesClient(t *testing.T) {
+	srv := zkilltest.NewServer()
+	defer srv.Close()
+	srv.SetKill(model.ZkillMailFeedResponse{KillmailID: 42, SolarSystemID: 30000142})
+	srv.RateLimitNext(1, time.Second)
+	gate := common.NewPauseGate()
+	client := newClient(srv, zkill.WithPauseGate(gate))
+
+	start := time.Now()
+	kill, err := client.GetSingleKillmail(context.Background(), 42)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if kill.KillmailID != 42 || kill.SolarSystemID != 30000142 {
+		t.Errorf("unexpected kill %+v", kill)
+	}
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Errorf("expected the retry to honor Retry-After, took %v", elapsed)
+	}
+	if hits := srv.Hits(zkilltest.KillPath(42)); hits != 2 {
+		t.Errorf("expected 2 requests, got %d", hits)
+	}
+}
+
+func TestServer_FailNext(t *testing.T) {
+	srv := zkilltest.NewServer()
+	defer srv.Close()
+	srv.FailNext(1, http.StatusBadGateway)
+
+	if _, err := newClient(srv).GetKillsPageData(context.Background(), "character", 1, 1, 2024, 5); err == nil {
+		t.Fatal("expected an error for a 502")
+	}
+	if got := len(srv.Requests()); got != 1 {
+		t.Errorf("expected 1 request, got %d", got)
+	}
+}
+
+func TestServer_OversizedPage(t *testing.T) {
+	srv := zkilltest.NewServer()
+	defer srv.Close()
+	kills := make([]model.ZkillMail, 100)
+	srv.SetKillsPage("character", 1, 2024, 5, 1, kills)
+
+	_, err := newClient(srv, zkill.WithMaxResponseSize(512)).GetKillsPageData(context.Background(), "character", 1, 1, 2024, 5)
+	if !errors.Is(err, common.ErrResponseTooLarge) {
+		t.Errorf("expected ErrResponseTooLarge, got %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package testsupport
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/modules/esi"
+)
+
+var (
+	_ common.CacheRepository        = (*MemoryCache)(nil)
+	_ common.ContextCacheRepository = (*MemoryCache)(nil)
+	_ common.AuthClient             = (*AuthClient)(nil)
+	_ esi.AuthClient                = (*AuthClient)(nil)
+	_ common.HttpClient             = (*HttpClient)(nil)
+)
+
+// MemoryCache is common.MemoryCache, kept here so tests need one import.
+type MemoryCache = common.MemoryCache
+
+// NewMemoryCache returns an empty MemoryCache.
+func NewMemoryCache() *MemoryCache {
+	return common.NewMemoryCache()
+}
+
+// AuthClient is a common.AuthClient and esi.AuthClient calling
+// RefreshTokenFunc. With no func set, it returns a token whose access token
+// is "refreshed-" plus the refresh token.
+type AuthClient struct {
+	RefreshTokenFunc func(refreshToken string) (*oauth2.Token, error)
+}
+
+// RefreshToken calls RefreshTokenFunc.
+func (a *AuthClient) RefreshToken(refreshToken string) (*oauth2.Token, error) {
+	if a.RefreshTokenFunc == nil {
+		return &oauth2.Token{AccessToken: "refreshed-" + refreshToken, RefreshToken: refreshToken}, nil
+	}
+	return a.RefreshTokenFunc(refreshToken)
+}
+
+// HttpClient is a common.HttpClient whose requests all go through DoFunc.
+// RetryWithExponentialBackoff runs the operation once unless RetryFunc is set.
+type HttpClient struct {
+	DoFunc    func(req *http.Request) (*http.Response, error)
+	RetryFunc func(operation func() (interface{}, error)) (interface{}, error)
+}
+
+// Do calls DoFunc, or answers 200 with an empty JSON object if it is nil.
+func (h *HttpClient) Do(req *http.Request) (*http.Response, error) {
+	if h.DoFunc == nil {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader("{}")),
+			Request:    req,
+		}, nil
+	}
+	return h.DoFunc(req)
+}
+
+// Get sends a GET through Do.
+func (h *HttpClient) Get(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return h.Do(req)
+}
+
+// Post sends a POST through Do.
+func (h *HttpClient) Post(url, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	return h.Do(req)
+}
+
+// PostForm sends a form POST through Do.
+func (h *HttpClient) PostForm(u string, data url.Values) (*http.Response, error) {
+	return h.Post(u, "application/x-www-form-urlencoded", strings.NewReader(data.Encode()))
+}
+
+// Head sends a HEAD through Do.
+func (h *HttpClient) Head(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodHead, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return h.Do(req)
+}
+
+// CloseIdleConnections does nothing.
+func (h *HttpClient) CloseIdleConnections() {}
+
+// RetryWithExponentialBackoff calls RetryFunc, or runs operation once.
+func (h *HttpClient) RetryWithExponentialBackoff(operation func() (interface{}, error)) (interface{}, error) {
+	if h.RetryFunc == nil {
+		return operation()
+	}
+	return h.RetryFunc(operation)
+}
+
+// SetRandAndSleepForTest does nothing.
+func (h *HttpClient) SetRandAndSleepForTest(sleep func(d time.Duration), seed int64) {}
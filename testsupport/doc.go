@@ -0,0 +1,13 @@
+// Package testsupport provides fakes of the library's interfaces (EsiService,
+// EsiClient, ZKillClient, ZKillService, CacheRepository, AuthClient and
+// HttpClient) for tests in this module and in downstream projects.
+//
+// Service and client fakes have one Func field per method; set only the ones
+// a test exercises, the rest return zero values:
+//
+//	svc := &testsupport.EsiService{
+//		GetMarketPricesFunc: func(ctx context.Context) ([]model.MarketPrice, error) {
+//			return []model.MarketPrice{{TypeID: 34, AveragePrice: 5}}, nil
+//		},
+//	}
+package testsupport
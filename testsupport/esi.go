@@ -0,0 +1,729 @@
+package testsupport
+
+import (
+	"context"
+	"io"
+
+	"golang.org/x/oauth2"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/esi"
+)
+
+var (
+	_ esi.EsiService = (*EsiService)(nil)
+	_ esi.EsiClient  = (*EsiClient)(nil)
+)
+
+// EsiService is an esi.EsiService whose methods call the matching Func
+// field. Methods whose field is nil return zero values.
+type EsiService struct {
+	GetUserInfoFunc                  func(ctx context.Context, token *oauth2.Token) (*model.User, error)
+	GetCharacterInfoFunc             func(ctx context.Context, characterID model.CharacterID) (*model.Character, error)
+	GetCharacterAssetsFunc           func(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.LocationInventory, error)
+	GetCorporationAssetsFunc         func(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]model.LocationInventory, error)
+	GetAllCharacterAssetsFunc        func(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.LocationAssets, error)
+	GetAllCorporationAssetsFunc      func(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]model.LocationAssets, error)
+	GetCharacterAssetsRawFunc        func(ctx context.Context, characterID model.CharacterID, token *oauth2.Token, opts esi.AssetOptions) ([]model.Asset, error)
+	GetCorporationAssetsRawFunc      func(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token, opts esi.AssetOptions) ([]model.Asset, error)
+	GetCharacterAssetLocationsFunc   func(ctx context.Context, characterID model.CharacterID, itemIDs []int64, token *oauth2.Token) ([]model.AssetLocation, error)
+	GetCorporationAssetLocationsFunc func(ctx context.Context, corporationID model.CorporationID, itemIDs []int64, token *oauth2.Token) ([]model.AssetLocation, error)
+	ValueAssetsFunc                  func(ctx context.Context, locations []model.LocationAssets) (*model.AssetValuation, error)
+	GetMarketPricesFunc              func(ctx context.Context) ([]model.MarketPrice, error)
+	GetRegionOrdersFunc              func(ctx context.Context, regionID int64, typeID model.TypeID, orderType string) ([]model.MarketOrder, error)
+	GetStructureOrdersFunc           func(ctx context.Context, structureID int64, token *oauth2.Token) ([]model.MarketOrder, error)
+	GetMarketHistoryFunc             func(ctx context.Context, regionID int64, typeID model.TypeID) ([]model.MarketHistoryDay, error)
+	GetCharacterOrdersFunc           func(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.OwnedMarketOrder, error)
+	GetCharacterOrderHistoryFunc     func(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.OwnedMarketOrder, error)
+	GetCorporationOrdersFunc         func(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]model.OwnedMarketOrder, error)
+	GetCharacterLocationFunc         func(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (model.SystemID, error)
+	GetCharacterShipFunc             func(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (*model.CharacterShip, error)
+	GetCloneLocationsFunc            func(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (int64, []int64, error)
+	GetCharacterOnlineFunc           func(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (*model.CharacterOnline, error)
+	GetJumpFatigueFunc               func(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (*model.JumpFatigue, error)
+	GetClonesFunc                    func(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (*model.CloneLocation, error)
+	GetImplantsFunc                  func(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.TypeID, error)
+	GetStructureFunc                 func(ctx context.Context, structureID int64, token *oauth2.Token) (*model.Structure, error)
+	GetStationFunc                   func(ctx context.Context, stationID int64) (*model.Station, error)
+	GetEsiKillMailFunc               func(ctx context.Context, killID int64, hash string) (*model.EsiKillMail, error)
+	CharacterIDSearchFunc            func(characterID model.CharacterID, name string, token *oauth2.Token) (model.CharacterID, error)
+	CorporationIDSearchFunc          func(characterID model.CharacterID, name string, token *oauth2.Token) (model.CorporationID, error)
+	AllianceIDSearchFunc             func(characterID model.CharacterID, name string, token *oauth2.Token) (model.AllianceID, error)
+	IDSearchFunc                     func(characterID model.CharacterID, name, category string, token *oauth2.Token) (int64, error)
+	GetPublicCharacterDataFunc       func(characterID model.CharacterID, token *oauth2.Token) (*model.CharacterResponse, error)
+	GetCharacterDataFunc             func(characterID model.CharacterID, token *oauth2.Token) (*model.CharacterResponse, error)
+	GetSystemNameFunc                func(systemID model.SystemID) string
+	GetSolarSystemFunc               func(ctx context.Context, systemID model.SystemID) (*model.SolarSystem, error)
+	GetConstellationFunc             func(ctx context.Context, constellationID int64) (*model.Constellation, error)
+	GetRouteFunc                     func(ctx context.Context, origin, destination int64, flag string) ([]int64, error)
+	GetTypeFunc                      func(ctx context.Context, typeID model.TypeID) (*model.ItemType, error)
+	GetFittingsFunc                  func(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.Fitting, error)
+	CreateFittingFunc                func(ctx context.Context, characterID model.CharacterID, fit model.Fitting, token *oauth2.Token) (int64, error)
+	ResolveNamesFunc                 func(ctx context.Context, ids []int64) ([]model.UniverseName, error)
+	ResolveIDsFunc                   func(ctx context.Context, names []string) (*model.UniverseIDs, error)
+	LoadESIDataFunc                  func(ctx context.Context, ids model.Ids) (*model.ESIData, error)
+	WarmCacheFunc                    func(ctx context.Context, ids model.Ids) error
+	GetCharacterCorporationFunc      func(characterID model.CharacterID, token *oauth2.Token) (model.CorporationID, error)
+	GetCharacterPortraitFunc         func(characterID model.CharacterID) (string, error)
+	GetCorporationInfoFunc           func(ctx context.Context, corporationID model.CorporationID) (*model.Corporation, error)
+	GetAllianceInfoFunc              func(ctx context.Context, allianceID model.AllianceID) (*model.Alliance, error)
+	GetCorporationMembersFunc        func(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]int64, error)
+	GetCorporationStructuresFunc     func(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]model.CorporationStructure, error)
+	GetMiningObserversFunc           func(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]model.MiningObserver, error)
+	GetMiningObserverLedgerFunc      func(ctx context.Context, corporationID model.CorporationID, observerID int64, token *oauth2.Token) ([]model.MiningLedgerEntry, error)
+	GetCharacterNotificationsFunc    func(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.Notification, error)
+	GetSovereigntyCampaignsFunc      func(ctx context.Context) ([]model.SovereigntyCampaign, error)
+	GetIncursionsFunc                func(ctx context.Context) ([]model.Incursion, error)
+	GetFWSystemsFunc                 func(ctx context.Context) ([]model.FWSystem, error)
+	GetWarsFunc                      func(ctx context.Context, maxWarID int64) ([]int64, error)
+	GetWarFunc                       func(ctx context.Context, warID int64) (*model.War, error)
+	GetPublicContractsFunc           func(ctx context.Context, regionID int64) ([]model.Contract, error)
+	GetPublicContractItemsFunc       func(ctx context.Context, contractID int64) ([]model.ContractItem, error)
+	GetCharacterContractsFunc        func(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.Contract, error)
+	GetCharacterContractItemsFunc    func(ctx context.Context, characterID model.CharacterID, contractID int64, token *oauth2.Token) ([]model.ContractItem, error)
+	GetCharacterWalletFunc           func(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (float64, error)
+	GetCharacterWalletJournalFunc    func(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.WalletJournalEntry, error)
+	GetCorporationWalletsFunc        func(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]model.CorporationWallet, error)
+	GetCorporationWalletJournalFunc  func(ctx context.Context, corporationID model.CorporationID, division int, token *oauth2.Token) ([]model.WalletJournalEntry, error)
+	GetCharacterSkillsFunc           func(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (*model.CharacterSkills, error)
+	GetCharacterSkillQueueFunc       func(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.SkillQueueEntry, error)
+	GetCharacterAttributesFunc       func(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (*model.CharacterAttributes, error)
+}
+
+// GetUserInfo calls GetUserInfoFunc.
+func (m *EsiService) GetUserInfo(ctx context.Context, token *oauth2.Token) (*model.User, error) {
+	if m.GetUserInfoFunc == nil {
+		return nil, nil
+	}
+	return m.GetUserInfoFunc(ctx, token)
+}
+
+// GetCharacterInfo calls GetCharacterInfoFunc.
+func (m *EsiService) GetCharacterInfo(ctx context.Context, characterID model.CharacterID) (*model.Character, error) {
+	if m.GetCharacterInfoFunc == nil {
+		return nil, nil
+	}
+	return m.GetCharacterInfoFunc(ctx, characterID)
+}
+
+// GetCharacterAssets calls GetCharacterAssetsFunc.
+func (m *EsiService) GetCharacterAssets(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.LocationInventory, error) {
+	if m.GetCharacterAssetsFunc == nil {
+		return nil, nil
+	}
+	return m.GetCharacterAssetsFunc(ctx, characterID, token)
+}
+
+// GetCorporationAssets calls GetCorporationAssetsFunc.
+func (m *EsiService) GetCorporationAssets(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]model.LocationInventory, error) {
+	if m.GetCorporationAssetsFunc == nil {
+		return nil, nil
+	}
+	return m.GetCorporationAssetsFunc(ctx, corporationID, token)
+}
+
+// GetAllCharacterAssets calls GetAllCharacterAssetsFunc.
+func (m *EsiService) GetAllCharacterAssets(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.LocationAssets, error) {
+	if m.GetAllCharacterAssetsFunc == nil {
+		return nil, nil
+	}
+	return m.GetAllCharacterAssetsFunc(ctx, characterID, token)
+}
+
+// GetAllCorporationAssets calls GetAllCorporationAssetsFunc.
+func (m *EsiService) GetAllCorporationAssets(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]model.LocationAssets, error) {
+	if m.GetAllCorporationAssetsFunc == nil {
+		return nil, nil
+	}
+	return m.GetAllCorporationAssetsFunc(ctx, corporationID, token)
+}
+
+// GetCharacterAssetsRaw calls GetCharacterAssetsRawFunc.
+func (m *EsiService) GetCharacterAssetsRaw(ctx context.Context, characterID model.CharacterID, token *oauth2.Token, opts esi.AssetOptions) ([]model.Asset, error) {
+	if m.GetCharacterAssetsRawFunc == nil {
+		return nil, nil
+	}
+	return m.GetCharacterAssetsRawFunc(ctx, characterID, token, opts)
+}
+
+// GetCorporationAssetsRaw calls GetCorporationAssetsRawFunc.
+func (m *EsiService) GetCorporationAssetsRaw(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token, opts esi.AssetOptions) ([]model.Asset, error) {
+	if m.GetCorporationAssetsRawFunc == nil {
+		return nil, nil
+	}
+	return m.GetCorporationAssetsRawFunc(ctx, corporationID, token, opts)
+}
+
+// GetCharacterAssetLocations calls GetCharacterAssetLocationsFunc.
+func (m *EsiService) GetCharacterAssetLocations(ctx context.Context, characterID model.CharacterID, itemIDs []int64, token *oauth2.Token) ([]model.AssetLocation, error) {
+	if m.GetCharacterAssetLocationsFunc == nil {
+		return nil, nil
+	}
+	return m.GetCharacterAssetLocationsFunc(ctx, characterID, itemIDs, token)
+}
+
+// GetCorporationAssetLocations calls GetCorporationAssetLocationsFunc.
+func (m *EsiService) GetCorporationAssetLocations(ctx context.Context, corporationID model.CorporationID, itemIDs []int64, token *oauth2.Token) ([]model.AssetLocation, error) {
+	if m.GetCorporationAssetLocationsFunc == nil {
+		return nil, nil
+	}
+	return m.GetCorporationAssetLocationsFunc(ctx, corporationID, itemIDs, token)
+}
+
+// ValueAssets calls ValueAssetsFunc.
+func (m *EsiService) ValueAssets(ctx context.Context, locations []model.LocationAssets) (*model.AssetValuation, error) {
+	if m.ValueAssetsFunc == nil {
+		return nil, nil
+	}
+	return m.ValueAssetsFunc(ctx, locations)
+}
+
+// GetMarketPrices calls GetMarketPricesFunc.
+func (m *EsiService) GetMarketPrices(ctx context.Context) ([]model.MarketPrice, error) {
+	if m.GetMarketPricesFunc == nil {
+		return nil, nil
+	}
+	return m.GetMarketPricesFunc(ctx)
+}
+
+// GetRegionOrders calls GetRegionOrdersFunc.
+func (m *EsiService) GetRegionOrders(ctx context.Context, regionID int64, typeID model.TypeID, orderType string) ([]model.MarketOrder, error) {
+	if m.GetRegionOrdersFunc == nil {
+		return nil, nil
+	}
+	return m.GetRegionOrdersFunc(ctx, regionID, typeID, orderType)
+}
+
+// GetStructureOrders calls GetStructureOrdersFunc.
+func (m *EsiService) GetStructureOrders(ctx context.Context, structureID int64, token *oauth2.Token) ([]model.MarketOrder, error) {
+	if m.GetStructureOrdersFunc == nil {
+		return nil, nil
+	}
+	return m.GetStructureOrdersFunc(ctx, structureID, token)
+}
+
+// GetMarketHistory calls GetMarketHistoryFunc.
+func (m *EsiService) GetMarketHistory(ctx context.Context, regionID int64, typeID model.TypeID) ([]model.MarketHistoryDay, error) {
+	if m.GetMarketHistoryFunc == nil {
+		return nil, nil
+	}
+	return m.GetMarketHistoryFunc(ctx, regionID, typeID)
+}
+
+// GetCharacterOrders calls GetCharacterOrdersFunc.
+func (m *EsiService) GetCharacterOrders(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.OwnedMarketOrder, error) {
+	if m.GetCharacterOrdersFunc == nil {
+		return nil, nil
+	}
+	return m.GetCharacterOrdersFunc(ctx, characterID, token)
+}
+
+// GetCharacterOrderHistory calls GetCharacterOrderHistoryFunc.
+func (m *EsiService) GetCharacterOrderHistory(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.OwnedMarketOrder, error) {
+	if m.GetCharacterOrderHistoryFunc == nil {
+		return nil, nil
+	}
+	return m.GetCharacterOrderHistoryFunc(ctx, characterID, token)
+}
+
+// GetCorporationOrders calls GetCorporationOrdersFunc.
+func (m *EsiService) GetCorporationOrders(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]model.OwnedMarketOrder, error) {
+	if m.GetCorporationOrdersFunc == nil {
+		return nil, nil
+	}
+	return m.GetCorporationOrdersFunc(ctx, corporationID, token)
+}
+
+// GetCharacterLocation calls GetCharacterLocationFunc.
+func (m *EsiService) GetCharacterLocation(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (model.SystemID, error) {
+	if m.GetCharacterLocationFunc == nil {
+		return 0, nil
+	}
+	return m.GetCharacterLocationFunc(ctx, characterID, token)
+}
+
+// GetCharacterShip calls GetCharacterShipFunc.
+func (m *EsiService) GetCharacterShip(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (*model.CharacterShip, error) {
+	if m.GetCharacterShipFunc == nil {
+		return nil, nil
+	}
+	return m.GetCharacterShipFunc(ctx, characterID, token)
+}
+
+// GetCloneLocations calls GetCloneLocationsFunc.
+func (m *EsiService) GetCloneLocations(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (int64, []int64, error) {
+	if m.GetCloneLocationsFunc == nil {
+		return 0, nil, nil
+	}
+	return m.GetCloneLocationsFunc(ctx, characterID, token)
+}
+
+// GetCharacterOnline calls GetCharacterOnlineFunc.
+func (m *EsiService) GetCharacterOnline(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (*model.CharacterOnline, error) {
+	if m.GetCharacterOnlineFunc == nil {
+		return nil, nil
+	}
+	return m.GetCharacterOnlineFunc(ctx, characterID, token)
+}
+
+// GetJumpFatigue calls GetJumpFatigueFunc.
+func (m *EsiService) GetJumpFatigue(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (*model.JumpFatigue, error) {
+	if m.GetJumpFatigueFunc == nil {
+		return nil, nil
+	}
+	return m.GetJumpFatigueFunc(ctx, characterID, token)
+}
+
+// GetClones calls GetClonesFunc.
+func (m *EsiService) GetClones(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (*model.CloneLocation, error) {
+	if m.GetClonesFunc == nil {
+		return nil, nil
+	}
+	return m.GetClonesFunc(ctx, characterID, token)
+}
+
+// GetImplants calls GetImplantsFunc.
+func (m *EsiService) GetImplants(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.TypeID, error) {
+	if m.GetImplantsFunc == nil {
+		return nil, nil
+	}
+	return m.GetImplantsFunc(ctx, characterID, token)
+}
+
+// GetStructure calls GetStructureFunc.
+func (m *EsiService) GetStructure(ctx context.Context, structureID int64, token *oauth2.Token) (*model.Structure, error) {
+	if m.GetStructureFunc == nil {
+		return nil, nil
+	}
+	return m.GetStructureFunc(ctx, structureID, token)
+}
+
+// GetStation calls GetStationFunc.
+func (m *EsiService) GetStation(ctx context.Context, stationID int64) (*model.Station, error) {
+	if m.GetStationFunc == nil {
+		return nil, nil
+	}
+	return m.GetStationFunc(ctx, stationID)
+}
+
+// GetEsiKillMail calls GetEsiKillMailFunc.
+func (m *EsiService) GetEsiKillMail(ctx context.Context, killID int64, hash string) (*model.EsiKillMail, error) {
+	if m.GetEsiKillMailFunc == nil {
+		return nil, nil
+	}
+	return m.GetEsiKillMailFunc(ctx, killID, hash)
+}
+
+// CharacterIDSearch calls CharacterIDSearchFunc.
+func (m *EsiService) CharacterIDSearch(characterID model.CharacterID, name string, token *oauth2.Token) (model.CharacterID, error) {
+	if m.CharacterIDSearchFunc == nil {
+		return 0, nil
+	}
+	return m.CharacterIDSearchFunc(characterID, name, token)
+}
+
+// CorporationIDSearch calls CorporationIDSearchFunc.
+func (m *EsiService) CorporationIDSearch(characterID model.CharacterID, name string, token *oauth2.Token) (model.CorporationID, error) {
+	if m.CorporationIDSearchFunc == nil {
+		return 0, nil
+	}
+	return m.CorporationIDSearchFunc(characterID, name, token)
+}
+
+// AllianceIDSearch calls AllianceIDSearchFunc.
+func (m *EsiService) AllianceIDSearch(characterID model.CharacterID, name string, token *oauth2.Token) (model.AllianceID, error) {
+	if m.AllianceIDSearchFunc == nil {
+		return 0, nil
+	}
+	return m.AllianceIDSearchFunc(characterID, name, token)
+}
+
+// IDSearch calls IDSearchFunc.
+func (m *EsiService) IDSearch(characterID model.CharacterID, name, category string, token *oauth2.Token) (int64, error) {
+	if m.IDSearchFunc == nil {
+		return 0, nil
+	}
+	return m.IDSearchFunc(characterID, name, category, token)
+}
+
+// GetPublicCharacterData calls GetPublicCharacterDataFunc.
+func (m *EsiService) GetPublicCharacterData(characterID model.CharacterID, token *oauth2.Token) (*model.CharacterResponse, error) {
+	if m.GetPublicCharacterDataFunc == nil {
+		return nil, nil
+	}
+	return m.GetPublicCharacterDataFunc(characterID, token)
+}
+
+// GetCharacterData calls GetCharacterDataFunc.
+func (m *EsiService) GetCharacterData(characterID model.CharacterID, token *oauth2.Token) (*model.CharacterResponse, error) {
+	if m.GetCharacterDataFunc == nil {
+		return nil, nil
+	}
+	return m.GetCharacterDataFunc(characterID, token)
+}
+
+// GetSystemName calls GetSystemNameFunc.
+func (m *EsiService) GetSystemName(systemID model.SystemID) string {
+	if m.GetSystemNameFunc == nil {
+		return ""
+	}
+	return m.GetSystemNameFunc(systemID)
+}
+
+// GetSolarSystem calls GetSolarSystemFunc.
+func (m *EsiService) GetSolarSystem(ctx context.Context, systemID model.SystemID) (*model.SolarSystem, error) {
+	if m.GetSolarSystemFunc == nil {
+		return nil, nil
+	}
+	return m.GetSolarSystemFunc(ctx, systemID)
+}
+
+// GetConstellation calls GetConstellationFunc.
+func (m *EsiService) GetConstellation(ctx context.Context, constellationID int64) (*model.Constellation, error) {
+	if m.GetConstellationFunc == nil {
+		return nil, nil
+	}
+	return m.GetConstellationFunc(ctx, constellationID)
+}
+
+// GetRoute calls GetRouteFunc.
+func (m *EsiService) GetRoute(ctx context.Context, origin, destination int64, flag string) ([]int64, error) {
+	if m.GetRouteFunc == nil {
+		return nil, nil
+	}
+	return m.GetRouteFunc(ctx, origin, destination, flag)
+}
+
+// GetType calls GetTypeFunc.
+func (m *EsiService) GetType(ctx context.Context, typeID model.TypeID) (*model.ItemType, error) {
+	if m.GetTypeFunc == nil {
+		return nil, nil
+	}
+	return m.GetTypeFunc(ctx, typeID)
+}
+
+// GetFittings calls GetFittingsFunc.
+func (m *EsiService) GetFittings(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.Fitting, error) {
+	if m.GetFittingsFunc == nil {
+		return nil, nil
+	}
+	return m.GetFittingsFunc(ctx, characterID, token)
+}
+
+// CreateFitting calls CreateFittingFunc.
+func (m *EsiService) CreateFitting(ctx context.Context, characterID model.CharacterID, fit model.Fitting, token *oauth2.Token) (int64, error) {
+	if m.CreateFittingFunc == nil {
+		return 0, nil
+	}
+	return m.CreateFittingFunc(ctx, characterID, fit, token)
+}
+
+// ResolveNames calls ResolveNamesFunc.
+func (m *EsiService) ResolveNames(ctx context.Context, ids []int64) ([]model.UniverseName, error) {
+	if m.ResolveNamesFunc == nil {
+		return nil, nil
+	}
+	return m.ResolveNamesFunc(ctx, ids)
+}
+
+// ResolveIDs calls ResolveIDsFunc.
+func (m *EsiService) ResolveIDs(ctx context.Context, names []string) (*model.UniverseIDs, error) {
+	if m.ResolveIDsFunc == nil {
+		return nil, nil
+	}
+	return m.ResolveIDsFunc(ctx, names)
+}
+
+// LoadESIData calls LoadESIDataFunc.
+func (m *EsiService) LoadESIData(ctx context.Context, ids model.Ids) (*model.ESIData, error) {
+	if m.LoadESIDataFunc == nil {
+		return nil, nil
+	}
+	return m.LoadESIDataFunc(ctx, ids)
+}
+
+// WarmCache calls WarmCacheFunc.
+func (m *EsiService) WarmCache(ctx context.Context, ids model.Ids) error {
+	if m.WarmCacheFunc == nil {
+		return nil
+	}
+	return m.WarmCacheFunc(ctx, ids)
+}
+
+// GetCharacterCorporation calls GetCharacterCorporationFunc.
+func (m *EsiService) GetCharacterCorporation(characterID model.CharacterID, token *oauth2.Token) (model.CorporationID, error) {
+	if m.GetCharacterCorporationFunc == nil {
+		return 0, nil
+	}
+	return m.GetCharacterCorporationFunc(characterID, token)
+}
+
+// GetCharacterPortrait calls GetCharacterPortraitFunc.
+func (m *EsiService) GetCharacterPortrait(characterID model.CharacterID) (string, error) {
+	if m.GetCharacterPortraitFunc == nil {
+		return "", nil
+	}
+	return m.GetCharacterPortraitFunc(characterID)
+}
+
+// GetCorporationInfo calls GetCorporationInfoFunc.
+func (m *EsiService) GetCorporationInfo(ctx context.Context, corporationID model.CorporationID) (*model.Corporation, error) {
+	if m.GetCorporationInfoFunc == nil {
+		return nil, nil
+	}
+	return m.GetCorporationInfoFunc(ctx, corporationID)
+}
+
+// GetAllianceInfo calls GetAllianceInfoFunc.
+func (m *EsiService) GetAllianceInfo(ctx context.Context, allianceID model.AllianceID) (*model.Alliance, error) {
+	if m.GetAllianceInfoFunc == nil {
+		return nil, nil
+	}
+	return m.GetAllianceInfoFunc(ctx, allianceID)
+}
+
+// GetCorporationMembers calls GetCorporationMembersFunc.
+func (m *EsiService) GetCorporationMembers(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]int64, error) {
+	if m.GetCorporationMembersFunc == nil {
+		return nil, nil
+	}
+	return m.GetCorporationMembersFunc(ctx, corporationID, token)
+}
+
+// GetCorporationStructures calls GetCorporationStructuresFunc.
+func (m *EsiService) GetCorporationStructures(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]model.CorporationStructure, error) {
+	if m.GetCorporationStructuresFunc == nil {
+		return nil, nil
+	}
+	return m.GetCorporationStructuresFunc(ctx, corporationID, token)
+}
+
+// GetMiningObservers calls GetMiningObserversFunc.
+func (m *EsiService) GetMiningObservers(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]model.MiningObserver, error) {
+	if m.GetMiningObserversFunc == nil {
+		return nil, nil
+	}
+	return m.GetMiningObserversFunc(ctx, corporationID, token)
+}
+
+// GetMiningObserverLedger calls GetMiningObserverLedgerFunc.
+func (m *EsiService) GetMiningObserverLedger(ctx context.Context, corporationID model.CorporationID, observerID int64, token *oauth2.Token) ([]model.MiningLedgerEntry, error) {
+	if m.GetMiningObserverLedgerFunc == nil {
+		return nil, nil
+	}
+	return m.GetMiningObserverLedgerFunc(ctx, corporationID, observerID, token)
+}
+
+// GetCharacterNotifications calls GetCharacterNotificationsFunc.
+func (m *EsiService) GetCharacterNotifications(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.Notification, error) {
+	if m.GetCharacterNotificationsFunc == nil {
+		return nil, nil
+	}
+	return m.GetCharacterNotificationsFunc(ctx, characterID, token)
+}
+
+// GetSovereigntyCampaigns calls GetSovereigntyCampaignsFunc.
+func (m *EsiService) GetSovereigntyCampaigns(ctx context.Context) ([]model.SovereigntyCampaign, error) {
+	if m.GetSovereigntyCampaignsFunc == nil {
+		return nil, nil
+	}
+	return m.GetSovereigntyCampaignsFunc(ctx)
+}
+
+// GetIncursions calls GetIncursionsFunc.
+func (m *EsiService) GetIncursions(ctx context.Context) ([]model.Incursion, error) {
+	if m.GetIncursionsFunc == nil {
+		return nil, nil
+	}
+	return m.GetIncursionsFunc(ctx)
+}
+
+// GetFWSystems calls GetFWSystemsFunc.
+func (m *EsiService) GetFWSystems(ctx context.Context) ([]model.FWSystem, error) {
+	if m.GetFWSystemsFunc == nil {
+		return nil, nil
+	}
+	return m.GetFWSystemsFunc(ctx)
+}
+
+// GetWars calls GetWarsFunc.
+func (m *EsiService) GetWars(ctx context.Context, maxWarID int64) ([]int64, error) {
+	if m.GetWarsFunc == nil {
+		return nil, nil
+	}
+	return m.GetWarsFunc(ctx, maxWarID)
+}
+
+// GetWar calls GetWarFunc.
+func (m *EsiService) GetWar(ctx context.Context, warID int64) (*model.War, error) {
+	if m.GetWarFunc == nil {
+		return nil, nil
+	}
+	return m.GetWarFunc(ctx, warID)
+}
+
+// GetPublicContracts calls GetPublicContractsFunc.
+func (m *EsiService) GetPublicContracts(ctx context.Context, regionID int64) ([]model.Contract, error) {
+	if m.GetPublicContractsFunc == nil {
+		return nil, nil
+	}
+	return m.GetPublicContractsFunc(ctx, regionID)
+}
+
+// GetPublicContractItems calls GetPublicContractItemsFunc.
+func (m *EsiService) GetPublicContractItems(ctx context.Context, contractID int64) ([]model.ContractItem, error) {
+	if m.GetPublicContractItemsFunc == nil {
+		return nil, nil
+	}
+	return m.GetPublicContractItemsFunc(ctx, contractID)
+}
+
+// GetCharacterContracts calls GetCharacterContractsFunc.
+func (m *EsiService) GetCharacterContracts(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.Contract, error) {
+	if m.GetCharacterContractsFunc == nil {
+		return nil, nil
+	}
+	return m.GetCharacterContractsFunc(ctx, characterID, token)
+}
+
+// GetCharacterContractItems calls GetCharacterContractItemsFunc.
+func (m *EsiService) GetCharacterContractItems(ctx context.Context, characterID model.CharacterID, contractID int64, token *oauth2.Token) ([]model.ContractItem, error) {
+	if m.GetCharacterContractItemsFunc == nil {
+		return nil, nil
+	}
+	return m.GetCharacterContractItemsFunc(ctx, characterID, contractID, token)
+}
+
+// GetCharacterWallet calls GetCharacterWalletFunc.
+func (m *EsiService) GetCharacterWallet(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (float64, error) {
+	if m.GetCharacterWalletFunc == nil {
+		return 0, nil
+	}
+	return m.GetCharacterWalletFunc(ctx, characterID, token)
+}
+
+// GetCharacterWalletJournal calls GetCharacterWalletJournalFunc.
+func (m *EsiService) GetCharacterWalletJournal(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.WalletJournalEntry, error) {
+	if m.GetCharacterWalletJournalFunc == nil {
+		return nil, nil
+	}
+	return m.GetCharacterWalletJournalFunc(ctx, characterID, token)
+}
+
+// GetCorporationWallets calls GetCorporationWalletsFunc.
+func (m *EsiService) GetCorporationWallets(ctx context.Context, corporationID model.CorporationID, token *oauth2.Token) ([]model.CorporationWallet, error) {
+	if m.GetCorporationWalletsFunc == nil {
+		return nil, nil
+	}
+	return m.GetCorporationWalletsFunc(ctx, corporationID, token)
+}
+
+// GetCorporationWalletJournal calls GetCorporationWalletJournalFunc.
+func (m *EsiService) GetCorporationWalletJournal(ctx context.Context, corporationID model.CorporationID, division int, token *oauth2.Token) ([]model.WalletJournalEntry, error) {
+	if m.GetCorporationWalletJournalFunc == nil {
+		return nil, nil
+	}
+	return m.GetCorporationWalletJournalFunc(ctx, corporationID, division, token)
+}
+
+// GetCharacterSkills calls GetCharacterSkillsFunc.
+func (m *EsiService) GetCharacterSkills(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (*model.CharacterSkills, error) {
+	if m.GetCharacterSkillsFunc == nil {
+		return nil, nil
+	}
+	return m.GetCharacterSkillsFunc(ctx, characterID, token)
+}
+
+// GetCharacterSkillQueue calls GetCharacterSkillQueueFunc.
+func (m *EsiService) GetCharacterSkillQueue(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) ([]model.SkillQueueEntry, error) {
+	if m.GetCharacterSkillQueueFunc == nil {
+		return nil, nil
+	}
+	return m.GetCharacterSkillQueueFunc(ctx, characterID, token)
+}
+
+// GetCharacterAttributes calls GetCharacterAttributesFunc.
+func (m *EsiService) GetCharacterAttributes(ctx context.Context, characterID model.CharacterID, token *oauth2.Token) (*model.CharacterAttributes, error) {
+	if m.GetCharacterAttributesFunc == nil {
+		return nil, nil
+	}
+	return m.GetCharacterAttributesFunc(ctx, characterID, token)
+}
+
+// EsiClient is an esi.EsiClient whose methods call the matching Func field.
+// Methods whose field is nil return zero values.
+type EsiClient struct {
+	GetJSONFunc         func(ctx context.Context, endpoint string, entity interface{}, token *oauth2.Token, params map[string]string, opts ...esi.CallOption) error
+	GetBytesFunc        func(ctx context.Context, endpoint string, token *oauth2.Token, params map[string]string, opts ...esi.CallOption) ([]byte, error)
+	GetFreshJSONFunc    func(ctx context.Context, endpoint string, entity interface{}, token *oauth2.Token, params map[string]string, opts ...esi.CallOption) error
+	GetJSONAllPagesFunc func(ctx context.Context, endpoint string, entity interface{}, token *oauth2.Token, params map[string]string, opts ...esi.CallOption) error
+	PostJSONFunc        func(ctx context.Context, endpoint string, token *oauth2.Token, body io.Reader, expectedStatusCodes ...int) ([]byte, error)
+	DeleteJSONFunc      func(ctx context.Context, endpoint string, token *oauth2.Token, body io.Reader, expectedStatusCodes ...int) ([]byte, error)
+	DoRequestFunc       func(ctx context.Context, method, urlStr string, token *oauth2.Token, body io.Reader, expectedStatus ...int) ([]byte, error)
+	StatsFunc           func() common.ClientStats
+}
+
+// GetJSON calls GetJSONFunc.
+func (m *EsiClient) GetJSON(ctx context.Context, endpoint string, entity interface{}, token *oauth2.Token, params map[string]string, opts ...esi.CallOption) error {
+	if m.GetJSONFunc == nil {
+		return nil
+	}
+	return m.GetJSONFunc(ctx, endpoint, entity, token, params, opts...)
+}
+
+// GetBytes calls GetBytesFunc.
+func (m *EsiClient) GetBytes(ctx context.Context, endpoint string, token *oauth2.Token, params map[string]string, opts ...esi.CallOption) ([]byte, error) {
+	if m.GetBytesFunc == nil {
+		return nil, nil
+	}
+	return m.GetBytesFunc(ctx, endpoint, token, params, opts...)
+}
+
+// GetFreshJSON calls GetFreshJSONFunc.
+func (m *EsiClient) GetFreshJSON(ctx context.Context, endpoint string, entity interface{}, token *oauth2.Token, params map[string]string, opts ...esi.CallOption) error {
+	if m.GetFreshJSONFunc == nil {
+		return nil
+	}
+	return m.GetFreshJSONFunc(ctx, endpoint, entity, token, params, opts...)
+}
+
+// GetJSONAllPages calls GetJSONAllPagesFunc.
+func (m *EsiClient) GetJSONAllPages(ctx context.Context, endpoint string, entity interface{}, token *oauth2.Token, params map[string]string, opts ...esi.CallOption) error {
+	if m.GetJSONAllPagesFunc == nil {
+		return nil
+	}
+	return m.GetJSONAllPagesFunc(ctx, endpoint, entity, token, params, opts...)
+}
+
+// PostJSON calls PostJSONFunc.
+func (m *EsiClient) PostJSON(ctx context.Context, endpoint string, token *oauth2.Token, body io.Reader, expectedStatusCodes ...int) ([]byte, error) {
+	if m.PostJSONFunc == nil {
+		return nil, nil
+	}
+	return m.PostJSONFunc(ctx, endpoint, token, body, expectedStatusCodes...)
+}
+
+// DeleteJSON calls DeleteJSONFunc.
+func (m *EsiClient) DeleteJSON(ctx context.Context, endpoint string, token *oauth2.Token, body io.Reader, expectedStatusCodes ...int) ([]byte, error) {
+	if m.DeleteJSONFunc == nil {
+		return nil, nil
+	}
+	return m.DeleteJSONFunc(ctx, endpoint, token, body, expectedStatusCodes...)
+}
+
+// DoRequest calls DoRequestFunc.
+func (m *EsiClient) DoRequest(ctx context.Context, method, urlStr string, token *oauth2.Token, body io.Reader, expectedStatus ...int) ([]byte, error) {
+	if m.DoRequestFunc == nil {
+		return nil, nil
+	}
+	return m.DoRequestFunc(ctx, method, urlStr, token, body, expectedStatus...)
+}
+
+// Stats calls StatsFunc.
+func (m *EsiClient) Stats() common.ClientStats {
+	if m.StatsFunc == nil {
+		return common.ClientStats{}
+	}
+	return m.StatsFunc()
+}
@@ -0,0 +1,65 @@
+package testsupport_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/esi"
+	"github.com/guarzo/eveapi/testsupport"
+)
+
+func TestEsiClientOverFakes(t *testing.T) {
+	httpClient := &testsupport.HttpClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{},
+				Body:       io.NopCloser(strings.NewReader(`{"name":"Jita"}`)),
+			}, nil
+		},
+	}
+	cache := testsupport.NewMemoryCache()
+	client := esi.NewEsiClient("https://esi.evetech.net/latest/", httpClient, cache, &testsupport.AuthClient{})
+
+	var sys model.SolarSystem
+	if err := client.GetJSON(context.Background(), "universe/systems/30000142/", &sys, nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sys.Name != "Jita" || cache.Len() != 1 {
+		t.Errorf("expected a decoded, cached response, got %+v with %d cache entries", sys, cache.Len())
+	}
+}
+
+func TestEsiService_ZeroValues(t *testing.T) {
+	svc := &testsupport.EsiService{
+		GetMarketPricesFunc: func(ctx context.Context) ([]model.MarketPrice, error) {
+			return []model.MarketPrice{{TypeID: 34}}, nil
+		},
+	}
+	var _ esi.EsiService = svc
+	prices, err := svc.GetMarketPrices(context.Background())
+	if err != nil || len(prices) != 1 {
+		t.Errorf("expected the configured func to be called, got %v, %v", prices, err)
+	}
+	if s, err := svc.GetStation(context.Background(), 60003760); s != nil || err != nil {
+		t.Errorf("expected zero values from an unset func, got %v, %v", s, err)
+	}
+}
+
+func TestMemoryCache_Expiration(t *testing.T) {
+	c := testsupport.NewMemoryCache()
+	c.Set("a", []byte("1"), time.Millisecond)
+	c.Set("b", []byte("2"), 0)
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := c.Get("a"); ok {
+		t.Errorf("expected a to expire")
+	}
+	if v, ok := c.Get("b"); !ok || string(v) != "2" {
+		t.Errorf("expected b to persist, got %q, %v", v, ok)
+	}
+}
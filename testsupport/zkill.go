@@ -0,0 +1,114 @@
+package testsupport
+
+import (
+	"context"
+
+	"github.com/guarzo/eveapi/common"
+	"github.com/guarzo/eveapi/common/model"
+	"github.com/guarzo/eveapi/modules/zkill"
+)
+
+var (
+	_ zkill.ZKillClient  = (*ZKillClient)(nil)
+	_ zkill.ZKillService = (*ZKillService)(nil)
+)
+
+// ZKillClient is a zkill.ZKillClient whose methods call the matching Func
+// field. Methods whose field is nil return zero values.
+type ZKillClient struct {
+	GetKillsPageDataFunc  func(ctx context.Context, entityType string, entityID int64, page, year, month int) ([]model.ZkillMail, error)
+	GetLossPageDataFunc   func(ctx context.Context, entityType string, entityID int64, page, year, month int) ([]model.ZkillMail, error)
+	RemoveCacheEntryFunc  func(cacheKey string)
+	GetSingleKillmailFunc func(ctx context.Context, killID int64) (model.ZkillMailFeedResponse, error)
+	BuildCacheKeyFunc     func(apiType, entityType string, entityID int64, year, month, page int) string
+	StatsFunc             func() common.ClientStats
+}
+
+// GetKillsPageData calls GetKillsPageDataFunc.
+func (m *ZKillClient) GetKillsPageData(ctx context.Context, entityType string, entityID int64, page, year, month int) ([]model.ZkillMail, error) {
+	if m.GetKillsPageDataFunc == nil {
+		return nil, nil
+	}
+	return m.GetKillsPageDataFunc(ctx, entityType, entityID, page, year, month)
+}
+
+// GetLossPageData calls GetLossPageDataFunc.
+func (m *ZKillClient) GetLossPageData(ctx context.Context, entityType string, entityID int64, page, year, month int) ([]model.ZkillMail, error) {
+	if m.GetLossPageDataFunc == nil {
+		return nil, nil
+	}
+	return m.GetLossPageDataFunc(ctx, entityType, entityID, page, year, month)
+}
+
+// RemoveCacheEntry calls RemoveCacheEntryFunc.
+func (m *ZKillClient) RemoveCacheEntry(cacheKey string) {
+	if m.RemoveCacheEntryFunc == nil {
+		return
+	}
+	m.RemoveCacheEntryFunc(cacheKey)
+}
+
+// GetSingleKillmail calls GetSingleKillmailFunc.
+func (m *ZKillClient) GetSingleKillmail(ctx context.Context, killID int64) (model.ZkillMailFeedResponse, error) {
+	if m.GetSingleKillmailFunc == nil {
+		return model.ZkillMailFeedResponse{}, nil
+	}
+	return m.GetSingleKillmailFunc(ctx, killID)
+}
+
+// BuildCacheKey calls BuildCacheKeyFunc.
+func (m *ZKillClient) BuildCacheKey(apiType, entityType string, entityID int64, year, month, page int) string {
+	if m.BuildCacheKeyFunc == nil {
+		return ""
+	}
+	return m.BuildCacheKeyFunc(apiType, entityType, entityID, year, month, page)
+}
+
+// Stats calls StatsFunc.
+func (m *ZKillClient) Stats() common.ClientStats {
+	if m.StatsFunc == nil {
+		return common.ClientStats{}
+	}
+	return m.StatsFunc()
+}
+
+// ZKillService is a zkill.ZKillService whose methods call the matching Func
+// field. Methods whose field is nil return zero values.
+type ZKillService struct {
+	GetKillMailDataForMonthFunc func(ctx context.Context, params *model.Params, year, month int) ([]model.FlattenedKillMail, error)
+	AggregateKillMailDumpsFunc  func(base, addition []model.FlattenedKillMail) []model.FlattenedKillMail
+	AddEsiKillMailFunc          func(ctx context.Context, mail model.ZkillMail, aggregated []model.FlattenedKillMail) ([]model.FlattenedKillMail, error)
+	GetSingleKillmailFunc       func(ctx context.Context, killID int64) (model.ZkillMailFeedResponse, error)
+}
+
+// GetKillMailDataForMonth calls GetKillMailDataForMonthFunc.
+func (m *ZKillService) GetKillMailDataForMonth(ctx context.Context, params *model.Params, year, month int) ([]model.FlattenedKillMail, error) {
+	if m.GetKillMailDataForMonthFunc == nil {
+		return nil, nil
+	}
+	return m.GetKillMailDataForMonthFunc(ctx, params, year, month)
+}
+
+// AggregateKillMailDumps calls AggregateKillMailDumpsFunc.
+func (m *ZKillService) AggregateKillMailDumps(base, addition []model.FlattenedKillMail) []model.FlattenedKillMail {
+	if m.AggregateKillMailDumpsFunc == nil {
+		return nil
+	}
+	return m.AggregateKillMailDumpsFunc(base, addition)
+}
+
+// AddEsiKillMail calls AddEsiKillMailFunc.
+func (m *ZKillService) AddEsiKillMail(ctx context.Context, mail model.ZkillMail, aggregated []model.FlattenedKillMail) ([]model.FlattenedKillMail, error) {
+	if m.AddEsiKillMailFunc == nil {
+		return nil, nil
+	}
+	return m.AddEsiKillMailFunc(ctx, mail, aggregated)
+}
+
+// GetSingleKillmail calls GetSingleKillmailFunc.
+func (m *ZKillService) GetSingleKillmail(ctx context.Context, killID int64) (model.ZkillMailFeedResponse, error) {
+	if m.GetSingleKillmailFunc == nil {
+		return model.ZkillMailFeedResponse{}, nil
+	}
+	return m.GetSingleKillmailFunc(ctx, killID)
+}